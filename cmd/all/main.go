@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/robfig/cron/v3"
+	"pr-reporter/internal/config"
+	"pr-reporter/internal/errkind"
+	"pr-reporter/internal/health"
+	"pr-reporter/internal/logging"
+	"pr-reporter/internal/report"
+	"pr-reporter/internal/tracing"
+)
+
+func main() {
+	reports := flag.String("reports", strings.Join(report.Names, ","), "Comma-separated reports to run: "+strings.Join(report.Names, ", "))
+	weekly := flag.Bool("weekly", false, "Post the weekly summaries instead of the daily reports")
+	monthly := flag.Bool("monthly", false, "Post the monthly retrospectives instead of the daily reports")
+	schedule := flag.String("schedule", "", "Keep running and run the reports on this cron schedule (e.g. \"0 9 * * 1-5\"), serving health endpoints (default: REPORT_SCHEDULE, or run once)")
+	flag.Parse()
+
+	// Load environment variables from .env file
+	err := godotenv.Load()
+	logging.Setup()
+	if err != nil {
+		slog.Warn(".env file not found or could not be loaded, using system environment variables")
+	}
+
+	settings := config.LoadScheduler()
+	if *schedule != "" {
+		settings.Schedule = *schedule
+	}
+
+	// Configurations are built for every run, as some settings (e.g.,
+	// SLACK_POST_AT) depend on the day of the run
+	configs := func() ([]report.Config, error) {
+		var cfgs []report.Config
+		for _, name := range strings.Split(*reports, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			cfg, err := report.ConfigFor(name)
+			if err != nil {
+				return nil, err
+			}
+			if *weekly {
+				cfg = report.WeeklyConfig(cfg)
+			}
+			if *monthly {
+				cfg = report.MonthlyConfig(cfg)
+			}
+			cfgs = append(cfgs, cfg)
+		}
+		return cfgs, nil
+	}
+	if _, err := configs(); err != nil {
+		logging.Exit("Could not configure reports", err)
+	}
+
+	shutdownTracing, err := tracing.Init()
+	if err != nil {
+		slog.Warn("Tracing disabled", "error", err)
+	}
+	defer shutdownTracing()
+
+	run := func() error {
+		cfgs, err := configs()
+		if err != nil {
+			return err
+		}
+		slog.Info("Starting PR reports", "reports", len(cfgs))
+		if err := report.RunAll(cfgs); err != nil {
+			return err
+		}
+		slog.Info("PR reports sent", "reports", len(cfgs))
+		return nil
+	}
+
+	if settings.Schedule == "" {
+		err = run()
+		shutdownTracing() // Export the runs' spans before exiting
+		if err != nil {
+			logging.Exit("Could not run every PR report", err)
+		}
+		return
+	}
+
+	runScheduled(settings, run)
+}
+
+// runScheduled runs the reports on the schedule until interrupted, serving
+// the scheduler's health on settings.HealthAddr
+func runScheduled(settings config.Scheduler, run func() error) {
+	scheduler := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+
+	var entryID cron.EntryID
+	monitor := health.NewMonitor(func() time.Time { return scheduler.Entry(entryID).Next })
+	if settings.MaxRunTime > 0 {
+		monitor.MaxRunTime = settings.MaxRunTime
+	}
+
+	entryID, err := scheduler.AddFunc(settings.Schedule, func() {
+		monitor.Begin()
+		err := run()
+		monitor.End(err)
+		if err != nil {
+			slog.Error("Scheduled PR reports failed", "error", err)
+		}
+	})
+	if err != nil {
+		logging.Exit("Invalid report schedule", &errkind.ConfigError{Err: err}, "schedule", settings.Schedule)
+	}
+
+	server := &http.Server{Addr: settings.HealthAddr, Handler: monitor.Handler()}
+	go func() {
+		slog.Info("Serving health endpoints", "addr", settings.HealthAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Fatal("Health server error", "error", err)
+		}
+	}()
+
+	scheduler.Start()
+	monitor.SetReady()
+	slog.Info("Scheduled PR reports", "schedule", settings.Schedule, "next", scheduler.Entry(entryID).Next)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	slog.Info("Stopping scheduler, waiting for the run in progress")
+	<-scheduler.Stop().Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Shutdown(ctx)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"pr-reporter/internal/errkind"
+	"pr-reporter/internal/logging"
+	"pr-reporter/internal/report"
+)
+
+func main() {
+	format := flag.String("format", "csv", "Export format: csv, json or md")
+	out := flag.String("out", "", "File to write the export to (- for standard output)")
+	name := flag.String("report", "frontend", "Report to export: "+strings.Join(report.Names, ", "))
+	flag.Parse()
+
+	// Load environment variables from .env file
+	err := godotenv.Load()
+	logging.Setup()
+	if err != nil {
+		slog.Warn(".env file not found or could not be loaded, using system environment variables")
+	}
+
+	if *out == "" {
+		logging.Exit("Invalid arguments", errkind.Configf("--out is required (use - for standard output)"))
+	}
+
+	cfg, err := report.ConfigFor(*name)
+	if err != nil {
+		logging.Exit("Invalid arguments", err)
+	}
+
+	if err := report.Export(cfg, strings.ToLower(*format), *out); err != nil {
+		logging.Exit("Could not export PRs", err, "report", cfg.Name)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"pr-reporter/internal/logging"
+	"pr-reporter/internal/report"
+)
+
+func main() {
+	name := flag.String("report", "", "Only list deliveries of this report: "+strings.Join(report.Names, ", ")+" (default: all reports)")
+	since := flag.Duration("since", 24*time.Hour, "List deliveries sent within this duration (e.g. 72h)")
+	failed := flag.Bool("failed", false, "Only list failed deliveries")
+	messages := flag.Bool("messages", false, "List the latest Slack report of every channel with its permalink instead of deliveries")
+	flag.Parse()
+
+	// Load environment variables from .env file
+	err := godotenv.Load()
+	logging.Setup()
+	if err != nil {
+		slog.Warn(".env file not found or could not be loaded, using system environment variables")
+	}
+
+	// Every report shares the state database, any configuration finds it
+	cfg := report.FrontendConfig()
+	if *name != "" {
+		cfg, err = report.ConfigFor(*name)
+		if err != nil {
+			logging.Exit("Invalid arguments", err)
+		}
+	}
+
+	if *messages {
+		if err := report.PostedReports(cfg, *name == "", os.Stdout); err != nil {
+			logging.Exit("Could not read posted reports", err)
+		}
+		return
+	}
+
+	if err := report.History(cfg, *name == "", time.Now().Add(-*since), *failed, os.Stdout); err != nil {
+		logging.Exit("Could not read the audit log", err)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"pr-reporter/internal/errkind"
+	"pr-reporter/internal/logging"
+	"pr-reporter/internal/report"
+)
+
+func main() {
+	date := flag.String("date", "", "Day of the report to replay (YYYY-MM-DD)")
+	name := flag.String("report", "frontend", "Report to replay: "+strings.Join(report.Names, ", "))
+	send := flag.Bool("send", false, "Post the replayed report to Slack instead of printing it")
+	flag.Parse()
+
+	// Load environment variables from .env file
+	err := godotenv.Load()
+	logging.Setup()
+	if err != nil {
+		slog.Warn(".env file not found or could not be loaded, using system environment variables")
+	}
+
+	if *date == "" {
+		logging.Exit("Invalid arguments", errkind.Configf("--date is required (YYYY-MM-DD)"))
+	}
+	day, err := time.ParseInLocation("2006-01-02", *date, time.Local)
+	if err != nil {
+		logging.Exit("Invalid arguments", errkind.Configf("invalid --date %q, expected YYYY-MM-DD", *date))
+	}
+
+	cfg, err := report.ConfigFor(*name)
+	if err != nil {
+		logging.Exit("Invalid arguments", err)
+	}
+
+	if err := report.Replay(cfg, day, *send, os.Stdout); err != nil {
+		logging.Exit("Could not replay report", err, "report", cfg.Name)
+	}
+}
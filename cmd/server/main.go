@@ -0,0 +1,134 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/joho/godotenv"
+	"pr-reporter/internal/config"
+	"pr-reporter/internal/logging"
+	"pr-reporter/internal/report"
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/tracing"
+)
+
+func main() {
+	// Load environment variables from .env file
+	err := godotenv.Load()
+	logging.Setup()
+	if err != nil {
+		slog.Warn(".env file not found or could not be loaded, using system environment variables")
+	}
+
+	shutdownTracing, err := tracing.Init()
+	if err != nil {
+		slog.Warn("Tracing disabled", "error", err)
+	}
+	defer shutdownTracing()
+
+	settings := config.LoadServer()
+
+	interactionOpts := slack.InteractionOptions{
+		SigningSecret:  settings.SigningSecret,
+		StateFile:      settings.StateFile,
+		SnoozeDuration: settings.Snooze, // Optional snooze duration for the "Snooze" button
+		OnApprove:      report.Approve,
+	}
+
+	// Keep pinned live status messages fresh while the server runs
+	if settings.LiveRefresh > 0 {
+		slog.Info("Refreshing live status messages", "interval", settings.LiveRefresh)
+		go report.RefreshLive(settings.LiveRefresh)
+	}
+
+	port := settings.Port
+
+	// Serve the static HTML reports when they are written locally
+	htmlDir := settings.HTMLDir
+	reportsHandler := http.StripPrefix("/reports/", http.FileServer(http.Dir(htmlDir)))
+
+	// The admin API is only served when a token is configured
+	var adminHandler http.Handler
+	if settings.AdminToken != "" {
+		adminHandler = report.NewAdminHandler(settings.AdminToken)
+	}
+
+	runCommand := func(cmd slack.Command) (string, error) {
+		return report.RunOnDemand(cmd.Text, cmd.ChannelID)
+	}
+
+	// Socket Mode needs no public endpoint, use it when an app token is configured
+	if settings.AppToken != "" {
+		socketOpts := slack.SocketOptions{
+			AppToken:    settings.AppToken,
+			BotToken:    settings.BotToken,
+			APIURL:      settings.SlackAPIURL,
+			Interaction: interactionOpts,
+		}
+
+		slog.Info("Starting PR Reporter bot in Socket Mode")
+
+		if htmlDir != "" || adminHandler != nil {
+			go func() {
+				mux := http.NewServeMux()
+				if htmlDir != "" {
+					mux.Handle("/reports/", reportsHandler)
+					slog.Info("Serving HTML reports", "dir", htmlDir, "addr", ":"+port+"/reports/")
+				}
+				if adminHandler != nil {
+					for _, path := range report.AdminPaths {
+						mux.Handle(path, adminHandler)
+					}
+					slog.Info("Serving the admin API", "addr", ":"+port)
+				}
+				if err := http.ListenAndServe(":"+port, mux); err != nil {
+					slog.Warn("HTTP server error", "error", err)
+				}
+			}()
+		}
+
+		if err := slack.RunSocketMode(socketOpts, report.HandleMention, runCommand); err != nil {
+			logging.Fatal("Socket Mode error", "error", err)
+		}
+		return
+	}
+
+	if interactionOpts.SigningSecret == "" {
+		logging.Fatal("SLACK_SIGNING_SECRET is required to verify Slack requests (or set SLACK_APP_TOKEN for Socket Mode)")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/slack/interactive", slack.NewInteractionHandler(interactionOpts))
+
+	// /pr-report slash command for on-demand reports
+	commandOpts := slack.CommandOptions{
+		SigningSecret: interactionOpts.SigningSecret,
+	}
+	mux.Handle("/slack/commands", slack.NewCommandHandler(commandOpts, runCommand))
+
+	// Mentions of the bot, subscribed to in the Events API settings
+	eventOpts := slack.EventOptions{
+		SigningSecret: interactionOpts.SigningSecret,
+		BotToken:      settings.BotToken,
+		APIURL:        settings.SlackAPIURL,
+	}
+	mux.Handle("/slack/events", slack.NewEventHandler(eventOpts, report.HandleMention))
+
+	if htmlDir != "" {
+		mux.Handle("/reports/", reportsHandler)
+		slog.Info("Serving HTML reports", "dir", htmlDir, "path", "/reports/")
+	}
+
+	if adminHandler != nil {
+		for _, path := range report.AdminPaths {
+			mux.Handle(path, adminHandler)
+		}
+		slog.Info("Serving the admin API", "paths", report.AdminPaths)
+	}
+
+	slog.Info("Starting PR Reporter server", "addr", ":"+port)
+
+	if err := http.ListenAndServe(":"+port, mux); err != nil {
+		logging.Fatal("Server error", "error", err)
+	}
+}
@@ -0,0 +1,191 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/webhook"
+)
+
+// DefaultRegion is the S3 region used when none is configured
+const DefaultRegion = "us-east-1"
+
+// Options contains options for archiving reports to S3-compatible object
+// storage: Amazon S3, Google Cloud Storage (with HMAC keys) or MinIO
+type Options struct {
+	Bucket          string // Bucket the reports are archived in
+	Prefix          string // Key prefix (e.g., "pr-reports/")
+	Endpoint        string // Storage endpoint (default: Amazon S3 in Region; "https://storage.googleapis.com" for GCS)
+	Region          string // Bucket region (default: us-east-1; "auto" for GCS)
+	AccessKeyID     string // Access key ID (GCS: HMAC key access ID)
+	SecretAccessKey string // Secret access key (GCS: HMAC key secret)
+	SessionToken    string // Session token of temporary credentials (optional)
+}
+
+// Configured reports whether the options contain a bucket and credentials
+func (o Options) Configured() bool {
+	return o.Bucket != "" && o.AccessKeyID != "" && o.SecretAccessKey != ""
+}
+
+// httpClient is used to upload to object storage
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Archive"}).Client(30 * time.Second)
+
+// ArchiveReport stores the rendered message and the JSON payload of a report
+// under date-based keys: <prefix><report>/<yyyy>/<mm>/<dd>/<report>-<hhmmss>.txt
+// and .json
+func ArchiveReport(ctx context.Context, opts Options, report model.Report, message string) error {
+	if !opts.Configured() {
+		return fmt.Errorf("archive bucket and credentials are required")
+	}
+
+	payload, err := json.MarshalIndent(webhook.NewPayload(report), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding report payload: %w", err)
+	}
+
+	date := report.Date.UTC()
+	base := fmt.Sprintf("%s%s/%s/%s-%s", opts.Prefix, report.Name, date.Format("2006/01/02"), report.Name, date.Format("150405"))
+
+	objects := []struct {
+		key         string
+		contentType string
+		body        []byte
+	}{
+		{base + ".txt", "text/plain; charset=utf-8", []byte(message)},
+		{base + ".json", "application/json", payload},
+	}
+	for _, object := range objects {
+		if err := putObject(ctx, opts, object.key, object.contentType, object.body); err != nil {
+			return fmt.Errorf("error archiving %s: %w", object.key, err)
+		}
+
+		slog.Debug("Archived report", "key", object.key, "bytes", len(object.body), "bucket", opts.Bucket)
+	}
+
+	return nil
+}
+
+// putObject uploads an object with a request signed with AWS Signature Version 4
+func putObject(ctx context.Context, opts Options, key, contentType string, body []byte) error {
+	region := opts.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	// Amazon S3 uses virtual-hosted buckets, other endpoints path-style ones
+	var objectURL string
+	if opts.Endpoint == "" {
+		objectURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", opts.Bucket, region, escapePath(key))
+	} else {
+		objectURL = fmt.Sprintf("%s/%s/%s", strings.TrimRight(opts.Endpoint, "/"), opts.Bucket, escapePath(key))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	sign(req, opts, region, body, time.Now())
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return httpx.NewStatusError("Archive", resp)
+	}
+
+	return nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req
+func sign(req *http.Request, opts Options, region string, body []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if opts.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", opts.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	if req.ContentLength > 0 {
+		headers["content-length"] = strconv.FormatInt(req.ContentLength, 10)
+	}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+opts.SecretAccessKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		opts.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// escapePath escapes each segment of an object key for use in a URL
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// sha256Hex returns the hex-encoded SHA-256 hash of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,192 @@
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/jira"
+)
+
+// apiURL is the Asana REST API base URL
+const apiURL = "https://app.asana.com/api/1.0"
+
+// FetchOptions contains options for fetching Asana task information
+type FetchOptions struct {
+	Token string // Asana personal access token
+}
+
+// task is the part of an Asana task used here
+type task struct {
+	Name         string `json:"name"`
+	Completed    bool   `json:"completed"`
+	PermalinkURL string `json:"permalink_url"`
+	Assignee     *struct {
+		Name string `json:"name"`
+	} `json:"assignee"`
+	Memberships []struct {
+		Section *struct {
+			Name string `json:"name"`
+		} `json:"section"`
+	} `json:"memberships"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	CustomFields []struct {
+		Name         string `json:"name"`
+		DisplayValue string `json:"display_value"`
+	} `json:"custom_fields"`
+}
+
+// httpClient is used to call the Asana API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Asana"}).Client(30 * time.Second)
+
+// Task references in PR descriptions: task URLs of the old
+// (app.asana.com/0/<project>/<task>) and new (.../project/<project>/task/<task>)
+// formats, or IDs written as "Asana: <task>" or "Asana #<task>"
+var (
+	taskURLRegex    = regexp.MustCompile(`app\.asana\.com/0/\d+/(\d+)`)
+	taskURLNewRegex = regexp.MustCompile(`app\.asana\.com/\d+/\d+/project/\d+/task/(\d+)`)
+	taskIDRegex     = regexp.MustCompile(`(?i)\basana\s*[:#]\s*(\d{6,})`)
+)
+
+// TaskIDFromText returns the ID of the first Asana task referenced in a PR
+// description, or ""
+func TaskIDFromText(text string) string {
+	for _, re := range []*regexp.Regexp{taskURLNewRegex, taskURLRegex, taskIDRegex} {
+		if match := re.FindStringSubmatch(text); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// FetchTaskInfo fetches the status, name and assignee of a single Asana task.
+// The status is the task's board section, or "Completed" / "Open" when it
+// isn't in one.
+func FetchTaskInfo(ctx context.Context, opts FetchOptions, taskID string) (*jira.TicketInfo, error) {
+	if taskID == "" {
+		return nil, fmt.Errorf("task ID is required")
+	}
+	if opts.Token == "" {
+		return nil, fmt.Errorf("Asana token is required")
+	}
+
+	slog.Debug("Fetching Asana task", "task", taskID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/tasks/%s?opt_fields=name,completed,permalink_url,assignee.name,memberships.section.name,tags.name,custom_fields.name,custom_fields.display_value", apiURL, taskID), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+opts.Token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Asana task %s: %w", taskID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &jira.TicketInfo{
+			TicketID:  taskID,
+			Status:    "Not Found",
+			Summary:   "Task not found",
+			IsBlocked: false,
+		}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpx.NewStatusError("Asana", resp)
+	}
+
+	var result struct {
+		Data task `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding Asana response: %w", err)
+	}
+
+	ticketInfo := &jira.TicketInfo{
+		TicketID: taskID,
+		Status:   "Open",
+		Summary:  result.Data.Name,
+		URL:      result.Data.PermalinkURL,
+	}
+	if result.Data.Assignee != nil {
+		ticketInfo.Assignee = result.Data.Assignee.Name
+	}
+
+	// Asana has no built-in priority, projects commonly add a "Priority" field
+	for _, field := range result.Data.CustomFields {
+		if strings.EqualFold(field.Name, "Priority") {
+			ticketInfo.Priority = field.DisplayValue
+			break
+		}
+	}
+
+	var names []string
+	for _, membership := range result.Data.Memberships {
+		if membership.Section != nil && membership.Section.Name != "" {
+			names = append(names, membership.Section.Name)
+		}
+	}
+	if len(names) > 0 {
+		ticketInfo.Status = names[0]
+	}
+	if result.Data.Completed {
+		ticketInfo.Status = "Completed"
+	}
+
+	// Check if blocked by section or tag name, like JIRA tickets
+	for _, tag := range result.Data.Tags {
+		names = append(names, tag.Name)
+	}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if strings.Contains(name, "block") || strings.Contains(name, "impediment") || strings.Contains(name, "pause") {
+			ticketInfo.IsBlocked = true
+			break
+		}
+	}
+
+	slog.Debug("Fetched Asana task", "task", taskID, "status", ticketInfo.Status, "blocked", ticketInfo.IsBlocked, "assignee", ticketInfo.Assignee)
+
+	return ticketInfo, nil
+}
+
+// FetchTasksInfo fetches information for multiple Asana tasks. It stops with
+// the context's error once ctx is done.
+func FetchTasksInfo(ctx context.Context, opts FetchOptions, taskIDs []string) (map[string]*jira.TicketInfo, error) {
+	results := make(map[string]*jira.TicketInfo)
+
+	for _, taskID := range taskIDs {
+		if taskID == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, fmt.Errorf("error fetching Asana tasks: %v", err)
+		}
+
+		ticketInfo, err := FetchTaskInfo(ctx, opts, taskID)
+		if err != nil {
+			slog.Warn("Could not fetch Asana task", "task", taskID, "error", err)
+			results[taskID] = &jira.TicketInfo{
+				TicketID:  taskID,
+				Status:    "Error",
+				Summary:   fmt.Sprintf("Error: %v", err),
+				IsBlocked: false,
+			}
+			continue
+		}
+
+		results[taskID] = ticketInfo
+	}
+
+	return results, nil
+}
@@ -0,0 +1,293 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/filter"
+	"pr-reporter/internal/github"
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/jira"
+)
+
+// DefaultURL is the Azure DevOps Services base URL, used when none is configured
+const DefaultURL = "https://dev.azure.com"
+
+// apiVersion is the Azure DevOps REST API version used
+const apiVersion = "7.0"
+
+// ticketPrefix marks work item IDs in PR results, following the AB#123
+// syntax Azure Boards uses to link work items
+const ticketPrefix = "AB#"
+
+// maxWorkItemsPerRequest is the largest batch the work items API accepts
+const maxWorkItemsPerRequest = 200
+
+// FetchOptions contains options for fetching PRs and work items from Azure DevOps
+type FetchOptions struct {
+	filter.Options // Which PRs to include (labels match PR tags)
+
+	URL          string // Azure DevOps base URL (default: https://dev.azure.com, or the collection URL of a Server)
+	Organization string // Organization name (empty for Azure DevOps Server collection URLs)
+	Project      string // Project name
+	Repo         string // Repository name
+	Token        string // Personal Access Token with Code (read) and Work Items (read) scopes
+}
+
+// identity is an Azure DevOps user or group reference
+type identity struct {
+	UniqueName string `json:"uniqueName"`
+}
+
+// pullRequest is the part of an Azure DevOps pull request used here
+type pullRequest struct {
+	ID           int       `json:"pullRequestId"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	IsDraft      bool      `json:"isDraft"`
+	CreatedBy    identity  `json:"createdBy"`
+	CreationDate time.Time `json:"creationDate"`
+	SourceRef    string    `json:"sourceRefName"` // e.g., "refs/heads/feature"
+	TargetRef    string    `json:"targetRefName"`
+	Reviewers    []struct {
+		identity
+		Vote int `json:"vote"` // 10 approved, 5 approved with suggestions, 0 no vote, -5 waiting for author, -10 rejected
+	} `json:"reviewers"`
+	Labels []struct {
+		Name   string `json:"name"`
+		Active bool   `json:"active"`
+	} `json:"labels"`
+}
+
+// httpClient is used to call the Azure DevOps API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Azure DevOps"}).Client(30 * time.Second)
+
+// FetchPRs fetches the active PRs of an Azure DevOps repository as PR results,
+// applying the same filters as GitHub. The first work item linked to a PR
+// takes the place of its JIRA ticket.
+func FetchPRs(ctx context.Context, opts FetchOptions) ([]*github.PRResult, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("Azure DevOps token is required")
+	}
+	if opts.Project == "" || opts.Repo == "" {
+		return nil, fmt.Errorf("Azure DevOps project and repository are required")
+	}
+
+	repoPath := fmt.Sprintf("/_apis/git/repositories/%s/pullrequests", url.PathEscape(opts.Repo))
+
+	var prs []pullRequest
+	for skip := 0; ; skip += 100 {
+		var page struct {
+			Value []pullRequest `json:"value"`
+		}
+		if err := get(ctx, opts, fmt.Sprintf("%s?searchCriteria.status=active&$top=100&$skip=%d", repoPath, skip), &page); err != nil {
+			return nil, fmt.Errorf("error fetching PRs from %s/%s: %w", opts.Project, opts.Repo, err)
+		}
+		prs = append(prs, page.Value...)
+		if len(page.Value) < 100 {
+			break
+		}
+	}
+
+	logger := slog.With("repo", opts.Project+"/"+opts.Repo)
+	logger.Debug("Fetched active PRs", "count", len(prs))
+
+	filters := opts.Chain()
+	var results []*github.PRResult
+	for _, pr := range prs {
+		var labels []string
+		for _, label := range pr.Labels {
+			if label.Active {
+				labels = append(labels, label.Name)
+			}
+		}
+
+		if reason := filter.Apply(filters, filterPR(pr, labels)); reason != "" {
+			logger.Debug("Skipped PR", "pr", pr.ID, "reason", reason)
+			continue
+		}
+
+		result := &github.PRResult{
+			Number:    pr.ID,
+			Title:     pr.Title,
+			URL:       fmt.Sprintf("%s/_git/%s/pullrequest/%d", projectURL(opts), url.PathEscape(opts.Repo), pr.ID),
+			IsDraft:   pr.IsDraft,
+			Labels:    labels,
+			Author:    pr.CreatedBy.UniqueName,
+			Body:      pr.Description,
+			CreatedAt: pr.CreationDate,
+			UpdatedAt: pr.CreationDate, // The PR list doesn't include the last update
+		}
+
+		for _, reviewer := range pr.Reviewers {
+			switch {
+			case reviewer.Vote > 0:
+				result.Reviews = append(result.Reviews, github.Review{User: reviewer.UniqueName, State: "APPROVED"})
+			case reviewer.Vote < 0:
+				result.Reviews = append(result.Reviews, github.Review{User: reviewer.UniqueName, State: "CHANGES_REQUESTED"})
+			default:
+				result.Reviewers = append(result.Reviewers, reviewer.UniqueName)
+			}
+		}
+
+		workItem, err := fetchLinkedWorkItem(ctx, opts, repoPath, pr.ID)
+		if err != nil {
+			logger.Warn("Could not fetch work items", "pr", pr.ID, "error", err)
+		} else if workItem != "" {
+			result.JiraTicket = ticketPrefix + workItem
+		}
+
+		logger.Debug("Included PR", "pr", pr.ID, "draft", result.IsDraft, "ticket", result.JiraTicket)
+
+		results = append(results, result)
+	}
+
+	logger.Debug("Filtered PRs", "count", len(results))
+
+	return results, nil
+}
+
+// fetchLinkedWorkItem returns the ID of the first work item linked to a PR, or ""
+func fetchLinkedWorkItem(ctx context.Context, opts FetchOptions, repoPath string, id int) (string, error) {
+	var refs struct {
+		Value []struct {
+			ID string `json:"id"`
+		} `json:"value"`
+	}
+	if err := get(ctx, opts, fmt.Sprintf("%s/%d/workitems", repoPath, id), &refs); err != nil {
+		return "", err
+	}
+	if len(refs.Value) == 0 {
+		return "", nil
+	}
+	return refs.Value[0].ID, nil
+}
+
+// FetchWorkItems fetches the title and state of the work items referenced by
+// PR results, keyed by their ticket ID. Work items in a "Blocked" state or
+// flagged as blocked count as blocked.
+func FetchWorkItems(ctx context.Context, opts FetchOptions, ticketIDs []string) (map[string]*jira.TicketInfo, error) {
+	var ids []string
+	for _, ticketID := range ticketIDs {
+		if id := strings.TrimPrefix(ticketID, ticketPrefix); id != ticketID {
+			ids = append(ids, id)
+		}
+	}
+
+	result := make(map[string]*jira.TicketInfo)
+	for start := 0; start < len(ids); start += maxWorkItemsPerRequest {
+		end := start + maxWorkItemsPerRequest
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var page struct {
+			Value []struct {
+				ID     int `json:"id"`
+				Fields struct {
+					Title    string `json:"System.Title"`
+					State    string `json:"System.State"`
+					Blocked  string `json:"Microsoft.VSTS.CMMI.Blocked"`
+					Priority int    `json:"Microsoft.VSTS.Common.Priority"`
+				} `json:"fields"`
+			} `json:"value"`
+		}
+		query := url.Values{
+			"ids":         {strings.Join(ids[start:end], ",")},
+			"fields":      {"System.Title,System.State,Microsoft.VSTS.CMMI.Blocked,Microsoft.VSTS.Common.Priority"},
+			"errorPolicy": {"omit"},
+		}
+		if err := get(ctx, opts, "/_apis/wit/workitems?"+query.Encode(), &page); err != nil {
+			return result, fmt.Errorf("error fetching work items: %w", err)
+		}
+
+		for _, item := range page.Value {
+			ticketID := ticketPrefix + strconv.Itoa(item.ID)
+			result[ticketID] = &jira.TicketInfo{
+				TicketID:  ticketID,
+				Status:    item.Fields.State,
+				Summary:   item.Fields.Title,
+				IsBlocked: strings.EqualFold(item.Fields.State, "Blocked") || strings.EqualFold(item.Fields.Blocked, "Yes"),
+				URL:       fmt.Sprintf("%s/_workitems/edit/%d", projectURL(opts), item.ID),
+			}
+			if item.Fields.Priority > 0 {
+				result[ticketID].Priority = strconv.Itoa(item.Fields.Priority)
+			}
+		}
+	}
+
+	slog.Debug("Fetched work items", "fetched", len(result), "requested", len(ids))
+
+	return result, nil
+}
+
+// projectURL returns the web and API base URL of the project
+func projectURL(opts FetchOptions) string {
+	baseURL := strings.TrimRight(opts.URL, "/")
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	if opts.Organization != "" {
+		baseURL += "/" + url.PathEscape(opts.Organization)
+	}
+	return baseURL + "/" + url.PathEscape(opts.Project)
+}
+
+// get calls the Azure DevOps REST API and decodes the response into result
+func get(ctx context.Context, opts FetchOptions, path string, result interface{}) error {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, projectURL(opts)+path+separator+"api-version="+apiVersion, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth("", opts.Token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return httpx.NewStatusError("Azure DevOps", resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("error decoding Azure DevOps response: %w", err)
+	}
+
+	return nil
+}
+
+// filterPR returns what the filters look at of a PR with its active labels
+func filterPR(pr pullRequest, labels []string) filter.PR {
+	result := filter.PR{
+		Number:    pr.ID,
+		Title:     pr.Title,
+		Author:    pr.CreatedBy.UniqueName,
+		Base:      strings.TrimPrefix(pr.TargetRef, "refs/heads/"),
+		Head:      strings.TrimPrefix(pr.SourceRef, "refs/heads/"),
+		Labels:    labels,
+		Draft:     pr.IsDraft,
+		CreatedAt: pr.CreationDate,
+		UpdatedAt: pr.CreationDate,
+	}
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.Vote == 0 {
+			result.Reviewers = append(result.Reviewers, reviewer.UniqueName)
+		}
+	}
+	return result
+}
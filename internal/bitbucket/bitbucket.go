@@ -0,0 +1,309 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/filter"
+	"pr-reporter/internal/github"
+	"pr-reporter/internal/httpx"
+)
+
+// cloudAPIURL is the Bitbucket Cloud REST API base URL
+const cloudAPIURL = "https://api.bitbucket.org/2.0"
+
+// FetchOptions contains options for fetching PRs from Bitbucket Cloud or
+// Bitbucket Server / Data Center
+type FetchOptions struct {
+	filter.Options // Which PRs to include (Bitbucket has no labels)
+
+	URL         string // Bitbucket Server base URL (empty for Bitbucket Cloud)
+	Workspace   string // Cloud workspace, or Server project key
+	Repo        string // Repository slug
+	Username    string // Username for app password (Cloud) or password (Server) auth
+	AppPassword string // App password (Cloud) or password (Server)
+	Token       string // Access token, used instead of username and app password
+}
+
+// cloudUser is a Bitbucket Cloud user reference
+type cloudUser struct {
+	Nickname    string `json:"nickname"`
+	DisplayName string `json:"display_name"`
+}
+
+// cloudPR is the part of a Bitbucket Cloud pull request used here
+type cloudPR struct {
+	ID          int         `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Draft       bool        `json:"draft"`
+	Author      cloudUser   `json:"author"`
+	Reviewers   []cloudUser `json:"reviewers"`
+	Source      cloudRef    `json:"source"`
+	Destination cloudRef    `json:"destination"`
+	CreatedOn   time.Time   `json:"created_on"`
+	UpdatedOn   time.Time   `json:"updated_on"`
+	Links       struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+	Participants []struct {
+		User     cloudUser `json:"user"`
+		Role     string    `json:"role"`
+		Approved bool      `json:"approved"`
+		State    string    `json:"state"` // "approved", "changes_requested" or null
+	} `json:"participants"`
+}
+
+// cloudRef is the branch a Bitbucket Cloud PR is opened from or targets
+type cloudRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+// serverRef is the branch a Bitbucket Server PR is opened from or targets
+type serverRef struct {
+	DisplayID string `json:"displayId"`
+}
+
+// serverUser is a Bitbucket Server user reference
+type serverUser struct {
+	Name string `json:"name"`
+}
+
+// serverPR is the part of a Bitbucket Server pull request used here
+type serverPR struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Draft       bool   `json:"draft"`
+	Author      struct {
+		User serverUser `json:"user"`
+	} `json:"author"`
+	Reviewers []struct {
+		User   serverUser `json:"user"`
+		Status string     `json:"status"` // APPROVED, NEEDS_WORK or UNAPPROVED
+	} `json:"reviewers"`
+	FromRef     serverRef `json:"fromRef"`
+	ToRef       serverRef `json:"toRef"`
+	CreatedDate int64     `json:"createdDate"` // Milliseconds since the epoch
+	UpdatedDate int64     `json:"updatedDate"`
+	Links       struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+// httpClient is used to call the Bitbucket API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Bitbucket"}).Client(30 * time.Second)
+
+// FetchPRs fetches the open PRs of a Bitbucket repository as PR results,
+// applying the same filters as GitHub. Bitbucket has no labels or assignees,
+// so those stay empty.
+func FetchPRs(ctx context.Context, opts FetchOptions) ([]*github.PRResult, error) {
+	if opts.Token == "" && (opts.Username == "" || opts.AppPassword == "") {
+		return nil, fmt.Errorf("Bitbucket token or username and app password are required")
+	}
+	if opts.Workspace == "" || opts.Repo == "" {
+		return nil, fmt.Errorf("Bitbucket workspace and repository are required")
+	}
+
+	logger := slog.With("repo", opts.Workspace+"/"+opts.Repo)
+	filters := opts.Chain()
+	include := func(result *github.PRResult, base, head string) bool {
+		reason := filter.Apply(filters, filter.PR{
+			Number:    result.Number,
+			Title:     result.Title,
+			Author:    result.Author,
+			Base:      base,
+			Head:      head,
+			Reviewers: result.Reviewers,
+			Draft:     result.IsDraft,
+			CreatedAt: result.CreatedAt,
+			UpdatedAt: result.UpdatedAt,
+		})
+		if reason != "" {
+			logger.Debug("Skipped PR", "pr", result.Number, "reason", reason)
+		}
+		return reason == ""
+	}
+
+	var results []*github.PRResult
+	var err error
+	if opts.URL == "" {
+		results, err = fetchCloudPRs(ctx, opts, include)
+	} else {
+		results, err = fetchServerPRs(ctx, opts, include)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Filtered PRs", "count", len(results))
+
+	return results, nil
+}
+
+// includeFunc reports whether a PR opened from head and targeting base passes
+// the filters
+type includeFunc func(result *github.PRResult, base, head string) bool
+
+// fetchCloudPRs fetches the open PRs passing include with their participants
+// from Bitbucket Cloud
+func fetchCloudPRs(ctx context.Context, opts FetchOptions, include includeFunc) ([]*github.PRResult, error) {
+	query := url.Values{
+		"state":   {"OPEN"},
+		"pagelen": {"50"},
+		"fields":  {"+values.participants,+values.reviewers,+values.draft"},
+	}
+	next := fmt.Sprintf("%s/repositories/%s/%s/pullrequests?%s", cloudAPIURL, url.PathEscape(opts.Workspace), url.PathEscape(opts.Repo), query.Encode())
+
+	var results []*github.PRResult
+	for next != "" {
+		var page struct {
+			Values []cloudPR `json:"values"`
+			Next   string    `json:"next"`
+		}
+		if err := get(ctx, opts, next, &page); err != nil {
+			return nil, fmt.Errorf("error fetching PRs from %s/%s: %w", opts.Workspace, opts.Repo, err)
+		}
+		next = page.Next
+
+		for _, pr := range page.Values {
+			result := &github.PRResult{
+				Number:     pr.ID,
+				Title:      pr.Title,
+				URL:        pr.Links.HTML.Href,
+				JiraTicket: github.JiraTicketFromTitle(pr.Title),
+				IsDraft:    pr.Draft,
+				Author:     pr.Author.Nickname,
+				Body:       pr.Description,
+				CreatedAt:  pr.CreatedOn,
+				UpdatedAt:  pr.UpdatedOn,
+			}
+
+			reviewed := make(map[string]bool)
+			for _, participant := range pr.Participants {
+				state := ""
+				switch {
+				case participant.Approved || participant.State == "approved":
+					state = "APPROVED"
+				case participant.State == "changes_requested":
+					state = "CHANGES_REQUESTED"
+				}
+				if state == "" {
+					continue
+				}
+				result.Reviews = append(result.Reviews, github.Review{User: participant.User.Nickname, State: state})
+				reviewed[participant.User.Nickname] = true
+			}
+			for _, reviewer := range pr.Reviewers {
+				if !reviewed[reviewer.Nickname] {
+					result.Reviewers = append(result.Reviewers, reviewer.Nickname)
+				}
+			}
+
+			if include(result, pr.Destination.Branch.Name, pr.Source.Branch.Name) {
+				results = append(results, result)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// fetchServerPRs fetches the open PRs passing include with their reviewers
+// from Bitbucket Server
+func fetchServerPRs(ctx context.Context, opts FetchOptions, include includeFunc) ([]*github.PRResult, error) {
+	baseURL := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/pull-requests", strings.TrimRight(opts.URL, "/"), url.PathEscape(opts.Workspace), url.PathEscape(opts.Repo))
+
+	var results []*github.PRResult
+	start := 0
+	for {
+		var page struct {
+			Values        []serverPR `json:"values"`
+			IsLastPage    bool       `json:"isLastPage"`
+			NextPageStart int        `json:"nextPageStart"`
+		}
+		if err := get(ctx, opts, fmt.Sprintf("%s?state=OPEN&limit=100&start=%d", baseURL, start), &page); err != nil {
+			return nil, fmt.Errorf("error fetching PRs from %s/%s: %w", opts.Workspace, opts.Repo, err)
+		}
+
+		for _, pr := range page.Values {
+			result := &github.PRResult{
+				Number:     pr.ID,
+				Title:      pr.Title,
+				JiraTicket: github.JiraTicketFromTitle(pr.Title),
+				IsDraft:    pr.Draft,
+				Author:     pr.Author.User.Name,
+				Body:       pr.Description,
+				CreatedAt:  time.UnixMilli(pr.CreatedDate),
+				UpdatedAt:  time.UnixMilli(pr.UpdatedDate),
+			}
+			if len(pr.Links.Self) > 0 {
+				result.URL = pr.Links.Self[0].Href
+			}
+
+			for _, reviewer := range pr.Reviewers {
+				switch reviewer.Status {
+				case "APPROVED":
+					result.Reviews = append(result.Reviews, github.Review{User: reviewer.User.Name, State: "APPROVED"})
+				case "NEEDS_WORK":
+					result.Reviews = append(result.Reviews, github.Review{User: reviewer.User.Name, State: "CHANGES_REQUESTED"})
+				default:
+					result.Reviewers = append(result.Reviewers, reviewer.User.Name)
+				}
+			}
+
+			if include(result, pr.ToRef.DisplayID, pr.FromRef.DisplayID) {
+				results = append(results, result)
+			}
+		}
+
+		if page.IsLastPage || len(page.Values) == 0 {
+			break
+		}
+		start = page.NextPageStart
+	}
+
+	return results, nil
+}
+
+// get calls the Bitbucket API and decodes the response into result
+func get(ctx context.Context, opts FetchOptions, rawURL string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.Token)
+	} else {
+		req.SetBasicAuth(opts.Username, opts.AppPassword)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return httpx.NewStatusError("Bitbucket", resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("error decoding Bitbucket response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"time"
+)
+
+// Default chart size in pixels
+const (
+	DefaultWidth  = 600
+	DefaultHeight = 160
+)
+
+// padding is the space between the plot and the image border in pixels
+const padding = 10
+
+// Chart colors
+var (
+	backgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	gridColor       = color.RGBA{0xe8, 0xe8, 0xe8, 0xff}
+	areaColor       = color.RGBA{0xd6, 0xe6, 0xf5, 0xff}
+	lineColor       = color.RGBA{0x12, 0x64, 0xa3, 0xff}
+)
+
+// Point is a value of the chart at a time
+type Point struct {
+	Time  time.Time
+	Value int
+}
+
+// TrendPNG draws points as an area chart, oldest first, and encodes it as
+// PNG. The x axis is proportional to time and the y axis starts at zero, with
+// grid lines at each quarter of the highest value.
+func TrendPNG(points []Point, width, height int) ([]byte, error) {
+	if len(points) < 2 {
+		return nil, fmt.Errorf("at least 2 points are needed for a chart, got %d", len(points))
+	}
+	if width <= 2*padding || height <= 2*padding {
+		width, height = DefaultWidth, DefaultHeight
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fill(img, img.Bounds(), backgroundColor)
+
+	maxValue := 1
+	for _, point := range points {
+		if point.Value > maxValue {
+			maxValue = point.Value
+		}
+	}
+
+	plotWidth := float64(width - 2*padding)
+	plotHeight := float64(height - 2*padding)
+	start, end := points[0].Time, points[len(points)-1].Time
+	span := end.Sub(start).Seconds()
+
+	x := func(point Point) int {
+		if span <= 0 {
+			return padding
+		}
+		return padding + int(point.Time.Sub(start).Seconds()/span*plotWidth)
+	}
+	y := func(value float64) int {
+		return height - padding - int(value/float64(maxValue)*plotHeight)
+	}
+
+	for quarter := 0; quarter <= 4; quarter++ {
+		gridY := y(float64(maxValue) * float64(quarter) / 4)
+		fill(img, image.Rect(padding, gridY, width-padding, gridY+1), gridColor)
+	}
+
+	// Fill the area under the line, then draw the line on top of it
+	for i := 1; i < len(points); i++ {
+		x0, x1 := x(points[i-1]), x(points[i])
+		for px := x0; px <= x1; px++ {
+			ratio := 0.0
+			if x1 > x0 {
+				ratio = float64(px-x0) / float64(x1-x0)
+			}
+			value := float64(points[i-1].Value) + ratio*float64(points[i].Value-points[i-1].Value)
+			fill(img, image.Rect(px, y(value), px+1, y(0)), areaColor)
+		}
+	}
+	for i := 1; i < len(points); i++ {
+		drawLine(img, x(points[i-1]), y(float64(points[i-1].Value)), x(points[i]), y(float64(points[i].Value)), lineColor)
+	}
+
+	// Mark the latest value
+	last := points[len(points)-1]
+	lastX, lastY := x(last), y(float64(last.Value))
+	fill(img, image.Rect(lastX-3, lastY-3, lastX+4, lastY+4), lineColor)
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, fmt.Errorf("error encoding chart: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+// fill paints a rectangle of the image
+func fill(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	for py := rect.Min.Y; py < rect.Max.Y; py++ {
+		for px := rect.Min.X; px < rect.Max.X; px++ {
+			img.SetRGBA(px, py, c)
+		}
+	}
+}
+
+// drawLine draws a 2 pixel wide line with Bresenham's algorithm
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	diff := dx + dy
+	for {
+		fill(img, image.Rect(x0, y0-1, x0+2, y0+1), c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * diff
+		if e2 >= dy {
+			diff += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			diff += dx
+			y0 += sy
+		}
+	}
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
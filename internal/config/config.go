@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitHub holds the GitHub connection shared by all reports
+type GitHub struct {
+	Token     string        // GITHUB_TOKEN, or TOKEN (Actions secrets can't start with GITHUB_)
+	Owner     string        // GITHUB_OWNER, or OWNER
+	APIURL    string        // GITHUB_API_URL, REST API of GitHub Enterprise Server (set by GitHub Actions)
+	SSOEmails bool          // GITHUB_SSO_EMAILS
+	Timeout   time.Duration // GITHUB_TIMEOUT (0: the client's default)
+}
+
+// LoadGitHub reads the GitHub connection from the environment
+func LoadGitHub() GitHub {
+	return GitHub{
+		Token:     Or("GITHUB_TOKEN", String("TOKEN")),
+		Owner:     Or("GITHUB_OWNER", String("OWNER")),
+		APIURL:    String("GITHUB_API_URL"),
+		SSOEmails: Bool("GITHUB_SSO_EMAILS"),
+		Timeout:   Duration("GITHUB_TIMEOUT"),
+	}
+}
+
+// Jira holds the JIRA connection shared by all reports
+type Jira struct {
+	URL      string        // JIRA_URL
+	Username string        // JIRA_USERNAME
+	APIToken string        // JIRA_API_TOKEN
+	UsePAT   bool          // JIRA_USE_PAT
+	Timeout  time.Duration // JIRA_TIMEOUT (0: the client's default)
+}
+
+// LoadJira reads the JIRA connection from the environment
+func LoadJira() Jira {
+	return Jira{
+		URL:      String("JIRA_URL"),
+		Username: String("JIRA_USERNAME"),
+		APIToken: String("JIRA_API_TOKEN"),
+		UsePAT:   Bool("JIRA_USE_PAT"),
+		Timeout:  Duration("JIRA_TIMEOUT"),
+	}
+}
+
+// defaultPort is the port the server listens on without PORT
+const defaultPort = "8080"
+
+// Server holds the settings of the interactive server (cmd/server)
+type Server struct {
+	Port          string        // PORT (default 8080)
+	SigningSecret string        // SLACK_SIGNING_SECRET, verifies HTTP requests from Slack
+	AppToken      string        // SLACK_APP_TOKEN, runs the server in Socket Mode when set
+	BotToken      string        // SLACK_TOKEN
+	SlackAPIURL   string        // SLACK_API_URL, e.g. an egress proxy (default: https://slack.com/api/)
+	StateFile     string        // STATE_FILE
+	HTMLDir       string        // HTML_REPORT_DIR, served under /reports/ when set
+	Snooze        time.Duration // SLACK_SNOOZE_DURATION (0: the default of the Snooze button)
+	LiveRefresh   time.Duration // SLACK_LIVE_REFRESH (0: live status messages aren't refreshed)
+	AdminToken    string        // ADMIN_TOKEN, enables the admin API when set
+}
+
+// LoadServer reads the server settings from the environment
+func LoadServer() Server {
+	server := Server{
+		Port:          Or("PORT", defaultPort),
+		SigningSecret: String("SLACK_SIGNING_SECRET"),
+		AppToken:      String("SLACK_APP_TOKEN"),
+		BotToken:      String("SLACK_TOKEN"),
+		SlackAPIURL:   String("SLACK_API_URL"),
+		StateFile:     String("STATE_FILE"),
+		HTMLDir:       String("HTML_REPORT_DIR"),
+		Snooze:        Duration("SLACK_SNOOZE_DURATION"),
+		LiveRefresh:   Duration("SLACK_LIVE_REFRESH"),
+		AdminToken:    String("ADMIN_TOKEN"),
+	}
+	if server.LiveRefresh < 0 {
+		slog.Warn("Invalid SLACK_LIVE_REFRESH, live status messages won't be refreshed", "value", String("SLACK_LIVE_REFRESH"))
+		server.LiveRefresh = 0
+	}
+	return server
+}
+
+// defaultHealthAddr is where the scheduler serves its health endpoints
+// without HEALTH_ADDR
+const defaultHealthAddr = ":8080"
+
+// Scheduler holds the settings of the scheduled mode of cmd/all
+type Scheduler struct {
+	Schedule   string        // REPORT_SCHEDULE, cron expression of the runs (empty: run once and exit)
+	HealthAddr string        // HEALTH_ADDR, address of /healthz, /readyz and /status (default :8080)
+	MaxRunTime time.Duration // HEALTH_MAX_RUN_TIME, after which a run in progress fails /healthz (0: the default)
+}
+
+// LoadScheduler reads the scheduler settings from the environment
+func LoadScheduler() Scheduler {
+	return Scheduler{
+		Schedule:   String("REPORT_SCHEDULE"),
+		HealthAddr: Or("HEALTH_ADDR", defaultHealthAddr),
+		MaxRunTime: Duration("HEALTH_MAX_RUN_TIME"),
+	}
+}
+
+// RateLimit is the request budget of an API
+type RateLimit struct {
+	Rate  float64 // Requests per second (0: unlimited)
+	Burst int     // Requests that may be sent at once before Rate spaces them
+}
+
+// LoadRateLimit reads the rate limit of an API from RATE_LIMITS (e.g.,
+// "github=5000/h,slack=1", per second without a unit) and RATE_BURSTS (e.g.,
+// "github=20"), keyed by the API name in lower case without spaces. What
+// isn't set keeps the value of fallback.
+func LoadRateLimit(api string, fallback RateLimit) RateLimit {
+	limit := fallback
+	key := rateLimitKey(api)
+
+	if value, set := rateLimitEntry("RATE_LIMITS", key); set {
+		rate, err := parseRate(value)
+		if err != nil {
+			slog.Warn("Ignoring invalid rate limit", "key", "RATE_LIMITS", "api", key, "value", value, "error", err)
+		} else {
+			limit.Rate = rate
+		}
+	}
+	if value, set := rateLimitEntry("RATE_BURSTS", key); set {
+		burst, err := strconv.Atoi(value)
+		if err != nil || burst < 1 {
+			slog.Warn("Ignoring invalid burst, expected a positive number", "key", "RATE_BURSTS", "api", key, "value", value)
+		} else {
+			limit.Burst = burst
+		}
+	}
+	return limit
+}
+
+// rateLimitKey returns the key of an API in RATE_LIMITS and RATE_BURSTS
+func rateLimitKey(api string) string {
+	return strings.ToLower(strings.ReplaceAll(api, " ", ""))
+}
+
+// rateLimitEntry returns the value of the API key in the list of envKey
+func rateLimitEntry(envKey, key string) (string, bool) {
+	for api, value := range Map(envKey) {
+		if rateLimitKey(api) == key {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// parseRate parses a request rate in requests per second: "5", "5/s", "300/m"
+// or "5000/h"
+func parseRate(value string) (float64, error) {
+	count, unit, _ := strings.Cut(value, "/")
+	rate, err := strconv.ParseFloat(strings.TrimSpace(count), 64)
+	if err != nil || rate < 0 {
+		return 0, fmt.Errorf("expected a number of requests, optionally per s, m or h")
+	}
+	switch strings.TrimSpace(unit) {
+	case "", "s":
+		return rate, nil
+	case "m":
+		return rate / 60, nil
+	case "h":
+		return rate / 3600, nil
+	}
+	return 0, fmt.Errorf("unknown unit %q, expected s, m or h", unit)
+}
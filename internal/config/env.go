@@ -0,0 +1,98 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// String reads an environment variable
+func String(key string) string {
+	return os.Getenv(key)
+}
+
+// Or reads an environment variable, returning fallback when unset
+func Or(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Bool reads a "true"/"false" environment variable
+func Bool(key string) bool {
+	return strings.ToLower(os.Getenv(key)) == "true"
+}
+
+// Int reads an integer environment variable, returning 0 when unset or invalid
+func Int(key string) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("Invalid number, using the default", "key", key, "value", value)
+		return 0
+	}
+	return n
+}
+
+// Duration reads a Go duration environment variable, returning 0 when unset or invalid
+func Duration(key string) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("Invalid duration, using the default", "key", key, "value", value)
+		return 0
+	}
+	return d
+}
+
+// List reads a comma-separated environment variable, dropping empty entries
+func List(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Map reads a comma-separated list of key=value pairs
+func Map(key string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range List(key) {
+		k, v, found := strings.Cut(pair, "=")
+		if !found {
+			slog.Warn("Ignoring entry, expected key=value", "key", key, "entry", pair)
+			continue
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+// UserMapping reads a user mapping (format: slack_id:github_user,...) into a
+// GitHub username -> Slack user ID map
+func UserMapping(key string) map[string]string {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		parts := strings.Split(strings.TrimSpace(pair), ":")
+		if len(parts) == 2 {
+			slackUserID := strings.TrimSpace(parts[0])
+			githubUser := strings.TrimSpace(parts[1])
+			if githubUser != "" {
+				mapping[githubUser] = slackUserID
+			}
+		}
+	}
+	return mapping
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Feature flags gating optional enrichments
+const (
+	FeatureReviews = "reviews" // PR reviews, for reviewers, review counts, turnaround and SLAs (GitHub)
+	FeatureChecks  = "checks"  // CI check status of PRs (GitHub)
+	FeatureDelta   = "delta"   // Changes since the previous report
+	FeatureTickets = "tickets" // Ticket status lookups in the tracker
+	FeatureEmails  = "emails"  // Mapping GitHub users missing from USER_MAPPING to Slack by email
+)
+
+// knownFeatures are the built-in feature flags, for warnings about typos
+var knownFeatures = []string{FeatureReviews, FeatureChecks, FeatureDelta, FeatureTickets, FeatureEmails}
+
+// Features holds the feature flags that are set, by name. Unset flags leave
+// their features to the individual settings.
+type Features map[string]bool
+
+// LoadFeatures reads the feature flags of a report from FEATURES (e.g.,
+// "reviews=true,checks=false,delta=true") and prefix+"FEATURES", whose flags
+// override the shared ones so a feature can roll out one team at a time
+func LoadFeatures(prefix string) Features {
+	features := make(Features)
+	for _, key := range []string{"FEATURES", prefix + "FEATURES"} {
+		for name, value := range Map(key) {
+			name = strings.ToLower(name)
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				slog.Warn("Ignoring feature flag, expected true or false", "key", key, "feature", name, "value", value)
+				continue
+			}
+			if !isKnownFeature(name) {
+				slog.Warn("Unknown feature flag", "key", key, "feature", name, "known", knownFeatures)
+			}
+			features[name] = enabled
+		}
+	}
+	return features
+}
+
+// Lookup returns whether a feature is on, and whether its flag is set
+func (f Features) Lookup(name string) (enabled, set bool) {
+	enabled, set = f[name]
+	return enabled, set
+}
+
+// Enabled reports whether a feature is on, or fallback when its flag isn't set
+func (f Features) Enabled(name string, fallback bool) bool {
+	if enabled, set := f[name]; set {
+		return enabled
+	}
+	return fallback
+}
+
+// isKnownFeature reports whether name is a built-in feature flag
+func isKnownFeature(name string) bool {
+	for _, known := range knownFeatures {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
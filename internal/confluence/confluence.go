@@ -0,0 +1,252 @@
+package confluence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/model"
+)
+
+// Page modes
+const (
+	ModeRolling = "rolling" // One page per report, updated on every run
+	ModeDaily   = "daily"   // A new page per report and day
+)
+
+// Options contains options for publishing a PR report to Confluence
+type Options struct {
+	URL      string // Confluence base URL (e.g., "https://your-org.atlassian.net/wiki")
+	Username string // Confluence username (for Basic auth)
+	APIToken string // Confluence API token or Personal Access Token
+	UsePAT   bool   // Use Personal Access Token instead of Basic auth
+	Space    string // Space key the page is published in
+	ParentID string // ID of the page the report pages are created under (optional)
+	Mode     string // ModeRolling (default) or ModeDaily
+}
+
+// Configured reports whether the options contain a Confluence space to publish to
+func (o Options) Configured() bool {
+	return o.URL != "" && o.APIToken != "" && o.Space != ""
+}
+
+// page is the part of a Confluence content object used here
+type page struct {
+	ID      string `json:"id"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+}
+
+// httpClient is used to call the Confluence API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Confluence"}).Client(30 * time.Second)
+
+// PublishReport creates or updates the Confluence page of the report
+func PublishReport(ctx context.Context, opts Options, report model.Report) error {
+	if !opts.Configured() {
+		return fmt.Errorf("Confluence URL, API token and space are required")
+	}
+
+	title := pageTitle(opts, report)
+	body := renderStorage(report)
+
+	existing, err := findPage(ctx, opts, title)
+	if err != nil {
+		return fmt.Errorf("error looking up Confluence page %q: %w", title, err)
+	}
+
+	content := map[string]interface{}{
+		"type":  "page",
+		"title": title,
+		"space": map[string]string{"key": opts.Space},
+		"body": map[string]interface{}{
+			"storage": map[string]string{"value": body, "representation": "storage"},
+		},
+	}
+
+	if existing == nil {
+		if opts.ParentID != "" {
+			content["ancestors"] = []map[string]string{{"id": opts.ParentID}}
+		}
+
+		var created page
+		if err := call(ctx, opts, http.MethodPost, "/rest/api/content", content, &created); err != nil {
+			return fmt.Errorf("error creating Confluence page %q: %w", title, err)
+		}
+		slog.Info("Created Confluence page", "title", title, "id", created.ID)
+		return nil
+	}
+
+	content["version"] = map[string]int{"number": existing.Version.Number + 1}
+	if err := call(ctx, opts, http.MethodPut, "/rest/api/content/"+existing.ID, content, nil); err != nil {
+		return fmt.Errorf("error updating Confluence page %q: %w", title, err)
+	}
+
+	slog.Debug("Updated Confluence page", "title", title, "id", existing.ID, "version", existing.Version.Number+1)
+
+	return nil
+}
+
+// pageTitle returns the page title, dated in daily mode. Titles are unique
+// within a space, so they identify the page to update.
+func pageTitle(opts Options, report model.Report) string {
+	title := report.Title
+	if title == "" {
+		title = report.Name + " PR Report"
+	}
+	if opts.Mode == ModeDaily {
+		title += " – " + report.Date.Format("2006-01-02")
+	}
+	return title
+}
+
+// findPage returns the page with the given title in the space, or nil
+func findPage(ctx context.Context, opts Options, title string) (*page, error) {
+	query := url.Values{
+		"spaceKey": {opts.Space},
+		"title":    {title},
+		"type":     {"page"},
+		"expand":   {"version"},
+	}
+
+	var result struct {
+		Results []page `json:"results"`
+	}
+	if err := call(ctx, opts, http.MethodGet, "/rest/api/content?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Results) == 0 {
+		return nil, nil
+	}
+
+	return &result.Results[0], nil
+}
+
+// call sends an authenticated request to the Confluence REST API and decodes
+// the response into result unless it is nil
+func call(ctx context.Context, opts Options, method, path string, payload, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(opts.URL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if opts.UsePAT {
+		req.Header.Set("Authorization", "Bearer "+opts.APIToken)
+	} else {
+		req.SetBasicAuth(opts.Username, opts.APIToken)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return httpx.NewStatusError("Confluence", resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// renderStorage renders the report in the Confluence storage format
+func renderStorage(report model.Report) string {
+	text := report.Text()
+	escape := html.EscapeString
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<p>📅 %s · <strong>%s: %d</strong></p>", report.Date.Format("2006-01-02 15:04"), escape(text.TotalOpenPRs), len(report.PRs))
+
+	b.WriteString("<table><tbody><tr><th>#</th><th>PR</th><th>Jira</th><th>Status</th><th>Assignee</th></tr>")
+	for i, pr := range report.PRs {
+		description := pr.Description
+		if description == "" {
+			description = text.NoDescription
+		}
+
+		jira := escape(text.None)
+		if pr.JiraTicket != "" {
+			jira = escape(pr.JiraTicket)
+			if ticketURL := report.TicketURL(pr); ticketURL != "" {
+				jira = fmt.Sprintf(`<a href="%s">%s</a>`, escape(ticketURL), jira)
+			}
+		}
+
+		status := pr.JiraStatus
+		if status == "" {
+			status = text.UnknownStatus
+		}
+		colour := "Grey"
+		if pr.IsBlocked {
+			colour = "Red"
+		}
+
+		assignee := pr.GithubAssignee
+		if assignee == "" {
+			assignee = text.Unassigned
+		}
+
+		flags := ""
+		if pr.IsBlocked {
+			flags += " 🚫"
+		}
+		if pr.IsDraft {
+			flags += " 📝"
+		}
+
+		fmt.Fprintf(&b, `<tr><td>%d</td><td><a href="%s">PR-%d</a> %s%s</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+			i+1, escape(report.PRURL(pr)), pr.Number, escape(description), flags, jira, statusMacro(status, colour), escape(assignee))
+	}
+	b.WriteString("</tbody></table>")
+
+	links := func(prs []*model.PR) string {
+		var result []string
+		for _, pr := range prs {
+			result = append(result, fmt.Sprintf(`<a href="%s">PR-%d</a>`, escape(report.PRURL(pr)), pr.Number))
+		}
+		return strings.Join(result, ", ")
+	}
+
+	blocked, drafts := report.Blocked(), report.Drafts()
+	if len(blocked) > 0 {
+		fmt.Fprintf(&b, "<p>🚫 <strong>%s:</strong> %s</p>", escape(text.Blocked), links(blocked))
+	}
+	if len(drafts) > 0 {
+		fmt.Fprintf(&b, "<p>📝 <strong>%s:</strong> %s</p>", escape(text.Draft), links(drafts))
+	}
+	if len(blocked) == 0 && len(drafts) == 0 {
+		fmt.Fprintf(&b, "<p>✅ <strong>%s:</strong> %s</p>", escape(text.BlockedOrDraft), escape(text.None))
+	}
+
+	return b.String()
+}
+
+// statusMacro renders a JIRA status as a Confluence status lozenge
+func statusMacro(status, colour string) string {
+	return fmt.Sprintf(`<ac:structured-macro ac:name="status"><ac:parameter ac:name="colour">%s</ac:parameter><ac:parameter ac:name="title">%s</ac:parameter></ac:structured-macro>`,
+		colour, html.EscapeString(status))
+}
@@ -0,0 +1,131 @@
+package datadog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"pr-reporter/internal/httpx"
+)
+
+// DefaultSite is the Datadog site used when none is configured
+const DefaultSite = "datadoghq.com"
+
+// Alert types of events
+const (
+	AlertSuccess = "success"
+	AlertError   = "error"
+)
+
+// Options contains options for sending events and metrics to Datadog
+type Options struct {
+	APIKey string   // Datadog API key
+	Site   string   // Datadog site (default: datadoghq.com; e.g., "datadoghq.eu", "us5.datadoghq.com")
+	Tags   []string // Tags added to every event and metric (e.g., "team:web")
+}
+
+// Event is a Datadog event
+type Event struct {
+	Title          string   `json:"title"`
+	Text           string   `json:"text"`
+	AlertType      string   `json:"alert_type,omitempty"`
+	AggregationKey string   `json:"aggregation_key,omitempty"`
+	SourceTypeName string   `json:"source_type_name,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+}
+
+// Metric is a gauge sent to Datadog
+type Metric struct {
+	Name  string
+	Value float64
+	Tags  []string
+}
+
+// httpClient is used to call the Datadog API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Datadog"}).Client(30 * time.Second)
+
+// SendEvent posts an event with the configured tags added
+func SendEvent(opts Options, event Event) error {
+	if opts.APIKey == "" {
+		return fmt.Errorf("Datadog API key is required")
+	}
+
+	event.Tags = append(append([]string{}, opts.Tags...), event.Tags...)
+	if err := post(opts, "/api/v1/events", event); err != nil {
+		return fmt.Errorf("error sending Datadog event: %w", err)
+	}
+
+	slog.Debug("Sent Datadog event", "title", event.Title)
+
+	return nil
+}
+
+// SendMetrics submits gauges with the current time and the configured tags added
+func SendMetrics(opts Options, metrics []Metric) error {
+	if opts.APIKey == "" {
+		return fmt.Errorf("Datadog API key is required")
+	}
+
+	type series struct {
+		Metric string       `json:"metric"`
+		Points [][2]float64 `json:"points"`
+		Type   string       `json:"type"`
+		Tags   []string     `json:"tags,omitempty"`
+	}
+
+	now := float64(time.Now().Unix())
+	var payload struct {
+		Series []series `json:"series"`
+	}
+	for _, metric := range metrics {
+		payload.Series = append(payload.Series, series{
+			Metric: metric.Name,
+			Points: [][2]float64{{now, metric.Value}},
+			Type:   "gauge",
+			Tags:   append(append([]string{}, opts.Tags...), metric.Tags...),
+		})
+	}
+
+	if err := post(opts, "/api/v1/series", payload); err != nil {
+		return fmt.Errorf("error sending Datadog metrics: %w", err)
+	}
+
+	slog.Debug("Sent Datadog metrics", "metrics", len(metrics))
+
+	return nil
+}
+
+// post sends a JSON payload to a Datadog API endpoint
+func post(opts Options, path string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	site := opts.Site
+	if site == "" {
+		site = DefaultSite
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "https://api."+site+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", opts.APIKey)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return httpx.NewStatusError("Datadog", resp)
+	}
+
+	return nil
+}
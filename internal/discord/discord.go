@@ -0,0 +1,259 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/model"
+)
+
+// Options contains options for sending a PR report to Discord. Either a
+// webhook URL or a bot token with a channel ID is required.
+type Options struct {
+	WebhookURL string // Discord channel webhook URL
+	BotToken   string // Bot token, used when no webhook URL is set
+	ChannelID  string // Channel the bot posts to
+}
+
+// Configured reports whether the options contain a Discord destination
+func (o Options) Configured() bool {
+	return o.WebhookURL != "" || (o.BotToken != "" && o.ChannelID != "")
+}
+
+// apiURL is the Discord REST API base URL used by bots
+const apiURL = "https://discord.com/api/v10"
+
+// Discord message limits
+const (
+	maxEmbedsPerMessage = 10
+	maxTitleLength      = 256
+)
+
+// Embed colors
+const (
+	colorOpen    = 0x2EB67D // Green
+	colorBlocked = 0xE01E5A // Red
+	colorDraft   = 0x95A5A6 // Grey
+)
+
+// message is a Discord message payload
+type message struct {
+	Content         string          `json:"content,omitempty"`
+	Embeds          []embed         `json:"embeds,omitempty"`
+	AllowedMentions allowedMentions `json:"allowed_mentions"`
+}
+
+// allowedMentions controls who a message may ping
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// embed is a Discord rich embed
+type embed struct {
+	Title       string       `json:"title,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Color       int          `json:"color,omitempty"`
+	Fields      []embedField `json:"fields,omitempty"`
+}
+
+// embedField is a name/value pair shown in an embed
+type embedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// httpClient is used to post to Discord
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Discord"}).Client(30 * time.Second)
+
+// SendReport posts the report to a Discord channel with one embed per PR.
+// Discord allows ten embeds per message, so longer reports are split into
+// several messages.
+func SendReport(ctx context.Context, opts Options, report model.Report) error {
+	if !opts.Configured() {
+		return fmt.Errorf("Discord webhook URL or bot token and channel ID are required")
+	}
+
+	messages := buildMessages(report)
+
+	for i, msg := range messages {
+		if err := post(ctx, opts, msg); err != nil {
+			return fmt.Errorf("error posting message %d/%d to Discord: %w", i+1, len(messages), err)
+		}
+
+		slog.Debug("Sent Discord message", "part", i+1, "parts", len(messages), "embeds", len(msg.Embeds))
+	}
+
+	return nil
+}
+
+// buildMessages lays the report out as Discord messages: the header goes in
+// the first message, the blocked and draft summary in the last one
+func buildMessages(report model.Report) []message {
+	text := report.Text()
+
+	var header []string
+	if report.Title != "" {
+		header = append(header, "📋 **"+report.Title+"**")
+	}
+	header = append(header,
+		"📅 "+report.Date.Format("2006-01-02"),
+		fmt.Sprintf("📊 **%s: %d**", text.TotalOpenPRs, len(report.PRs)),
+	)
+
+	var embeds []embed
+	for i, pr := range report.PRs {
+		embeds = append(embeds, prEmbed(report, text, i+1, pr))
+	}
+
+	var messages []message
+	for start := 0; start < len(embeds); start += maxEmbedsPerMessage {
+		end := start + maxEmbedsPerMessage
+		if end > len(embeds) {
+			end = len(embeds)
+		}
+		messages = append(messages, message{Embeds: embeds[start:end]})
+	}
+
+	if len(messages) == 0 {
+		messages = append(messages, message{})
+	}
+	messages[0].Content = strings.Join(header, "\n")
+	messages = append(messages, message{Content: summary(report, text)})
+
+	// Never ping anyone, assignees are GitHub usernames or Slack mentions
+	for i := range messages {
+		messages[i].AllowedMentions = allowedMentions{Parse: []string{}}
+	}
+
+	return messages
+}
+
+// prEmbed formats a single PR as an embed linking to the PR
+func prEmbed(report model.Report, text model.Strings, index int, pr *model.PR) embed {
+	description := pr.Description
+	if description == "" {
+		description = text.NoDescription
+	}
+
+	jira := text.None
+	if pr.JiraTicket != "" {
+		jira = pr.JiraTicket
+		if url := report.TicketURL(pr); url != "" {
+			jira = fmt.Sprintf("[%s](%s)", pr.JiraTicket, url)
+		}
+	}
+
+	status := pr.JiraStatus
+	if status == "" {
+		status = text.UnknownStatus
+	}
+
+	assignee := pr.GithubAssignee
+	if assignee == "" {
+		assignee = text.Unassigned
+	}
+
+	color := colorOpen
+	var flags []string
+	if pr.IsBlocked {
+		color = colorBlocked
+		flags = append(flags, "🚫 "+text.ReasonBlocked)
+	}
+	if pr.IsDraft {
+		if !pr.IsBlocked {
+			color = colorDraft
+		}
+		flags = append(flags, "📝 "+text.Draft)
+	}
+
+	return embed{
+		Title:       truncate(fmt.Sprintf("%d. PR-%d: %s", index, pr.Number, description), maxTitleLength),
+		URL:         report.PRURL(pr),
+		Description: strings.Join(flags, " · "),
+		Color:       color,
+		Fields: []embedField{
+			{Name: "Jira", Value: jira, Inline: true},
+			{Name: "Status", Value: status, Inline: true},
+			{Name: "Assignee", Value: assignee, Inline: true},
+		},
+	}
+}
+
+// summary lists blocked and draft PRs at the end of the report
+func summary(report model.Report, text model.Strings) string {
+	links := func(prs []*model.PR) string {
+		var result []string
+		for _, pr := range prs {
+			result = append(result, fmt.Sprintf("[PR-%d](<%s>)", pr.Number, report.PRURL(pr)))
+		}
+		return strings.Join(result, ", ")
+	}
+
+	var lines []string
+	blocked, drafts := report.Blocked(), report.Drafts()
+	if len(blocked) > 0 {
+		lines = append(lines, fmt.Sprintf("🚫 **%s:** %s", text.Blocked, links(blocked)))
+	}
+	if len(drafts) > 0 {
+		lines = append(lines, fmt.Sprintf("📝 **%s:** %s", text.Draft, links(drafts)))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, fmt.Sprintf("✅ **%s:** %s", text.BlockedOrDraft, text.None))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// post sends a message through the webhook or as the bot. httpClient waits
+// and retries when Discord rate limits the request.
+func post(ctx context.Context, opts Options, msg message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	url := opts.WebhookURL
+	if url == "" {
+		url = fmt.Sprintf("%s/channels/%s/messages", apiURL, opts.ChannelID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if opts.WebhookURL == "" {
+		req.Header.Set("Authorization", "Bot "+opts.BotToken)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Webhooks answer 204 No Content, bots 200 with the created message
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return httpx.NewStatusError("Discord", resp)
+	}
+
+	return nil
+}
+
+// truncate shortens s to at most max characters
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
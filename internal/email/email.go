@@ -0,0 +1,328 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/model"
+)
+
+// Options contains options for emailing a PR report
+type Options struct {
+	Host     string   // SMTP server host
+	Port     string   // SMTP server port (default: 587; 465 uses implicit TLS)
+	Username string   // SMTP username (optional)
+	Password string   // SMTP password
+	From     string   // Sender address
+	To       []string // Recipient addresses, e.g. a distribution list
+}
+
+// Configured reports whether the options contain an SMTP server and recipients
+func (o Options) Configured() bool {
+	return o.Host != "" && o.From != "" && len(o.To) > 0
+}
+
+// DefaultPort is the SMTP submission port used when none is configured
+const DefaultPort = "587"
+
+// reportTemplate renders the report as an HTML email. Styles are inline since
+// most mail clients ignore style sheets.
+var reportTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, 'Segoe UI', Helvetica, Arial, sans-serif; color: #1d1c1d; font-size: 14px;">
+{{if .Report.Title}}<h2 style="margin-bottom: 4px;">📋 {{.Report.Title}}</h2>{{end}}
+<p style="color: #616061; margin-top: 0;">📅 {{.Date}}</p>
+<p><strong>📊 {{.Text.TotalOpenPRs}}: {{len .Report.PRs}}</strong></p>
+{{if .Report.PRs}}<table cellpadding="6" cellspacing="0" style="border-collapse: collapse; width: 100%;">
+<tr style="background: #f4f4f4; text-align: left;">
+<th>#</th><th>PR</th><th>Jira</th><th>Status</th><th>Assignee</th>
+</tr>
+{{range $i, $pr := .PRs}}<tr style="border-top: 1px solid #ddd;{{if $pr.Blocked}} background: #fdecef;{{else if $pr.Draft}} color: #616061;{{end}}">
+<td>{{$pr.Index}}</td>
+<td><a href="{{$pr.URL}}">PR-{{$pr.Number}}</a> {{$pr.Description}}{{if $pr.Blocked}} 🚫{{end}}{{if $pr.Draft}} 📝{{end}}</td>
+<td>{{if $pr.JiraURL}}<a href="{{$pr.JiraURL}}">{{$pr.JiraTicket}}</a>{{else}}{{$pr.JiraTicket}}{{end}}</td>
+<td><strong>{{$pr.Status}}</strong></td>
+<td>{{$pr.Assignee}}</td>
+</tr>
+{{end}}</table>{{end}}
+<p>
+{{if .Blocked}}🚫 <strong>{{.Text.Blocked}}:</strong> {{range $i, $pr := .Blocked}}{{if $i}}, {{end}}<a href="{{$pr.URL}}">PR-{{$pr.Number}}</a>{{end}}<br>{{end}}
+{{if .Drafts}}📝 <strong>{{.Text.Draft}}:</strong> {{range $i, $pr := .Drafts}}{{if $i}}, {{end}}<a href="{{$pr.URL}}">PR-{{$pr.Number}}</a>{{end}}<br>{{end}}
+{{if not (or .Blocked .Drafts)}}✅ <strong>{{.Text.BlockedOrDraft}}:</strong> {{.Text.None}}{{end}}
+</p>
+</body>
+</html>
+`))
+
+// templateData is the data passed to reportTemplate
+type templateData struct {
+	Report  model.Report
+	Text    model.Strings
+	Date    string
+	PRs     []templatePR
+	Blocked []templatePR
+	Drafts  []templatePR
+}
+
+// templatePR is a PR with its links and placeholders resolved
+type templatePR struct {
+	Index       int
+	Number      int
+	URL         string
+	Description string
+	JiraTicket  string
+	JiraURL     string
+	Status      string
+	Assignee    string
+	Blocked     bool
+	Draft       bool
+}
+
+// SendReport emails the report as HTML, with a plain text alternative, to
+// every recipient
+func SendReport(ctx context.Context, opts Options, report model.Report) error {
+	if !opts.Configured() {
+		return fmt.Errorf("SMTP host, sender and recipients are required")
+	}
+
+	message, err := buildMessage(opts, report)
+	if err != nil {
+		return err
+	}
+
+	port := opts.Port
+	if port == "" {
+		port = DefaultPort
+	}
+
+	if err := send(ctx, opts, port, message); err != nil {
+		return fmt.Errorf("error sending email via %s:%s: %w", opts.Host, port, err)
+	}
+
+	slog.Debug("Emailed report", "recipients", len(opts.To), "server", opts.Host+":"+port)
+
+	return nil
+}
+
+// renderHTML renders the report as an HTML email body
+func renderHTML(report model.Report) (string, error) {
+	text := report.Text()
+	data := templateData{
+		Report: report,
+		Text:   text,
+		Date:   report.Date.Format("2006-01-02"),
+	}
+
+	for i, pr := range report.PRs {
+		tpr := newTemplatePR(report, text, i+1, pr)
+		data.PRs = append(data.PRs, tpr)
+		if tpr.Blocked {
+			data.Blocked = append(data.Blocked, tpr)
+		} else if tpr.Draft {
+			data.Drafts = append(data.Drafts, tpr)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := reportTemplate.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("error rendering email: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// newTemplatePR resolves the links and placeholders of a PR
+func newTemplatePR(report model.Report, text model.Strings, index int, pr *model.PR) templatePR {
+	tpr := templatePR{
+		Index:       index,
+		Number:      pr.Number,
+		URL:         report.PRURL(pr),
+		Description: pr.Description,
+		JiraTicket:  pr.JiraTicket,
+		JiraURL:     report.TicketURL(pr),
+		Status:      pr.JiraStatus,
+		Assignee:    pr.GithubAssignee,
+		Blocked:     pr.IsBlocked,
+		Draft:       pr.IsDraft,
+	}
+
+	if tpr.Description == "" {
+		tpr.Description = text.NoDescription
+	}
+	if tpr.JiraTicket == "" {
+		tpr.JiraTicket = text.None
+	}
+	if tpr.Status == "" {
+		tpr.Status = text.UnknownStatus
+	}
+	if tpr.Assignee == "" {
+		tpr.Assignee = text.Unassigned
+	}
+
+	return tpr
+}
+
+// renderText renders the report as plain text for mail clients without HTML
+func renderText(report model.Report) string {
+	text := report.Text()
+
+	var b strings.Builder
+	if report.Title != "" {
+		fmt.Fprintf(&b, "%s\n", report.Title)
+	}
+	fmt.Fprintf(&b, "%s\n\n", report.Date.Format("2006-01-02"))
+	fmt.Fprintf(&b, "%s: %d\n\n", text.TotalOpenPRs, len(report.PRs))
+
+	for i, pr := range report.PRs {
+		tpr := newTemplatePR(report, text, i+1, pr)
+		fmt.Fprintf(&b, "%d. PR-%d %s | %s | %s | %s\n   %s\n", tpr.Index, tpr.Number, tpr.Description, tpr.JiraTicket, tpr.Status, tpr.Assignee, tpr.URL)
+	}
+
+	return b.String()
+}
+
+// buildMessage builds the MIME message with HTML and plain text parts
+func buildMessage(opts Options, report model.Report) ([]byte, error) {
+	htmlBody, err := renderHTML(report)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := report.Title
+	if subject == "" {
+		subject = "PR Report"
+	}
+	subject += " – " + report.Date.Format("2006-01-02")
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", renderText(report)},
+		{"text/html; charset=UTF-8", htmlBody},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", opts.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(opts.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", writer.Boundary())
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// send delivers the message. Port 465 uses implicit TLS; other ports upgrade
+// with STARTTLS when the server supports it. The SMTP client has no context
+// of its own, so ctx bounds the connection instead: its deadline applies to
+// the whole exchange and canceling it closes the connection.
+func send(ctx context.Context, opts Options, port string, message []byte) error {
+	addr := net.JoinHostPort(opts.Host, port)
+	tlsConfig := &tls.Config{ServerName: opts.Host}
+
+	var conn net.Conn
+	var err error
+	if port == "465" {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := converse(conn, opts, port, tlsConfig, message); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// converse sends the message over an open connection to the SMTP server
+func converse(conn net.Conn, opts Options, port string, tlsConfig *tls.Config, message []byte) error {
+	client, err := smtp.NewClient(conn, opts.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if port != "465" {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.Username != "" {
+		auth := smtp.PlainAuth("", opts.Username, opts.Password, opts.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(opts.From); err != nil {
+		return err
+	}
+	for _, to := range opts.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("recipient %s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
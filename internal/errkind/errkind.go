@@ -0,0 +1,86 @@
+package errkind
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes of the commands, by error category (see ExitCode). Automation
+// wrapping the commands can rely on them.
+const (
+	ExitOK       = 0 // The report was sent, or there was nothing to do
+	ExitFailure  = 1 // Any error without a category
+	ExitConfig   = 2 // A setting or argument is missing or invalid
+	ExitAuth     = 3 // An API rejected the configured credentials
+	ExitFetch    = 4 // The PRs could not be fetched
+	ExitDelivery = 5 // The report could not be delivered to every output
+)
+
+// Error categories, for errors.Is
+var (
+	ErrConfig   = errors.New("configuration error")
+	ErrAuth     = errors.New("authentication error")
+	ErrFetch    = errors.New("fetch error")
+	ErrDelivery = errors.New("delivery error")
+)
+
+// ConfigError is a missing or invalid setting or argument
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string        { return e.Err.Error() }
+func (e *ConfigError) Unwrap() error        { return e.Err }
+func (e *ConfigError) Is(target error) bool { return target == ErrConfig }
+
+// Configf returns a ConfigError with a formatted message
+func Configf(format string, args ...any) error {
+	return &ConfigError{Err: fmt.Errorf(format, args...)}
+}
+
+// AuthError is an API rejecting the configured credentials, such as an
+// expired token or one missing a scope
+type AuthError struct {
+	API string // API that rejected the credentials (e.g., "GitHub")
+	Err error
+}
+
+func (e *AuthError) Error() string        { return e.Err.Error() }
+func (e *AuthError) Unwrap() error        { return e.Err }
+func (e *AuthError) Is(target error) bool { return target == ErrAuth }
+
+// FetchError is a failure to fetch the PRs of a report
+type FetchError struct {
+	Err error
+}
+
+func (e *FetchError) Error() string        { return e.Err.Error() }
+func (e *FetchError) Unwrap() error        { return e.Err }
+func (e *FetchError) Is(target error) bool { return target == ErrFetch }
+
+// DeliveryError is a failure to deliver a report to one or more outputs
+type DeliveryError struct {
+	Err error
+}
+
+func (e *DeliveryError) Error() string        { return e.Err.Error() }
+func (e *DeliveryError) Unwrap() error        { return e.Err }
+func (e *DeliveryError) Is(target error) bool { return target == ErrDelivery }
+
+// ExitCode returns the exit code for err. Rejected credentials take
+// precedence over the fetch or delivery they made fail.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrConfig):
+		return ExitConfig
+	case errors.Is(err, ErrAuth):
+		return ExitAuth
+	case errors.Is(err, ErrFetch):
+		return ExitFetch
+	case errors.Is(err, ErrDelivery):
+		return ExitDelivery
+	}
+	return ExitFailure
+}
@@ -0,0 +1,196 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/model"
+)
+
+// Export formats of the PR dataset
+const (
+	CSV      = "csv"
+	JSON     = "json"
+	Markdown = "md"
+)
+
+// Formats lists the supported export formats
+func Formats() []string {
+	return []string{CSV, JSON, Markdown}
+}
+
+// IsSupported reports whether format is a supported export format
+func IsSupported(format string) bool {
+	for _, supported := range Formats() {
+		if format == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// row is a single PR of the exported dataset
+type row struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author"`
+	Assignee    string    `json:"assignee"`
+	JiraTicket  string    `json:"jira_ticket"`
+	JiraStatus  string    `json:"jira_status"`
+	Description string    `json:"description"`
+	IsDraft     bool      `json:"is_draft"`
+	IsBlocked   bool      `json:"is_blocked"`
+	Labels      []string  `json:"labels"`
+	Reviewers   []string  `json:"reviewers"`
+	ChecksState string    `json:"checks_state"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Filename returns the default file name of a report export
+func Filename(report model.Report, format string) string {
+	return fmt.Sprintf("%s-prs-%s.%s", report.GithubRepo, report.Date.Format("2006-01-02"), format)
+}
+
+// Encode encodes the full PR dataset of a report in the given format
+func Encode(report model.Report, format string) ([]byte, error) {
+	rows := make([]row, len(report.PRs))
+	for i, pr := range report.PRs {
+		rows[i] = row{
+			Number:      pr.Number,
+			Title:       pr.Title,
+			URL:         report.PRURL(pr),
+			Author:      pr.Author,
+			Assignee:    pr.GithubAssignee,
+			JiraTicket:  pr.JiraTicket,
+			JiraStatus:  pr.JiraStatus,
+			Description: pr.Description,
+			IsDraft:     pr.IsDraft,
+			IsBlocked:   pr.IsBlocked,
+			Labels:      pr.Labels,
+			Reviewers:   pr.Reviewers,
+			ChecksState: pr.ChecksState,
+			CreatedAt:   pr.CreatedAt,
+			UpdatedAt:   pr.UpdatedAt,
+		}
+	}
+
+	switch format {
+	case JSON:
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("error encoding JSON export: %w", err)
+		}
+		return data, nil
+
+	case CSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.Write([]string{"number", "title", "url", "author", "assignee", "jira_ticket", "jira_status", "description",
+			"is_draft", "is_blocked", "labels", "reviewers", "checks_state", "created_at", "updated_at"})
+		for _, row := range rows {
+			w.Write([]string{
+				strconv.Itoa(row.Number),
+				row.Title,
+				row.URL,
+				row.Author,
+				row.Assignee,
+				row.JiraTicket,
+				row.JiraStatus,
+				row.Description,
+				strconv.FormatBool(row.IsDraft),
+				strconv.FormatBool(row.IsBlocked),
+				strings.Join(row.Labels, "; "),
+				strings.Join(row.Reviewers, "; "),
+				row.ChecksState,
+				formatTime(row.CreatedAt),
+				formatTime(row.UpdatedAt),
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, fmt.Errorf("error encoding CSV export: %w", err)
+		}
+		return buf.Bytes(), nil
+
+	case Markdown:
+		return encodeMarkdown(report, rows), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported export format %q (supported: %s)", format, strings.Join(Formats(), ", "))
+	}
+}
+
+// encodeMarkdown formats the dataset as a Markdown table
+func encodeMarkdown(report model.Report, rows []row) []byte {
+	var b bytes.Buffer
+
+	title := report.Title
+	if title == "" {
+		title = "Open PRs"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "%s/%s, %s: %d open PR(s)\n\n", report.GithubOwner, report.GithubRepo, report.Date.Format("2006-01-02"), len(rows))
+
+	b.WriteString("| PR | Title | Author | Assignee | JIRA | Status | Draft | Blocked | Labels | Checks | Created | Updated |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|---|---|---|---|\n")
+	for i, row := range rows {
+		jira := row.JiraTicket
+		if url := report.TicketURL(report.PRs[i]); url != "" {
+			jira = fmt.Sprintf("[%s](%s)", row.JiraTicket, url)
+		}
+		fmt.Fprintf(&b, "| [#%d](%s) | %s | %s | %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			row.Number,
+			row.URL,
+			markdownCell(row.Title),
+			markdownCell(row.Author),
+			markdownCell(row.Assignee),
+			jira,
+			markdownCell(row.JiraStatus),
+			yesNo(row.IsDraft),
+			yesNo(row.IsBlocked),
+			markdownCell(strings.Join(row.Labels, ", ")),
+			row.ChecksState,
+			formatDate(row.CreatedAt),
+			formatDate(row.UpdatedAt),
+		)
+	}
+
+	return b.Bytes()
+}
+
+// markdownCell escapes text for use in a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// yesNo formats a flag for the Markdown table
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return ""
+}
+
+// formatTime formats a timestamp for the CSV export, leaving unknown times empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatDate formats a date for the Markdown table, leaving unknown dates empty
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,166 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/rules"
+)
+
+// PR is what filters look at, taken from a PR of any source
+type PR struct {
+	Number    int
+	Title     string
+	Author    string
+	Assignee  string
+	Base      string // Branch the PR targets
+	Head      string // Branch the PR is opened from
+	Labels    []string
+	Reviewers []string // Requested reviewers that haven't reviewed yet
+	Draft     bool
+	Closed    bool // Closed or merged, for merge and review stats
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Filter decides whether a PR is included in a report. It returns why the PR
+// is left out, or "" to include it.
+type Filter func(pr PR) string
+
+// Options holds the filters of a report. Every source applies them, so they
+// are embedded in the fetch options of each source.
+type Options struct {
+	Labels       []string       // Labels to filter by (if empty, fetch all open PRs)
+	AllowedUsers []string       // Users whose PRs to include
+	BaseBranches []string       // Base branches PRs must target (if empty, any branch)
+	MaxAge       time.Duration  // Leave out open PRs opened longer ago than this (0: no limit)
+	TitlePattern *regexp.Regexp // Only include PRs whose title matches (nil: any title)
+	Rule         *rules.Rule    // Only include PRs satisfying this expression (nil: any PR)
+	Filters      []Filter       `json:"-"` // Additional filters applied after the ones above
+}
+
+// Chain assembles the filters configured in opts: author, label, base branch,
+// age, title pattern and rule, followed by opts.Filters. Criteria left unset
+// don't filter anything.
+func (opts Options) Chain() []Filter {
+	var filters []Filter
+	if len(opts.AllowedUsers) > 0 {
+		filters = append(filters, AuthorFilter(opts.AllowedUsers))
+	}
+	if len(opts.Labels) > 0 {
+		filters = append(filters, LabelFilter(opts.Labels))
+	}
+	if len(opts.BaseBranches) > 0 {
+		filters = append(filters, BaseBranchFilter(opts.BaseBranches))
+	}
+	if opts.MaxAge > 0 {
+		filters = append(filters, MaxAgeFilter(opts.MaxAge))
+	}
+	if opts.TitlePattern != nil {
+		filters = append(filters, TitleFilter(opts.TitlePattern))
+	}
+	if opts.Rule != nil {
+		filters = append(filters, RuleFilter(opts.Rule))
+	}
+	return append(filters, opts.Filters...)
+}
+
+// Apply returns why the first filter rejecting the PR left it out, or "" when
+// every filter includes it
+func Apply(filters []Filter, pr PR) string {
+	for _, filter := range filters {
+		if reason := filter(pr); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+// AuthorFilter includes the PRs opened by one of users (case-insensitive)
+func AuthorFilter(users []string) Filter {
+	return func(pr PR) string {
+		for _, user := range users {
+			if user = strings.TrimSpace(user); user != "" && strings.EqualFold(user, pr.Author) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("author %s isn't one of the allowed users", pr.Author)
+	}
+}
+
+// LabelFilter includes the PRs with a label containing one of labels
+// (case-insensitive partial match)
+func LabelFilter(labels []string) Filter {
+	return func(pr PR) string {
+		for _, name := range pr.Labels {
+			for _, label := range labels {
+				if strings.Contains(strings.ToLower(name), strings.ToLower(label)) {
+					return ""
+				}
+			}
+		}
+		return fmt.Sprintf("no label matches %s", strings.Join(labels, ", "))
+	}
+}
+
+// BaseBranchFilter includes the PRs targeting one of branches
+func BaseBranchFilter(branches []string) Filter {
+	return func(pr PR) string {
+		for _, branch := range branches {
+			if branch == pr.Base {
+				return ""
+			}
+		}
+		return fmt.Sprintf("base branch %q isn't one of %s", pr.Base, strings.Join(branches, ", "))
+	}
+}
+
+// MaxAgeFilter leaves out the open PRs opened more than maxAge ago. Closed
+// and merged PRs are kept, so merge stats still count them.
+func MaxAgeFilter(maxAge time.Duration) Filter {
+	return func(pr PR) string {
+		if pr.Closed || pr.CreatedAt.IsZero() || time.Since(pr.CreatedAt) <= maxAge {
+			return ""
+		}
+		return fmt.Sprintf("opened more than %s ago", maxAge)
+	}
+}
+
+// TitleFilter includes the PRs whose title matches pattern
+func TitleFilter(pattern *regexp.Regexp) Filter {
+	return func(pr PR) string {
+		if pattern.MatchString(pr.Title) {
+			return ""
+		}
+		return fmt.Sprintf("title doesn't match %s", pattern)
+	}
+}
+
+// RuleFilter includes the PRs satisfying rule. PRs the rule can't be
+// evaluated for are left out.
+func RuleFilter(rule *rules.Rule) Filter {
+	return func(pr PR) string {
+		matched, err := rule.Match(rules.PR{
+			Number:    pr.Number,
+			Title:     pr.Title,
+			Author:    pr.Author,
+			Assignee:  pr.Assignee,
+			Base:      pr.Base,
+			Head:      pr.Head,
+			Labels:    pr.Labels,
+			Reviewers: pr.Reviewers,
+			Draft:     pr.Draft,
+			CreatedAt: pr.CreatedAt,
+			UpdatedAt: pr.UpdatedAt,
+		})
+		switch {
+		case err != nil:
+			return err.Error()
+		case !matched:
+			return fmt.Sprintf("doesn't satisfy rule %s", rule)
+		}
+		return ""
+	}
+}
@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"pr-reporter/internal/rules"
+)
+
+func TestFilters(t *testing.T) {
+	rule, err := rules.Compile(`has(labels, "Poker") && !draft`)
+	if err != nil {
+		t.Fatalf("compiling rule: %v", err)
+	}
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		pr     PR
+		want   bool // Whether the PR is included
+	}{
+		{name: "author allowed", filter: AuthorFilter([]string{"alice", "bob"}), pr: PR{Author: "bob"}, want: true},
+		{name: "author case-insensitive", filter: AuthorFilter([]string{" Alice "}), pr: PR{Author: "alice"}, want: true},
+		{name: "author not allowed", filter: AuthorFilter([]string{"alice"}), pr: PR{Author: "mallory"}},
+		{name: "author blank users", filter: AuthorFilter([]string{"", " "}), pr: PR{Author: ""}},
+
+		{name: "label partial match", filter: LabelFilter([]string{"poker"}), pr: PR{Labels: []string{"bug", "Poker Team"}}, want: true},
+		{name: "label any of", filter: LabelFilter([]string{"casino", "bug"}), pr: PR{Labels: []string{"BUG"}}, want: true},
+		{name: "label no match", filter: LabelFilter([]string{"poker"}), pr: PR{Labels: []string{"bug"}}},
+		{name: "label none on PR", filter: LabelFilter([]string{"poker"}), pr: PR{}},
+
+		{name: "base branch listed", filter: BaseBranchFilter([]string{"main", "release"}), pr: PR{Base: "release"}, want: true},
+		{name: "base branch exact match", filter: BaseBranchFilter([]string{"main"}), pr: PR{Base: "Main"}},
+		{name: "base branch not listed", filter: BaseBranchFilter([]string{"main"}), pr: PR{Base: "feature/x"}},
+
+		{name: "max age recent", filter: MaxAgeFilter(7 * 24 * time.Hour), pr: PR{CreatedAt: recent}, want: true},
+		{name: "max age too old", filter: MaxAgeFilter(7 * 24 * time.Hour), pr: PR{CreatedAt: old}},
+		{name: "max age keeps closed PRs", filter: MaxAgeFilter(7 * 24 * time.Hour), pr: PR{CreatedAt: old, Closed: true}, want: true},
+		{name: "max age unknown creation", filter: MaxAgeFilter(7 * 24 * time.Hour), pr: PR{}, want: true},
+
+		{name: "title matches", filter: TitleFilter(regexp.MustCompile(`^\[POKER-\d+\]`)), pr: PR{Title: "[POKER-12] Fix chips"}, want: true},
+		{name: "title doesn't match", filter: TitleFilter(regexp.MustCompile(`^\[POKER-\d+\]`)), pr: PR{Title: "Fix chips"}},
+
+		{name: "rule satisfied", filter: RuleFilter(rule), pr: PR{Labels: []string{"Poker"}}, want: true},
+		{name: "rule not satisfied", filter: RuleFilter(rule), pr: PR{Labels: []string{"Poker"}, Draft: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := tt.filter(tt.pr)
+			if got := reason == ""; got != tt.want {
+				t.Errorf("included = %v (reason %q), want %v", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	rule, err := rules.Compile(`!draft`)
+	if err != nil {
+		t.Fatalf("compiling rule: %v", err)
+	}
+	noHotfix := func(pr PR) string {
+		if pr.Head == "hotfix" {
+			return "hotfix branch"
+		}
+		return ""
+	}
+	opts := Options{
+		Labels:       []string{"poker"},
+		AllowedUsers: []string{"alice"},
+		BaseBranches: []string{"main"},
+		MaxAge:       7 * 24 * time.Hour,
+		TitlePattern: regexp.MustCompile(`POKER`),
+		Rule:         rule,
+		Filters:      []Filter{noHotfix},
+	}
+	included := PR{Author: "alice", Labels: []string{"Poker"}, Base: "main", Title: "POKER-1", CreatedAt: time.Now()}
+
+	tests := []struct {
+		name   string
+		opts   Options
+		modify func(pr *PR)
+		want   string // Reason the PR is left out, "" to include it
+	}{
+		{name: "nothing set", opts: Options{}, modify: func(pr *PR) { *pr = PR{Draft: true} }},
+		{name: "every filter passes", opts: opts},
+		{name: "author first", opts: opts, modify: func(pr *PR) { pr.Author, pr.Labels = "bob", nil }, want: "author bob isn't one of the allowed users"},
+		{name: "label", opts: opts, modify: func(pr *PR) { pr.Labels = nil }, want: "no label matches poker"},
+		{name: "base branch", opts: opts, modify: func(pr *PR) { pr.Base = "dev" }, want: `base branch "dev" isn't one of main`},
+		{name: "max age", opts: opts, modify: func(pr *PR) { pr.CreatedAt = time.Now().Add(-30 * 24 * time.Hour) }, want: "opened more than 168h0m0s ago"},
+		{name: "title", opts: opts, modify: func(pr *PR) { pr.Title = "Fix" }, want: "title doesn't match POKER"},
+		{name: "rule", opts: opts, modify: func(pr *PR) { pr.Draft = true }, want: "doesn't satisfy rule !draft"},
+		{name: "additional filters last", opts: opts, modify: func(pr *PR) { pr.Head = "hotfix" }, want: "hotfix branch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := included
+			pr.Labels = append([]string(nil), included.Labels...)
+			if tt.modify != nil {
+				tt.modify(&pr)
+			}
+			if got := Apply(tt.opts.Chain(), pr); got != tt.want {
+				t.Errorf("Apply(Chain()) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+	"golang.org/x/oauth2"
+)
+
+// graphqlURL is the GitHub GraphQL API endpoint
+const graphqlURL = "https://api.github.com/graphql"
+
+// graphqlEndpoint returns the GraphQL API endpoint next to the REST API of
+// opts.BaseURL (GitHub Enterprise Server serves /api/v3 and /api/graphql)
+func graphqlEndpoint(opts FetchOptions) string {
+	if opts.BaseURL == "" {
+		return graphqlURL
+	}
+	return strings.TrimSuffix(strings.TrimRight(opts.BaseURL, "/"), "/v3") + "/graphql"
+}
+
+// ssoQuery lists the SAML SSO identities linked to organization members
+const ssoQuery = `query($org: String!, $cursor: String) {
+  organization(login: $org) {
+    samlIdentityProvider {
+      externalIdentities(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          user { login }
+          samlIdentity { nameId emails { value } }
+        }
+      }
+    }
+  }
+}`
+
+// FetchUserEmails collects candidate email addresses for GitHub users so they
+// can be matched to Slack accounts. Addresses come from the public profile,
+// the author emails of the users' commits on the given PRs and, with
+// opts.SSOEmails, the organization's SAML SSO identities. GitHub noreply
+// addresses are skipped since they never match a Slack account.
+func FetchUserEmails(opts FetchOptions, logins []string, prs []*PRResult) (map[string][]string, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("GitHub token is required")
+	}
+
+	ctx := context.Background()
+	client := newClient(opts)
+
+	// Lower-cased login -> login as given, since GitHub logins are case-insensitive
+	wanted := make(map[string]string)
+	for _, login := range logins {
+		wanted[strings.ToLower(login)] = login
+	}
+
+	emails := make(map[string][]string)
+	addEmail := func(login, email string) {
+		email = strings.ToLower(strings.TrimSpace(email))
+		login, exists := wanted[strings.ToLower(login)]
+		if email == "" || isNoReplyEmail(email) || !exists {
+			return
+		}
+		for _, existing := range emails[login] {
+			if existing == email {
+				return
+			}
+		}
+		emails[login] = append(emails[login], email)
+	}
+
+	// Public profile emails
+	for _, login := range logins {
+		user, _, err := client.Users.Get(ctx, login)
+		if err != nil {
+			slog.Warn("Could not fetch GitHub profile", "user", login, "error", err)
+			continue
+		}
+		addEmail(login, user.GetEmail())
+	}
+
+	// Commit author emails, usually the work address even when the profile hides it
+	for _, pr := range prs {
+		if _, exists := wanted[strings.ToLower(pr.Author)]; !exists {
+			continue
+		}
+
+		commits, _, err := client.PullRequests.ListCommits(ctx, opts.Owner, opts.Repo, pr.Number, &github.ListOptions{PerPage: 100})
+		if err != nil {
+			slog.Warn("Could not fetch commits", "repo", opts.Owner+"/"+opts.Repo, "pr", pr.Number, "error", err)
+			continue
+		}
+
+		for _, commit := range commits {
+			// Only trust commits GitHub linked to the PR author's account
+			if !strings.EqualFold(commit.GetAuthor().GetLogin(), pr.Author) {
+				continue
+			}
+			addEmail(pr.Author, commit.GetCommit().GetAuthor().GetEmail())
+		}
+	}
+
+	// Organization SSO identities
+	if opts.SSOEmails {
+		ssoEmails, err := fetchSSOEmails(ctx, graphqlEndpoint(opts), opts.Token, opts.Owner)
+		if err != nil {
+			slog.Warn("Could not fetch SSO identities", "org", opts.Owner, "error", err)
+		} else {
+			for login, addresses := range ssoEmails {
+				for _, email := range addresses {
+					addEmail(login, email)
+				}
+			}
+		}
+	}
+
+	slog.Debug("Found email addresses of GitHub users", "found", len(emails), "users", len(logins))
+
+	return emails, nil
+}
+
+// fetchSSOEmails returns the SAML SSO emails of organization members keyed by
+// GitHub login. It requires a token of an organization owner.
+func fetchSSOEmails(ctx context.Context, endpoint, token, org string) (map[string][]string, error) {
+	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+
+	var response struct {
+		Data struct {
+			Organization struct {
+				SamlIdentityProvider *struct {
+					ExternalIdentities struct {
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []struct {
+							User *struct {
+								Login string `json:"login"`
+							} `json:"user"`
+							SamlIdentity struct {
+								NameID string `json:"nameId"`
+								Emails []struct {
+									Value string `json:"value"`
+								} `json:"emails"`
+							} `json:"samlIdentity"`
+						} `json:"nodes"`
+					} `json:"externalIdentities"`
+				} `json:"samlIdentityProvider"`
+			} `json:"organization"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	emails := make(map[string][]string)
+	var cursor *string
+	for {
+		body, err := json.Marshal(map[string]interface{}{
+			"query":     ssoQuery,
+			"variables": map[string]interface{}{"org": org, "cursor": cursor},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding GraphQL response: %w", err)
+		}
+		if len(response.Errors) > 0 {
+			return nil, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+		}
+
+		provider := response.Data.Organization.SamlIdentityProvider
+		if provider == nil {
+			return nil, fmt.Errorf("organization %s has no SAML SSO configured", org)
+		}
+
+		for _, node := range provider.ExternalIdentities.Nodes {
+			if node.User == nil {
+				continue
+			}
+			if strings.Contains(node.SamlIdentity.NameID, "@") {
+				emails[node.User.Login] = append(emails[node.User.Login], node.SamlIdentity.NameID)
+			}
+			for _, email := range node.SamlIdentity.Emails {
+				emails[node.User.Login] = append(emails[node.User.Login], email.Value)
+			}
+		}
+
+		pageInfo := provider.ExternalIdentities.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		next := pageInfo.EndCursor
+		cursor = &next
+	}
+
+	return emails, nil
+}
+
+// isNoReplyEmail reports whether email is a GitHub-generated noreply address
+func isNoReplyEmail(email string) bool {
+	return strings.HasSuffix(email, "@users.noreply.github.com") || email == "noreply@github.com"
+}
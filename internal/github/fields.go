@@ -0,0 +1,56 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Fields of PRs returned by the API are pointers that can be nil (PRs opened
+// by apps, for example, may lack some of them). The helpers below read them
+// with defaults instead of dereferencing, so one odd PR can't crash a run.
+
+// prTitle returns the title of a PR, or "PR #<number>" without one
+func prTitle(pr *github.PullRequest) string {
+	if title := pr.GetTitle(); title != "" {
+		return title
+	}
+	return fmt.Sprintf("PR #%d", pr.GetNumber())
+}
+
+// prURL returns the web URL of a PR, or builds it from the repository and
+// number when the API left it out
+func prURL(opts FetchOptions, pr *github.PullRequest) string {
+	if url := pr.GetHTMLURL(); url != "" {
+		return url
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/pull/%d", opts.Owner, opts.Repo, pr.GetNumber())
+}
+
+// labelNames returns the names of labels, skipping missing ones
+func labelNames(labels []*github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if name := label.GetName(); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// requestedReviewers returns the logins of the users and the slugs (prefixed
+// with "team:") of the teams requested to review a PR
+func requestedReviewers(pr *github.PullRequest) []string {
+	var reviewers []string
+	for _, reviewer := range pr.RequestedReviewers {
+		if login := reviewer.GetLogin(); login != "" {
+			reviewers = append(reviewers, login)
+		}
+	}
+	for _, team := range pr.RequestedTeams {
+		if slug := team.GetSlug(); slug != "" {
+			reviewers = append(reviewers, "team:"+slug)
+		}
+	}
+	return reviewers
+}
@@ -0,0 +1,24 @@
+package github
+
+import (
+	"github.com/google/go-github/v45/github"
+	"pr-reporter/internal/filter"
+)
+
+// filterPR returns what the filters look at of a GitHub PR
+func filterPR(pr *github.PullRequest) filter.PR {
+	return filter.PR{
+		Number:    pr.GetNumber(),
+		Title:     pr.GetTitle(),
+		Author:    pr.GetUser().GetLogin(),
+		Assignee:  pr.GetAssignee().GetLogin(),
+		Base:      pr.GetBase().GetRef(),
+		Head:      pr.GetHead().GetRef(),
+		Labels:    labelNames(pr.Labels),
+		Reviewers: requestedReviewers(pr),
+		Draft:     pr.GetDraft(),
+		Closed:    pr.GetState() != "open",
+		CreatedAt: pr.GetCreatedAt(),
+		UpdatedAt: pr.GetUpdatedAt(),
+	}
+}
@@ -2,65 +2,99 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v45/github"
 	"golang.org/x/oauth2"
+	"pr-reporter/internal/errkind"
+	"pr-reporter/internal/filter"
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/logging"
 )
 
 // FetchOptions contains options for fetching PRs from GitHub
 type FetchOptions struct {
-	Token         string   // GitHub API token
-	Owner         string   // Repository owner
-	Repo          string   // Repository name
-	Labels        []string // Labels to filter by (if empty, fetch all open PRs)
-	AllowedUsers  []string // Users whose PRs to include
-	DebugMode     bool     // Enable debug logging
+	filter.Options // Which PRs to include
+
+	Token        string        // GitHub API token
+	Owner        string        // Repository owner
+	Repo         string        // Repository name
+	BaseURL      string        // REST API URL, for GitHub Enterprise Server (default: https://api.github.com/)
+	FetchDetails bool          // Fetch reviews and CI check status for each PR with FetchDetails (extra API calls)
+	ReviewTimes  bool          // Also fetch when each PR was marked ready for review (extra API call, needs FetchDetails)
+	SkipReviews  bool          // Leave reviews out of FetchDetails
+	SkipChecks   bool          // Leave CI check status out of FetchDetails
+	SSOEmails    bool          // Include org SAML SSO emails when looking up user emails (needs an org owner token)
+	Timeout      time.Duration // Timeout of each GitHub API call (default: 30s)
 }
 
+// defaultTimeout bounds each GitHub API call when FetchOptions.Timeout is unset
+const defaultTimeout = 30 * time.Second
+
 // PRResult represents a single PR fetched from GitHub
 type PRResult struct {
 	Number      int
 	Title       string
 	URL         string
-	Assignee    string  // GitHub username (not Slack format yet)
+	Assignee    string // GitHub username (not Slack format yet)
 	JiraTicket  string
 	IsDraft     bool
 	Labels      []string
 	Author      string
+	Body        string
+	HeadSHA     string   // Commit the PR branch points to, whose checks FetchDetails fetches
+	Reviewers   []string // Requested reviewers (users and teams) that haven't reviewed yet
+	Reviews     []Review // Latest review per reviewer (only with FetchDetails)
+	ChecksState string   // Combined CI state: "success", "failure", "pending" or "" (only with FetchDetails)
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	ReadyAt       time.Time            // When the PR was opened or last marked ready for review (only with ReviewTimes)
+	RequestedAt   map[string]time.Time // When each requested reviewer was last asked for a review, by username (only with ReviewTimes)
+	FirstReviewAt time.Time            // When the first review by someone else than the author was submitted (only with FetchDetails)
+	ApprovedAt    time.Time            // When the first approval was submitted (only with FetchDetails)
+	MergedAt      time.Time            // When the PR was merged (only for merged PRs)
+}
+
+// Review represents the latest review state a user left on a PR
+type Review struct {
+	User        string
+	State       string // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED
+	SubmittedAt time.Time
 }
 
 // FetchPRs fetches pull requests from a GitHub repository based on provided options
 // If no labels are specified, it fetches all open PRs from the repo
 // If labels are specified, it only fetches PRs with at least one matching label
-func FetchPRs(opts FetchOptions) ([]*PRResult, error) {
+// Reviews and checks are fetched separately with FetchDetails.
+func FetchPRs(ctx context.Context, opts FetchOptions) ([]*PRResult, error) {
 	if opts.Token == "" {
-		return nil, fmt.Errorf("GitHub token is required")
+		return nil, errkind.Configf("GitHub token is required")
 	}
 	if opts.Owner == "" {
-		return nil, fmt.Errorf("repository owner is required")
+		return nil, errkind.Configf("repository owner is required")
 	}
 	if opts.Repo == "" {
-		return nil, fmt.Errorf("repository name is required")
+		return nil, errkind.Configf("repository name is required")
 	}
 
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: opts.Token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-	client := github.NewClient(tc)
+	client := newClient(opts)
+	logger := slog.With("repo", opts.Owner+"/"+opts.Repo)
 
 	// Verify authentication
-	if opts.DebugMode {
+	if logging.Debug() {
 		user, _, err := client.Users.Get(ctx, "")
 		if err != nil {
-			return nil, fmt.Errorf("error verifying GitHub authentication: %v", err)
+			return nil, classify(fmt.Errorf("error verifying GitHub authentication: %w", err))
 		}
-		log.Printf("Debug: Authenticated as GitHub user: %s", *user.Login)
+		logger.Debug("Authenticated to GitHub", "user", user.GetLogin())
 	}
 
 	// Set up GitHub list options
@@ -73,148 +107,309 @@ func FetchPRs(opts FetchOptions) ([]*PRResult, error) {
 
 	allPRs, _, err := client.PullRequests.List(ctx, opts.Owner, opts.Repo, listOpts)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching PRs from %s/%s: %v", opts.Owner, opts.Repo, err)
+		return nil, classify(fmt.Errorf("error fetching PRs from %s/%s: %w", opts.Owner, opts.Repo, err))
 	}
 
-	if opts.DebugMode {
-		log.Printf("Debug: Found %d total open PRs in %s/%s", len(allPRs), opts.Owner, opts.Repo)
-	}
+	logger.Debug("Fetched open PRs", "count", len(allPRs))
 
+	filters := opts.Chain()
 	var filteredPRs []*PRResult
 
-	// Regex to extract JIRA ticket (matches POKER-#### format)
-	jiraRegex := regexp.MustCompile(`POKER-\d+`)
-
 	for _, pr := range allPRs {
-		// Debug PR info
-		if opts.DebugMode {
-			log.Printf("Debug: Examining PR #%d: %s", *pr.Number, *pr.Title)
-			log.Printf("Debug: PR created by: %s", *pr.User.Login)
-			log.Printf("Debug: PR is draft: %t", *pr.Draft)
-
-			labelNames := make([]string, 0, len(pr.Labels))
-			for _, label := range pr.Labels {
-				labelNames = append(labelNames, *label.Name)
-			}
-			log.Printf("Debug: PR labels: %s", strings.Join(labelNames, ", "))
+		if pr == nil {
+			continue
 		}
+		prLogger := logger.With("pr", pr.GetNumber())
+		prLogger.Debug("Examining PR", "title", pr.GetTitle(), "author", pr.GetUser().GetLogin(), "draft", pr.GetDraft())
 
-		// Skip if no user info
-		if pr.User == nil || pr.User.Login == nil {
-			if opts.DebugMode {
-				log.Printf("Debug: PR #%d skipped - no user", *pr.Number)
-			}
+		// Skip PRs that can't be identified or attributed
+		if pr.GetNumber() == 0 {
+			prLogger.Debug("Skipped PR without number")
+			continue
+		}
+		if pr.GetUser().GetLogin() == "" {
+			prLogger.Debug("Skipped PR without author")
 			continue
 		}
 
-		// Filter by allowed users if specified
-		if len(opts.AllowedUsers) > 0 {
-			userFound := false
-			for _, allowedUser := range opts.AllowedUsers {
-				allowedUser = strings.TrimSpace(allowedUser)
-				if allowedUser == "" {
-					continue
-				}
+		if reason := filter.Apply(filters, filterPR(pr)); reason != "" {
+			prLogger.Debug("Skipped PR", "reason", reason)
+			continue
+		}
 
-				if strings.EqualFold(allowedUser, *pr.User.Login) {
-					userFound = true
-					if opts.DebugMode {
-						log.Printf("Debug: PR #%d matches allowed user: %s", *pr.Number, allowedUser)
-					}
-					break
-				}
-			}
+		// Extract JIRA ticket from PR title
+		jiraTicket := JiraTicketFromTitle(pr.GetTitle())
+		if jiraTicket != "" {
+			prLogger = prLogger.With("ticket", jiraTicket)
+		}
 
-			if !userFound {
-				if opts.DebugMode {
-					log.Printf("Debug: PR #%d skipped - user %s not in allowed user list", *pr.Number, *pr.User.Login)
-				}
-				continue
-			}
+		// Create PR result (the assignee is just the GitHub username, no Slack
+		// formatting yet)
+		prResult := &PRResult{
+			Number:     pr.GetNumber(),
+			Title:      prTitle(pr),
+			URL:        prURL(opts, pr),
+			Assignee:   pr.GetAssignee().GetLogin(),
+			JiraTicket: jiraTicket,
+			IsDraft:    pr.GetDraft(),
+			Labels:     labelNames(pr.Labels),
+			Author:     pr.GetUser().GetLogin(),
+			Body:       pr.GetBody(),
+			HeadSHA:    pr.GetHead().GetSHA(),
+			Reviewers:  requestedReviewers(pr),
+			CreatedAt:  pr.GetCreatedAt(),
+			UpdatedAt:  pr.GetUpdatedAt(),
 		}
 
-		// Filter by labels if specified
-		if len(opts.Labels) > 0 {
-			hasMatchingLabel := false
-			for _, label := range pr.Labels {
-				if label.Name != nil {
-					for _, filterLabel := range opts.Labels {
-						// Case-insensitive partial match
-						if strings.Contains(strings.ToLower(*label.Name), strings.ToLower(filterLabel)) {
-							hasMatchingLabel = true
-							if opts.DebugMode {
-								log.Printf("Debug: PR #%d has matching label: %s (matches filter: %s)", 
-									*pr.Number, *label.Name, filterLabel)
-							}
-							break
-						}
-					}
-					if hasMatchingLabel {
-						break
-					}
-				}
+		prLogger.Debug("Included PR", "labels", prResult.Labels, "assignee", prResult.Assignee)
+
+		filteredPRs = append(filteredPRs, prResult)
+	}
+
+	logger.Debug("Filtered PRs", "count", len(filteredPRs))
+
+	return filteredPRs, nil
+}
+
+// FetchDetails fetches the reviews and CI check status of PRs fetched with
+// FetchPRs, and when they were marked ready for review with opts.ReviewTimes.
+// opts.SkipReviews and opts.SkipChecks leave reviews or checks out.
+// Details that can't be fetched for a PR are logged and left out, and the
+// returned error says how many PRs miss some.
+func FetchDetails(ctx context.Context, opts FetchOptions, prs []*PRResult) error {
+	if opts.Token == "" {
+		return fmt.Errorf("GitHub token is required")
+	}
+
+	client := newClient(opts)
+	logger := slog.With("repo", opts.Owner+"/"+opts.Repo)
+
+	incomplete := 0
+	for _, prResult := range prs {
+		prLogger := logger.With("pr", prResult.Number)
+		complete := true
+
+		if !opts.SkipReviews {
+			if err := fetchReviews(ctx, client, opts.Owner, opts.Repo, prResult); err != nil {
+				prLogger.Warn("Could not fetch reviews", "error", err)
+				complete = false
 			}
+		}
 
-			if !hasMatchingLabel {
-				if opts.DebugMode {
-					log.Printf("Debug: PR #%d skipped - no matching label found from: %v", 
-						*pr.Number, opts.Labels)
+		if opts.ReviewTimes && !prResult.IsDraft {
+			readyAt, requestedAt, err := fetchReviewTimes(ctx, client, opts.Owner, opts.Repo, prResult.Number)
+			if err != nil {
+				prLogger.Warn("Could not fetch events", "error", err)
+				complete = false
+			} else {
+				prResult.ReadyAt = readyAt
+				if readyAt.IsZero() {
+					prResult.ReadyAt = prResult.CreatedAt
 				}
-				continue
+				prResult.RequestedAt = requestedAt
 			}
 		}
 
-		// Extract JIRA ticket from PR title
-		jiraTicket := ""
-		if pr.Title != nil {
-			matches := jiraRegex.FindStringSubmatch(*pr.Title)
-			if len(matches) > 0 {
-				jiraTicket = matches[0]
+		if prResult.HeadSHA != "" && !opts.SkipChecks {
+			checksState, err := fetchChecksState(ctx, client, opts.Owner, opts.Repo, prResult.HeadSHA)
+			if err != nil {
+				prLogger.Warn("Could not fetch checks", "error", err)
+				complete = false
+			} else {
+				prResult.ChecksState = checksState
 			}
+		}
 
-			if opts.DebugMode && jiraTicket != "" {
-				log.Printf("Debug: PR #%d JIRA ticket extracted: %s", *pr.Number, jiraTicket)
-			}
+		if !complete {
+			incomplete++
 		}
+		prLogger.Debug("Fetched PR details", "reviews", len(prResult.Reviews), "checks", prResult.ChecksState)
+	}
 
-		// Extract labels
-		prLabels := make([]string, 0, len(pr.Labels))
-		for _, label := range pr.Labels {
-			if label.Name != nil {
-				prLabels = append(prLabels, *label.Name)
-			}
+	if incomplete > 0 {
+		return fmt.Errorf("could not fetch all reviews and checks of %d of %d PRs from GitHub, they may show as unreviewed", incomplete, len(prs))
+	}
+	return nil
+}
+
+// jiraRegex matches JIRA tickets in PR titles (POKER-#### format)
+var jiraRegex = regexp.MustCompile(`POKER-\d+`)
+
+// JiraTicketFromTitle returns the JIRA ticket referenced in a PR title, or ""
+func JiraTicketFromTitle(title string) string {
+	return jiraRegex.FindString(title)
+}
+
+// transport retries failed GitHub requests and rate limits, shared by all
+// clients so that requests to GitHub are spaced together
+var transport = httpx.NewTransport(nil, httpx.Options{Name: "GitHub", Rate: 10})
+
+// classify marks errors of GitHub rejecting the token as errkind.AuthError.
+// Rate limits are reported with their own error types and left as they are.
+func classify(err error) error {
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		switch errResp.Response.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return &errkind.AuthError{API: "GitHub", Err: err}
 		}
+	}
+	return err
+}
+
+// newClient creates a GitHub API client authenticated with the token of opts
+// whose calls, retries included, time out after opts.Timeout
+func newClient(opts FetchOptions) *github.Client {
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: opts.Token},
+	)
+	httpClient := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: &oauth2.Transport{Source: ts, Base: transport},
+	}
+	if httpClient.Timeout <= 0 {
+		httpClient.Timeout = defaultTimeout
+	}
 
-		// Get assignee (just GitHub username, no Slack formatting yet)
-		assignee := ""
-		if pr.Assignee != nil && pr.Assignee.Login != nil {
-			assignee = *pr.Assignee.Login
+	client := github.NewClient(httpClient)
+	if opts.BaseURL != "" {
+		baseURL, err := url.Parse(strings.TrimRight(opts.BaseURL, "/") + "/")
+		if err != nil {
+			slog.Warn("Invalid GitHub API URL, using api.github.com", "url", opts.BaseURL, "error", err)
+		} else {
+			client.BaseURL = baseURL
 		}
+	}
+	return client
+}
 
-		// Create PR result
-		prResult := &PRResult{
-			Number:     *pr.Number,
-			Title:      *pr.Title,
-			URL:        *pr.HTMLURL,
-			Assignee:   assignee,
-			JiraTicket: jiraTicket,
-			IsDraft:    *pr.Draft,
-			Labels:     prLabels,
-			Author:     *pr.User.Login,
+// fetchReviews sets the latest review left by each reviewer on a PR, and
+// when it was first reviewed and approved by someone else than its author
+func fetchReviews(ctx context.Context, client *github.Client, owner, repo string, pr *PRResult) error {
+	reviews, _, err := client.PullRequests.ListReviews(ctx, owner, repo, pr.Number, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return err
+	}
+
+	// Reviews are returned in chronological order, keep the last one per user
+	latest := make(map[string]int)
+	var result []Review
+	for _, review := range reviews {
+		if review.GetUser().GetLogin() == "" || review.GetState() == "" {
+			continue
+		}
+		// Pending reviews haven't been submitted yet
+		if review.GetState() == "PENDING" {
+			continue
 		}
 
-		if opts.DebugMode {
-			log.Printf("Debug: PR #%d matched all criteria and is included", *pr.Number)
-			log.Printf("Debug: PR #%d draft status: %t", *pr.Number, prResult.IsDraft)
-			log.Printf("Debug: PR #%d assignee: %s", *pr.Number, prResult.Assignee)
+		r := Review{
+			User:        review.GetUser().GetLogin(),
+			State:       review.GetState(),
+			SubmittedAt: review.GetSubmittedAt(),
+		}
+		if idx, exists := latest[r.User]; exists {
+			result[idx] = r
+		} else {
+			latest[r.User] = len(result)
+			result = append(result, r)
 		}
 
-		filteredPRs = append(filteredPRs, prResult)
+		if r.User == pr.Author || r.SubmittedAt.IsZero() {
+			continue
+		}
+		if pr.FirstReviewAt.IsZero() {
+			pr.FirstReviewAt = r.SubmittedAt
+		}
+		if pr.ApprovedAt.IsZero() && r.State == "APPROVED" {
+			pr.ApprovedAt = r.SubmittedAt
+		}
 	}
 
-	if opts.DebugMode {
-		log.Printf("Debug: Filtered to %d PRs matching criteria", len(filteredPRs))
+	pr.Reviews = result
+	return nil
+}
+
+// fetchReviewTimes returns when a PR was last marked ready for review, or zero
+// if it never was a draft, and when each user was last requested to review it
+func fetchReviewTimes(ctx context.Context, client *github.Client, owner, repo string, number int) (time.Time, map[string]time.Time, error) {
+	var readyAt time.Time
+	requestedAt := make(map[string]time.Time)
+	listOpts := &github.ListOptions{PerPage: 100}
+	for {
+		events, resp, err := client.Issues.ListIssueEvents(ctx, owner, repo, number, listOpts)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		for _, event := range events {
+			switch event.GetEvent() {
+			case "ready_for_review":
+				readyAt = event.GetCreatedAt()
+			case "review_requested":
+				if reviewer := event.GetRequestedReviewer().GetLogin(); reviewer != "" {
+					requestedAt[reviewer] = event.GetCreatedAt()
+				}
+			}
+		}
+		if resp.NextPage == 0 {
+			return readyAt, requestedAt, nil
+		}
+		listOpts.Page = resp.NextPage
 	}
+}
 
-	return filteredPRs, nil
+// fetchChecksState combines commit statuses and check runs for a commit into
+// a single state: "failure" if anything failed, "pending" if anything is still
+// running, "success" if everything passed, or "" if there are no checks at all
+func fetchChecksState(ctx context.Context, client *github.Client, owner, repo, sha string) (string, error) {
+	combined, _, err := client.Repositories.GetCombinedStatus(ctx, owner, repo, sha, nil)
+	if err != nil {
+		return "", err
+	}
+
+	checkRuns, _, err := client.Checks.ListCheckRunsForRef(ctx, owner, repo, sha, &github.ListCheckRunsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	total := 0
+	failed := false
+	pending := false
+
+	if combined != nil && combined.GetTotalCount() > 0 {
+		total += combined.GetTotalCount()
+		switch combined.GetState() {
+		case "failure", "error":
+			failed = true
+		case "pending":
+			pending = true
+		}
+	}
+
+	if checkRuns != nil {
+		for _, run := range checkRuns.CheckRuns {
+			total++
+			if run.GetStatus() != "completed" {
+				pending = true
+				continue
+			}
+			switch run.GetConclusion() {
+			case "failure", "timed_out", "cancelled", "action_required":
+				failed = true
+			}
+		}
+	}
+
+	switch {
+	case total == 0:
+		return "", nil
+	case failed:
+		return "failure", nil
+	case pending:
+		return "pending", nil
+	default:
+		return "success", nil
+	}
 }
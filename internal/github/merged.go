@@ -0,0 +1,86 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"pr-reporter/internal/filter"
+)
+
+// FetchMergedPRs fetches the PRs merged since the given time that match the
+// label and user filters of opts, most recently merged first
+func FetchMergedPRs(opts FetchOptions, since time.Time) ([]*PRResult, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("GitHub token is required")
+	}
+	if opts.Owner == "" || opts.Repo == "" {
+		return nil, fmt.Errorf("repository owner and name are required")
+	}
+
+	ctx := context.Background()
+	client := newClient(opts)
+	filters := opts.Chain()
+
+	// Closed PRs sorted by last update: a PR merged since then was also
+	// updated since then, so paging can stop at the first older one
+	listOpts := &github.PullRequestListOptions{
+		State:       "closed",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var merged []*PRResult
+	for {
+		prs, resp, err := client.PullRequests.List(ctx, opts.Owner, opts.Repo, listOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching closed PRs from %s/%s: %w", opts.Owner, opts.Repo, err)
+		}
+
+		done := false
+		for _, pr := range prs {
+			if pr == nil {
+				continue
+			}
+			if updated := pr.GetUpdatedAt(); !updated.IsZero() && updated.Before(since) {
+				done = true
+				break
+			}
+			if pr.GetMergedAt().Before(since) || pr.GetNumber() == 0 || pr.GetUser().GetLogin() == "" {
+				continue
+			}
+			if filter.Apply(filters, filterPR(pr)) != "" {
+				continue
+			}
+
+			merged = append(merged, &PRResult{
+				Number:     pr.GetNumber(),
+				Title:      prTitle(pr),
+				URL:        prURL(opts, pr),
+				Assignee:   pr.GetAssignee().GetLogin(),
+				JiraTicket: JiraTicketFromTitle(pr.GetTitle()),
+				Labels:     labelNames(pr.Labels),
+				Author:     pr.GetUser().GetLogin(),
+				Body:       pr.GetBody(),
+				CreatedAt:  pr.GetCreatedAt(),
+				UpdatedAt:  pr.GetUpdatedAt(),
+				MergedAt:   pr.GetMergedAt(),
+			})
+		}
+
+		if done || resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+
+	sort.Slice(merged, func(i, j int) bool { return merged[i].MergedAt.After(merged[j].MergedAt) })
+
+	slog.Debug("Fetched merged PRs", "repo", opts.Owner+"/"+opts.Repo, "since", since, "count", len(merged))
+
+	return merged, nil
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"pr-reporter/internal/filter"
+)
+
+// FetchReviewCounts counts the PRs each user reviewed since the given time,
+// across open and closed PRs matching the label and user filters of opts.
+// Several reviews of the same PR by one user count once, and authors
+// commenting on their own PRs don't count.
+func FetchReviewCounts(opts FetchOptions, since time.Time) (map[string]int, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("GitHub token is required")
+	}
+	if opts.Owner == "" || opts.Repo == "" {
+		return nil, fmt.Errorf("repository owner and name are required")
+	}
+
+	ctx := context.Background()
+	client := newClient(opts)
+	filters := opts.Chain()
+
+	// A PR reviewed since then was also updated since then, so paging can
+	// stop at the first PR updated earlier
+	listOpts := &github.PullRequestListOptions{
+		State:       "all",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	counts := make(map[string]int)
+	checked := 0
+	for {
+		prs, resp, err := client.PullRequests.List(ctx, opts.Owner, opts.Repo, listOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching PRs from %s/%s: %w", opts.Owner, opts.Repo, err)
+		}
+
+		done := false
+		for _, pr := range prs {
+			if pr == nil {
+				continue
+			}
+			if updated := pr.GetUpdatedAt(); !updated.IsZero() && updated.Before(since) {
+				done = true
+				break
+			}
+			if pr.GetNumber() == 0 || pr.GetUser().GetLogin() == "" || filter.Apply(filters, filterPR(pr)) != "" {
+				continue
+			}
+
+			reviews, _, err := client.PullRequests.ListReviews(ctx, opts.Owner, opts.Repo, pr.GetNumber(), &github.ListOptions{PerPage: 100})
+			if err != nil {
+				slog.Warn("Could not fetch reviews", "repo", opts.Owner+"/"+opts.Repo, "pr", pr.GetNumber(), "error", err)
+				continue
+			}
+			checked++
+
+			reviewed := make(map[string]bool)
+			for _, review := range reviews {
+				reviewer := review.GetUser().GetLogin()
+				if reviewer == "" || reviewer == pr.GetUser().GetLogin() || review.GetState() == "PENDING" {
+					continue
+				}
+				if review.GetSubmittedAt().Before(since) || reviewed[reviewer] {
+					continue
+				}
+				reviewed[reviewer] = true
+				counts[reviewer]++
+			}
+		}
+
+		if done || resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+
+	slog.Debug("Counted reviews", "repo", opts.Owner+"/"+opts.Repo, "since", since, "reviewers", len(counts), "prs", checked)
+
+	return counts, nil
+}
@@ -0,0 +1,253 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/filter"
+	"pr-reporter/internal/github"
+	"pr-reporter/internal/httpx"
+)
+
+// DefaultURL is the GitLab instance used when none is configured
+const DefaultURL = "https://gitlab.com"
+
+// FetchOptions contains options for fetching merge requests from GitLab
+type FetchOptions struct {
+	filter.Options // Which MRs to include
+
+	URL          string // GitLab base URL (default: https://gitlab.com)
+	Token        string // Personal, group or project access token with read_api scope
+	Project      string // Project path (e.g., "my-group/fips-web-client") or numeric ID
+	FetchDetails bool   // Fetch approvals and pipeline status for each MR (extra API calls)
+}
+
+// user is a GitLab user reference
+type user struct {
+	Username string `json:"username"`
+}
+
+// mergeRequest is the part of a GitLab merge request used here
+type mergeRequest struct {
+	IID         int       `json:"iid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	WebURL      string    `json:"web_url"`
+	Draft       bool      `json:"draft"`
+	WIP         bool      `json:"work_in_progress"` // Draft flag of GitLab versions before 13.2
+	Labels      []string  `json:"labels"`
+	Target      string    `json:"target_branch"`
+	Source      string    `json:"source_branch"`
+	Author      user      `json:"author"`
+	Assignee    *user     `json:"assignee"`
+	Reviewers   []user    `json:"reviewers"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// httpClient is used to call the GitLab API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "GitLab"}).Client(30 * time.Second)
+
+// FetchMRs fetches the open merge requests of a GitLab project as PR results,
+// applying the same filters as GitHub
+func FetchMRs(ctx context.Context, opts FetchOptions) ([]*github.PRResult, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("GitLab token is required")
+	}
+	if opts.Project == "" {
+		return nil, fmt.Errorf("GitLab project is required")
+	}
+
+	var mrs []mergeRequest
+	path := fmt.Sprintf("/projects/%s/merge_requests?state=opened&per_page=100", url.PathEscape(opts.Project))
+	for path != "" {
+		var page []mergeRequest
+		next, err := get(ctx, opts, path, &page)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching merge requests of %s: %w", opts.Project, err)
+		}
+		mrs = append(mrs, page...)
+		path = next
+	}
+
+	logger := slog.With("repo", opts.Project)
+	logger.Debug("Fetched open MRs", "count", len(mrs))
+
+	filters := opts.Chain()
+	var results []*github.PRResult
+	for _, mr := range mrs {
+		if reason := filter.Apply(filters, filterMR(mr)); reason != "" {
+			logger.Debug("Skipped MR", "mr", mr.IID, "reason", reason)
+			continue
+		}
+
+		result := &github.PRResult{
+			Number:     mr.IID,
+			Title:      mr.Title,
+			URL:        mr.WebURL,
+			JiraTicket: github.JiraTicketFromTitle(mr.Title),
+			IsDraft:    mr.Draft || mr.WIP,
+			Labels:     mr.Labels,
+			Author:     mr.Author.Username,
+			Body:       mr.Description,
+			CreatedAt:  mr.CreatedAt,
+			UpdatedAt:  mr.UpdatedAt,
+		}
+		if mr.Assignee != nil {
+			result.Assignee = mr.Assignee.Username
+		}
+
+		if opts.FetchDetails {
+			approvers, err := fetchApprovers(ctx, opts, mr.IID)
+			if err != nil {
+				logger.Warn("Could not fetch approvals", "mr", mr.IID, "error", err)
+			}
+			for _, approver := range approvers {
+				result.Reviews = append(result.Reviews, github.Review{User: approver, State: "APPROVED"})
+			}
+
+			checksState, err := fetchPipelineState(ctx, opts, mr.IID)
+			if err != nil {
+				logger.Warn("Could not fetch pipeline", "mr", mr.IID, "error", err)
+			} else {
+				result.ChecksState = checksState
+			}
+		}
+
+		// Reviewers that haven't approved yet are still requested
+		for _, reviewer := range mr.Reviewers {
+			approved := false
+			for _, review := range result.Reviews {
+				if strings.EqualFold(review.User, reviewer.Username) {
+					approved = true
+					break
+				}
+			}
+			if !approved {
+				result.Reviewers = append(result.Reviewers, reviewer.Username)
+			}
+		}
+
+		logger.Debug("Included MR", "mr", mr.IID, "draft", result.IsDraft, "assignee", result.Assignee)
+
+		results = append(results, result)
+	}
+
+	logger.Debug("Filtered MRs", "count", len(results))
+
+	return results, nil
+}
+
+// fetchApprovers returns the usernames of the users who approved an MR
+func fetchApprovers(ctx context.Context, opts FetchOptions, iid int) ([]string, error) {
+	var approvals struct {
+		ApprovedBy []struct {
+			User user `json:"user"`
+		} `json:"approved_by"`
+	}
+	if _, err := get(ctx, opts, fmt.Sprintf("/projects/%s/merge_requests/%d/approvals", url.PathEscape(opts.Project), iid), &approvals); err != nil {
+		return nil, err
+	}
+
+	var approvers []string
+	for _, approval := range approvals.ApprovedBy {
+		approvers = append(approvers, approval.User.Username)
+	}
+	return approvers, nil
+}
+
+// fetchPipelineState maps the status of the latest MR pipeline to the combined
+// CI states used for GitHub: "success", "failure", "pending" or ""
+func fetchPipelineState(ctx context.Context, opts FetchOptions, iid int) (string, error) {
+	var pipelines []struct {
+		Status string `json:"status"`
+	}
+	if _, err := get(ctx, opts, fmt.Sprintf("/projects/%s/merge_requests/%d/pipelines?per_page=1", url.PathEscape(opts.Project), iid), &pipelines); err != nil {
+		return "", err
+	}
+	if len(pipelines) == 0 {
+		return "", nil
+	}
+
+	switch pipelines[0].Status {
+	case "success":
+		return "success", nil
+	case "failed", "canceled":
+		return "failure", nil
+	case "skipped", "manual":
+		return "", nil
+	default:
+		return "pending", nil
+	}
+}
+
+// get calls the GitLab REST API and decodes the response into result. It
+// returns the path of the next page, or "" on the last page.
+func get(ctx context.Context, opts FetchOptions, path string, result interface{}) (string, error) {
+	baseURL := strings.TrimRight(opts.URL, "/")
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/api/v4"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("PRIVATE-TOKEN", opts.Token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", httpx.NewStatusError("GitLab", resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return "", fmt.Errorf("error decoding GitLab response: %w", err)
+	}
+
+	nextPage := resp.Header.Get("X-Next-Page")
+	if nextPage == "" {
+		return "", nil
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("page", nextPage)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// filterMR returns what the filters look at of a merge request. Approvals
+// are only fetched for included MRs, so every reviewer counts as requested.
+func filterMR(mr mergeRequest) filter.PR {
+	pr := filter.PR{
+		Number:    mr.IID,
+		Title:     mr.Title,
+		Author:    mr.Author.Username,
+		Base:      mr.Target,
+		Head:      mr.Source,
+		Labels:    mr.Labels,
+		Draft:     mr.Draft || mr.WIP,
+		CreatedAt: mr.CreatedAt,
+		UpdatedAt: mr.UpdatedAt,
+	}
+	if mr.Assignee != nil {
+		pr.Assignee = mr.Assignee.Username
+	}
+	for _, reviewer := range mr.Reviewers {
+		pr.Reviewers = append(pr.Reviewers, reviewer.Username)
+	}
+	return pr
+}
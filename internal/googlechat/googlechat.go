@@ -0,0 +1,231 @@
+package googlechat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/model"
+)
+
+// Options contains options for sending a PR report to Google Chat
+type Options struct {
+	WebhookURL string // Google Chat space incoming webhook URL
+}
+
+// maxPRsPerCard keeps each card well below the Google Chat message size limit
+const maxPRsPerCard = 25
+
+// object is a JSON object of the Google Chat card format
+type object map[string]interface{}
+
+// httpClient is used to post to Google Chat webhooks
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Google Chat"}).Client(30 * time.Second)
+
+// SendReport posts the report to a Google Chat space as cards. Long reports
+// are split into several messages, kept together in one thread.
+func SendReport(ctx context.Context, opts Options, report model.Report) error {
+	if opts.WebhookURL == "" {
+		return fmt.Errorf("Google Chat webhook URL is required")
+	}
+
+	webhookURL, err := threadedURL(opts.WebhookURL, fmt.Sprintf("pr-report-%s-%d", report.Name, report.Date.Unix()))
+	if err != nil {
+		return fmt.Errorf("invalid Google Chat webhook URL: %w", err)
+	}
+
+	messages := buildMessages(report)
+
+	for i, msg := range messages {
+		if err := postMessage(ctx, webhookURL, msg); err != nil {
+			return fmt.Errorf("error posting message %d/%d to Google Chat: %w", i+1, len(messages), err)
+		}
+
+		slog.Debug("Sent Google Chat message", "part", i+1, "parts", len(messages))
+	}
+
+	return nil
+}
+
+// threadedURL adds the thread key to the webhook URL so all parts of a report
+// land in the same thread
+func threadedURL(webhookURL, threadKey string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("threadKey", threadKey)
+	query.Set("messageReplyOption", "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// buildMessages lays the report out as card messages: the header goes on the
+// first card and the blocked and draft summary on the last one
+func buildMessages(report model.Report) []object {
+	text := report.Text()
+
+	title := report.Title
+	if title == "" {
+		title = report.Name
+	}
+
+	var widgets []object
+	for i, pr := range report.PRs {
+		widgets = append(widgets, prWidget(report, text, i+1, pr))
+	}
+
+	// Split PRs into chunks, always producing at least one card
+	var chunks [][]object
+	for start := 0; start < len(widgets); start += maxPRsPerCard {
+		end := start + maxPRsPerCard
+		if end > len(widgets) {
+			end = len(widgets)
+		}
+		chunks = append(chunks, widgets[start:end])
+	}
+	if len(chunks) == 0 {
+		chunks = append(chunks, nil)
+	}
+
+	messages := make([]object, len(chunks))
+	for i, chunk := range chunks {
+		var sections []object
+		if len(chunk) > 0 {
+			sections = append(sections, object{"widgets": chunk})
+		}
+		if i == len(chunks)-1 {
+			sections = append(sections, object{"widgets": []object{summaryWidget(report, text)}})
+		}
+
+		header := object{
+			"title":    "📋 " + title,
+			"subtitle": fmt.Sprintf("📅 %s · 📊 %s: %d", report.Date.Format("2006-01-02"), text.TotalOpenPRs, len(report.PRs)),
+		}
+		if i > 0 {
+			header = object{"title": fmt.Sprintf("%s (%s %d/%d)", title, text.Continued, i+1, len(chunks))}
+		}
+
+		messages[i] = object{
+			"cardsV2": []object{{
+				"cardId": fmt.Sprintf("pr-report-%d", i+1),
+				"card": object{
+					"header":   header,
+					"sections": sections,
+				},
+			}},
+		}
+	}
+
+	return messages
+}
+
+// prWidget formats a single PR as a decorated text linking to the PR
+func prWidget(report model.Report, text model.Strings, index int, pr *model.PR) object {
+	description := pr.Description
+	if description == "" {
+		description = text.NoDescription
+	}
+
+	jira := text.None
+	if pr.JiraTicket != "" {
+		jira = html.EscapeString(pr.JiraTicket)
+		if ticketURL := report.TicketURL(pr); ticketURL != "" {
+			jira = fmt.Sprintf(`<a href="%s">%s</a>`, ticketURL, jira)
+		}
+	}
+
+	status := pr.JiraStatus
+	if status == "" {
+		status = text.UnknownStatus
+	}
+
+	assignee := pr.GithubAssignee
+	if assignee == "" {
+		assignee = text.Unassigned
+	}
+
+	var flags []string
+	if pr.IsBlocked {
+		flags = append(flags, "🚫 "+text.ReasonBlocked)
+	}
+	if pr.IsDraft {
+		flags = append(flags, "📝 "+text.Draft)
+	}
+
+	topLabel := fmt.Sprintf("%d. PR-%d", index, pr.Number)
+	if len(flags) > 0 {
+		topLabel += " · " + strings.Join(flags, " · ")
+	}
+
+	return object{
+		"decoratedText": object{
+			"topLabel":    topLabel,
+			"text":        fmt.Sprintf(`<a href="%s">%s</a><br>%s · <b>%s</b>`, report.PRURL(pr), html.EscapeString(description), jira, html.EscapeString(status)),
+			"bottomLabel": assignee,
+			"wrapText":    true,
+		},
+	}
+}
+
+// summaryWidget lists blocked and draft PRs at the end of the report
+func summaryWidget(report model.Report, text model.Strings) object {
+	links := func(prs []*model.PR) string {
+		var result []string
+		for _, pr := range prs {
+			result = append(result, fmt.Sprintf(`<a href="%s">PR-%d</a>`, report.PRURL(pr), pr.Number))
+		}
+		return strings.Join(result, ", ")
+	}
+
+	var lines []string
+	blocked, drafts := report.Blocked(), report.Drafts()
+	if len(blocked) > 0 {
+		lines = append(lines, fmt.Sprintf("🚫 <b>%s:</b> %s", text.Blocked, links(blocked)))
+	}
+	if len(drafts) > 0 {
+		lines = append(lines, fmt.Sprintf("📝 <b>%s:</b> %s", text.Draft, links(drafts)))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, fmt.Sprintf("✅ <b>%s:</b> %s", text.BlockedOrDraft, text.None))
+	}
+
+	return object{"textParagraph": object{"text": strings.Join(lines, "<br>")}}
+}
+
+// postMessage sends a single message to a Google Chat webhook
+func postMessage(ctx context.Context, webhookURL string, msg object) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return httpx.NewStatusError("Google Chat", resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults of a Monitor
+const (
+	DefaultMaxRunTime = time.Hour       // Runs taking longer are considered stuck
+	DefaultGrace      = 5 * time.Minute // How late a scheduled run may start
+)
+
+// Run is the outcome of a scheduled run
+type Run struct {
+	Start    time.Time  `json:"start"`
+	End      *time.Time `json:"end,omitempty"`
+	Success  bool       `json:"success"`
+	Error    string     `json:"error,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+}
+
+// Status is the state of the scheduler, as served by the endpoints
+type Status struct {
+	Healthy bool       `json:"healthy"`
+	Ready   bool       `json:"ready"`
+	Reason  string     `json:"reason,omitempty"` // Why the scheduler is unhealthy
+	Started time.Time  `json:"started"`
+	Running *Run       `json:"running,omitempty"` // The run in progress
+	LastRun *Run       `json:"last_run,omitempty"`
+	NextRun *time.Time `json:"next_run,omitempty"`
+}
+
+// Monitor tracks the runs of a scheduler and serves /healthz, /readyz and
+// /status for Kubernetes probes and uptime monitors. The scheduler is
+// unhealthy when a run takes longer than MaxRunTime or a scheduled run is
+// more than Grace late, i.e. when it is wedged.
+type Monitor struct {
+	MaxRunTime time.Duration    // Runs taking longer are considered stuck (default 1h)
+	Grace      time.Duration    // How late a scheduled run may start (default 5m)
+	Next       func() time.Time // Time of the next scheduled run (zero: unknown)
+
+	mu      sync.Mutex
+	started time.Time
+	ready   bool
+	running *Run
+	last    *Run
+}
+
+// NewMonitor returns a Monitor with the default limits
+func NewMonitor(next func() time.Time) *Monitor {
+	return &Monitor{MaxRunTime: DefaultMaxRunTime, Grace: DefaultGrace, Next: next, started: time.Now()}
+}
+
+// SetReady marks the scheduler as started, so /readyz succeeds
+func (m *Monitor) SetReady() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ready = true
+}
+
+// Begin records the start of a run
+func (m *Monitor) Begin() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = &Run{Start: time.Now()}
+}
+
+// End records the end of the run in progress with its error, if any
+func (m *Monitor) End(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	run := m.running
+	if run == nil {
+		run = &Run{Start: time.Now()}
+	}
+	end := time.Now()
+	run.End = &end
+	run.Duration = end.Sub(run.Start).Round(time.Millisecond).String()
+	run.Success = err == nil
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	m.last = run
+	m.running = nil
+}
+
+// Status returns the current state of the scheduler
+func (m *Monitor) Status() Status {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := Status{Healthy: true, Ready: m.ready, Started: m.started, LastRun: m.last}
+	if m.Next != nil {
+		if next := m.Next(); !next.IsZero() {
+			status.NextRun = &next
+		}
+	}
+	if m.running != nil {
+		running := *m.running
+		status.Running = &running
+	}
+
+	maxRunTime, grace := m.MaxRunTime, m.Grace
+	if maxRunTime <= 0 {
+		maxRunTime = DefaultMaxRunTime
+	}
+	if grace <= 0 {
+		grace = DefaultGrace
+	}
+
+	switch {
+	case m.running != nil && now.Sub(m.running.Start) > maxRunTime:
+		status.Healthy = false
+		status.Reason = "run in progress for more than " + maxRunTime.String()
+	case m.running == nil && m.ready && status.NextRun != nil && now.Sub(*status.NextRun) > grace:
+		status.Healthy = false
+		status.Reason = "scheduled run is more than " + grace.String() + " late"
+	}
+
+	return status
+}
+
+// Handler serves /healthz (the scheduler isn't wedged), /readyz (the
+// scheduler started) and /status (always 200), each with the Status as JSON
+func (m *Monitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		status := m.Status()
+		writeStatus(w, status, status.Healthy)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		status := m.Status()
+		writeStatus(w, status, status.Ready)
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, m.Status(), true)
+	})
+	return mux
+}
+
+// writeStatus writes the status as JSON, with 503 Service Unavailable when
+// the probe fails
+func writeStatus(w http.ResponseWriter, status Status, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(status)
+}
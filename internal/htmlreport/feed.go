@@ -0,0 +1,172 @@
+package htmlreport
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/model"
+)
+
+// feedFile is the Atom feed of the reports in the report directory
+const feedFile = "feed.xml"
+
+// maxFeedEntries is how many reports the feed keeps, newest first
+const maxFeedEntries = 50
+
+// atomFeed is an Atom feed document
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// atomAuthor is the author of a feed
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// atomLink is a link of a feed or entry
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// atomEntry is one report in the feed
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Summary string      `xml:"summary"`
+	Content atomContent `xml:"content"`
+}
+
+// atomContent is the HTML content of an entry
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// entryTemplate renders the PR list shown by feed readers
+var entryTemplate = template.Must(template.New("entry").Parse(`<p>{{.Summary}}</p>
+{{if .PRs}}<ul>
+{{range .PRs}}<li><a href="{{.URL}}">PR-{{.Number}}</a> {{.Description}} – {{.Status}}{{if .IsBlocked}} 🚫{{end}}{{if .IsDraft}} 📝{{end}}</li>
+{{end}}</ul>{{end}}`))
+
+// writeFeed adds the report to the Atom feed in dir. A report rerun on the
+// same day replaces that day's entry, like its dated page.
+func writeFeed(opts Options, report model.Report, page string) error {
+	path := filepath.Join(opts.Dir, feedFile)
+
+	var feed atomFeed
+	if data, err := os.ReadFile(path); err == nil {
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return fmt.Errorf("error parsing %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	entry, err := feedEntry(opts, report, page)
+	if err != nil {
+		return err
+	}
+
+	entries := []atomEntry{entry}
+	for _, existing := range feed.Entries {
+		if existing.ID != entry.ID {
+			entries = append(entries, existing)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Updated > entries[j].Updated })
+	if len(entries) > maxFeedEntries {
+		entries = entries[:maxFeedEntries]
+	}
+
+	feed = atomFeed{
+		Title:   "PR Reports",
+		ID:      "urn:pr-reporter:reports",
+		Updated: entries[0].Updated,
+		Author:  atomAuthor{Name: "PR Reporter"},
+		Links: []atomLink{
+			{Href: pageURL(opts, feedFile), Rel: "self", Type: "application/atom+xml"},
+			{Href: pageURL(opts, "index.html"), Rel: "alternate", Type: "text/html"},
+		},
+		Entries: entries,
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding Atom feed: %w", err)
+	}
+
+	return writeFile(path, append([]byte(xml.Header), data...))
+}
+
+// feedEntry builds the feed entry of a report linking to its dated page
+func feedEntry(opts Options, report model.Report, page string) (atomEntry, error) {
+	text := report.Text()
+
+	title := report.Title
+	if title == "" {
+		title = report.Name
+	}
+
+	summary := fmt.Sprintf("%s: %d · 🚫 %s: %d · 📝 %s: %d",
+		text.TotalOpenPRs, len(report.PRs), text.Blocked, len(report.Blocked()), text.Draft, len(report.Drafts()))
+
+	data := struct {
+		Summary string
+		PRs     []pagePR
+	}{Summary: summary}
+	for _, pr := range report.PRs {
+		ppr := pagePR{
+			PR:          pr,
+			URL:         report.PRURL(pr),
+			Description: pr.Description,
+			Status:      pr.JiraStatus,
+		}
+		if ppr.Description == "" {
+			ppr.Description = text.NoDescription
+		}
+		if ppr.Status == "" {
+			ppr.Status = text.UnknownStatus
+		}
+		data.PRs = append(data.PRs, ppr)
+	}
+
+	var b bytes.Buffer
+	if err := entryTemplate.Execute(&b, data); err != nil {
+		return atomEntry{}, fmt.Errorf("error rendering feed entry: %w", err)
+	}
+
+	return atomEntry{
+		Title:   fmt.Sprintf("%s – %s", title, report.Date.Format("2006-01-02")),
+		ID:      fmt.Sprintf("urn:pr-reporter:%s:%s", report.Name, report.Date.Format("2006-01-02")),
+		Updated: report.Date.UTC().Format(time.RFC3339),
+		Link:    atomLink{Href: pageURL(opts, page), Rel: "alternate", Type: "text/html"},
+		Summary: summary,
+		Content: atomContent{Type: "html", Body: b.String()},
+	}, nil
+}
+
+// pageURL returns the link to a file of the report directory, absolute when
+// a base URL is configured
+func pageURL(opts Options, name string) string {
+	if opts.BaseURL == "" {
+		return name
+	}
+	return strings.TrimRight(opts.BaseURL, "/") + "/" + name
+}
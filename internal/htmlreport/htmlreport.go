@@ -0,0 +1,254 @@
+package htmlreport
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/model"
+)
+
+// Options contains options for writing the report as a static HTML page
+type Options struct {
+	Dir     string        // Directory the pages are written to
+	Refresh time.Duration // Reload interval of the page in browsers, for dashboards (0: never)
+	BaseURL string        // Public URL of the directory, for absolute links in the Atom feed (e.g., "https://pr-reporter.example.com/reports")
+}
+
+// pageTemplate renders the report page
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">
+{{end}}<title>{{.Title}} – {{.Date}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 1200px; padding: 0 1rem; color: #1d1c1d; background: #fff; }
+h1 { margin-bottom: 0.25rem; }
+.meta { color: #616061; margin-top: 0; }
+.stats { display: flex; gap: 1rem; margin: 1.5rem 0; }
+.stat { flex: 1; border-radius: 8px; padding: 1rem; background: #f4f4f4; }
+.stat strong { display: block; font-size: 2rem; }
+.stat.blocked { background: #fdecef; }
+.stat.draft { background: #eef0f2; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.6rem; border-bottom: 1px solid #ddd; vertical-align: top; }
+th { background: #f4f4f4; }
+tr.blocked { background: #fdecef; }
+tr.draft { color: #616061; }
+a { color: #1264a3; text-decoration: none; }
+a:hover { text-decoration: underline; }
+.status { font-weight: 600; }
+@media (prefers-color-scheme: dark) {
+  body { color: #e8e8e8; background: #1a1d21; }
+  th, .stat { background: #2c2d30; }
+  .stat.blocked, tr.blocked { background: #4a1f29; }
+  .stat.draft { background: #35373b; }
+  tr.draft, .meta { color: #ababad; }
+  th, td { border-color: #3c3d40; }
+  a { color: #4da3ff; }
+}
+</style>
+</head>
+<body>
+<h1>📋 {{.Title}}</h1>
+<p class="meta">📅 {{.Date}} · {{.Report.GithubOwner}}/{{.Report.GithubRepo}}</p>
+<div class="stats">
+<div class="stat"><strong>{{len .PRs}}</strong>{{.Text.TotalOpenPRs}}</div>
+<div class="stat blocked"><strong>{{.Blocked}}</strong>🚫 {{.Text.Blocked}}</div>
+<div class="stat draft"><strong>{{.Drafts}}</strong>📝 {{.Text.Draft}}</div>
+</div>
+{{if .PRs}}<table>
+<tr><th>#</th><th>PR</th><th>Jira</th><th>Status</th><th>Assignee</th><th>Author</th></tr>
+{{range .PRs}}<tr{{if .IsBlocked}} class="blocked"{{else if .IsDraft}} class="draft"{{end}}>
+<td>{{.Index}}</td>
+<td><a href="{{.URL}}">PR-{{.Number}}</a> {{.Description}}{{if .IsBlocked}} 🚫{{end}}{{if .IsDraft}} 📝{{end}}</td>
+<td>{{if .JiraURL}}<a href="{{.JiraURL}}">{{.JiraTicket}}</a>{{else}}{{.JiraTicket}}{{end}}</td>
+<td class="status">{{.Status}}</td>
+<td>{{.Assignee}}</td>
+<td>{{.Author}}</td>
+</tr>
+{{end}}</table>{{else}}<p>✅ {{.Text.BlockedOrDraft}}: {{.Text.None}}</p>{{end}}
+</body>
+</html>
+`))
+
+// indexTemplate lists the archived report pages
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>PR Reports</title>
+<link rel="alternate" type="application/atom+xml" title="PR Reports" href="feed.xml">
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 800px; padding: 0 1rem; }
+li { margin: 0.3rem 0; }
+</style>
+</head>
+<body>
+<h1>📋 PR Reports</h1>
+<p><a href="feed.xml">Atom feed</a></p>
+<ul>
+{{range .}}<li><a href="{{.}}">{{.}}</a></li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+// pageData is the data passed to pageTemplate
+type pageData struct {
+	Report  model.Report
+	Text    model.Strings
+	Lang    string
+	Title   string
+	Date    string
+	Refresh int
+	Blocked int
+	Drafts  int
+	PRs     []pagePR
+}
+
+// pagePR is a PR with its links and placeholders resolved
+type pagePR struct {
+	*model.PR
+	Index       int
+	URL         string
+	JiraURL     string
+	Description string
+	Status      string
+	Assignee    string
+}
+
+// WriteReport writes the report to <name>.html in opts.Dir, keeps a dated
+// copy for the archive, adds it to the Atom feed and refreshes the index page
+// listing all reports
+func WriteReport(opts Options, report model.Report) error {
+	if opts.Dir == "" {
+		return fmt.Errorf("HTML report directory is required")
+	}
+
+	page, err := Render(opts, report)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return fmt.Errorf("error creating %s: %w", opts.Dir, err)
+	}
+
+	latest := filepath.Join(opts.Dir, report.Name+".html")
+	archived := filepath.Join(opts.Dir, fmt.Sprintf("%s-%s.html", report.Name, report.Date.Format("2006-01-02")))
+	for _, path := range []string{latest, archived} {
+		if err := writeFile(path, page); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFeed(opts, report, filepath.Base(archived)); err != nil {
+		return err
+	}
+
+	if err := writeIndex(opts.Dir); err != nil {
+		return err
+	}
+
+	slog.Debug("Wrote HTML report", "latest", latest, "archived", archived)
+
+	return nil
+}
+
+// Render renders the report as a standalone HTML page
+func Render(opts Options, report model.Report) ([]byte, error) {
+	text := report.Text()
+
+	title := report.Title
+	if title == "" {
+		title = report.Name
+	}
+
+	data := pageData{
+		Report:  report,
+		Text:    text,
+		Lang:    report.Locale,
+		Title:   title,
+		Date:    report.Date.Format("2006-01-02 15:04"),
+		Refresh: int(opts.Refresh.Seconds()),
+		Blocked: len(report.Blocked()),
+		Drafts:  len(report.Drafts()),
+	}
+	if data.Lang == "" {
+		data.Lang = model.DefaultLocale
+	}
+
+	for i, pr := range report.PRs {
+		ppr := pagePR{
+			PR:          pr,
+			Index:       i + 1,
+			URL:         report.PRURL(pr),
+			JiraURL:     report.TicketURL(pr),
+			Description: pr.Description,
+			Status:      pr.JiraStatus,
+			Assignee:    pr.GithubAssignee,
+		}
+		if ppr.Description == "" {
+			ppr.Description = text.NoDescription
+		}
+		if ppr.Status == "" {
+			ppr.Status = text.UnknownStatus
+		}
+		if ppr.Assignee == "" {
+			ppr.Assignee = text.Unassigned
+		}
+		data.PRs = append(data.PRs, ppr)
+	}
+
+	var b bytes.Buffer
+	if err := pageTemplate.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("error rendering HTML report: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+// writeIndex writes index.html linking every report page in dir, newest first
+func writeIndex(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error listing %s: %w", dir, err)
+	}
+
+	var pages []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".html") && name != "index.html" {
+			pages = append(pages, name)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(pages)))
+
+	var b bytes.Buffer
+	if err := indexTemplate.Execute(&b, pages); err != nil {
+		return fmt.Errorf("error rendering HTML index: %w", err)
+	}
+
+	return writeFile(filepath.Join(dir, "index.html"), b.Bytes())
+}
+
+// writeFile replaces a file atomically so the page is never served half written
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,419 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pr-reporter/internal/config"
+)
+
+// Default retry and circuit breaker settings
+const (
+	defaultRetries    = 4                // Retries after the first attempt
+	defaultBackoff    = 1 * time.Second  // Wait before the first retry without a Retry-After header
+	defaultMaxBackoff = 60 * time.Second // Longest single wait
+	defaultBreakAfter = 5                // Consecutive failed attempts that open a circuit
+	defaultBreakFor   = 30 * time.Second // How long an open circuit rejects requests
+)
+
+// Options configure a Transport
+type Options struct {
+	Name       string        // API name in log messages (e.g., "GitHub")
+	Retries    int           // Retries after the first attempt (0: default 4, negative: none)
+	Backoff    time.Duration // Wait before the first retry, doubled after each one and jittered (default 1s)
+	MaxBackoff time.Duration // Longest single wait (default 60s)
+	Rate       float64       // Requests per second sent to each host (0: unlimited), overridden by RATE_LIMITS
+	Burst      int           // Requests sent to a host at once before Rate spaces them (default 1), overridden by RATE_BURSTS
+	BreakAfter int           // Consecutive failed attempts to a host that open its circuit (0: default 5, negative: never)
+	BreakFor   time.Duration // How long an open circuit rejects requests before one is let through again (default 30s)
+}
+
+// Transport is an http.RoundTripper that limits requests to each host with a
+// token bucket (see Options.Rate and Options.Burst) and retries transient
+// failures: every request answered with 429 Too Many Requests (or 403 with
+// Retry-After), and idempotent requests failing with a network error or 502,
+// 503 or 504. It waits as long as the Retry-After header asks, or backs off
+// exponentially with jitter when it is missing.
+//
+// A host that keeps failing, with network errors, timeouts or 5xx responses,
+// gets its circuit opened: its requests, retries included, fail at once with
+// an *OpenCircuit error for a while, then a single request is let through to
+// test whether it recovered.
+type Transport struct {
+	base http.RoundTripper
+	opts Options
+
+	limitOnce sync.Once
+	rateLimit config.RateLimit // Options with the configured overrides, see limit
+
+	mu       sync.Mutex
+	buckets  map[string]*bucket  // Host -> its token bucket
+	circuits map[string]*circuit // Host -> its circuit breaker
+}
+
+// bucket holds the tokens of a host, one per request
+type bucket struct {
+	tokens float64   // Tokens left, negative when requests are waiting for them
+	last   time.Time // When tokens was last refilled
+}
+
+// circuit tracks the failures of a host
+type circuit struct {
+	failures  int       // Consecutive failed attempts
+	openUntil time.Time // Requests are rejected until then (zero: closed)
+	probing   bool      // A request is testing whether the host recovered
+}
+
+// OpenCircuit is a host whose circuit is open. It is also the error of the
+// requests rejected while it is.
+type OpenCircuit struct {
+	API      string    // API name (e.g., "JIRA")
+	Host     string    // Host that kept failing
+	Failures int       // Consecutive failed attempts
+	Until    time.Time // When a request is let through again
+}
+
+func (c *OpenCircuit) Error() string {
+	return fmt.Sprintf("%s at %s failed %d times in a row, skipping its requests until %s", c.API, c.Host, c.Failures, c.Until.Format("15:04:05"))
+}
+
+// Causes of a StatusError, for errors.Is
+var (
+	ErrUnauthorized = errors.New("unauthorized") // 401: the credentials were rejected
+	ErrForbidden    = errors.New("forbidden")    // 403: the credentials lack a permission
+	ErrNotFound     = errors.New("not found")    // 404
+	ErrRateLimited  = errors.New("rate limited") // 429, after the Transport's retries
+	ErrServerError  = errors.New("server error") // 5xx
+)
+
+// StatusError is an API answering with an unexpected HTTP status. It matches
+// the sentinel error of its status with errors.Is.
+type StatusError struct {
+	API        string // API name (e.g., "Asana")
+	StatusCode int
+	Status     string // Status line (e.g., "404 Not Found")
+	Body       string // Start of the response body
+}
+
+// NewStatusError reads the start of the body of resp into a StatusError
+func NewStatusError(api string, resp *http.Response) *StatusError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return &StatusError{API: api, StatusCode: resp.StatusCode, Status: resp.Status, Body: strings.TrimSpace(string(body))}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned %s: %s", e.API, e.Status, e.Body)
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// transports are every Transport created, for OpenCircuits
+var (
+	transportsMu sync.Mutex
+	transports   []*Transport
+)
+
+// OpenCircuits returns the hosts whose circuit is open, of the transports of
+// the given APIs (Options.Name)
+func OpenCircuits(apis ...string) []*OpenCircuit {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	var open []*OpenCircuit
+	for _, t := range transports {
+		if !slices.Contains(apis, t.opts.Name) {
+			continue
+		}
+		t.mu.Lock()
+		for host, c := range t.circuits {
+			if !c.openUntil.IsZero() {
+				open = append(open, &OpenCircuit{API: t.opts.Name, Host: host, Failures: c.failures, Until: c.openUntil})
+			}
+		}
+		t.mu.Unlock()
+	}
+	return open
+}
+
+// NewTransport returns a Transport sending requests through base
+// (http.DefaultTransport when nil)
+func NewTransport(base http.RoundTripper, opts Options) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if opts.Retries == 0 {
+		opts.Retries = defaultRetries
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultMaxBackoff
+	}
+	if opts.BreakAfter == 0 {
+		opts.BreakAfter = defaultBreakAfter
+	}
+	if opts.BreakFor <= 0 {
+		opts.BreakFor = defaultBreakFor
+	}
+
+	t := &Transport{base: base, opts: opts, buckets: make(map[string]*bucket), circuits: make(map[string]*circuit)}
+	transportsMu.Lock()
+	transports = append(transports, t)
+	transportsMu.Unlock()
+	return t
+}
+
+// Client returns an http.Client sending requests through t whose requests,
+// retries included, time out after timeout (0: no timeout)
+func (t *Transport) Client(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: t}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := t.opts.Backoff
+
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(req.Context(), req.URL.Host); err != nil {
+			return nil, err
+		}
+		if err := t.allow(req.URL.Host); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		t.record(req.URL.Host, resp, err)
+		if !t.retryable(req, resp, err) || attempt >= t.opts.Retries {
+			return resp, err
+		}
+
+		// Requests with a body can only be retried when it can be read again
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := jitter(backoff)
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			if after, ok := retryAfter(resp); ok {
+				wait = after
+			}
+			reason = resp.Status
+			resp.Body.Close()
+		}
+		if wait > t.opts.MaxBackoff {
+			wait = t.opts.MaxBackoff
+		}
+		backoff *= 2
+
+		slog.Warn("Request failed, retrying", "api", t.opts.Name, "path", req.URL.Path, "reason", reason, "wait", wait, "attempt", attempt+1, "max", t.opts.Retries)
+
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryable reports whether a request failed in a way worth retrying
+func (t *Transport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// The caller gave up: its deadline passed or it was canceled
+		if req.Context().Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return idempotent(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// Rate-limited requests weren't processed, so even POSTs can be sent again
+		return true
+	case http.StatusForbidden:
+		// GitHub answers secondary rate limits with 403 and Retry-After
+		return resp.Header.Get("Retry-After") != ""
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(req.Method)
+	}
+	return false
+}
+
+// allow returns an *OpenCircuit error when the circuit of host is open. Once
+// it has been open for long enough, a single request is let through.
+func (t *Transport) allow(host string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c := t.circuits[host]
+	if c == nil || c.openUntil.IsZero() {
+		return nil
+	}
+	if time.Now().Before(c.openUntil) || c.probing {
+		return &OpenCircuit{API: t.opts.Name, Host: host, Failures: c.failures, Until: c.openUntil}
+	}
+	c.probing = true
+	return nil
+}
+
+// record updates the circuit of host with the outcome of an attempt. Network
+// errors, timeouts and 5xx responses are failures; canceled requests and rate
+// limits don't count either way.
+func (t *Transport) record(host string, resp *http.Response, err error) {
+	if t.opts.BreakAfter < 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c := t.circuits[host]
+	if c == nil {
+		c = &circuit{}
+		t.circuits[host] = c
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled), err == nil && resp.StatusCode == http.StatusTooManyRequests:
+		// Says nothing about the host, the next request tests it instead
+		c.probing = false
+	case err != nil || resp.StatusCode >= 500:
+		c.failures++
+		if c.probing || (c.openUntil.IsZero() && c.failures >= t.opts.BreakAfter) {
+			c.openUntil = time.Now().Add(t.opts.BreakFor)
+			c.probing = false
+			slog.Warn("Host keeps failing, opening its circuit", "api", t.opts.Name, "host", host, "failures", c.failures, "until", c.openUntil)
+		}
+	default:
+		if !c.openUntil.IsZero() {
+			slog.Info("Host recovered, closing its circuit", "api", t.opts.Name, "host", host)
+		}
+		*c = circuit{}
+	}
+}
+
+// limit returns the rate limit of t: its options, overridden by RATE_LIMITS
+// and RATE_BURSTS. The environment is read on the first request, once the
+// .env file is loaded.
+func (t *Transport) limit() config.RateLimit {
+	t.limitOnce.Do(func() {
+		t.rateLimit = config.LoadRateLimit(t.opts.Name, config.RateLimit{Rate: t.opts.Rate, Burst: t.opts.Burst})
+		if t.rateLimit.Burst <= 0 {
+			t.rateLimit.Burst = 1
+		}
+	})
+	return t.rateLimit
+}
+
+// wait blocks until a token of the host's bucket is available. Buckets hold
+// up to the burst of tokens and refill at the rate; a request finding the
+// bucket empty takes a token ahead of time and waits until it has refilled,
+// so waiting requests are sent in order.
+func (t *Transport) wait(ctx context.Context, host string) error {
+	limit := t.limit()
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	b, exists := t.buckets[host]
+	if !exists {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		t.buckets[host] = b
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	b.tokens--
+	delay := time.Duration(-b.tokens / limit.Rate * float64(time.Second))
+	t.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the token back to the requests queued after this one
+		t.mu.Lock()
+		b.tokens++
+		t.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// idempotent reports whether requests of a method can safely be sent twice
+func idempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns how long the Retry-After header of a response asks to
+// wait, given in seconds (fractional ones too, as Discord sends) or as an
+// HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(header, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// jitter returns a random wait between half and all of backoff, so that
+// clients failing together don't retry together
+func jitter(backoff time.Duration) time.Duration {
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,253 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-reporter/internal/config"
+)
+
+// roundTripFunc stubs the base transport of a Transport
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// response returns an empty response with a status code
+func response(status int) *http.Response {
+	return &http.Response{StatusCode: status, Status: http.StatusText(status), Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}
+}
+
+// errNetwork stands in for a connection failure
+var errNetwork = errors.New("connection refused")
+
+func TestCircuitBreaker(t *testing.T) {
+	const breakFor = 20 * time.Millisecond
+
+	// Each step sends one request answered with status (0: a network error),
+	// after waiting out the cooldown when cooldown is set
+	type step struct {
+		status   int
+		cooldown bool
+		rejected bool // The circuit is open, so the request isn't sent
+	}
+	tests := []struct {
+		name       string
+		breakAfter int
+		steps      []step
+	}{
+		{
+			name:       "opens after BreakAfter failures",
+			breakAfter: 3,
+			steps:      []step{{status: 500}, {status: 503}, {status: 502}, {status: 200, rejected: true}},
+		},
+		{
+			name:       "network errors count",
+			breakAfter: 2,
+			steps:      []step{{status: 0}, {status: 0}, {status: 200, rejected: true}},
+		},
+		{
+			name:       "success resets the count",
+			breakAfter: 3,
+			steps:      []step{{status: 500}, {status: 500}, {status: 200}, {status: 500}, {status: 500}, {status: 200}},
+		},
+		{
+			name:       "client errors and rate limits don't count",
+			breakAfter: 2,
+			steps:      []step{{status: 404}, {status: 429}, {status: 401}, {status: 429}, {status: 200}},
+		},
+		{
+			name:       "half-opens after the cooldown and closes on success",
+			breakAfter: 2,
+			steps: []step{
+				{status: 500}, {status: 500}, {status: 200, rejected: true},
+				{status: 200, cooldown: true}, {status: 500}, {status: 200},
+			},
+		},
+		{
+			name:       "failed probe opens it again at once",
+			breakAfter: 2,
+			steps: []step{
+				{status: 500}, {status: 500},
+				{status: 500, cooldown: true}, {status: 200, rejected: true},
+			},
+		},
+		{
+			name:       "negative BreakAfter never opens",
+			breakAfter: -1,
+			steps:      []step{{status: 500}, {status: 500}, {status: 500}, {status: 500}, {status: 500}, {status: 500}, {status: 200}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status int
+			sent := 0
+			transport := NewTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				sent++
+				if status == 0 {
+					return nil, errNetwork
+				}
+				return response(status), nil
+			}), Options{Name: "Breaker test", Retries: -1, BreakAfter: tt.breakAfter, BreakFor: breakFor})
+
+			for i, step := range tt.steps {
+				if step.cooldown {
+					time.Sleep(breakFor + 5*time.Millisecond)
+				}
+				status = step.status
+				before := sent
+
+				req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/items", nil)
+				resp, err := transport.RoundTrip(req)
+				if resp != nil {
+					resp.Body.Close()
+				}
+
+				var open *OpenCircuit
+				rejected := errors.As(err, &open)
+				if rejected != step.rejected {
+					t.Fatalf("step %d: rejected = %v (error %v), want %v", i, rejected, err, step.rejected)
+				}
+				if wantSent := !step.rejected; (sent > before) != wantSent {
+					t.Fatalf("step %d: request sent = %v, want %v", i, sent > before, wantSent)
+				}
+				if rejected && (open.API != "Breaker test" || open.Host != "api.example.com") {
+					t.Errorf("step %d: OpenCircuit = %+v, want the Breaker test API at api.example.com", i, open)
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerProbesOnce(t *testing.T) {
+	transport := NewTransport(nil, Options{Name: "Probe test", BreakAfter: 1, BreakFor: time.Millisecond})
+	transport.record("api.example.com", response(500), nil)
+	time.Sleep(2 * time.Millisecond)
+
+	// Only one request tests whether the host recovered
+	if err := transport.allow("api.example.com"); err != nil {
+		t.Fatalf("first request after the cooldown: %v, want it let through", err)
+	}
+	if err := transport.allow("api.example.com"); err == nil {
+		t.Fatal("second request while probing was let through, want it rejected")
+	}
+	if err := transport.allow("other.example.com"); err != nil {
+		t.Errorf("request to another host: %v, want it let through", err)
+	}
+}
+
+func TestOpenCircuits(t *testing.T) {
+	transport := NewTransport(nil, Options{Name: "Listed test", BreakAfter: 1})
+	transport.record("api.example.com", nil, errNetwork)
+	transport.record("ok.example.com", response(200), nil)
+
+	open := OpenCircuits("Listed test")
+	if len(open) != 1 || open[0].Host != "api.example.com" || open[0].Failures != 1 {
+		t.Fatalf("OpenCircuits(Listed test) = %+v, want api.example.com after 1 failure", open)
+	}
+	if open := OpenCircuits("Other API"); len(open) != 0 {
+		t.Errorf("OpenCircuits(Other API) = %+v, want none", open)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	const rate = 50 // One token every 20ms
+	interval := time.Second / rate
+
+	// immediate reports whether a request is sent without waiting for a token
+	immediate := func(transport *Transport) bool {
+		start := time.Now()
+		if err := transport.wait(context.Background(), "api.example.com"); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+		elapsed := time.Since(start)
+		if elapsed >= interval/2 && elapsed < interval*3/4 {
+			t.Fatalf("wait took %s, neither immediate nor a token interval (%s)", elapsed, interval)
+		}
+		return elapsed < interval/2
+	}
+
+	tests := []struct {
+		name  string
+		burst int
+		pause int    // Token intervals to pause after the first requests
+		want  []bool // Whether each request is sent at once, the pause coming after the first burst of them
+	}{
+		{name: "burst goes out at once", burst: 3, want: []bool{true, true, true, false, false}},
+		{name: "default burst of one", burst: 0, want: []bool{true, false, false}},
+		{name: "refills at the rate", burst: 3, pause: 2, want: []bool{true, true, true, true, true, false}},
+		{name: "refills up to the burst", burst: 2, pause: 5, want: []bool{true, true, true, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := NewTransport(nil, Options{Name: "Bucket test", Rate: rate, Burst: tt.burst})
+			for i, want := range tt.want {
+				if i == max(tt.burst, 1) && tt.pause > 0 {
+					time.Sleep(time.Duration(tt.pause)*interval + interval/4)
+				}
+				if got := immediate(transport); got != want {
+					t.Fatalf("request %d sent at once = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenBucketCanceled(t *testing.T) {
+	transport := NewTransport(nil, Options{Name: "Bucket test", Rate: 50})
+	if err := transport.wait(context.Background(), "api.example.com"); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+
+	// A request giving up while waiting hands its token to the next one
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := transport.wait(ctx, "api.example.com"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled wait = %v, want context.Canceled", err)
+	}
+	start := time.Now()
+	if err := transport.wait(context.Background(), "api.example.com"); err != nil {
+		t.Fatalf("wait after cancel: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 30*time.Millisecond {
+		t.Errorf("wait after a canceled request took %s, want at most one token interval (20ms)", elapsed)
+	}
+}
+
+func TestRateLimitOverrides(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits string // RATE_LIMITS
+		bursts string // RATE_BURSTS
+		want   config.RateLimit
+	}{
+		{name: "options", want: config.RateLimit{Rate: 4, Burst: 1}},
+		{name: "per second", limits: "bucket test=10", want: config.RateLimit{Rate: 10, Burst: 1}},
+		{name: "per minute", limits: "buckettest=300/m", want: config.RateLimit{Rate: 5, Burst: 1}},
+		{name: "per hour with burst", limits: "BucketTest=3600/h", bursts: "bucket test=20", want: config.RateLimit{Rate: 1, Burst: 20}},
+		{name: "unlimited", limits: "buckettest=0", want: config.RateLimit{Rate: 0, Burst: 1}},
+		{name: "other APIs", limits: "github=1", bursts: "github=9", want: config.RateLimit{Rate: 4, Burst: 1}},
+		{name: "invalid rate", limits: "buckettest=fast", want: config.RateLimit{Rate: 4, Burst: 1}},
+		{name: "invalid unit", limits: "buckettest=5/d", want: config.RateLimit{Rate: 4, Burst: 1}},
+		{name: "invalid burst", bursts: "buckettest=0", want: config.RateLimit{Rate: 4, Burst: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RATE_LIMITS", tt.limits)
+			t.Setenv("RATE_BURSTS", tt.bursts)
+			transport := NewTransport(nil, Options{Name: "Bucket Test", Rate: 4})
+			if got := transport.limit(); got != tt.want {
+				t.Errorf("limit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,32 +1,42 @@
 package jira
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/andygrunwald/go-jira"
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/logging"
 )
 
 // FetchOptions contains options for fetching JIRA ticket information
 type FetchOptions struct {
-	URL       string // JIRA base URL
-	Username  string // JIRA username (for Basic auth)
-	APIToken  string // JIRA API token or Personal Access Token
-	UsePAT    bool   // Use Personal Access Token instead of Basic auth
-	DebugMode bool   // Enable debug logging
+	URL      string        // JIRA base URL
+	Username string        // JIRA username (for Basic auth)
+	APIToken string        // JIRA API token or Personal Access Token
+	UsePAT   bool          // Use Personal Access Token instead of Basic auth
+	Timeout  time.Duration // Timeout of each JIRA API call (default: 30s)
 }
 
+// defaultTimeout bounds each JIRA API call when FetchOptions.Timeout is unset
+const defaultTimeout = 30 * time.Second
+
 // TicketInfo represents information about a JIRA ticket
 type TicketInfo struct {
 	TicketID  string
 	Status    string
 	Summary   string
 	IsBlocked bool
+	URL       string // Web URL of the ticket, set by trackers other than JIRA
+	Assignee  string // Name of the ticket's assignee, set by trackers that report it
+	Priority  string // Priority name (e.g., "High"), empty when the ticket has none
 }
 
 // FetchTicketInfo fetches information for a single JIRA ticket
-func FetchTicketInfo(opts FetchOptions, ticketID string) (*TicketInfo, error) {
+func FetchTicketInfo(ctx context.Context, opts FetchOptions, ticketID string) (*TicketInfo, error) {
 	if ticketID == "" {
 		return nil, fmt.Errorf("ticket ID is required")
 	}
@@ -36,60 +46,25 @@ func FetchTicketInfo(opts FetchOptions, ticketID string) (*TicketInfo, error) {
 		return nil, fmt.Errorf("JIRA credentials not fully configured")
 	}
 
-	if opts.DebugMode {
-		log.Printf("Debug: Initializing JIRA client for %s", opts.URL)
-		log.Printf("Debug: Using PAT authentication: %v", opts.UsePAT)
-	}
-
-	// Create JIRA client with appropriate authentication
-	var jiraClient *jira.Client
-	if opts.UsePAT {
-		if opts.DebugMode {
-			log.Println("Debug: Using JIRA Personal Access Token authentication")
-		}
-
-		tp := jira.PATAuthTransport{
-			Token: opts.APIToken,
-		}
-
-		var err error
-		jiraClient, err = jira.NewClient(tp.Client(), opts.URL)
-		if err != nil {
-			return nil, fmt.Errorf("error creating JIRA client with PAT: %v", err)
-		}
-	} else {
-		if opts.DebugMode {
-			log.Println("Debug: Using JIRA Basic authentication (email + API token)")
-		}
-
-		tp := jira.BasicAuthTransport{
-			Username: opts.Username,
-			Password: opts.APIToken,
-		}
-
-		var err error
-		jiraClient, err = jira.NewClient(tp.Client(), opts.URL)
-		if err != nil {
-			return nil, fmt.Errorf("error creating JIRA client with Basic auth: %v", err)
-		}
+	jiraClient, err := newClient(opts)
+	if err != nil {
+		return nil, err
 	}
 
 	// Test JIRA connection in debug mode
-	if opts.DebugMode {
-		log.Printf("Debug: Testing JIRA connection to %s", opts.URL)
-		myself, _, err := jiraClient.User.GetSelf()
+	if logging.Debug() {
+		myself, _, err := jiraClient.User.GetSelfWithContext(ctx)
 		if err != nil {
-			log.Printf("Debug: JIRA authentication test failed: %v", err)
+			slog.Debug("JIRA authentication test failed", "url", opts.URL, "error", err)
 		} else {
-			log.Printf("Debug: Successfully authenticated to JIRA as: %s", myself.DisplayName)
+			slog.Debug("Authenticated to JIRA", "url", opts.URL, "user", myself.DisplayName)
 		}
 	}
 
-	if opts.DebugMode {
-		log.Printf("Debug: Fetching JIRA info for ticket %s", ticketID)
-	}
+	logger := slog.With("ticket", ticketID)
+	logger.Debug("Fetching JIRA ticket")
 
-	issue, resp, err := jiraClient.Issue.Get(ticketID, nil)
+	issue, resp, err := jiraClient.Issue.GetWithContext(ctx, ticketID, nil)
 	if err != nil {
 		if resp != nil && resp.StatusCode == 404 {
 			return &TicketInfo{
@@ -99,7 +74,7 @@ func FetchTicketInfo(opts FetchOptions, ticketID string) (*TicketInfo, error) {
 				IsBlocked: false,
 			}, nil
 		}
-		return nil, fmt.Errorf("error fetching JIRA ticket %s: %v", ticketID, err)
+		return nil, fmt.Errorf("error fetching JIRA ticket %s: %w", ticketID, err)
 	}
 
 	ticketInfo := &TicketInfo{
@@ -114,26 +89,22 @@ func FetchTicketInfo(opts FetchOptions, ticketID string) (*TicketInfo, error) {
 		// Extract status
 		if issue.Fields.Status != nil && issue.Fields.Status.Name != "" {
 			ticketInfo.Status = issue.Fields.Status.Name
-			if opts.DebugMode {
-				log.Printf("Debug: JIRA ticket %s status: %s", ticketID, ticketInfo.Status)
-			}
 		} else {
 			ticketInfo.Status = "No Status"
-			if opts.DebugMode {
-				log.Printf("Debug: JIRA ticket %s has no status field", ticketID)
-			}
+			logger.Debug("JIRA ticket has no status field")
 		}
 
 		// Extract description/summary
 		if issue.Fields.Summary != "" {
 			ticketInfo.Summary = issue.Fields.Summary
-			if opts.DebugMode {
-				log.Printf("Debug: JIRA ticket %s summary: %s", ticketID, ticketInfo.Summary)
-			}
 		} else {
 			ticketInfo.Summary = "No Description"
 		}
 
+		if issue.Fields.Priority != nil {
+			ticketInfo.Priority = issue.Fields.Priority.Name
+		}
+
 		// Check if blocked by status name
 		if issue.Fields.Status != nil && issue.Fields.Status.Name != "" {
 			statusName := strings.ToLower(issue.Fields.Status.Name)
@@ -141,9 +112,7 @@ func FetchTicketInfo(opts FetchOptions, ticketID string) (*TicketInfo, error) {
 				strings.Contains(statusName, "impediment") ||
 				strings.Contains(statusName, "pause") {
 				ticketInfo.IsBlocked = true
-				if opts.DebugMode {
-					log.Printf("Debug: JIRA ticket %s marked as blocked due to status: %s", ticketID, issue.Fields.Status.Name)
-				}
+				logger.Debug("JIRA ticket is blocked by its status", "status", issue.Fields.Status.Name)
 			}
 		}
 
@@ -155,39 +124,87 @@ func FetchTicketInfo(opts FetchOptions, ticketID string) (*TicketInfo, error) {
 					strings.Contains(labelLower, "impediment") ||
 					strings.Contains(labelLower, "pause") {
 					ticketInfo.IsBlocked = true
-					if opts.DebugMode {
-						log.Printf("Debug: JIRA ticket %s marked as blocked due to label: %s", ticketID, label)
-					}
+					logger.Debug("JIRA ticket is blocked by a label", "label", label)
 					break
 				}
 			}
 		}
 	} else {
 		ticketInfo.Status = "No Data"
-		if opts.DebugMode {
-			log.Printf("Debug: JIRA ticket %s returned no usable data", ticketID)
-		}
+		logger.Debug("JIRA ticket returned no usable data")
 	}
 
-	if opts.DebugMode {
-		log.Printf("Debug: Final status for JIRA %s: %s (blocked: %v)", ticketID, ticketInfo.Status, ticketInfo.IsBlocked)
-	}
+	logger.Debug("Fetched JIRA ticket", "status", ticketInfo.Status, "summary", ticketInfo.Summary, "blocked", ticketInfo.IsBlocked)
 
 	return ticketInfo, nil
 }
 
-// FetchTicketsInfo fetches information for multiple JIRA tickets
-func FetchTicketsInfo(opts FetchOptions, ticketIDs []string) (map[string]*TicketInfo, error) {
+// transport retries failed JIRA requests, shared by all clients so that
+// requests to a JIRA site are spaced together
+var transport = httpx.NewTransport(nil, httpx.Options{Name: "JIRA", Rate: 10})
+
+// newClient creates a JIRA client with Basic or Personal Access Token
+// authentication whose calls, retries included, time out after opts.Timeout
+func newClient(opts FetchOptions) (*jira.Client, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	// Create JIRA client with appropriate authentication
+	var jiraClient *jira.Client
+	if opts.UsePAT {
+		slog.Debug("Using JIRA Personal Access Token authentication", "url", opts.URL)
+
+		tp := jira.PATAuthTransport{
+			Token:     opts.APIToken,
+			Transport: transport,
+		}
+		httpClient := tp.Client()
+		httpClient.Timeout = timeout
+
+		var err error
+		jiraClient, err = jira.NewClient(httpClient, opts.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error creating JIRA client with PAT: %w", err)
+		}
+	} else {
+		slog.Debug("Using JIRA Basic authentication (email + API token)", "url", opts.URL)
+
+		tp := jira.BasicAuthTransport{
+			Username:  opts.Username,
+			Password:  opts.APIToken,
+			Transport: transport,
+		}
+		httpClient := tp.Client()
+		httpClient.Timeout = timeout
+
+		var err error
+		jiraClient, err = jira.NewClient(httpClient, opts.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error creating JIRA client with Basic auth: %w", err)
+		}
+	}
+
+	return jiraClient, nil
+}
+
+// FetchTicketsInfo fetches information for multiple JIRA tickets. It stops
+// with the context's error once ctx is done.
+func FetchTicketsInfo(ctx context.Context, opts FetchOptions, ticketIDs []string) (map[string]*TicketInfo, error) {
 	results := make(map[string]*TicketInfo)
 
 	for _, ticketID := range ticketIDs {
 		if ticketID == "" {
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return results, fmt.Errorf("error fetching JIRA tickets: %w", err)
+		}
 
-		ticketInfo, err := FetchTicketInfo(opts, ticketID)
+		ticketInfo, err := FetchTicketInfo(ctx, opts, ticketID)
 		if err != nil {
-			log.Printf("Warning: Error fetching JIRA ticket %s: %v", ticketID, err)
+			slog.Warn("Could not fetch JIRA ticket", "ticket", ticketID, "error", err)
 			// Store error info
 			results[ticketID] = &TicketInfo{
 				TicketID:  ticketID,
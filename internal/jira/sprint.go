@@ -0,0 +1,128 @@
+package jira
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Sprint is a sprint of a JIRA board with the status of its tickets
+type Sprint struct {
+	Name      string
+	Start     time.Time       // Start of the sprint (zero if not set)
+	End       time.Time       // Planned end of the sprint (zero if not set)
+	Completed time.Time       // When the sprint was closed (zero while active)
+	Tickets   map[string]bool // Ticket key -> whether the ticket is done
+}
+
+// FetchActiveSprint fetches the active sprint of a board and its tickets.
+// It returns nil when the board has no active sprint.
+func FetchActiveSprint(opts FetchOptions, boardID int) (*Sprint, error) {
+	if opts.Username == "" || opts.APIToken == "" || opts.URL == "" {
+		return nil, fmt.Errorf("JIRA credentials not fully configured")
+	}
+
+	jiraClient, err := newClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sprints, _, err := jiraClient.Board.GetAllSprintsWithOptions(boardID, &jira.GetAllSprintsOptions{State: "active"})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching active sprint of board %d: %w", boardID, err)
+	}
+	if len(sprints.Values) == 0 {
+		slog.Debug("JIRA board has no active sprint", "board", boardID)
+		return nil, nil
+	}
+
+	return fetchSprintTickets(jiraClient, opts, sprints.Values[0])
+}
+
+// FetchLastClosedSprint fetches the most recently closed sprint of a board
+// and its tickets. It returns nil when no sprint of the board was closed yet.
+func FetchLastClosedSprint(opts FetchOptions, boardID int) (*Sprint, error) {
+	if opts.Username == "" || opts.APIToken == "" || opts.URL == "" {
+		return nil, fmt.Errorf("JIRA credentials not fully configured")
+	}
+
+	jiraClient, err := newClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Closed sprints are listed oldest first, page through all of them
+	var last *jira.Sprint
+	sprintOpts := &jira.GetAllSprintsOptions{State: "closed", SearchOptions: jira.SearchOptions{MaxResults: 50}}
+	for {
+		sprints, _, err := jiraClient.Board.GetAllSprintsWithOptions(boardID, sprintOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching closed sprints of board %d: %w", boardID, err)
+		}
+		for i := range sprints.Values {
+			if last == nil || closedAt(sprints.Values[i]).After(closedAt(*last)) {
+				last = &sprints.Values[i]
+			}
+		}
+
+		sprintOpts.StartAt += len(sprints.Values)
+		if sprints.IsLast || len(sprints.Values) == 0 {
+			break
+		}
+	}
+	if last == nil {
+		slog.Debug("JIRA board has no closed sprint", "board", boardID)
+		return nil, nil
+	}
+
+	return fetchSprintTickets(jiraClient, opts, *last)
+}
+
+// closedAt returns when a sprint was closed, falling back to its planned end
+func closedAt(sprint jira.Sprint) time.Time {
+	if sprint.CompleteDate != nil {
+		return *sprint.CompleteDate
+	}
+	if sprint.EndDate != nil {
+		return *sprint.EndDate
+	}
+	return time.Time{}
+}
+
+// fetchSprintTickets fetches the tickets of a sprint with whether they are done
+func fetchSprintTickets(jiraClient *jira.Client, opts FetchOptions, found jira.Sprint) (*Sprint, error) {
+	sprint := &Sprint{Name: found.Name, Tickets: make(map[string]bool)}
+	if found.StartDate != nil {
+		sprint.Start = *found.StartDate
+	}
+	if found.EndDate != nil {
+		sprint.End = *found.EndDate
+	}
+	if found.CompleteDate != nil {
+		sprint.Completed = *found.CompleteDate
+	}
+
+	searchOpts := &jira.SearchOptions{MaxResults: 100, Fields: []string{"status"}}
+	for {
+		issues, resp, err := jiraClient.Issue.Search(fmt.Sprintf("sprint = %d", found.ID), searchOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching tickets of sprint %s: %w", found.Name, err)
+		}
+
+		for _, issue := range issues {
+			done := issue.Fields != nil && issue.Fields.Status != nil && issue.Fields.Status.StatusCategory.Key == jira.StatusCategoryComplete
+			sprint.Tickets[issue.Key] = done
+		}
+
+		searchOpts.StartAt += len(issues)
+		if len(issues) == 0 || searchOpts.StartAt >= resp.Total {
+			break
+		}
+	}
+
+	slog.Debug("Fetched JIRA sprint tickets", "sprint", sprint.Name, "tickets", len(sprint.Tickets))
+
+	return sprint, nil
+}
@@ -0,0 +1,192 @@
+package linear
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/jira"
+)
+
+// apiURL is the Linear GraphQL API endpoint
+const apiURL = "https://api.linear.app/graphql"
+
+// FetchOptions contains options for fetching Linear issue information
+type FetchOptions struct {
+	APIKey   string   // Linear personal API key
+	TeamKeys []string // Team keys of the issue identifiers to match in PR titles (e.g., ENG for ENG-123)
+}
+
+// issueQuery fetches an issue by its identifier
+const issueQuery = `query Issue($id: String!) {
+  issue(id: $id) {
+    title
+    url
+    state { name }
+    priority
+    priorityLabel
+    labels { nodes { name } }
+  }
+}`
+
+// issue is the part of a Linear issue used here
+type issue struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	State struct {
+		Name string `json:"name"`
+	} `json:"state"`
+	Priority      int    `json:"priority"`      // 0: no priority, 1 (urgent) to 4 (low)
+	PriorityLabel string `json:"priorityLabel"` // Name of the priority (e.g., "Urgent")
+	Labels        struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+// httpClient is used to call the Linear API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Linear"}).Client(30 * time.Second)
+
+// anyTeamRegex matches identifiers of any team when no team keys are configured
+var anyTeamRegex = regexp.MustCompile(`\b[A-Z][A-Z0-9]{0,6}-\d+\b`)
+
+// IssueIDFromTitle returns the Linear issue identifier referenced in a PR
+// title, or "". Only identifiers of the given teams match, if any are set.
+func IssueIDFromTitle(teamKeys []string, title string) string {
+	if len(teamKeys) == 0 {
+		return anyTeamRegex.FindString(title)
+	}
+
+	var keys []string
+	for _, key := range teamKeys {
+		keys = append(keys, regexp.QuoteMeta(strings.ToUpper(strings.TrimSpace(key))))
+	}
+	return regexp.MustCompile(`\b(` + strings.Join(keys, "|") + `)-\d+\b`).FindString(title)
+}
+
+// FetchIssueInfo fetches the status and title of a single Linear issue
+func FetchIssueInfo(ctx context.Context, opts FetchOptions, issueID string) (*jira.TicketInfo, error) {
+	if issueID == "" {
+		return nil, fmt.Errorf("issue ID is required")
+	}
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("Linear API key is required")
+	}
+
+	slog.Debug("Fetching Linear issue", "ticket", issueID)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     issueQuery,
+		"variables": map[string]string{"id": issueID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", opts.APIKey)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Linear issue %s: %w", issueID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpx.NewStatusError("Linear", resp)
+	}
+
+	var result struct {
+		Data struct {
+			Issue *issue `json:"issue"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding Linear response: %w", err)
+	}
+
+	if result.Data.Issue == nil {
+		if len(result.Errors) > 0 && !strings.Contains(strings.ToLower(result.Errors[0].Message), "not found") {
+			return nil, fmt.Errorf("error fetching Linear issue %s: %s", issueID, result.Errors[0].Message)
+		}
+		return &jira.TicketInfo{
+			TicketID:  issueID,
+			Status:    "Not Found",
+			Summary:   "Issue not found",
+			IsBlocked: false,
+		}, nil
+	}
+
+	ticketInfo := &jira.TicketInfo{
+		TicketID: issueID,
+		Status:   result.Data.Issue.State.Name,
+		Summary:  result.Data.Issue.Title,
+		URL:      result.Data.Issue.URL,
+	}
+	if result.Data.Issue.Priority > 0 {
+		ticketInfo.Priority = result.Data.Issue.PriorityLabel
+	}
+
+	// Check if blocked by state or label name, like JIRA tickets
+	names := []string{result.Data.Issue.State.Name}
+	for _, label := range result.Data.Issue.Labels.Nodes {
+		names = append(names, label.Name)
+	}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if strings.Contains(name, "block") || strings.Contains(name, "impediment") || strings.Contains(name, "pause") {
+			ticketInfo.IsBlocked = true
+			break
+		}
+	}
+
+	slog.Debug("Fetched Linear issue", "ticket", issueID, "status", ticketInfo.Status, "blocked", ticketInfo.IsBlocked)
+
+	return ticketInfo, nil
+}
+
+// FetchIssuesInfo fetches information for multiple Linear issues. It stops
+// with the context's error once ctx is done.
+func FetchIssuesInfo(ctx context.Context, opts FetchOptions, issueIDs []string) (map[string]*jira.TicketInfo, error) {
+	results := make(map[string]*jira.TicketInfo)
+
+	for _, issueID := range issueIDs {
+		if issueID == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, fmt.Errorf("error fetching Linear issues: %v", err)
+		}
+
+		ticketInfo, err := FetchIssueInfo(ctx, opts, issueID)
+		if err != nil {
+			slog.Warn("Could not fetch Linear issue", "ticket", issueID, "error", err)
+			results[issueID] = &jira.TicketInfo{
+				TicketID:  issueID,
+				Status:    "Error",
+				Summary:   fmt.Sprintf("Error: %v", err),
+				IsBlocked: false,
+			}
+			continue
+		}
+
+		results[issueID] = ticketInfo
+	}
+
+	return results, nil
+}
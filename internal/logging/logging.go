@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"pr-reporter/internal/config"
+	"pr-reporter/internal/errkind"
+)
+
+// Setup makes log/slog (and the standard log package, at info level) write
+// leveled records to stderr. LOG_LEVEL picks the lowest level logged (debug,
+// info, warn or error; default info, or debug with DEBUG=true) and LOG_FORMAT
+// the handler (text or json; default text). Call it after loading the .env
+// file.
+func Setup() {
+	level, levelErr := parseLevel()
+	handlerOpts := &slog.HandlerOptions{Level: level}
+
+	format := strings.ToLower(strings.TrimSpace(config.String("LOG_FORMAT")))
+	if format == "json" {
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, handlerOpts)))
+	} else {
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, handlerOpts)))
+	}
+
+	if levelErr != nil {
+		slog.Warn("Invalid LOG_LEVEL, using info", "level", config.String("LOG_LEVEL"))
+	}
+	if format != "" && format != "json" && format != "text" {
+		slog.Warn("Unknown LOG_FORMAT, using text", "format", format)
+	}
+}
+
+// parseLevel reads LOG_LEVEL, falling back to DEBUG for existing configurations
+func parseLevel() (slog.Level, error) {
+	value := strings.TrimSpace(config.String("LOG_LEVEL"))
+	if value == "" {
+		if config.Bool("DEBUG") {
+			return slog.LevelDebug, nil
+		}
+		return slog.LevelInfo, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return slog.LevelInfo, err
+	}
+	return level, nil
+}
+
+// Debug reports whether debug records are logged, for checks that are only
+// worth their API calls when their result is logged
+func Debug() bool {
+	return slog.Default().Enabled(context.Background(), slog.LevelDebug)
+}
+
+// Fatal logs an error record and exits with status 1
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// Exit logs an error record for err and exits with the status of its
+// category (see errkind.ExitCode)
+func Exit(msg string, err error, args ...any) {
+	code := errkind.ExitCode(err)
+	slog.Error(msg, append(args, "error", err, "exit_code", code)...)
+	os.Exit(code)
+}
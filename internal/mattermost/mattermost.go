@@ -0,0 +1,205 @@
+package mattermost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/model"
+)
+
+// Options contains options for posting a PR report to Mattermost
+type Options struct {
+	URL         string            // Mattermost server URL (e.g., "https://chat.example.com")
+	Token       string            // Bot or personal access token
+	Channel     string            // Channel ID to post to
+	UserMapping map[string]string // GitHub username -> Mattermost username, for mentions
+}
+
+// Configured reports whether the options contain a Mattermost destination
+func (o Options) Configured() bool {
+	return o.URL != "" && o.Token != "" && o.Channel != ""
+}
+
+// maxMessageLength stays below the 16383 character limit of a Mattermost post
+const maxMessageLength = 16000
+
+// httpClient is used to call the Mattermost API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Mattermost"}).Client(30 * time.Second)
+
+// SendReport posts the report to a Mattermost channel. Reports too long for
+// a single post are split, with the remaining parts posted as replies.
+func SendReport(ctx context.Context, opts Options, report model.Report) error {
+	if !opts.Configured() {
+		return fmt.Errorf("Mattermost URL, token and channel are required")
+	}
+
+	parts := splitMessage(formatReport(opts, report), maxMessageLength)
+
+	var rootID string
+	for i, part := range parts {
+		id, err := createPost(ctx, opts, part, rootID)
+		if err != nil {
+			return fmt.Errorf("error posting part %d/%d to Mattermost: %w", i+1, len(parts), err)
+		}
+		if rootID == "" {
+			rootID = id
+		}
+
+		slog.Debug("Posted Mattermost part", "part", i+1, "parts", len(parts), "id", id)
+	}
+
+	return nil
+}
+
+// formatReport renders the report as Mattermost Markdown
+func formatReport(opts Options, report model.Report) string {
+	text := report.Text()
+
+	var b strings.Builder
+	if report.Title != "" {
+		fmt.Fprintf(&b, "#### 📋 %s\n", report.Title)
+	}
+	fmt.Fprintf(&b, "📅 %s\n", report.Date.Format("2006-01-02"))
+	fmt.Fprintf(&b, "📊 **%s: %d**\n\n", text.TotalOpenPRs, len(report.PRs))
+
+	for i, pr := range report.PRs {
+		b.WriteString(formatPR(opts, report, text, i+1, pr))
+		b.WriteString("\n")
+	}
+
+	link := func(pr *model.PR) string {
+		return fmt.Sprintf("[PR-%d](%s)", pr.Number, report.PRURL(pr))
+	}
+
+	blocked, drafts := report.Blocked(), report.Drafts()
+	b.WriteString("\n")
+	if len(blocked) > 0 {
+		fmt.Fprintf(&b, "🚫 **%s:** %s\n", text.Blocked, joinPRs(blocked, link))
+	}
+	if len(drafts) > 0 {
+		fmt.Fprintf(&b, "📝 **%s:** %s\n", text.Draft, joinPRs(drafts, link))
+	}
+	if len(blocked) == 0 && len(drafts) == 0 {
+		fmt.Fprintf(&b, "✅ **%s:** %s\n", text.BlockedOrDraft, text.None)
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatPR formats a single PR line with its JIRA ticket and assignee
+func formatPR(opts Options, report model.Report, text model.Strings, index int, pr *model.PR) string {
+	description := pr.Description
+	if description == "" {
+		description = text.NoDescription
+	}
+
+	jira := text.None
+	if pr.JiraTicket != "" {
+		jira = pr.JiraTicket
+		if url := report.TicketURL(pr); url != "" {
+			jira = fmt.Sprintf("[%s](%s)", pr.JiraTicket, url)
+		}
+	}
+
+	status := pr.JiraStatus
+	if status == "" {
+		status = text.UnknownStatus
+	}
+
+	line := fmt.Sprintf("%d. [PR-%d](%s) %s · %s · **%s** · %s", index, pr.Number, report.PRURL(pr), description, jira, status, mention(opts, text, pr.GithubAssignee))
+	if pr.IsBlocked {
+		line += " · 🚫"
+	}
+	if pr.IsDraft {
+		line += " · 📝"
+	}
+
+	return line
+}
+
+// mention returns an @-mention for a mapped GitHub user, or the GitHub username
+func mention(opts Options, text model.Strings, githubUser string) string {
+	if githubUser == "" {
+		return text.Unassigned
+	}
+	if username, exists := opts.UserMapping[githubUser]; exists && username != "" {
+		return "@" + strings.TrimPrefix(username, "@")
+	}
+	return githubUser
+}
+
+// joinPRs formats PRs with format and joins them with commas
+func joinPRs(prs []*model.PR, format func(*model.PR) string) string {
+	var result []string
+	for _, pr := range prs {
+		result = append(result, format(pr))
+	}
+	return strings.Join(result, ", ")
+}
+
+// splitMessage splits content on line boundaries into parts of at most maxLength characters
+func splitMessage(content string, maxLength int) []string {
+	var parts []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(content, "\n") {
+		if current.Len() > 0 && current.Len()+len(line)+1 > maxLength {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+
+	return parts
+}
+
+// createPost creates a post, as a reply when rootID is set, and returns its ID
+func createPost(ctx context.Context, opts Options, message, rootID string) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"channel_id": opts.Channel,
+		"message":    message,
+		"root_id":    rootID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(opts.URL, "/")+"/api/v4/posts", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+opts.Token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", httpx.NewStatusError("Mattermost", resp)
+	}
+
+	var post struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&post); err != nil {
+		return "", fmt.Errorf("error decoding Mattermost response: %w", err)
+	}
+
+	return post.ID, nil
+}
@@ -0,0 +1,59 @@
+package model
+
+// AnomalyDays is the trailing window of daily reports the current counts are
+// compared with
+const AnomalyDays = 7
+
+// anomalyMinDays is how many earlier days are needed to detect anomalies
+const anomalyMinDays = 3
+
+// anomalyMinIncrease keeps small queues from alerting on every new PR
+const anomalyMinIncrease = 2
+
+// Anomaly metrics
+const (
+	MetricOpen    = "open"
+	MetricBlocked = "blocked"
+)
+
+// Anomaly is a count that grew unusually compared with its trailing average
+type Anomaly struct {
+	Metric  string  // MetricOpen or MetricBlocked
+	Current int     // Count in the current report
+	Average float64 // Average of the trailing daily counts
+}
+
+// Increase returns how much the current count exceeds the average, as a
+// fraction of the average (e.g., 0.5 for 50%)
+func (a Anomaly) Increase() float64 {
+	if a.Average == 0 {
+		return 0
+	}
+	return (float64(a.Current) - a.Average) / a.Average
+}
+
+// DetectAnomalies compares the current open and blocked PR counts with the
+// averages of their trailing daily counts (oldest first). A count is
+// anomalous when it exceeds its average by more than threshold (e.g., 0.5 for
+// 50%) and by at least two PRs. Nothing is detected with fewer than three
+// earlier days.
+func DetectAnomalies(openHistory, blockedHistory []int, open, blocked int, threshold float64) []Anomaly {
+	var anomalies []Anomaly
+	check := func(metric string, history []int, current int) {
+		if len(history) < anomalyMinDays {
+			return
+		}
+		sum := 0
+		for _, count := range history {
+			sum += count
+		}
+		average := float64(sum) / float64(len(history))
+		if float64(current)-average >= anomalyMinIncrease && float64(current) > average*(1+threshold) {
+			anomalies = append(anomalies, Anomaly{Metric: metric, Current: current, Average: average})
+		}
+	}
+
+	check(MetricOpen, openHistory, open)
+	check(MetricBlocked, blockedHistory, blocked)
+	return anomalies
+}
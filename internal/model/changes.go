@@ -0,0 +1,82 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// Changes is what changed in a report's PRs since the previous report
+type Changes struct {
+	Since       time.Time    // When the previous report ran
+	Opened      []*PR        // PRs that weren't in the previous report
+	Closed      []*PR        // PRs of the previous report that were merged or closed since, as they were then
+	Transitions []Transition // PRs whose draft state or ticket status changed
+}
+
+// Transition is a change of a PR between two reports
+type Transition struct {
+	PR   *PR    // The PR as it is now
+	From string // Previous state (e.g., "Draft" or a JIRA status)
+	To   string // Current state
+}
+
+// Empty reports whether nothing changed
+func (c *Changes) Empty() bool {
+	return c == nil || len(c.Opened) == 0 && len(c.Closed) == 0 && len(c.Transitions) == 0
+}
+
+// MarkStatusChanges sets the previous JIRA status of the current PRs whose
+// status changed since the previous report
+func MarkStatusChanges(previous, current []*PR) {
+	before := make(map[int]string, len(previous))
+	for _, pr := range previous {
+		before[pr.Number] = pr.JiraStatus
+	}
+
+	for _, pr := range current {
+		if old := before[pr.Number]; old != "" && pr.JiraStatus != "" && old != pr.JiraStatus {
+			pr.PreviousStatus = old
+		}
+	}
+}
+
+// CompareReports returns what changed from the PRs of a previous report to
+// the current ones. Draft states are described with the locale's text.
+func CompareReports(previous, current []*PR, since time.Time, text Strings) *Changes {
+	changes := &Changes{Since: since}
+
+	before := make(map[int]*PR, len(previous))
+	for _, pr := range previous {
+		before[pr.Number] = pr
+	}
+
+	open := make(map[int]bool, len(current))
+	for _, pr := range current {
+		open[pr.Number] = true
+
+		old, existed := before[pr.Number]
+		if !existed {
+			changes.Opened = append(changes.Opened, pr)
+			continue
+		}
+
+		switch {
+		case old.IsDraft && !pr.IsDraft:
+			changes.Transitions = append(changes.Transitions, Transition{PR: pr, From: text.DraftState, To: text.ReadyState})
+		case !old.IsDraft && pr.IsDraft:
+			changes.Transitions = append(changes.Transitions, Transition{PR: pr, From: text.ReadyState, To: text.DraftState})
+		}
+		if old.JiraStatus != "" && pr.JiraStatus != "" && old.JiraStatus != pr.JiraStatus {
+			changes.Transitions = append(changes.Transitions, Transition{PR: pr, From: old.JiraStatus, To: pr.JiraStatus})
+		}
+	}
+
+	for _, pr := range previous {
+		if !open[pr.Number] {
+			changes.Closed = append(changes.Closed, pr)
+		}
+	}
+	sort.Slice(changes.Closed, func(i, j int) bool { return changes.Closed[i].Number < changes.Closed[j].Number })
+
+	return changes
+}
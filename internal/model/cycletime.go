@@ -0,0 +1,49 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// CycleTime summarizes how long the PRs merged during a month took from
+// opening to merge
+type CycleTime struct {
+	Month    time.Time     // First day of the month
+	Merged   int           // PRs merged during the month
+	Median   time.Duration // Median time from opening to merge
+	P75      time.Duration // 75th percentile
+	P90      time.Duration // 90th percentile
+	Previous *CycleTime    // The month before, for comparison (nil: no merges recorded)
+}
+
+// NewCycleTime computes the cycle time percentiles of a month from the
+// cycle times of its merged PRs. It returns nil when there were no merges.
+func NewCycleTime(month time.Time, cycleTimes []time.Duration) *CycleTime {
+	if len(cycleTimes) == 0 {
+		return nil
+	}
+
+	sorted := make([]time.Duration, len(cycleTimes))
+	for i, d := range cycleTimes {
+		sorted[i] = nonNegative(d)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &CycleTime{
+		Month:  month,
+		Merged: len(sorted),
+		Median: percentile(sorted, 0.5),
+		P75:    percentile(sorted, 0.75),
+		P90:    percentile(sorted, 0.9),
+	}
+}
+
+// percentile returns the nearest-rank percentile p (0-1) of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
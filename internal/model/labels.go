@@ -0,0 +1,59 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// LabelBreakdownDays is how far back the label breakdown compares the open
+// PR counts
+const LabelBreakdownDays = 7
+
+// LabelCount is the number of open PRs with a label
+type LabelCount struct {
+	Label    string // Label as configured (e.g., "hotfix")
+	Open     int    // Open PRs with the label
+	Previous int    // Open PRs with the label at the compared report
+}
+
+// Change returns how many more (or fewer) PRs have the label than at the
+// compared report
+func (c LabelCount) Change() int {
+	return c.Open - c.Previous
+}
+
+// LabelBreakdown counts the open PRs of tracked labels, compared with an
+// earlier report
+type LabelBreakdown struct {
+	Labels   []LabelCount // Tracked labels, in configured order
+	Compared time.Time    // When the compared report ran (zero: no earlier report)
+}
+
+// NewLabelBreakdown counts the open and previously open PRs of each label.
+// Labels match case-insensitively; a PR counts once per tracked label it has.
+// previous is nil when there is no earlier report to compare with.
+func NewLabelBreakdown(labels []string, open, previous []*PR, compared time.Time) *LabelBreakdown {
+	breakdown := &LabelBreakdown{Compared: compared}
+	for _, label := range labels {
+		breakdown.Labels = append(breakdown.Labels, LabelCount{
+			Label:    label,
+			Open:     countLabel(open, label),
+			Previous: countLabel(previous, label),
+		})
+	}
+	return breakdown
+}
+
+// countLabel counts the PRs with a label, ignoring case
+func countLabel(prs []*PR, label string) int {
+	count := 0
+	for _, pr := range prs {
+		for _, prLabel := range pr.Labels {
+			if strings.EqualFold(prLabel, label) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,542 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// Strings holds the translatable text of a report, shared by all outputs. JIRA statuses, PR titles
+// and descriptions are shown as they come from JIRA and GitHub.
+type Strings struct {
+	TotalOpenPRs     string // Header before the open PR count
+	Updated          string // Before the refresh time of live status messages
+	AssignedTo       string // Between the PR link and its assignee
+	Unassigned       string // In place of a missing assignee
+	NoDescription    string // In place of a missing description
+	UnknownStatus    string // In place of a missing JIRA status
+	Blocked          string // Blocked PR summary title
+	Draft            string // Draft PR summary title
+	BlockedOrDraft   string // Summary title when nothing is blocked or draft
+	BlockedAndDraft  string // Note after PRs that are both blocked and draft
+	None             string // Shown when a summary has no entries
+	Snoozed          string // Snoozed PR summary title
+	NeedsAttention   string // Title of the targeted mention section
+	OpenLongerThan   string // Before the age of PRs open long enough to be escalated (e.g., "Open for more than 7d")
+	ReasonBlocked    string // Attention reasons
+	ReasonStale      string
+	ReasonUnreviewed string
+	CallToAction     string // After the team mention
+	Continued        string // Note on the second and later parts of a split report
+	Changes          string // Title of the section listing changes since the previous report
+	Opened           string // New PRs since the previous report
+	Closed           string // PRs merged or closed since the previous report
+	Transitions      string // PRs whose draft state or ticket status changed
+	DraftState       string // State of a draft PR in transitions
+	ReadyState       string // State of a PR ready for review in transitions
+	AuthorStats      string // Title of the per-author stats table
+	Author           string // Stats table column headers
+	Open             string
+	AverageAge       string
+	Oldest           string
+	ClosedRecently   string
+	ReviewTurnaround string // Title of the average review turnaround line
+	FirstReview      string // Time to the first review in the turnaround line
+	Approval         string // Time to the first approval in the turnaround line
+	WeeklySummary    string // Title of the weekly summary before its date range
+	Merged           string // PRs merged during the week
+	CycleTime        string // Average time from opening to merge
+	Pending          string // Open PRs waiting for more than a week
+	BlockedTickets   string // Number of blocked tickets
+	Leaderboard      string // Title of the reviewer leaderboard
+	SLABreached      string // Title of review SLA breach alerts
+	SLACompliance    string // Share of review steps within their SLA over the last 30 days
+	MergeRate        string // Title of the merge rate line
+	MergedPerDay     string // Average PRs merged per day
+	OpenedPerDay     string // Average PRs opened per day
+	QueueDraining    string // More PRs merged than opened
+	QueueGrowing     string // More PRs opened than merged
+	QueueSteady      string // As many PRs merged as opened
+	MergedByDay      string // PRs merged on each day of the merge rate window
+	StillBlocked     string // Note on PRs blocked for so long their assignee isn't mentioned anymore
+	Suggested        string // Before the reviewer suggested for a PR without assignee or requested reviewers
+	DataIssues       string // Title of the list of data that couldn't be fetched for the report
+	SprintBurndown   string // Title of the sprint burn-down before the sprint name
+	SprintEnds       string // Before the planned end date of the sprint
+	SprintTickets    string // Tickets in the sprint
+	SprintDone       string // Sprint tickets completed
+	SprintInReview   string // Sprint tickets with open PRs
+	SprintNoPR       string // Sprint tickets without open PRs
+	OutsideSprint    string // Open PRs linked to tickets outside the sprint
+	MonthlyCycleTime string // Title of the monthly cycle time before the month
+	Median           string // Median of the cycle times
+	MergedPRs        string // Number of PRs merged during the month
+	MonthlyRetro     string // Title of the monthly retrospective before the month
+	OpenedPRs        string // PRs opened during the month
+	OpenAge          string // Average age of the open PRs
+	TopBlockers      string // Blocked PRs open the longest
+	LongestOpen      string // Open PR opened the longest ago
+	Age              string // Before the open PRs by age range
+	ReviewLoad       string // Title of the outstanding review requests per reviewer
+	PreviousReport   string // Link to the report posted on an earlier day
+	ByLabel          string // Title of the open PRs by label
+	ComparedWith     string // Before the date of the report the label counts are compared with
+	SprintClosed     string // After the name of the sprint that just closed
+	StoriesDone      string // Completed tickets of the closed sprint
+	PRsPerStory      string // Average PRs per completed ticket
+	CarryoverPRs     string // Open PRs of the sprint's tickets carried over to the next sprint
+	UnusualGrowth    string // Warning before counts that grew unusually
+	OpenCount        string // After the open PR count in the growth warning
+	BlockedCount     string // After the blocked PR count in the growth warning
+	Avg              string // Before the trailing average in the growth warning
+}
+
+// DefaultLocale is used when no locale is configured or the locale is unknown
+const DefaultLocale = "en"
+
+// locales holds the built-in translations keyed by language code
+var locales = map[string]Strings{
+	"en": {
+		TotalOpenPRs:     "Total Open PRs",
+		Updated:          "Updated",
+		AssignedTo:       "assigned to",
+		Unassigned:       "unassigned",
+		NoDescription:    "No description",
+		UnknownStatus:    "Unknown",
+		Blocked:          "Blocked",
+		Draft:            "Draft",
+		BlockedOrDraft:   "Blocked/Draft",
+		BlockedAndDraft:  "Blocked & Draft",
+		None:             "N/A",
+		Snoozed:          "Snoozed",
+		NeedsAttention:   "Needs attention",
+		OpenLongerThan:   "Open for more than",
+		ReasonBlocked:    "blocked",
+		ReasonStale:      "stale",
+		ReasonUnreviewed: "unreviewed",
+		CallToAction:     "Please make sure to review these pull requests!",
+		Continued:        "continued",
+		Changes:          "Since the last report",
+		Opened:           "New",
+		Closed:           "Merged or closed",
+		Transitions:      "Status changes",
+		DraftState:       "Draft",
+		ReadyState:       "Ready for review",
+		AuthorStats:      "Per-author stats",
+		Author:           "Author",
+		Open:             "Open",
+		AverageAge:       "Avg age",
+		Oldest:           "Oldest",
+		ClosedRecently:   "Closed (7d)",
+		ReviewTurnaround: "Review turnaround",
+		FirstReview:      "first review",
+		Approval:         "approval",
+		WeeklySummary:    "Weekly summary",
+		Merged:           "Merged",
+		CycleTime:        "avg cycle time",
+		Pending:          "Pending for more than 7 days",
+		BlockedTickets:   "Blocked tickets",
+		Leaderboard:      "Review leaderboard",
+		SLABreached:      "Review SLA breached",
+		SLACompliance:    "SLA compliance (30 days)",
+		MergeRate:        "Merge rate (7 days)",
+		MergedPerDay:     "merged/day",
+		OpenedPerDay:     "opened/day",
+		QueueDraining:    "queue draining",
+		QueueGrowing:     "queue growing",
+		QueueSteady:      "queue steady",
+		MergedByDay:      "Merged per day",
+		StillBlocked:     "still blocked",
+		Suggested:        "suggested reviewer",
+		DataIssues:       "Data issues",
+		SprintBurndown:   "Sprint burn-down",
+		SprintEnds:       "ends",
+		SprintTickets:    "tickets",
+		SprintDone:       "done",
+		SprintInReview:   "with open PRs",
+		SprintNoPR:       "without PRs",
+		OutsideSprint:    "open PRs outside the sprint",
+		MonthlyCycleTime: "Cycle time",
+		Median:           "median",
+		MergedPRs:        "merged",
+		MonthlyRetro:     "Monthly retrospective",
+		OpenedPRs:        "Opened",
+		OpenAge:          "Average age of open PRs",
+		TopBlockers:      "Top blockers",
+		LongestOpen:      "Longest open",
+		Age:              "Age",
+		ReviewLoad:       "Pending reviews",
+		PreviousReport:   "Previous report",
+		ByLabel:          "Open PRs by label",
+		ComparedWith:     "vs.",
+		SprintClosed:     "closed",
+		StoriesDone:      "stories done",
+		PRsPerStory:      "PRs per story",
+		CarryoverPRs:     "carryover PRs",
+		UnusualGrowth:    "Unusual backlog growth",
+		OpenCount:        "open PRs",
+		BlockedCount:     "blocked PRs",
+		Avg:              "avg",
+	},
+	"bg": {
+		TotalOpenPRs:     "Общо отворени PR-и",
+		Updated:          "Обновено",
+		AssignedTo:       "възложен на",
+		Unassigned:       "невъзложен",
+		NoDescription:    "Няма описание",
+		UnknownStatus:    "Неизвестен",
+		Blocked:          "Блокирани",
+		Draft:            "Чернови",
+		BlockedOrDraft:   "Блокирани/Чернови",
+		BlockedAndDraft:  "Блокиран и чернова",
+		None:             "Няма",
+		Snoozed:          "Отложени",
+		NeedsAttention:   "Нуждаят се от внимание",
+		OpenLongerThan:   "Отворени повече от",
+		ReasonBlocked:    "блокиран",
+		ReasonStale:      "застоял",
+		ReasonUnreviewed: "без преглед",
+		CallToAction:     "Моля, прегледайте тези pull request-и!",
+		Continued:        "продължение",
+		Changes:          "От последния отчет",
+		Opened:           "Нови",
+		Closed:           "Слети или затворени",
+		Transitions:      "Промени в статуса",
+		DraftState:       "Чернова",
+		ReadyState:       "Готов за преглед",
+		AuthorStats:      "Статистика по автор",
+		Author:           "Автор",
+		Open:             "Отворени",
+		AverageAge:       "Ср. възраст",
+		Oldest:           "Най-стар",
+		ClosedRecently:   "Затворени (7д)",
+		ReviewTurnaround: "Време за преглед",
+		FirstReview:      "първи преглед",
+		Approval:         "одобрение",
+		WeeklySummary:    "Седмично обобщение",
+		Merged:           "Слети",
+		CycleTime:        "ср. време до сливане",
+		Pending:          "Чакащи повече от 7 дни",
+		BlockedTickets:   "Блокирани задачи",
+		Leaderboard:      "Класация по прегледи",
+		SLABreached:      "Нарушен SLA за преглед",
+		SLACompliance:    "Спазване на SLA (30 дни)",
+		MergeRate:        "Скорост на сливане (7 дни)",
+		MergedPerDay:     "слети/ден",
+		OpenedPerDay:     "отворени/ден",
+		QueueDraining:    "опашката намалява",
+		QueueGrowing:     "опашката расте",
+		QueueSteady:      "опашката е стабилна",
+		MergedByDay:      "Слети по дни",
+		StillBlocked:     "все още блокиран",
+		Suggested:        "предложен рецензент",
+		DataIssues:       "Проблеми с данните",
+		SprintBurndown:   "Напредък на спринта",
+		SprintEnds:       "приключва",
+		SprintTickets:    "задачи",
+		SprintDone:       "готови",
+		SprintInReview:   "с отворени PR",
+		SprintNoPR:       "без PR",
+		OutsideSprint:    "отворени PR извън спринта",
+		MonthlyCycleTime: "Време за сливане",
+		Median:           "медиана",
+		MergedPRs:        "слети",
+		MonthlyRetro:     "Месечна ретроспекция",
+		OpenedPRs:        "Отворени",
+		OpenAge:          "Средна възраст на отворените PR-и",
+		TopBlockers:      "Най-дълго блокирани",
+		LongestOpen:      "Най-дълго отворен",
+		Age:              "Възраст",
+		ReviewLoad:       "Чакащи ревюта",
+		PreviousReport:   "Предишен отчет",
+		ByLabel:          "Отворени PR-и по етикет",
+		ComparedWith:     "спрямо",
+		SprintClosed:     "приключи",
+		StoriesDone:      "завършени истории",
+		PRsPerStory:      "PR-а на история",
+		CarryoverPRs:     "пренесени PR-а",
+		UnusualGrowth:    "Необичаен ръст на опашката",
+		OpenCount:        "отворени PR-а",
+		BlockedCount:     "блокирани PR-а",
+		Avg:              "ср.",
+	},
+	"de": {
+		TotalOpenPRs:     "Offene PRs insgesamt",
+		Updated:          "Aktualisiert",
+		AssignedTo:       "zugewiesen an",
+		Unassigned:       "nicht zugewiesen",
+		NoDescription:    "Keine Beschreibung",
+		UnknownStatus:    "Unbekannt",
+		Blocked:          "Blockiert",
+		Draft:            "Entwurf",
+		BlockedOrDraft:   "Blockiert/Entwurf",
+		BlockedAndDraft:  "Blockiert & Entwurf",
+		None:             "k. A.",
+		Snoozed:          "Zurückgestellt",
+		NeedsAttention:   "Braucht Aufmerksamkeit",
+		OpenLongerThan:   "Offen seit mehr als",
+		ReasonBlocked:    "blockiert",
+		ReasonStale:      "veraltet",
+		ReasonUnreviewed: "ohne Review",
+		CallToAction:     "Bitte schaut euch diese Pull Requests an!",
+		Continued:        "Fortsetzung",
+		Changes:          "Seit dem letzten Bericht",
+		Opened:           "Neu",
+		Closed:           "Gemergt oder geschlossen",
+		Transitions:      "Statusänderungen",
+		DraftState:       "Entwurf",
+		ReadyState:       "Bereit für Review",
+		AuthorStats:      "Statistik pro Autor",
+		Author:           "Autor",
+		Open:             "Offen",
+		AverageAge:       "Ø Alter",
+		Oldest:           "Ältester",
+		ClosedRecently:   "Geschlossen (7T)",
+		ReviewTurnaround: "Review-Durchlaufzeit",
+		FirstReview:      "erstes Review",
+		Approval:         "Freigabe",
+		WeeklySummary:    "Wochenübersicht",
+		Merged:           "Gemergt",
+		CycleTime:        "Ø Durchlaufzeit",
+		Pending:          "Seit mehr als 7 Tagen offen",
+		BlockedTickets:   "Blockierte Tickets",
+		Leaderboard:      "Review-Rangliste",
+		SLABreached:      "Review-SLA überschritten",
+		SLACompliance:    "SLA-Einhaltung (30 Tage)",
+		MergeRate:        "Merge-Rate (7 Tage)",
+		MergedPerDay:     "gemergt/Tag",
+		OpenedPerDay:     "geöffnet/Tag",
+		QueueDraining:    "Warteschlange schrumpft",
+		QueueGrowing:     "Warteschlange wächst",
+		QueueSteady:      "Warteschlange stabil",
+		MergedByDay:      "Gemergt pro Tag",
+		StillBlocked:     "immer noch blockiert",
+		Suggested:        "vorgeschlagener Reviewer",
+		DataIssues:       "Datenprobleme",
+		SprintBurndown:   "Sprint-Burn-down",
+		SprintEnds:       "endet",
+		SprintTickets:    "Tickets",
+		SprintDone:       "erledigt",
+		SprintInReview:   "mit offenen PRs",
+		SprintNoPR:       "ohne PRs",
+		OutsideSprint:    "offene PRs außerhalb des Sprints",
+		MonthlyCycleTime: "Durchlaufzeit",
+		Median:           "Median",
+		MergedPRs:        "gemergt",
+		MonthlyRetro:     "Monatsrückblick",
+		OpenedPRs:        "Geöffnet",
+		OpenAge:          "Durchschnittsalter offener PRs",
+		TopBlockers:      "Größte Blocker",
+		LongestOpen:      "Am längsten offen",
+		Age:              "Alter",
+		ReviewLoad:       "Offene Reviews",
+		PreviousReport:   "Vorheriger Bericht",
+		ByLabel:          "Offene PRs nach Label",
+		ComparedWith:     "vs.",
+		SprintClosed:     "abgeschlossen",
+		StoriesDone:      "Stories erledigt",
+		PRsPerStory:      "PRs pro Story",
+		CarryoverPRs:     "übertragene PRs",
+		UnusualGrowth:    "Ungewöhnliches Backlog-Wachstum",
+		OpenCount:        "offene PRs",
+		BlockedCount:     "blockierte PRs",
+		Avg:              "Ø",
+	},
+	"es": {
+		TotalOpenPRs:     "Total de PRs abiertos",
+		Updated:          "Actualizado",
+		AssignedTo:       "asignado a",
+		Unassigned:       "sin asignar",
+		NoDescription:    "Sin descripción",
+		UnknownStatus:    "Desconocido",
+		Blocked:          "Bloqueados",
+		Draft:            "Borradores",
+		BlockedOrDraft:   "Bloqueados/Borradores",
+		BlockedAndDraft:  "Bloqueado y borrador",
+		None:             "N/D",
+		Snoozed:          "Pospuestos",
+		NeedsAttention:   "Requieren atención",
+		OpenLongerThan:   "Abiertas hace más de",
+		ReasonBlocked:    "bloqueado",
+		ReasonStale:      "inactivo",
+		ReasonUnreviewed: "sin revisar",
+		CallToAction:     "¡Por favor, revisad estos pull requests!",
+		Continued:        "continuación",
+		Changes:          "Desde el último informe",
+		Opened:           "Nuevos",
+		Closed:           "Fusionados o cerrados",
+		Transitions:      "Cambios de estado",
+		DraftState:       "Borrador",
+		ReadyState:       "Listo para revisión",
+		AuthorStats:      "Estadísticas por autor",
+		Author:           "Autor",
+		Open:             "Abiertos",
+		AverageAge:       "Edad media",
+		Oldest:           "Más antiguo",
+		ClosedRecently:   "Cerrados (7d)",
+		ReviewTurnaround: "Tiempo de revisión",
+		FirstReview:      "primera revisión",
+		Approval:         "aprobación",
+		WeeklySummary:    "Resumen semanal",
+		Merged:           "Fusionados",
+		CycleTime:        "tiempo de ciclo medio",
+		Pending:          "Pendientes desde hace más de 7 días",
+		BlockedTickets:   "Tickets bloqueados",
+		Leaderboard:      "Clasificación de revisiones",
+		SLABreached:      "SLA de revisión incumplido",
+		SLACompliance:    "Cumplimiento del SLA (30 días)",
+		MergeRate:        "Ritmo de fusión (7 días)",
+		MergedPerDay:     "fusionados/día",
+		OpenedPerDay:     "abiertos/día",
+		QueueDraining:    "la cola disminuye",
+		QueueGrowing:     "la cola crece",
+		QueueSteady:      "la cola se mantiene",
+		MergedByDay:      "Fusionados por día",
+		StillBlocked:     "sigue bloqueado",
+		Suggested:        "revisor sugerido",
+		DataIssues:       "Problemas con los datos",
+		SprintBurndown:   "Avance del sprint",
+		SprintEnds:       "termina",
+		SprintTickets:    "tickets",
+		SprintDone:       "completados",
+		SprintInReview:   "con PRs abiertos",
+		SprintNoPR:       "sin PRs",
+		OutsideSprint:    "PRs abiertos fuera del sprint",
+		MonthlyCycleTime: "Tiempo de ciclo",
+		Median:           "mediana",
+		MergedPRs:        "fusionados",
+		MonthlyRetro:     "Retrospectiva mensual",
+		OpenedPRs:        "Abiertos",
+		OpenAge:          "Antigüedad media de los PRs abiertos",
+		TopBlockers:      "Principales bloqueos",
+		LongestOpen:      "Abierto más tiempo",
+		Age:              "Antigüedad",
+		ReviewLoad:       "Revisiones pendientes",
+		PreviousReport:   "Informe anterior",
+		ByLabel:          "PRs abiertos por etiqueta",
+		ComparedWith:     "vs.",
+		SprintClosed:     "cerrado",
+		StoriesDone:      "historias completadas",
+		PRsPerStory:      "PRs por historia",
+		CarryoverPRs:     "PRs arrastrados",
+		UnusualGrowth:    "Crecimiento inusual del backlog",
+		OpenCount:        "PRs abiertos",
+		BlockedCount:     "PRs bloqueados",
+		Avg:              "media",
+	},
+	"fr": {
+		TotalOpenPRs:     "Total des PR ouvertes",
+		Updated:          "Mis à jour",
+		AssignedTo:       "assignée à",
+		Unassigned:       "non assignée",
+		NoDescription:    "Pas de description",
+		UnknownStatus:    "Inconnu",
+		Blocked:          "Bloquées",
+		Draft:            "Brouillons",
+		BlockedOrDraft:   "Bloquées/Brouillons",
+		BlockedAndDraft:  "Bloquée & brouillon",
+		None:             "N/A",
+		Snoozed:          "En pause",
+		NeedsAttention:   "À traiter",
+		OpenLongerThan:   "Ouvertes depuis plus de",
+		ReasonBlocked:    "bloquée",
+		ReasonStale:      "inactive",
+		ReasonUnreviewed: "non relue",
+		CallToAction:     "Merci de relire ces pull requests !",
+		Continued:        "suite",
+		Changes:          "Depuis le dernier rapport",
+		Opened:           "Nouvelles",
+		Closed:           "Fusionnées ou fermées",
+		Transitions:      "Changements de statut",
+		DraftState:       "Brouillon",
+		ReadyState:       "Prête pour relecture",
+		AuthorStats:      "Statistiques par auteur",
+		Author:           "Auteur",
+		Open:             "Ouvertes",
+		AverageAge:       "Âge moyen",
+		Oldest:           "Plus ancienne",
+		ClosedRecently:   "Fermées (7j)",
+		ReviewTurnaround: "Délai de relecture",
+		FirstReview:      "première relecture",
+		Approval:         "approbation",
+		WeeklySummary:    "Résumé hebdomadaire",
+		Merged:           "Fusionnées",
+		CycleTime:        "durée de cycle moyenne",
+		Pending:          "En attente depuis plus de 7 jours",
+		BlockedTickets:   "Tickets bloqués",
+		Leaderboard:      "Classement des relectures",
+		SLABreached:      "SLA de relecture dépassé",
+		SLACompliance:    "Respect du SLA (30 jours)",
+		MergeRate:        "Rythme de fusion (7 jours)",
+		MergedPerDay:     "fusionnées/jour",
+		OpenedPerDay:     "ouvertes/jour",
+		QueueDraining:    "la file diminue",
+		QueueGrowing:     "la file grossit",
+		QueueSteady:      "la file est stable",
+		MergedByDay:      "Fusionnées par jour",
+		StillBlocked:     "toujours bloquée",
+		Suggested:        "relecteur suggéré",
+		DataIssues:       "Problèmes de données",
+		SprintBurndown:   "Avancement du sprint",
+		SprintEnds:       "se termine le",
+		SprintTickets:    "tickets",
+		SprintDone:       "terminés",
+		SprintInReview:   "avec des PR ouvertes",
+		SprintNoPR:       "sans PR",
+		OutsideSprint:    "PR ouvertes hors du sprint",
+		MonthlyCycleTime: "Temps de cycle",
+		Median:           "médiane",
+		MergedPRs:        "fusionnées",
+		MonthlyRetro:     "Rétrospective mensuelle",
+		OpenedPRs:        "Ouvertes",
+		OpenAge:          "Âge moyen des PRs ouvertes",
+		TopBlockers:      "Principaux blocages",
+		LongestOpen:      "Ouverte depuis le plus longtemps",
+		Age:              "Âge",
+		ReviewLoad:       "Revues en attente",
+		PreviousReport:   "Rapport précédent",
+		ByLabel:          "PRs ouvertes par label",
+		ComparedWith:     "vs.",
+		SprintClosed:     "terminé",
+		StoriesDone:      "stories terminées",
+		PRsPerStory:      "PRs par story",
+		CarryoverPRs:     "PRs reportées",
+		UnusualGrowth:    "Croissance inhabituelle du backlog",
+		OpenCount:        "PRs ouvertes",
+		BlockedCount:     "PRs bloquées",
+		Avg:              "moy.",
+	},
+}
+
+// Locales returns the supported locale codes in alphabetical order
+func Locales() []string {
+	codes := make([]string, 0, len(locales))
+	for code := range locales {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// IsSupportedLocale reports whether a locale has built-in translations.
+// Region suffixes are ignored (e.g., "de-AT" is supported through "de").
+func IsSupportedLocale(locale string) bool {
+	_, exists := locales[languageCode(locale)]
+	return exists
+}
+
+// LocaleStrings returns the translations for a locale, falling back to English
+func LocaleStrings(locale string) Strings {
+	if text, exists := locales[languageCode(locale)]; exists {
+		return text
+	}
+	return locales[DefaultLocale]
+}
+
+// languageCode reduces a locale such as "pt_BR" or "de-AT" to its language code
+func languageCode(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// TopBlockers is how many blocked PRs the monthly retrospective lists
+const TopBlockers = 5
+
+// MonthlySummary summarizes a calendar month of a report's PRs for the
+// monthly retrospective
+type MonthlySummary struct {
+	Start       time.Time     // First day of the month
+	End         time.Time     // End of the month, or when the summary was made if earlier
+	Opened      int           // PRs opened during the month that are still open or were merged
+	Merged      int           // PRs merged during the month
+	AverageAge  time.Duration // Average age of the open PRs at End
+	Blockers    []*PR         // Blocked open PRs, longest open first (at most TopBlockers)
+	LongestOpen *PR           // Open PR opened the longest ago, nil without open PRs
+}
+
+// RetrospectiveMonth returns the month the retrospective made at now covers:
+// the month of the previous day, so a run on the 1st covers the month that
+// just ended
+func RetrospectiveMonth(now time.Time) (start, end time.Time) {
+	day := now.AddDate(0, 0, -1)
+	start = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, now.Location())
+	end = start.AddDate(0, 1, 0)
+	if now.Before(end) {
+		end = now
+	}
+	return start, end
+}
+
+// NewMonthlySummary summarizes the month from start to end from the open PRs
+// and the PRs merged since start
+func NewMonthlySummary(open, merged []*PR, start, end time.Time) *MonthlySummary {
+	summary := &MonthlySummary{Start: start, End: end}
+
+	within := func(t time.Time) bool {
+		return !t.IsZero() && !t.Before(start) && t.Before(end)
+	}
+	for _, pr := range merged {
+		if within(pr.MergedAt) {
+			summary.Merged++
+			if within(pr.CreatedAt) {
+				summary.Opened++
+			}
+		}
+	}
+
+	var age time.Duration
+	aged := 0
+	for _, pr := range open {
+		if pr.CreatedAt.IsZero() {
+			continue
+		}
+		if within(pr.CreatedAt) {
+			summary.Opened++
+		}
+		age += nonNegative(end.Sub(pr.CreatedAt))
+		aged++
+		if summary.LongestOpen == nil || pr.CreatedAt.Before(summary.LongestOpen.CreatedAt) {
+			summary.LongestOpen = pr
+		}
+		if pr.IsBlocked {
+			summary.Blockers = append(summary.Blockers, pr)
+		}
+	}
+	if aged > 0 {
+		summary.AverageAge = age / time.Duration(aged)
+	}
+
+	sort.SliceStable(summary.Blockers, func(i, j int) bool {
+		return summary.Blockers[i].CreatedAt.Before(summary.Blockers[j].CreatedAt)
+	})
+	if len(summary.Blockers) > TopBlockers {
+		summary.Blockers = summary.Blockers[:TopBlockers]
+	}
+
+	return summary
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// PR is an open pull request with its JIRA ticket information, as shown in
+// reports on every output
+type PR struct {
+	Number      int
+	Title       string
+	URL         string // Web URL of the PR (a GitHub URL is built from the report's repository when empty)
+	Assignee    string // Slack mention format (e.g., "<@U123456>") or GitHub username
+	JiraTicket  string // Ticket ID: a JIRA key, or an ID of another tracker with TicketURL set
+	JiraStatus  string
+	Priority    string // Ticket priority (e.g., "High"), empty when unknown
+	Description string
+	IsDraft     bool
+	IsBlocked   bool
+	Author      string   // GitHub username of the PR author
+	Labels      []string // GitHub labels
+	Reviewers   []string // Reviewer names with their review state (e.g., "alice (approved)")
+	ChecksState string   // Combined CI state: "success", "failure", "pending" or ""
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ReviewCount int    // Number of users who submitted a review (only with detailed PR fetching)
+	TicketURL   string // Web URL of the ticket (a JIRA URL is built from the JIRA base URL when empty)
+
+	ReadyAt       time.Time // When the PR was opened or marked ready for review (zero when unknown)
+	FirstReviewAt time.Time // When the first review by someone else than the author was submitted
+	ApprovedAt    time.Time // When the first approval was submitted
+	MergedAt      time.Time // When the PR was merged (zero while open)
+
+	PreviousStatus string // JIRA status in the previous report when it changed since (empty otherwise)
+
+	BlockedSince  time.Time // When the PR was first listed as blocked in consecutive reports (zero: not tracked)
+	MentionsMuted bool      // The assignee isn't mentioned anymore, the PR has been blocked for too long
+
+	GithubAssignee     string               // GitHub username of the assignee
+	RequestedReviewers []string             // GitHub usernames of requested reviewers who haven't reviewed yet
+	ReviewerMentions   []string             // Slack mentions of mapped requested reviewers
+	ReviewRequestedAt  map[string]time.Time // When each requested reviewer was last asked, by GitHub username (GitHub only)
+	SuggestedReviewer  string               // Slack mention of the reviewer suggested round-robin for a PR nobody is on (empty: none)
+}
+
+// Report is a report ready to be delivered by any output
+type Report struct {
+	Name        string    // Report name (e.g., "frontend")
+	Title       string    // Report title (e.g., "Frontend Report")
+	GithubOwner string    // Repository owner (for PR links)
+	GithubRepo  string    // Repository name (for PR links)
+	JiraURL     string    // JIRA base URL (for ticket links)
+	Locale      string    // Report language
+	Date        time.Time // When the report was generated
+	PRs         []*PR     // Open PRs in report order
+}
+
+// PRURL returns the web URL of a PR, defaulting to its GitHub URL in the
+// report's repository
+func (r Report) PRURL(pr *PR) string {
+	if pr.URL != "" {
+		return pr.URL
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/pull/%d", r.GithubOwner, r.GithubRepo, pr.Number)
+}
+
+// TicketURL returns the web URL of a PR's ticket, or "" when it can't be linked
+func (r Report) TicketURL(pr *PR) string {
+	if pr.TicketURL != "" {
+		return pr.TicketURL
+	}
+	if pr.JiraTicket == "" || r.JiraURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/browse/%s", r.JiraURL, pr.JiraTicket)
+}
+
+// Blocked returns the PRs whose JIRA ticket is blocked, including blocked drafts
+func (r Report) Blocked() []*PR {
+	var prs []*PR
+	for _, pr := range r.PRs {
+		if pr.IsBlocked {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// GroupByTicket returns prs with the PRs referencing the same ticket moved
+// up to the first of them, keeping the order otherwise
+func GroupByTicket(prs []*PR) []*PR {
+	byTicket := make(map[string][]*PR)
+	for _, pr := range prs {
+		if pr.JiraTicket != "" {
+			byTicket[pr.JiraTicket] = append(byTicket[pr.JiraTicket], pr)
+		}
+	}
+
+	grouped := make([]*PR, 0, len(prs))
+	for _, pr := range prs {
+		if pr.JiraTicket == "" {
+			grouped = append(grouped, pr)
+			continue
+		}
+		if group, exists := byTicket[pr.JiraTicket]; exists {
+			grouped = append(grouped, group...)
+			delete(byTicket, pr.JiraTicket)
+		}
+	}
+	return grouped
+}
+
+// GroupByAssignee returns prs in one block per GitHub assignee, the blocks in
+// the order of their first PR and unassigned PRs last, keeping the order
+// within each block
+func GroupByAssignee(prs []*PR) []*PR {
+	var assignees []string
+	byAssignee := make(map[string][]*PR)
+	for _, pr := range prs {
+		if _, exists := byAssignee[pr.GithubAssignee]; !exists && pr.GithubAssignee != "" {
+			assignees = append(assignees, pr.GithubAssignee)
+		}
+		byAssignee[pr.GithubAssignee] = append(byAssignee[pr.GithubAssignee], pr)
+	}
+
+	grouped := make([]*PR, 0, len(prs))
+	for _, assignee := range append(assignees, "") {
+		grouped = append(grouped, byAssignee[assignee]...)
+	}
+	return grouped
+}
+
+// Drafts returns the draft PRs that aren't blocked
+func (r Report) Drafts() []*PR {
+	var prs []*PR
+	for _, pr := range r.PRs {
+		if pr.IsDraft && !pr.IsBlocked {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// Text returns the translated report text for the report's locale
+func (r Report) Text() Strings {
+	return LocaleStrings(r.Locale)
+}
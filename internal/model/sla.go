@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// Review steps a PR can have an SLA for
+const (
+	SLAFirstReview = "first_review"
+	SLAApproval    = "approval"
+)
+
+// SLA is how long PRs of a team may wait for review once ready for review
+type SLA struct {
+	FirstReview time.Duration // Limit until the first review (0: none)
+	Approval    time.Duration // Limit until the first approval (0: none)
+}
+
+// Enabled reports whether any limit is set
+func (s SLA) Enabled() bool {
+	return s.FirstReview > 0 || s.Approval > 0
+}
+
+// Limits returns the configured limits keyed by review step
+func (s SLA) Limits() map[string]time.Duration {
+	limits := make(map[string]time.Duration)
+	if s.FirstReview > 0 {
+		limits[SLAFirstReview] = s.FirstReview
+	}
+	if s.Approval > 0 {
+		limits[SLAApproval] = s.Approval
+	}
+	return limits
+}
+
+// SLAStart returns when a PR's SLA clock started: when it was marked ready
+// for review, or opened when that isn't known. Drafts have no SLA.
+func SLAStart(pr *PR) time.Time {
+	if pr.IsDraft {
+		return time.Time{}
+	}
+	if !pr.ReadyAt.IsZero() {
+		return pr.ReadyAt
+	}
+	return pr.CreatedAt
+}
+
+// SLAMetAt returns when a PR completed a review step, or zero while it is
+// still waiting
+func SLAMetAt(pr *PR, step string) time.Time {
+	if step == SLAApproval {
+		return pr.ApprovedAt
+	}
+	return pr.FirstReviewAt
+}
+
+// SLABreach is a PR waiting longer for a review step than its SLA allows
+type SLABreach struct {
+	PR      *PR
+	Step    string        // SLAFirstReview or SLAApproval
+	Limit   time.Duration // The SLA of the step
+	Waiting time.Duration // How long the PR has been waiting
+}
+
+// SLACompliance counts the review steps completed within their SLA
+type SLACompliance struct {
+	Met   int // Steps completed within the SLA
+	Total int // Steps completed or breached
+}
+
+// Percent returns the share of steps within the SLA, 100 when there are none
+func (c SLACompliance) Percent() int {
+	if c.Total == 0 {
+		return 100
+	}
+	return c.Met * 100 / c.Total
+}
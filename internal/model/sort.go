@@ -0,0 +1,143 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fields PRs can be sorted by
+const (
+	SortAge      = "age"      // Time since the PR was opened (default: oldest first)
+	SortPriority = "priority" // Priority of the ticket (default: highest first)
+	SortStatus   = "status"   // Ticket status, alphabetically (default: A to Z)
+	SortAssignee = "assignee" // Assignee, alphabetically (default: A to Z)
+	SortNumber   = "number"   // PR number (default: lowest first)
+)
+
+// SortOrder is the order of the PRs in a report
+type SortOrder struct {
+	By         string // Field to sort by (empty: the order of the source)
+	Descending bool   // Largest first: oldest, highest priority, Z to A or highest number
+}
+
+// ParseSortOrder parses a sort order such as "age", "priority:desc" or
+// "number:asc". Without a direction, age and priority sort descending and the
+// other fields ascending.
+func ParseSortOrder(value string) (SortOrder, error) {
+	by, direction, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ":")
+	if by == "" {
+		return SortOrder{}, nil
+	}
+
+	order := SortOrder{By: by}
+	switch by {
+	case SortAge, SortPriority:
+		order.Descending = true
+	case SortStatus, SortAssignee, SortNumber:
+	default:
+		return SortOrder{}, fmt.Errorf("unknown sort field %q (supported: age, priority, status, assignee, number)", by)
+	}
+
+	switch direction {
+	case "":
+	case "asc":
+		order.Descending = false
+	case "desc":
+		order.Descending = true
+	default:
+		return SortOrder{}, fmt.Errorf("unknown sort direction %q (supported: asc, desc)", direction)
+	}
+	return order, nil
+}
+
+// priorityRanks ranks common priority names, highest first: JIRA's, Linear's
+// (Urgent to Low) and Azure DevOps' numbers (1 to 4)
+var priorityRanks = map[string]int{
+	"blocker":  0,
+	"highest":  1,
+	"critical": 1,
+	"urgent":   1,
+	"1":        1,
+	"2":        2,
+	"3":        3,
+	"4":        4,
+	"high":     2,
+	"major":    2,
+	"medium":   3,
+	"low":      4,
+	"minor":    4,
+	"lowest":   5,
+	"trivial":  5,
+}
+
+// SortPRs sorts prs in place. PRs missing the sort field (no ticket priority,
+// status or assignee, or an unknown opening time) come last in either
+// direction, after PRs with a priority name that isn't ranked, and PRs that
+// compare equal keep their order.
+func SortPRs(prs []*PR, order SortOrder) {
+	if order.By == "" {
+		return
+	}
+
+	sort.SliceStable(prs, func(i, j int) bool {
+		a, b := prs[i], prs[j]
+		if tierA, tierB := sortTier(a, order.By), sortTier(b, order.By); tierA != tierB || tierA != tierKnown {
+			return tierA < tierB
+		}
+		if order.Descending {
+			a, b = b, a
+		}
+
+		switch order.By {
+		case SortAge:
+			return a.CreatedAt.After(b.CreatedAt)
+		case SortPriority:
+			return priorityRank(a.Priority) > priorityRank(b.Priority)
+		case SortStatus:
+			return strings.ToLower(a.JiraStatus) < strings.ToLower(b.JiraStatus)
+		case SortAssignee:
+			return strings.ToLower(a.GithubAssignee) < strings.ToLower(b.GithubAssignee)
+		default:
+			return a.Number < b.Number
+		}
+	})
+}
+
+// Tiers of PRs by their sort field, sorted in this order in either direction
+const (
+	tierKnown   = iota // The field is set and can be compared
+	tierUnknown        // The priority name isn't ranked
+	tierMissing        // The field isn't set
+)
+
+// sortTier returns the tier of a PR by the field it is sorted by
+func sortTier(pr *PR, by string) int {
+	switch by {
+	case SortAge:
+		if pr.CreatedAt.IsZero() {
+			return tierMissing
+		}
+	case SortPriority:
+		if pr.Priority == "" {
+			return tierMissing
+		}
+		if _, ranked := priorityRanks[strings.ToLower(pr.Priority)]; !ranked {
+			return tierUnknown
+		}
+	case SortStatus:
+		if pr.JiraStatus == "" {
+			return tierMissing
+		}
+	case SortAssignee:
+		if pr.GithubAssignee == "" {
+			return tierMissing
+		}
+	}
+	return tierKnown
+}
+
+// priorityRank ranks a known priority name from 0 (highest) down
+func priorityRank(priority string) int {
+	return priorityRanks[strings.ToLower(priority)]
+}
@@ -0,0 +1,100 @@
+package model
+
+import "time"
+
+// SprintBurndown counts the tickets of the active sprint by how far their PRs
+// got
+type SprintBurndown struct {
+	Name          string
+	End           time.Time // Planned end of the sprint (zero if not set)
+	Total         int       // Tickets in the sprint
+	Done          int       // Completed tickets
+	InReview      int       // Tickets not completed with open PRs
+	NoPR          int       // Tickets not completed without open PRs
+	OutsideSprint int       // Open PRs linked to tickets outside the sprint
+}
+
+// NewSprintBurndown correlates the open PRs with the tickets of a sprint,
+// given as ticket key -> whether the ticket is done
+func NewSprintBurndown(name string, end time.Time, tickets map[string]bool, open []*PR) *SprintBurndown {
+	burndown := &SprintBurndown{Name: name, End: end, Total: len(tickets)}
+
+	withPRs := make(map[string]bool)
+	for _, pr := range open {
+		if pr.JiraTicket == "" {
+			continue
+		}
+		if _, inSprint := tickets[pr.JiraTicket]; inSprint {
+			withPRs[pr.JiraTicket] = true
+		} else {
+			burndown.OutsideSprint++
+		}
+	}
+
+	for ticket, done := range tickets {
+		switch {
+		case done:
+			burndown.Done++
+		case withPRs[ticket]:
+			burndown.InReview++
+		default:
+			burndown.NoPR++
+		}
+	}
+
+	return burndown
+}
+
+// SprintSummary sums up a closed sprint from the PRs of its tickets
+type SprintSummary struct {
+	Name      string
+	Start     time.Time // Start of the sprint (zero if not set)
+	Completed time.Time // When the sprint was closed
+	Stories   int       // Tickets in the sprint
+	Done      int       // Completed tickets
+	PRs       int       // Distinct PRs linked to completed tickets while the sprint ran
+	Carryover []*PR     // Open PRs of the sprint's tickets that were already open when it closed
+}
+
+// PRsPerStory returns the average number of PRs per completed ticket
+func (s SprintSummary) PRsPerStory() float64 {
+	if s.Done == 0 {
+		return 0
+	}
+	return float64(s.PRs) / float64(s.Done)
+}
+
+// NewSprintSummary sums up a closed sprint, given its tickets as ticket key ->
+// whether the ticket is done. history holds the PR lists of the reports made
+// while the sprint ran, atClose the PRs open when it closed and open the PRs
+// open now.
+func NewSprintSummary(name string, start, completed time.Time, tickets map[string]bool, history [][]*PR, atClose, open []*PR) *SprintSummary {
+	summary := &SprintSummary{Name: name, Start: start, Completed: completed, Stories: len(tickets)}
+	for _, done := range tickets {
+		if done {
+			summary.Done++
+		}
+	}
+
+	seen := make(map[int]bool)
+	for _, prs := range history {
+		for _, pr := range prs {
+			if done := tickets[pr.JiraTicket]; done && !seen[pr.Number] {
+				seen[pr.Number] = true
+				summary.PRs++
+			}
+		}
+	}
+
+	openAtClose := make(map[int]bool)
+	for _, pr := range atClose {
+		openAtClose[pr.Number] = true
+	}
+	for _, pr := range open {
+		if _, inSprint := tickets[pr.JiraTicket]; inSprint && pr.JiraTicket != "" && openAtClose[pr.Number] {
+			summary.Carryover = append(summary.Carryover, pr)
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,266 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// AuthorStats summarizes the PRs of one author
+type AuthorStats struct {
+	Author     string        // GitHub username of the author
+	Open       int           // Open PRs
+	AverageAge time.Duration // Average age of the open PRs
+	Oldest     *PR           // Oldest open PR, nil when none is open
+	Closed     int           // PRs merged or closed within the stats window
+}
+
+// ComputeAuthorStats returns the stats of every author with open PRs or PRs
+// closed recently, most open PRs first. Ages are measured at now.
+func ComputeAuthorStats(open, closed []*PR, now time.Time) []AuthorStats {
+	byAuthor := make(map[string]*AuthorStats)
+	statsOf := func(author string) *AuthorStats {
+		if author == "" {
+			author = "-"
+		}
+		stats, exists := byAuthor[author]
+		if !exists {
+			stats = &AuthorStats{Author: author}
+			byAuthor[author] = stats
+		}
+		return stats
+	}
+
+	totalAge := make(map[string]time.Duration)
+	aged := make(map[string]int)
+	for _, pr := range open {
+		stats := statsOf(pr.Author)
+		stats.Open++
+		if pr.CreatedAt.IsZero() {
+			continue
+		}
+		totalAge[stats.Author] += now.Sub(pr.CreatedAt)
+		aged[stats.Author]++
+		if stats.Oldest == nil || stats.Oldest.CreatedAt.IsZero() || pr.CreatedAt.Before(stats.Oldest.CreatedAt) {
+			stats.Oldest = pr
+		}
+	}
+	for _, pr := range closed {
+		statsOf(pr.Author).Closed++
+	}
+
+	var all []AuthorStats
+	for author, stats := range byAuthor {
+		if aged[author] > 0 {
+			stats.AverageAge = totalAge[author] / time.Duration(aged[author])
+		}
+		all = append(all, *stats)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Open != all[j].Open {
+			return all[i].Open > all[j].Open
+		}
+		return all[i].Author < all[j].Author
+	})
+
+	return all
+}
+
+// Turnaround is the average time PRs waited for review
+type Turnaround struct {
+	FirstReview time.Duration // Average time from ready for review to the first review
+	Reviewed    int           // PRs in the first review average
+	Approval    time.Duration // Average time from ready for review to the first approval
+	Approved    int           // PRs in the approval average
+}
+
+// ComputeTurnaround averages the review turnaround of the PRs whose ready for
+// review time is known. Reviews left while a PR was still a draft count as
+// immediate.
+func ComputeTurnaround(prs []*PR) Turnaround {
+	var t Turnaround
+	var firstReview, approval time.Duration
+	for _, pr := range prs {
+		if pr.ReadyAt.IsZero() {
+			continue
+		}
+		if !pr.FirstReviewAt.IsZero() {
+			firstReview += nonNegative(pr.FirstReviewAt.Sub(pr.ReadyAt))
+			t.Reviewed++
+		}
+		if !pr.ApprovedAt.IsZero() {
+			approval += nonNegative(pr.ApprovedAt.Sub(pr.ReadyAt))
+			t.Approved++
+		}
+	}
+
+	if t.Reviewed > 0 {
+		t.FirstReview = firstReview / time.Duration(t.Reviewed)
+	}
+	if t.Approved > 0 {
+		t.Approval = approval / time.Duration(t.Approved)
+	}
+
+	return t
+}
+
+// nonNegative returns d, or zero when d is negative
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// ReviewerCount is the number of PRs a user reviewed
+type ReviewerCount struct {
+	Reviewer string // GitHub username of the reviewer
+	Reviews  int    // PRs reviewed within the leaderboard window
+}
+
+// Leaderboard ranks reviewers by the PRs they reviewed since a time
+type Leaderboard struct {
+	Since     time.Time       // Start of the window
+	Reviewers []ReviewerCount // Most reviews first
+}
+
+// NewLeaderboard ranks review counts keyed by reviewer, most reviews first
+// and alphabetically on ties
+func NewLeaderboard(counts map[string]int, since time.Time) *Leaderboard {
+	leaderboard := &Leaderboard{Since: since}
+	for reviewer, reviews := range counts {
+		leaderboard.Reviewers = append(leaderboard.Reviewers, ReviewerCount{Reviewer: reviewer, Reviews: reviews})
+	}
+	sort.Slice(leaderboard.Reviewers, func(i, j int) bool {
+		a, b := leaderboard.Reviewers[i], leaderboard.Reviewers[j]
+		if a.Reviews != b.Reviews {
+			return a.Reviews > b.Reviews
+		}
+		return a.Reviewer < b.Reviewer
+	})
+	return leaderboard
+}
+
+// MergeRateDays is the length of the rolling merge rate window in days
+const MergeRateDays = 7
+
+// MergeRate compares how fast PRs were merged and opened over the last
+// MergeRateDays days
+type MergeRate struct {
+	End          time.Time // End of the window
+	MergedPerDay []int     // PRs merged each day of the window, oldest first
+	Opened       int       // PRs opened during the window that are still open or were merged
+}
+
+// Merged returns the PRs merged during the window
+func (r MergeRate) Merged() int {
+	total := 0
+	for _, count := range r.MergedPerDay {
+		total += count
+	}
+	return total
+}
+
+// MergedRate returns the average PRs merged per day
+func (r MergeRate) MergedRate() float64 {
+	return float64(r.Merged()) / MergeRateDays
+}
+
+// OpenedRate returns the average PRs opened per day
+func (r MergeRate) OpenedRate() float64 {
+	return float64(r.Opened) / MergeRateDays
+}
+
+// NewMergeRate computes the merge rate of the MergeRateDays days ending at
+// end from the open PRs and the PRs merged during the window. Days are
+// counted back from end.
+func NewMergeRate(open, merged []*PR, end time.Time) MergeRate {
+	rate := MergeRate{End: end, MergedPerDay: make([]int, MergeRateDays)}
+	start := end.Add(-MergeRateDays * 24 * time.Hour)
+
+	for _, pr := range merged {
+		if pr.MergedAt.Before(start) || pr.MergedAt.After(end) {
+			continue
+		}
+		day := int(pr.MergedAt.Sub(start) / (24 * time.Hour))
+		if day == MergeRateDays {
+			day--
+		}
+		rate.MergedPerDay[day]++
+	}
+
+	for _, prs := range [][]*PR{open, merged} {
+		for _, pr := range prs {
+			if !pr.CreatedAt.Before(start) {
+				rate.Opened++
+			}
+		}
+	}
+
+	return rate
+}
+
+// AgeBucket counts the open PRs whose age falls within a range
+type AgeBucket struct {
+	Label string        // Range shown in the report (e.g., "1–3d")
+	Under time.Duration // Upper bound of the range (exclusive, 0: unbounded)
+	Count int           // Open PRs in the range
+}
+
+// ComputeAgeBuckets counts the PRs by age at now into "<1d", "1–3d", "3–7d"
+// and ">1w". PRs without a creation time aren't counted.
+func ComputeAgeBuckets(prs []*PR, now time.Time) []AgeBucket {
+	day := 24 * time.Hour
+	buckets := []AgeBucket{
+		{Label: "<1d", Under: day},
+		{Label: "1–3d", Under: 3 * day},
+		{Label: "3–7d", Under: 7 * day},
+		{Label: ">1w"},
+	}
+
+	for _, pr := range prs {
+		if pr.CreatedAt.IsZero() {
+			continue
+		}
+		age := nonNegative(now.Sub(pr.CreatedAt))
+		for i := range buckets {
+			if buckets[i].Under == 0 || age < buckets[i].Under {
+				buckets[i].Count++
+				break
+			}
+		}
+	}
+
+	return buckets
+}
+
+// ReviewLoad is the number of open PRs waiting for a reviewer
+type ReviewLoad struct {
+	Reviewer string // GitHub username of the reviewer, or "team:<slug>" for a team
+	Pending  int    // Open PRs ready for review with an outstanding review request
+}
+
+// ComputeReviewLoad counts the outstanding review requests of each reviewer
+// on the PRs ready for review, most requests first and alphabetically on ties
+func ComputeReviewLoad(prs []*PR) []ReviewLoad {
+	counts := make(map[string]int)
+	for _, pr := range prs {
+		if pr.IsDraft {
+			continue
+		}
+		for _, reviewer := range pr.RequestedReviewers {
+			counts[reviewer]++
+		}
+	}
+
+	var load []ReviewLoad
+	for reviewer, pending := range counts {
+		load = append(load, ReviewLoad{Reviewer: reviewer, Pending: pending})
+	}
+	sort.Slice(load, func(i, j int) bool {
+		if load[i].Pending != load[j].Pending {
+			return load[i].Pending > load[j].Pending
+		}
+		return load[i].Reviewer < load[j].Reviewer
+	})
+	return load
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// PendingAfter is how long an open PR has to wait to count as still pending
+// in the weekly summary
+const PendingAfter = 7 * 24 * time.Hour
+
+// WeeklySummary summarizes a week of a report's PRs
+type WeeklySummary struct {
+	Start          time.Time     // Start of the week
+	End            time.Time     // End of the week (when the summary was made)
+	Merged         []*PR         // PRs merged during the week, most recent first
+	CycleTime      time.Duration // Average time from opening to merge of the merged PRs
+	Pending        []*PR         // Open PRs ready for review that were opened more than PendingAfter ago, oldest first
+	BlockedTickets int           // Distinct blocked tickets of the open PRs
+}
+
+// NewWeeklySummary summarizes the week ending at end from the open PRs and
+// the PRs merged during the week
+func NewWeeklySummary(open, merged []*PR, end time.Time) *WeeklySummary {
+	summary := &WeeklySummary{
+		Start:  end.Add(-7 * 24 * time.Hour),
+		End:    end,
+		Merged: merged,
+	}
+
+	var cycleTime time.Duration
+	timed := 0
+	for _, pr := range merged {
+		if pr.CreatedAt.IsZero() || pr.MergedAt.IsZero() {
+			continue
+		}
+		cycleTime += nonNegative(pr.MergedAt.Sub(pr.CreatedAt))
+		timed++
+	}
+	if timed > 0 {
+		summary.CycleTime = cycleTime / time.Duration(timed)
+	}
+
+	blocked := make(map[string]bool)
+	for _, pr := range open {
+		if pr.IsBlocked && pr.JiraTicket != "" {
+			blocked[pr.JiraTicket] = true
+		}
+		if !pr.IsDraft && !pr.CreatedAt.IsZero() && end.Sub(pr.CreatedAt) > PendingAfter {
+			summary.Pending = append(summary.Pending, pr)
+		}
+	}
+	summary.BlockedTickets = len(blocked)
+	sort.SliceStable(summary.Pending, func(i, j int) bool { return summary.Pending[i].CreatedAt.Before(summary.Pending[j].CreatedAt) })
+
+	return summary
+}
@@ -0,0 +1,221 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/model"
+)
+
+// Notion API endpoint and version
+const (
+	apiURL     = "https://api.notion.com/v1"
+	apiVersion = "2022-06-28"
+)
+
+// Options contains options for syncing the PR list to a Notion database
+type Options struct {
+	Token      string // Notion internal integration secret
+	DatabaseID string // Database the PR rows are upserted into
+}
+
+// Configured reports whether the options contain a Notion database to sync to
+func (o Options) Configured() bool {
+	return o.Token != "" && o.DatabaseID != ""
+}
+
+// Database properties written for each PR. The database needs these
+// properties with these types.
+const (
+	propName         = "Name"          // Title
+	propURL          = "URL"           // URL
+	propReport       = "Report"        // Select
+	propDate         = "Date"          // Date
+	propStatus       = "Status"        // Select: Open, Draft or Blocked
+	propAssignee     = "Assignee"      // Text
+	propAge          = "Age"           // Number: days since the PR was opened
+	propTicket       = "Ticket"        // Text, linked to the ticket
+	propTicketStatus = "Ticket Status" // Select
+)
+
+// httpClient is used to call the Notion API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Notion"}).Client(30 * time.Second)
+
+// SyncReport upserts one row per PR into the Notion database. Rows are keyed
+// by report, date and PR URL, so each day gets its own rows and reruns on the
+// same day update them.
+func SyncReport(ctx context.Context, opts Options, report model.Report) error {
+	if !opts.Configured() {
+		return fmt.Errorf("Notion token and database ID are required")
+	}
+
+	existing, err := findRows(ctx, opts, report)
+	if err != nil {
+		return fmt.Errorf("error querying Notion database: %w", err)
+	}
+
+	created, updated := 0, 0
+	for _, pr := range report.PRs {
+		properties := rowProperties(report, pr)
+
+		if pageID, exists := existing[report.PRURL(pr)]; exists {
+			if err := call(ctx, opts, http.MethodPatch, "/pages/"+pageID, map[string]interface{}{"properties": properties}, nil); err != nil {
+				return fmt.Errorf("error updating Notion row of PR #%d: %w", pr.Number, err)
+			}
+			updated++
+			continue
+		}
+
+		page := map[string]interface{}{
+			"parent":     map[string]string{"database_id": opts.DatabaseID},
+			"properties": properties,
+		}
+		if err := call(ctx, opts, http.MethodPost, "/pages", page, nil); err != nil {
+			return fmt.Errorf("error creating Notion row of PR #%d: %w", pr.Number, err)
+		}
+		created++
+	}
+
+	slog.Debug("Synced report to Notion", "report", report.Name, "created", created, "updated", updated)
+
+	return nil
+}
+
+// findRows returns the IDs of the report's rows for the report date, keyed by PR URL
+func findRows(ctx context.Context, opts Options, report model.Report) (map[string]string, error) {
+	rows := make(map[string]string)
+
+	query := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"and": []map[string]interface{}{
+				{"property": propReport, "select": map[string]string{"equals": report.Name}},
+				{"property": propDate, "date": map[string]string{"equals": report.Date.Format("2006-01-02")}},
+			},
+		},
+		"page_size": 100,
+	}
+
+	for {
+		var result struct {
+			Results []struct {
+				ID         string `json:"id"`
+				Properties struct {
+					URL struct {
+						URL string `json:"url"`
+					} `json:"URL"`
+				} `json:"properties"`
+			} `json:"results"`
+			HasMore    bool   `json:"has_more"`
+			NextCursor string `json:"next_cursor"`
+		}
+		if err := call(ctx, opts, http.MethodPost, "/databases/"+opts.DatabaseID+"/query", query, &result); err != nil {
+			return nil, err
+		}
+
+		for _, row := range result.Results {
+			rows[row.Properties.URL.URL] = row.ID
+		}
+
+		if !result.HasMore {
+			return rows, nil
+		}
+		query["start_cursor"] = result.NextCursor
+	}
+}
+
+// rowProperties returns the database properties of a PR's row
+func rowProperties(report model.Report, pr *model.PR) map[string]interface{} {
+	text := report.Text()
+
+	description := pr.Description
+	if description == "" {
+		description = text.NoDescription
+	}
+
+	status := "Open"
+	if pr.IsDraft {
+		status = "Draft"
+	}
+	if pr.IsBlocked {
+		status = "Blocked"
+	}
+
+	properties := map[string]interface{}{
+		propName:     map[string]interface{}{"title": richText(fmt.Sprintf("PR-%d: %s", pr.Number, description), "")},
+		propURL:      map[string]string{"url": report.PRURL(pr)},
+		propReport:   selectOption(report.Name),
+		propDate:     map[string]interface{}{"date": map[string]string{"start": report.Date.Format("2006-01-02")}},
+		propStatus:   selectOption(status),
+		propAssignee: map[string]interface{}{"rich_text": richText(pr.GithubAssignee, "")},
+		propTicket:   map[string]interface{}{"rich_text": richText(pr.JiraTicket, report.TicketURL(pr))},
+	}
+
+	if !pr.CreatedAt.IsZero() {
+		properties[propAge] = map[string]interface{}{"number": int(report.Date.Sub(pr.CreatedAt).Hours() / 24)}
+	}
+	if pr.JiraStatus != "" {
+		properties[propTicketStatus] = selectOption(pr.JiraStatus)
+	} else {
+		properties[propTicketStatus] = map[string]interface{}{"select": nil}
+	}
+
+	return properties
+}
+
+// richText returns a rich text value, linked when link is set. Empty content
+// clears the property.
+func richText(content, link string) []map[string]interface{} {
+	if content == "" {
+		return []map[string]interface{}{}
+	}
+	value := map[string]interface{}{"content": content}
+	if link != "" {
+		value["link"] = map[string]string{"url": link}
+	}
+	return []map[string]interface{}{{"type": "text", "text": value}}
+}
+
+// selectOption returns a select value. Notion creates missing options, but
+// doesn't allow commas in their names.
+func selectOption(name string) map[string]interface{} {
+	return map[string]interface{}{"select": map[string]string{"name": strings.ReplaceAll(name, ",", " ")}}
+}
+
+// call sends an authenticated request to the Notion API and decodes the
+// response into result unless it is nil
+func call(ctx context.Context, opts Options, method, path string, payload, result interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+opts.Token)
+	req.Header.Set("Notion-Version", apiVersion)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return httpx.NewStatusError("Notion", resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
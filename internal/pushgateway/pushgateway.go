@@ -0,0 +1,91 @@
+package pushgateway
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+)
+
+// DefaultJob is the job label of pushed metrics when none is configured
+const DefaultJob = "pr_reporter"
+
+// Options contains options for pushing metrics to a Prometheus Pushgateway
+type Options struct {
+	URL string // Pushgateway base URL (e.g., "http://pushgateway:9091")
+	Job string // Job label (default: pr_reporter)
+}
+
+// Metric is a gauge pushed to the Pushgateway
+type Metric struct {
+	Name  string
+	Help  string
+	Value float64
+}
+
+// httpClient is used to push to the Pushgateway
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Pushgateway"}).Client(30 * time.Second)
+
+// Push pushes metrics to the group identified by the job and the grouping
+// labels. Metrics of the group that aren't pushed again keep their previous
+// value, so e.g. the time of the last successful run survives failed runs.
+func Push(opts Options, grouping map[string]string, metrics []Metric) error {
+	if opts.URL == "" {
+		return fmt.Errorf("Pushgateway URL is required")
+	}
+
+	job := opts.Job
+	if job == "" {
+		job = DefaultJob
+	}
+
+	path := "/metrics/job/" + url.PathEscape(job)
+	var keys []string
+	for key := range grouping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path += "/" + url.PathEscape(key) + "/" + url.PathEscape(grouping[key])
+	}
+
+	// POST replaces only the pushed metrics of the group, unlike PUT
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(opts.URL, "/")+path, strings.NewReader(encode(metrics)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error pushing metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return httpx.NewStatusError("Pushgateway", resp)
+	}
+
+	slog.Debug("Pushed metrics", "metrics", len(metrics), "path", path)
+
+	return nil
+}
+
+// encode formats metrics as gauges in the Prometheus text exposition format
+func encode(metrics []Metric) string {
+	var b strings.Builder
+	for _, metric := range metrics {
+		if metric.Help != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", metric.Name, metric.Help)
+		}
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", metric.Name)
+		fmt.Fprintf(&b, "%s %s\n", metric.Name, strconv.FormatFloat(metric.Value, 'g', -1, 64))
+	}
+	return b.String()
+}
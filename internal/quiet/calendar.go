@@ -0,0 +1,150 @@
+package quiet
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+)
+
+// Event is a calendar event during which reports are quiet
+type Event struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// httpClient is used to fetch calendars
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Calendar"}).Client(30 * time.Second)
+
+// FetchCalendar reads the events of an iCalendar file from an http(s) URL or
+// a local path. Recurring events only count on their first occurrence.
+func FetchCalendar(ctx context.Context, source string) ([]Event, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("error opening calendar: %w", err)
+		}
+		defer file.Close()
+		return ParseCalendar(file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching calendar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpx.NewStatusError("Calendar", resp)
+	}
+	return ParseCalendar(resp.Body)
+}
+
+// ParseCalendar reads the events of an iCalendar file. All-day events last
+// until the end of their last day in the local time zone; events without an
+// end last a day when all-day and are skipped otherwise.
+func ParseCalendar(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading calendar: %w", err)
+	}
+
+	var events []Event
+	var event *Event
+	allDay := false
+	for _, line := range lines {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		name, params, _ := strings.Cut(name, ";")
+
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				event, allDay = &Event{}, false
+			}
+		case "END":
+			if strings.EqualFold(value, "VEVENT") && event != nil {
+				if event.End.IsZero() && allDay {
+					event.End = event.Start.AddDate(0, 0, 1)
+				}
+				if !event.Start.IsZero() && event.End.After(event.Start) {
+					events = append(events, *event)
+				}
+				event = nil
+			}
+		case "SUMMARY":
+			if event != nil {
+				event.Summary = value
+			}
+		case "DTSTART", "DTEND":
+			if event == nil {
+				continue
+			}
+			t, date, err := parseCalendarTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s of calendar event %q: %w", name, event.Summary, err)
+			}
+			if strings.EqualFold(name, "DTSTART") {
+				event.Start, allDay = t, date
+			} else {
+				event.End = t
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// unfold returns the lines of an iCalendar file, joining folded lines
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseCalendarTime parses a DATE or DATE-TIME value, in UTC with a "Z"
+// suffix, in the time zone of a TZID parameter or else in the local time
+// zone. date reports whether the value is a date without a time.
+func parseCalendarTime(value, params string) (t time.Time, date bool, err error) {
+	location := time.Local
+	for _, param := range strings.Split(params, ";") {
+		if key, tzid, found := strings.Cut(param, "="); found && strings.EqualFold(key, "TZID") {
+			if loc, err := time.LoadLocation(strings.Trim(tzid, `"`)); err == nil {
+				location = loc
+			}
+		}
+	}
+
+	switch {
+	case len(value) == len("20060102"):
+		t, err = time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse("20060102T150405Z", value)
+		return t, false, err
+	default:
+		t, err = time.ParseInLocation("20060102T150405", value, location)
+		return t, false, err
+	}
+}
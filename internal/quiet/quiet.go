@@ -0,0 +1,197 @@
+package quiet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// What reports do during quiet hours
+const (
+	ModeMute  = "mute"  // Post without mentions or DMs (default)
+	ModeDefer = "defer" // Schedule the Slack report for the end of the quiet hours
+)
+
+// Options contains the quiet hours of reports
+type Options struct {
+	Windows  []Window // Recurring quiet windows in the local time zone
+	Calendar string   // URL or path of an iCalendar file whose events are quiet too (e.g., company holidays)
+	Mode     string   // ModeMute (default) or ModeDefer
+}
+
+// Window is a recurring quiet window, such as 22:00-08:00 every day or all of
+// Saturday and Sunday
+type Window struct {
+	Days  [7]bool       // Weekdays the window starts on, by time.Weekday (all false: every day)
+	Start time.Duration // Start after midnight
+	End   time.Duration // End after midnight; an end before the start is on the next day
+}
+
+// maxChain is how many adjoining quiet periods Until follows, such as a night
+// running into a holiday
+const maxChain = 16
+
+// Enabled reports whether any quiet hours are configured
+func (o Options) Enabled() bool {
+	return len(o.Windows) > 0 || o.Calendar != ""
+}
+
+// Until reports whether now falls within quiet hours, and when they end.
+// Adjoining quiet periods are followed to the end of the last one. Calendar
+// events that can't be fetched are logged and left out.
+func (o Options) Until(ctx context.Context, now time.Time) (time.Time, bool) {
+	var events []Event
+	if o.Calendar != "" {
+		var err error
+		if events, err = FetchCalendar(ctx, o.Calendar); err != nil {
+			slog.Warn("Could not fetch the quiet hours calendar, using the quiet windows only", "calendar", o.Calendar, "error", err)
+		}
+	}
+
+	end, quiet := now, false
+	for i := 0; i < maxChain; i++ {
+		next, found := periodEnd(o.Windows, events, end)
+		if !found || !next.After(end) {
+			break
+		}
+		end, quiet = next, true
+	}
+	return end, quiet
+}
+
+// periodEnd returns the latest end of the windows and events t falls in
+func periodEnd(windows []Window, events []Event, t time.Time) (time.Time, bool) {
+	var end time.Time
+	found := false
+	for _, window := range windows {
+		if windowEnd, active := window.end(t); active && windowEnd.After(end) {
+			end, found = windowEnd, true
+		}
+	}
+	for _, event := range events {
+		if !t.Before(event.Start) && t.Before(event.End) && event.End.After(end) {
+			end, found = event.End, true
+		}
+	}
+	return end, found
+}
+
+// end returns when the window t falls in ends, checking the window started
+// on the day of t and the day before, for windows spanning midnight
+func (w Window) end(t time.Time) (time.Time, bool) {
+	for offset := 0; offset >= -1; offset-- {
+		day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, t.Location())
+		if !w.appliesTo(day.Weekday()) {
+			continue
+		}
+		start := day.Add(w.Start)
+		end := day.Add(w.End)
+		if w.End <= w.Start {
+			end = end.Add(24 * time.Hour)
+		}
+		if !t.Before(start) && t.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// appliesTo reports whether the window starts on day
+func (w Window) appliesTo(day time.Weekday) bool {
+	return w.Days == [7]bool{} || w.Days[day]
+}
+
+// ParseWindows parses comma-separated quiet windows, each a time range, days
+// or both: "22:00-08:00", "Sat-Sun" or "Fri 18:00-24:00"
+func ParseWindows(spec string) ([]Window, error) {
+	var windows []Window
+	for _, entry := range strings.Split(spec, ",") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("invalid quiet window %q, expected days, a time range or both", strings.TrimSpace(entry))
+		}
+
+		window := Window{End: 24 * time.Hour}
+		for _, field := range fields {
+			var err error
+			if strings.Contains(field, ":") {
+				window.Start, window.End, err = parseTimeRange(field)
+			} else {
+				window.Days, err = parseDays(field)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid quiet window %q: %w", strings.TrimSpace(entry), err)
+			}
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// parseTimeRange parses "HH:MM-HH:MM"; the end may be 24:00
+func parseTimeRange(value string) (start, end time.Duration, err error) {
+	from, to, found := strings.Cut(value, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("expected a time range such as 22:00-08:00, got %q", value)
+	}
+	if start, err = parseClock(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(to); err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("time range %q is empty", value)
+	}
+	return start, end, nil
+}
+
+// parseClock parses "HH:MM" as the time after midnight
+func parseClock(value string) (time.Duration, error) {
+	hours, minutes, found := strings.Cut(value, ":")
+	h, hErr := strconv.Atoi(hours)
+	m, mErr := strconv.Atoi(minutes)
+	if !found || hErr != nil || mErr != nil || h < 0 || m < 0 || m > 59 || h > 24 || h == 24 && m > 0 {
+		return 0, fmt.Errorf("expected a time such as 08:00, got %q", value)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// parseDays parses a day ("Sat") or a range of days ("Mon-Fri", "Fri-Mon")
+func parseDays(value string) ([7]bool, error) {
+	var days [7]bool
+	from, to, isRange := strings.Cut(value, "-")
+	if !isRange {
+		to = from
+	}
+
+	first, last := weekday(from), weekday(to)
+	if first < 0 || last < 0 {
+		return days, fmt.Errorf("expected days such as Sat or Mon-Fri, got %q", value)
+	}
+	for day := first; ; day = (day + 1) % 7 {
+		days[day] = true
+		if day == last {
+			break
+		}
+	}
+	return days, nil
+}
+
+// weekday returns the time.Weekday of a day name of at least three letters
+// such as "Mon" or "monday", or -1
+func weekday(name string) int {
+	name = strings.ToLower(name)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if len(name) >= 3 && strings.HasPrefix(strings.ToLower(day.String()), name) {
+			return int(day)
+		}
+	}
+	return -1
+}
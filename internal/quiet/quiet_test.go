@@ -0,0 +1,196 @@
+package quiet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseWindows(t *testing.T) {
+	weekend := [7]bool{time.Saturday: true, time.Sunday: true}
+	tests := []struct {
+		spec    string
+		want    []Window
+		wantErr bool
+	}{
+		{spec: "22:00-08:00", want: []Window{{Start: 22 * time.Hour, End: 8 * time.Hour}}},
+		{spec: "Sat-Sun", want: []Window{{Days: weekend, End: 24 * time.Hour}}},
+		{spec: "Fri 18:00-24:00", want: []Window{{Days: [7]bool{time.Friday: true}, Start: 18 * time.Hour, End: 24 * time.Hour}}},
+		{spec: "fri-mon", want: []Window{{Days: [7]bool{time.Friday: true, time.Saturday: true, time.Sunday: true, time.Monday: true}, End: 24 * time.Hour}}},
+		{
+			spec: "22:00-08:00, Sat-Sun",
+			want: []Window{{Start: 22 * time.Hour, End: 8 * time.Hour}, {Days: weekend, End: 24 * time.Hour}},
+		},
+		{spec: "", want: nil},
+		{spec: "22:00", wantErr: true},
+		{spec: "08:00-08:00", wantErr: true},
+		{spec: "25:00-08:00", wantErr: true},
+		{spec: "Sa", wantErr: true},
+		{spec: "Sat 22:00-23:00 extra", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseWindows(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseWindows(%q) = %v, want an error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWindows(%q): %v", tt.spec, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseWindows(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseWindows(%q)[%d] = %+v, want %+v", tt.spec, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUntil(t *testing.T) {
+	// Wednesday, May 8, 2024
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.May, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name      string
+		windows   string
+		now       time.Time
+		wantUntil time.Time
+		wantQuiet bool
+	}{
+		{name: "before midnight", windows: "22:00-08:00", now: at(8, 23, 30), wantUntil: at(9, 8, 0), wantQuiet: true},
+		{name: "after midnight", windows: "22:00-08:00", now: at(9, 2, 0), wantUntil: at(9, 8, 0), wantQuiet: true},
+		{name: "at the end", windows: "22:00-08:00", now: at(9, 8, 0), wantUntil: at(9, 8, 0)},
+		{name: "outside", windows: "22:00-08:00", now: at(8, 12, 0), wantUntil: at(8, 12, 0)},
+		{name: "other day", windows: "Sat-Sun", now: at(8, 12, 0), wantUntil: at(8, 12, 0)},
+		{
+			// Friday evening runs into the weekend, which runs into Monday morning
+			name:      "back to back",
+			windows:   "Fri 18:00-24:00,Sat-Sun,Mon 00:00-08:00",
+			now:       at(10, 19, 0),
+			wantUntil: at(13, 8, 0),
+			wantQuiet: true,
+		},
+		{
+			name:      "overlapping",
+			windows:   "22:00-06:00,05:00-09:00",
+			now:       at(8, 23, 0),
+			wantUntil: at(9, 9, 0),
+			wantQuiet: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, err := ParseWindows(tt.windows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			until, quiet := Options{Windows: windows}.Until(context.Background(), tt.now)
+			if !until.Equal(tt.wantUntil) || quiet != tt.wantQuiet {
+				t.Errorf("Until(%s) = %s, %v, want %s, %v", tt.now, until, quiet, tt.wantUntil, tt.wantQuiet)
+			}
+		})
+	}
+}
+
+func TestUntilCalendar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	calendar := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Offsite\nDTSTART:20240509T080000Z\nDTEND:20240509T170000Z\nEND:VEVENT\nEND:VCALENDAR\n"
+	if err := os.WriteFile(path, []byte(calendar), 0644); err != nil {
+		t.Fatal(err)
+	}
+	windows, err := ParseWindows("22:00-08:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The night runs into the offsite, which ends the quiet hours
+	now := time.Date(2024, time.May, 8, 23, 0, 0, 0, time.UTC)
+	until, quiet := Options{Windows: windows, Calendar: path}.Until(context.Background(), now)
+	if want := time.Date(2024, time.May, 9, 17, 0, 0, 0, time.UTC); !until.Equal(want) || !quiet {
+		t.Errorf("Until(%s) = %s, %v, want %s, true", now, until, quiet, want)
+	}
+}
+
+func TestParseCalendar(t *testing.T) {
+	sofia, err := time.LoadLocation("Europe/Sofia")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		event     string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "all day",
+			event:     "DTSTART;VALUE=DATE:20241224\nDTEND;VALUE=DATE:20241227",
+			wantStart: time.Date(2024, time.December, 24, 0, 0, 0, 0, time.Local),
+			wantEnd:   time.Date(2024, time.December, 27, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:      "all day without end",
+			event:     "DTSTART;VALUE=DATE:20240501",
+			wantStart: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.Local),
+			wantEnd:   time.Date(2024, time.May, 2, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:      "time zone",
+			event:     "DTSTART;TZID=Europe/Sofia:20240509T090000\nDTEND;TZID=\"Europe/Sofia\":20240509T130000",
+			wantStart: time.Date(2024, time.May, 9, 9, 0, 0, 0, sofia),
+			wantEnd:   time.Date(2024, time.May, 9, 13, 0, 0, 0, sofia),
+		},
+		{
+			name:      "UTC",
+			event:     "DTSTART:20240509T060000Z\nDTEND:20240509T100000Z",
+			wantStart: time.Date(2024, time.May, 9, 6, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, time.May, 9, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "folded summary",
+			event:     "SUMMARY:Company\n  holiday\nDTSTART;VALUE=DATE:20240101\nDTEND;VALUE=DATE:20240102",
+			wantStart: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local),
+			wantEnd:   time.Date(2024, time.January, 2, 0, 0, 0, 0, time.Local),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" + strings.ReplaceAll(tt.event, "\n", "\r\n") + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+			events, err := ParseCalendar(strings.NewReader(calendar))
+			if err != nil {
+				t.Fatalf("ParseCalendar: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1: %+v", len(events), events)
+			}
+			if !events[0].Start.Equal(tt.wantStart) || !events[0].End.Equal(tt.wantEnd) {
+				t.Errorf("event = %s – %s, want %s – %s", events[0].Start, events[0].End, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParseCalendarSkipsEventsWithoutEnd(t *testing.T) {
+	calendar := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Standup\nDTSTART:20240509T090000Z\nEND:VEVENT\nEND:VCALENDAR\n"
+	events, err := ParseCalendar(strings.NewReader(calendar))
+	if err != nil {
+		t.Fatalf("ParseCalendar: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("got %+v, want no events", events)
+	}
+}
@@ -0,0 +1,351 @@
+package report
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/state"
+	"pr-reporter/internal/store"
+)
+
+// secretKey matches the configuration fields whose values the admin API
+// never returns
+var secretKey = regexp.MustCompile(`(?i)(token|secret|password|apikey|accesskey|webhookurl|credentials|headers)`)
+
+// AdminPaths are the paths served by the admin API
+var AdminPaths = []string{"/run", "/last-report", "/config", "/pause", "/resume"}
+
+// NewAdminHandler returns the admin API, which lets operators trigger and
+// inspect reports over HTTP. Every request must carry the token as
+// "Authorization: Bearer <token>".
+//
+//	POST /run           run a report ({"report": "frontend"}), or every report
+//	GET  /last-report   last snapshot and posted messages (?report=frontend)
+//	GET  /config        configuration with secrets redacted (?report=frontend)
+//	POST /pause         pause a report ({"report": "frontend", "for": "24h"})
+//	POST /resume        resume a paused report ({"report": "frontend"})
+func NewAdminHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/run", adminRun)
+	mux.HandleFunc("/last-report", adminLastReport)
+	mux.HandleFunc("/config", adminConfig)
+	mux.HandleFunc("/pause", adminPause)
+	mux.HandleFunc("/resume", adminResume)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			writeAdminError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid admin token"))
+			return
+		}
+		slog.Info("Admin API request", "method", r.Method, "path", r.URL.Path)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// adminRequest is the JSON body of the POST endpoints
+type adminRequest struct {
+	Report string `json:"report"` // Report name (empty: every report, for /run, /pause and /resume)
+	For    string `json:"for"`    // Pause duration, e.g. "24h" (empty: until resumed)
+	By     string `json:"by"`     // Who paused the report (optional)
+}
+
+// readAdminRequest reads the JSON body of a POST request. The report may also
+// be given as the report query parameter.
+func readAdminRequest(w http.ResponseWriter, r *http.Request) (adminRequest, bool) {
+	var req adminRequest
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s needs a POST request", r.URL.Path))
+		return req, false
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return req, false
+		}
+	}
+	if req.Report == "" {
+		req.Report = r.URL.Query().Get("report")
+	}
+	return req, true
+}
+
+// adminConfigs returns the configuration of the named report, or of every
+// report when name is empty
+func adminConfigs(w http.ResponseWriter, name string) ([]Config, bool) {
+	names := Names
+	if name != "" {
+		names = []string{name}
+	}
+
+	var cfgs []Config
+	for _, n := range names {
+		cfg, err := ConfigFor(n)
+		if err != nil {
+			writeAdminError(w, http.StatusNotFound, err)
+			return nil, false
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	return cfgs, true
+}
+
+// adminRun runs the report (or every report) as it would run on schedule,
+// skipping paused reports
+func adminRun(w http.ResponseWriter, r *http.Request) {
+	req, ok := readAdminRequest(w, r)
+	if !ok {
+		return
+	}
+	cfgs, ok := adminConfigs(w, req.Report)
+	if !ok {
+		return
+	}
+
+	result := struct {
+		Sent   []string `json:"sent"`
+		Paused []string `json:"paused,omitempty"`
+	}{Sent: []string{}}
+
+	var run []Config
+	for _, cfg := range cfgs {
+		if activePause(cfg) != nil {
+			result.Paused = append(result.Paused, cfg.Name)
+			continue
+		}
+		run = append(run, cfg)
+		result.Sent = append(result.Sent, cfg.Name)
+	}
+
+	if err := runPool(run, concurrency(), RunReport); err != nil {
+		writeAdminError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, result)
+}
+
+// adminLastReport returns the last snapshot of the report and the Slack
+// messages it was posted as
+func adminLastReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s needs a GET request", r.URL.Path))
+		return
+	}
+	name := r.URL.Query().Get("report")
+	if name == "" {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("the report query parameter is required"))
+		return
+	}
+	cfgs, ok := adminConfigs(w, name)
+	if !ok {
+		return
+	}
+	cfg := cfgs[0]
+
+	type message struct {
+		Channel           string    `json:"channel"`
+		PostedAt          time.Time `json:"posted_at"`
+		Permalink         string    `json:"permalink,omitempty"`
+		PreviousPermalink string    `json:"previous_permalink,omitempty"`
+	}
+	result := struct {
+		Report   string       `json:"report"`
+		TakenAt  *time.Time   `json:"taken_at,omitempty"`
+		PRs      []*model.PR  `json:"prs,omitempty"`
+		Messages []message    `json:"messages,omitempty"`
+		Paused   *state.Pause `json:"paused,omitempty"`
+	}{Report: cfg.Name, Paused: activePause(cfg)}
+
+	if snapshot := lastReport(cfg); snapshot != nil {
+		result.TakenAt = &snapshot.TakenAt
+		result.PRs = snapshot.PRs
+	}
+
+	stateStore, err := state.Load(statePath(cfg))
+	if err != nil {
+		writeAdminError(w, http.StatusInternalServerError, err)
+		return
+	}
+	repo := "|" + cfg.Slack.GithubOwner + "/" + cfg.Slack.GithubRepo
+	for key, msg := range stateStore.Messages {
+		if strings.HasSuffix(key, repo) && len(msg.Parts) > 0 {
+			result.Messages = append(result.Messages, message{
+				Channel:           msg.ChannelID,
+				PostedAt:          msg.PostedAt,
+				Permalink:         msg.Permalink,
+				PreviousPermalink: msg.PreviousPermalink,
+			})
+		}
+	}
+
+	if result.TakenAt == nil && len(result.Messages) == 0 {
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("%s report hasn't run yet", cfg.Name))
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, result)
+}
+
+// adminConfig returns the configuration of the report (or of every report)
+// with secrets redacted
+func adminConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s needs a GET request", r.URL.Path))
+		return
+	}
+	cfgs, ok := adminConfigs(w, r.URL.Query().Get("report"))
+	if !ok {
+		return
+	}
+
+	redacted := make(map[string]any, len(cfgs))
+	for _, cfg := range cfgs {
+		value, err := redactConfig(cfg)
+		if err != nil {
+			writeAdminError(w, http.StatusInternalServerError, err)
+			return
+		}
+		redacted[cfg.Name] = value
+	}
+	writeAdminJSON(w, http.StatusOK, redacted)
+}
+
+// redactConfig returns the configuration as generic JSON with the values of
+// tokens, passwords and other secrets replaced
+func redactConfig(cfg Config) (any, error) {
+	cfg.Clients = Clients{}
+	cfg.Notifiers = nil
+	cfg.Slack.StateFile = store.Redacted(cfg.Slack.StateFile)
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding the configuration: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("error encoding the configuration: %w", err)
+	}
+
+	var redact func(value any)
+	redact = func(value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, field := range v {
+				if secretKey.MatchString(key) {
+					if s, isString := field.(string); !isString || s != "" {
+						v[key] = "[redacted]"
+					}
+					continue
+				}
+				redact(field)
+			}
+		case []any:
+			for _, item := range v {
+				redact(item)
+			}
+		}
+	}
+	redact(value)
+
+	// Keep the configuration readable, Clients and Notifiers aren't settings
+	if fields, isMap := value.(map[string]any); isMap {
+		delete(fields, "Clients")
+		delete(fields, "Notifiers")
+	}
+	return value, nil
+}
+
+// adminPause pauses the report (or every report) for the requested duration
+func adminPause(w http.ResponseWriter, r *http.Request) {
+	req, ok := readAdminRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var duration time.Duration
+	if req.For != "" {
+		var err error
+		if duration, err = time.ParseDuration(req.For); err != nil {
+			writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid pause duration %q: %w", req.For, err))
+			return
+		}
+	}
+	by := req.By
+	if by == "" {
+		by = "admin API"
+	}
+
+	cfgs, ok := adminConfigs(w, req.Report)
+	if !ok {
+		return
+	}
+	var paused []string
+	for _, cfg := range cfgs {
+		if err := Pause(cfg, duration, by); err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		}
+		paused = append(paused, cfg.Name)
+	}
+
+	result := struct {
+		Paused []string   `json:"paused"`
+		Until  *time.Time `json:"until,omitempty"`
+	}{Paused: paused}
+	if duration > 0 {
+		until := time.Now().Add(duration)
+		result.Until = &until
+	}
+	writeAdminJSON(w, http.StatusOK, result)
+}
+
+// adminResume resumes the paused report (or every paused report)
+func adminResume(w http.ResponseWriter, r *http.Request) {
+	req, ok := readAdminRequest(w, r)
+	if !ok {
+		return
+	}
+	cfgs, ok := adminConfigs(w, req.Report)
+	if !ok {
+		return
+	}
+
+	resumed := []string{}
+	for _, cfg := range cfgs {
+		// Resuming every report skips the ones that aren't paused
+		if req.Report == "" && activePause(cfg) == nil {
+			continue
+		}
+		if err := Resume(cfg); err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		}
+		resumed = append(resumed, cfg.Name)
+	}
+	writeAdminJSON(w, http.StatusOK, struct {
+		Resumed []string `json:"resumed"`
+	}{resumed})
+}
+
+// writeAdminJSON writes value as the JSON response
+func writeAdminJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeAdminError writes err as a JSON error response
+func writeAdminError(w http.ResponseWriter, status int, err error) {
+	writeAdminJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/store"
+)
+
+// detectAnomalies compares the open and blocked PR counts with the last
+// report of each of the previous model.AnomalyDays days in the state database
+func detectAnomalies(cfg Config, prs []*slack.PRInfo) []model.Anomaly {
+	path := statePath(cfg)
+	if !store.IsDatabase(path) {
+		return nil
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		cfg.logger().Warn("Could not load snapshots", "error", err)
+		return nil
+	}
+	defer db.Close()
+
+	now := time.Now()
+	snapshots, err := db.Snapshots(cfg.Name, now.AddDate(0, 0, -model.AnomalyDays))
+	if err != nil {
+		cfg.logger().Warn("Could not load snapshots", "error", err)
+		return nil
+	}
+
+	// Snapshots are oldest first, so the last one of a day wins
+	today := now.Format("2006-01-02")
+	var openHistory, blockedHistory []int
+	lastDay := ""
+	for _, snapshot := range snapshots {
+		day := snapshot.TakenAt.Format("2006-01-02")
+		if day == today {
+			break
+		}
+		if day != lastDay {
+			openHistory = append(openHistory, 0)
+			blockedHistory = append(blockedHistory, 0)
+		}
+		openHistory[len(openHistory)-1] = len(snapshot.PRs)
+		blockedHistory[len(blockedHistory)-1] = countBlocked(snapshot.PRs)
+		lastDay = day
+	}
+
+	anomalies := model.DetectAnomalies(openHistory, blockedHistory, len(prs), countBlocked(prs), cfg.Anomaly)
+	cfg.logger().Debug("Compared PR counts with earlier days", "days", len(openHistory), "anomalies", len(anomalies))
+	return anomalies
+}
+
+// countBlocked counts the blocked PRs, including blocked drafts
+func countBlocked(prs []*model.PR) int {
+	count := 0
+	for _, pr := range prs {
+		if pr.IsBlocked {
+			count++
+		}
+	}
+	return count
+}
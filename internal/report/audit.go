@@ -0,0 +1,131 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/store"
+)
+
+// auditRetention is how long deliveries are kept in the audit log
+const auditRetention = 365 * 24 * time.Hour
+
+// recordDeliveries records the outcome of delivering the report with every
+// notifier in the audit log of the state database. errs holds the error of
+// each notifier, in notifier order. JSON state files don't keep an audit log.
+func recordDeliveries(cfg Config, report model.Report, outputs []Notifier, errs []error) {
+	hash := payloadHash(report)
+	now := time.Now()
+
+	deliveries := make([]store.Delivery, len(outputs))
+	for i, notifier := range outputs {
+		deliveries[i] = store.Delivery{
+			Report:      cfg.Name,
+			Output:      notifier.Name(),
+			Target:      deliveryTarget(cfg, notifier.Name()),
+			SentAt:      now,
+			PayloadHash: hash,
+		}
+		if errs[i] != nil {
+			deliveries[i].Error = errs[i].Error()
+		}
+	}
+	audit(cfg, deliveries...)
+}
+
+// auditMessage records a Slack message sent outside of the report, such as an
+// alert, in the audit log
+func auditMessage(cfg Config, output string, payload interface{}, err error) {
+	delivery := store.Delivery{
+		Report:      cfg.Name,
+		Output:      output,
+		Target:      deliveryTarget(cfg, "slack"),
+		SentAt:      time.Now(),
+		PayloadHash: payloadHash(payload),
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	audit(cfg, delivery)
+}
+
+// audit saves deliveries to the audit log and prunes the expired ones
+func audit(cfg Config, deliveries ...store.Delivery) {
+	path := statePath(cfg)
+	if !cfg.Audit || !store.IsDatabase(path) || len(deliveries) == 0 {
+		return
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		cfg.logger().Warn("Could not record deliveries in the audit log", "error", err)
+		return
+	}
+	defer db.Close()
+
+	if err := db.SaveDeliveries(deliveries); err != nil {
+		cfg.logger().Warn("Could not record deliveries in the audit log", "error", err)
+		return
+	}
+	if err := db.PruneDeliveries(time.Now().Add(-auditRetention)); err != nil {
+		cfg.logger().Warn("Could not prune the audit log", "error", err)
+	}
+
+	cfg.logger().Debug("Recorded deliveries in the audit log", "deliveries", len(deliveries))
+}
+
+// payloadHash returns the SHA-256 of the JSON encoding of a delivered payload
+func payloadHash(payload interface{}) string {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// deliveryTarget describes where an output delivers the report, without
+// secrets such as webhook URLs
+func deliveryTarget(cfg Config, output string) string {
+	switch output {
+	case "slack":
+		if cfg.Slack.WebhookURL != "" {
+			return "incoming webhook"
+		}
+		if len(cfg.Slack.Channels) > 0 {
+			var channels []string
+			for _, target := range cfg.Slack.Channels {
+				channels = append(channels, target.Channel)
+			}
+			return strings.Join(channels, ", ")
+		}
+		return cfg.Slack.Channel
+	case "discord":
+		if cfg.Discord.WebhookURL != "" {
+			return "incoming webhook"
+		}
+		return cfg.Discord.ChannelID
+	case "teams", "googlechat", "webhook":
+		return "incoming webhook"
+	case "mattermost":
+		return cfg.Mattermost.Channel
+	case "email":
+		return strings.Join(cfg.Email.To, ", ")
+	case "confluence":
+		return cfg.Confluence.Space
+	case "notion":
+		return cfg.Notion.DatabaseID
+	case "sheets":
+		return cfg.Sheets.SpreadsheetID
+	case "archive":
+		return cfg.Archive.Bucket
+	case "html":
+		return cfg.HTML.Dir
+	default:
+		return ""
+	}
+}
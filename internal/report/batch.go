@@ -0,0 +1,65 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/pushgateway"
+	"pr-reporter/internal/slack"
+)
+
+// RunOnce runs a report as a one-off batch job. Batch jobs exit before
+// Prometheus could scrape them, so the run's metrics are pushed to the
+// Pushgateway when one is configured. Paused reports (see Pause) are skipped.
+func RunOnce(cfg Config) error {
+	if skipPaused(cfg) {
+		return nil
+	}
+
+	start := time.Now()
+	prs, err := runReport(cfg)
+
+	if cfg.Datadog.APIKey != "" {
+		sendDatadog(cfg, prs, time.Since(start), err)
+	}
+
+	if cfg.Pushgateway.URL != "" {
+		if pushErr := pushRunMetrics(cfg, prs, time.Since(start), err == nil); pushErr != nil {
+			cfg.logger().Warn("Could not push run metrics", "error", pushErr)
+		}
+	}
+
+	return err
+}
+
+// pushRunMetrics pushes the duration, outcome and PR counts of a run. PR
+// counts are only pushed when the PRs were fetched.
+func pushRunMetrics(cfg Config, prs []*slack.PRInfo, duration time.Duration, success bool) error {
+	now := float64(time.Now().Unix())
+
+	metrics := []pushgateway.Metric{
+		{Name: "pr_reporter_run_duration_seconds", Help: "Duration of the last report run.", Value: duration.Seconds()},
+		{Name: "pr_reporter_run_success", Help: "Whether the last report run succeeded (1) or failed (0).", Value: boolValue(success)},
+		{Name: "pr_reporter_last_run_timestamp_seconds", Help: "Time of the last report run.", Value: now},
+	}
+	if success {
+		metrics = append(metrics, pushgateway.Metric{Name: "pr_reporter_last_success_timestamp_seconds", Help: "Time of the last successful report run.", Value: now})
+	}
+	if prs != nil {
+		report := newReport(cfg, prs)
+		metrics = append(metrics,
+			pushgateway.Metric{Name: "pr_reporter_open_prs", Help: "Open PRs in the last report.", Value: float64(len(report.PRs))},
+			pushgateway.Metric{Name: "pr_reporter_blocked_prs", Help: "Blocked PRs in the last report.", Value: float64(len(report.Blocked()))},
+			pushgateway.Metric{Name: "pr_reporter_draft_prs", Help: "Draft PRs in the last report.", Value: float64(len(report.Drafts()))},
+		)
+	}
+
+	return pushgateway.Push(cfg.Pushgateway, map[string]string{"report": cfg.Name}, metrics)
+}
+
+// boolValue converts a flag to a gauge value
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/state"
+)
+
+// trackBlockedStreaks counts the consecutive days each PR has been reported
+// as blocked and stops mentioning the assignee of PRs blocked for more than
+// cfg.MuteBlocked report days. A streak ends when the PR is unblocked, merged
+// or closed.
+func trackBlockedStreaks(cfg Config, prs []*slack.PRInfo) {
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		cfg.logger().Warn("Could not track blocked PRs", "error", err)
+		return
+	}
+
+	now := time.Now()
+	blocked := make(map[string]bool)
+	for _, pr := range prs {
+		if !pr.IsBlocked {
+			continue
+		}
+
+		key := state.PRKey(cfg.Slack.GithubOwner, cfg.Slack.GithubRepo, pr.Number)
+		blocked[key] = true
+
+		streak, exists := store.Blocked[key]
+		if !exists {
+			streak = &state.BlockedStreak{Report: cfg.Name, Since: now}
+			store.Blocked[key] = streak
+		}
+		if streak.LastReport.Local().Format("2006-01-02") != now.Format("2006-01-02") {
+			streak.Reports++
+			streak.LastReport = now
+		}
+
+		pr.BlockedSince = streak.Since
+		if streak.Reports > cfg.MuteBlocked {
+			pr.MentionsMuted = true
+			pr.Assignee = pr.GithubAssignee
+		}
+	}
+
+	for key, streak := range store.Blocked {
+		if streak.Report == cfg.Name && !blocked[key] {
+			delete(store.Blocked, key)
+		}
+	}
+
+	if err := store.Save(); err != nil {
+		cfg.logger().Warn("Could not save blocked PRs", "error", err)
+	}
+
+	cfg.logger().Debug("Tracking blocked PRs", "blocked", len(blocked))
+}
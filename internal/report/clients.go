@@ -0,0 +1,151 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"pr-reporter/internal/github"
+	"pr-reporter/internal/jira"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+)
+
+// GitHubClient fetches PRs and their details, merges, reviews and user emails
+// from GitHub
+type GitHubClient interface {
+	FetchPRs(ctx context.Context, opts github.FetchOptions) ([]*github.PRResult, error)
+	FetchDetails(ctx context.Context, opts github.FetchOptions, prs []*github.PRResult) error
+	FetchMergedPRs(opts github.FetchOptions, since time.Time) ([]*github.PRResult, error)
+	FetchReviewCounts(opts github.FetchOptions, since time.Time) (map[string]int, error)
+	FetchUserEmails(opts github.FetchOptions, logins []string, prs []*github.PRResult) (map[string][]string, error)
+}
+
+// JiraClient fetches tickets and sprints from JIRA
+type JiraClient interface {
+	FetchTicketInfo(ctx context.Context, opts jira.FetchOptions, ticketID string) (*jira.TicketInfo, error)
+	FetchTicketsInfo(ctx context.Context, opts jira.FetchOptions, ticketIDs []string) (map[string]*jira.TicketInfo, error)
+	FetchActiveSprint(opts jira.FetchOptions, boardID int) (*jira.Sprint, error)
+	FetchLastClosedSprint(opts jira.FetchOptions, boardID int) (*jira.Sprint, error)
+}
+
+// SlackClient posts reports, previews, digests and SLA alerts to Slack
+type SlackClient interface {
+	SendPRReport(ctx context.Context, opts slack.MessageOptions, prs []*slack.PRInfo) error
+	SendPreview(ctx context.Context, opts slack.MessageOptions, report string, prs []*slack.PRInfo) error
+	SendDigests(ctx context.Context, opts slack.MessageOptions, digests map[string][]slack.DigestPR) error
+	SendReminders(ctx context.Context, opts slack.MessageOptions, reminders map[string][]slack.ReminderPR) error
+	SendSLAAlert(opts slack.MessageOptions, breaches []model.SLABreach, compliance model.SLACompliance) error
+}
+
+// Clients are the API clients a report run uses. Unset clients use the real
+// APIs, so tests can replace just the ones they need (see NewFakeGitHub,
+// NewFakeJira and NewFakeSlack).
+type Clients struct {
+	GitHub GitHubClient
+	Jira   JiraClient
+	Slack  SlackClient
+}
+
+// NewGitHubClient returns a GitHubClient calling the GitHub API
+func NewGitHubClient() GitHubClient {
+	return gitHubAPI{}
+}
+
+// NewJiraClient returns a JiraClient calling the JIRA API
+func NewJiraClient() JiraClient {
+	return jiraAPI{}
+}
+
+// NewSlackClient returns a SlackClient calling the Slack API
+func NewSlackClient() SlackClient {
+	return slackAPI{}
+}
+
+// gitHubClient returns the GitHub client of a report
+func (cfg Config) gitHubClient() GitHubClient {
+	if cfg.Clients.GitHub != nil {
+		return cfg.Clients.GitHub
+	}
+	return NewGitHubClient()
+}
+
+// jiraClient returns the JIRA client of a report
+func (cfg Config) jiraClient() JiraClient {
+	if cfg.Clients.Jira != nil {
+		return cfg.Clients.Jira
+	}
+	return NewJiraClient()
+}
+
+// slackClient returns the Slack client of a report
+func (cfg Config) slackClient() SlackClient {
+	if cfg.Clients.Slack != nil {
+		return cfg.Clients.Slack
+	}
+	return NewSlackClient()
+}
+
+// gitHubAPI is the GitHubClient of the github package
+type gitHubAPI struct{}
+
+func (gitHubAPI) FetchPRs(ctx context.Context, opts github.FetchOptions) ([]*github.PRResult, error) {
+	return github.FetchPRs(ctx, opts)
+}
+
+func (gitHubAPI) FetchDetails(ctx context.Context, opts github.FetchOptions, prs []*github.PRResult) error {
+	return github.FetchDetails(ctx, opts, prs)
+}
+
+func (gitHubAPI) FetchMergedPRs(opts github.FetchOptions, since time.Time) ([]*github.PRResult, error) {
+	return github.FetchMergedPRs(opts, since)
+}
+
+func (gitHubAPI) FetchReviewCounts(opts github.FetchOptions, since time.Time) (map[string]int, error) {
+	return github.FetchReviewCounts(opts, since)
+}
+
+func (gitHubAPI) FetchUserEmails(opts github.FetchOptions, logins []string, prs []*github.PRResult) (map[string][]string, error) {
+	return github.FetchUserEmails(opts, logins, prs)
+}
+
+// jiraAPI is the JiraClient of the jira package
+type jiraAPI struct{}
+
+func (jiraAPI) FetchTicketInfo(ctx context.Context, opts jira.FetchOptions, ticketID string) (*jira.TicketInfo, error) {
+	return jira.FetchTicketInfo(ctx, opts, ticketID)
+}
+
+func (jiraAPI) FetchTicketsInfo(ctx context.Context, opts jira.FetchOptions, ticketIDs []string) (map[string]*jira.TicketInfo, error) {
+	return jira.FetchTicketsInfo(ctx, opts, ticketIDs)
+}
+
+func (jiraAPI) FetchActiveSprint(opts jira.FetchOptions, boardID int) (*jira.Sprint, error) {
+	return jira.FetchActiveSprint(opts, boardID)
+}
+
+func (jiraAPI) FetchLastClosedSprint(opts jira.FetchOptions, boardID int) (*jira.Sprint, error) {
+	return jira.FetchLastClosedSprint(opts, boardID)
+}
+
+// slackAPI is the SlackClient of the slack package
+type slackAPI struct{}
+
+func (slackAPI) SendPRReport(ctx context.Context, opts slack.MessageOptions, prs []*slack.PRInfo) error {
+	return slack.SendPRReport(ctx, opts, prs)
+}
+
+func (slackAPI) SendPreview(ctx context.Context, opts slack.MessageOptions, report string, prs []*slack.PRInfo) error {
+	return slack.SendPreview(ctx, opts, report, prs)
+}
+
+func (slackAPI) SendDigests(ctx context.Context, opts slack.MessageOptions, digests map[string][]slack.DigestPR) error {
+	return slack.SendDigests(ctx, opts, digests)
+}
+
+func (slackAPI) SendReminders(ctx context.Context, opts slack.MessageOptions, reminders map[string][]slack.ReminderPR) error {
+	return slack.SendReminders(ctx, opts, reminders)
+}
+
+func (slackAPI) SendSLAAlert(opts slack.MessageOptions, breaches []model.SLABreach, compliance model.SLACompliance) error {
+	return slack.SendSLAAlert(opts, breaches, compliance)
+}
@@ -0,0 +1,793 @@
+package report
+
+import (
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/archive"
+	"pr-reporter/internal/asana"
+	"pr-reporter/internal/azuredevops"
+	"pr-reporter/internal/bitbucket"
+	"pr-reporter/internal/config"
+	"pr-reporter/internal/confluence"
+	"pr-reporter/internal/datadog"
+	"pr-reporter/internal/discord"
+	"pr-reporter/internal/email"
+	"pr-reporter/internal/errkind"
+	"pr-reporter/internal/github"
+	"pr-reporter/internal/gitlab"
+	"pr-reporter/internal/googlechat"
+	"pr-reporter/internal/htmlreport"
+	"pr-reporter/internal/jira"
+	"pr-reporter/internal/linear"
+	"pr-reporter/internal/mattermost"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/notion"
+	"pr-reporter/internal/pushgateway"
+	"pr-reporter/internal/quiet"
+	"pr-reporter/internal/rules"
+	"pr-reporter/internal/sheets"
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/teams"
+	"pr-reporter/internal/webhook"
+)
+
+// Config contains everything needed to produce and deliver one report
+type Config struct {
+	Name        string                   // Report name (e.g., "frontend")
+	Source      string                   // Where PRs come from: SourceGitHub (default), SourceGitLab, SourceBitbucket or SourceAzureDevOps
+	GitHub      github.FetchOptions      // Where and how to fetch PRs (label and user filters apply to every source)
+	GitLab      gitlab.FetchOptions      // GitLab project, with SourceGitLab
+	Bitbucket   bitbucket.FetchOptions   // Bitbucket repository, with SourceBitbucket
+	AzureDevOps azuredevops.FetchOptions // Azure DevOps repository and work items, with SourceAzureDevOps
+	Tracker     string                   // Where tickets come from: TrackerJira (default), TrackerLinear or TrackerAsana (Azure DevOps always uses work items)
+	Jira        jira.FetchOptions        // JIRA connection for ticket status
+	Linear      linear.FetchOptions      // Linear connection, with TrackerLinear
+	Asana       asana.FetchOptions       // Asana connection, with TrackerAsana
+	Slack       slack.MessageOptions     // Where and how to post the report
+	Teams       teams.Options            // Microsoft Teams delivery (optional)
+	Discord     discord.Options          // Discord delivery (optional)
+	GoogleChat  googlechat.Options       // Google Chat delivery (optional)
+	Mattermost  mattermost.Options       // Mattermost delivery (optional)
+	Email       email.Options            // Email delivery via SMTP (optional)
+	Webhook     webhook.Options          // JSON report webhook (optional)
+	HTML        htmlreport.Options       // Static HTML page (optional)
+	Confluence  confluence.Options       // Confluence page (optional)
+	Notion      notion.Options           // Notion database sync (optional)
+	Sheets      sheets.Options           // Google Sheets history (optional)
+	Archive     archive.Options          // S3/GCS archive (optional)
+	Notifiers   []Notifier               // Additional outputs the report is delivered to (optional)
+	Clients     Clients                  // GitHub, JIRA and Slack clients (unset: the real APIs; fakes for tests)
+	Pushgateway pushgateway.Options      // Prometheus Pushgateway for metrics of one-off runs (optional)
+	Datadog     datadog.Options          // Datadog events and metrics of runs (optional)
+	UserMapping map[string]string        // GitHub username -> Slack user ID
+	Timeout     time.Duration            // Deadline of fetching the PRs and tickets and delivering to every output, previews, digests and reminders (0: none)
+	Digest      bool                     // Also DM each mapped user the PRs that involve them
+	Reminders   time.Duration            // Also DM requested reviewers the PRs whose review has been pending for longer than this (0: off)
+	RemindEvery time.Duration            // Minimum time between reminders of a reviewer about the same PR (default: 24h)
+	EmailLookup bool                     // Map GitHub users missing from UserMapping to Slack by email
+	Snapshots   bool                     // Record the PRs of every delivered report in the state database
+	Audit       bool                     // Record every delivery to an output in the audit log of the state database
+	ShowChanges bool                     // List PRs opened, closed and transitioned since the previous report above the PRs
+	Highlight   bool                     // Show the previous JIRA status of PRs whose status changed since the previous report
+	Anomaly     float64                  // Warn above the PRs when the open or blocked PR count exceeds its 7-day average by this fraction (0: off)
+	TrendChart  bool                     // Attach a chart of the open PR count over the last 30 days to the report
+	AuthorStats bool                     // Append a per-author stats table (open PRs, average age, oldest PR, closed in 7 days)
+	Turnaround  bool                     // Append the average time from ready for review to first review and approval (GitHub only)
+	CycleTime   bool                     // Record merges and show the previous month's cycle time in the first report of a month (GitHub only)
+	MergeRate   bool                     // Append the PRs merged and opened per day over the last 7 days (GitHub only)
+	Weekly      bool                     // Post the weekly summary instead of the PR list (see WeeklyConfig)
+	Monthly     bool                     // Post the monthly retrospective instead of the PR list (see MonthlyConfig)
+	Leadership  string                   // Slack channel the monthly retrospective is posted to (default: the report's channels)
+	Labels      []string                 // Append the open PRs of these labels compared with a week earlier (empty: not shown)
+	ReviewLoad  bool                     // Append the reviewers with the most outstanding review requests
+	Leaderboard time.Duration            // Append a leaderboard of reviewers by PRs reviewed within this window (0: not shown, GitHub only)
+	SLA         model.SLA                // Review SLAs; PRs breaching them are alerted in a separate message (GitHub only)
+	SprintBoard int                      // JIRA board whose active sprint is burned down below the PRs (0: not shown)
+	Velocity    bool                     // Sum up the board's last closed sprint in the first report after it closed (needs SprintBoard)
+	MuteBlocked int                      // Stop mentioning the assignee of a PR blocked for more than this many report days (0: always mention)
+	Quiet       quiet.Options            // Quiet hours during which reports post without mentions or are deferred (see applyQuietHours)
+	Muted       bool                     // Post without mentions or DMs, set during quiet hours
+	OnDemand    bool                     // Run requested from Slack (slash command or mention), posted right away during quiet hours
+	RoundRobin  bool                     // Suggest a reviewer round-robin for PRs without assignee or requested reviewers
+	Roster      []string                 // Slack user IDs reviewers are suggested from (empty: the members of the report channel)
+	SortOrder   model.SortOrder          // Order of the PRs in the report (zero: the order of the source)
+	Features    config.Features          // Feature flags set for the report, gating optional enrichments (see setFeatures)
+	Invalid     error                    // Invalid setting failing every run of the report with a config error (nil: none)
+}
+
+// PR sources
+const (
+	SourceGitHub      = "github"
+	SourceGitLab      = "gitlab"
+	SourceBitbucket   = "bitbucket"
+	SourceAzureDevOps = "azuredevops"
+)
+
+// Ticket trackers
+const (
+	TrackerJira   = "jira"
+	TrackerLinear = "linear"
+	TrackerAsana  = "asana"
+)
+
+// Names lists the reports that can be built from the environment
+var Names = []string{"frontend", "middletier"}
+
+// ConfigFor builds the configuration of a report by its name or repository name
+func ConfigFor(name string) (Config, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "frontend", "fips-web-client":
+		return FrontendConfig(), nil
+	case "middletier", "fips-poker-web-mt":
+		return MiddletierConfig(), nil
+	default:
+		return Config{}, errkind.Configf("unknown report %q (available: %s)", name, strings.Join(Names, ", "))
+	}
+}
+
+// FrontendConfig builds the Frontend report configuration from environment variables
+func FrontendConfig() Config {
+	cfg := baseConfig("frontend", "fips-web-client")
+
+	// Frontend uses "Poker" label unless overridden
+	cfg.GitHub.Labels = []string{"Poker"}
+	if labels := config.List("FRONTEND_LABELS"); len(labels) > 0 {
+		cfg.GitHub.Labels = labels
+	}
+
+	// Only include PRs from users in USER_MAPPING
+	for githubUser := range cfg.UserMapping {
+		cfg.GitHub.AllowedUsers = append(cfg.GitHub.AllowedUsers, githubUser)
+	}
+
+	cfg.Source = sourceFromEnv("FRONTEND_SOURCE")
+	setSLA(&cfg, "FRONTEND_")
+	setFilters(&cfg, "FRONTEND_")
+	setFeatures(&cfg, "FRONTEND_")
+	cfg.Slack.MessageFormat = messageFormatFromEnv("FRONTEND_")
+	cfg.SortOrder = sortOrderFromEnv("FRONTEND_")
+	cfg.GitLab.Project = gitlabProject("FRONTEND_GITLAB_PROJECT", cfg.GitHub.Repo)
+	cfg.Bitbucket.Repo = config.Or("FRONTEND_BITBUCKET_REPO", cfg.GitHub.Repo)
+	cfg.AzureDevOps.Repo = config.Or("FRONTEND_AZURE_DEVOPS_REPO", cfg.GitHub.Repo)
+	cfg.Tracker = trackerFromEnv("FRONTEND_TRACKER")
+	cfg.Linear.TeamKeys = config.List("FRONTEND_LINEAR_TEAMS")
+	cfg.SprintBoard = config.Int("FRONTEND_JIRA_SPRINT_BOARD")
+	cfg.Roster = config.List("FRONTEND_REVIEWER_ROSTER")
+	if len(cfg.Roster) == 0 {
+		cfg.Roster = config.List("REVIEWER_ROSTER")
+	}
+	clearJiraLinks(&cfg)
+
+	cfg.Slack.Channel = config.String("SLACK_CHANNEL")
+	cfg.Slack.Channels = channelTargets("SLACK_CHANNELS")
+	cfg.Slack.WebhookURL = config.String("SLACK_WEBHOOK_URL")
+	cfg.Leadership = config.String("LEADERSHIP_SLACK_CHANNEL")
+	cfg.Teams.WebhookURL = config.String("TEAMS_WEBHOOK_URL")
+	cfg.Discord.WebhookURL = config.String("DISCORD_WEBHOOK_URL")
+	cfg.Discord.ChannelID = config.String("DISCORD_CHANNEL_ID")
+	cfg.GoogleChat.WebhookURL = config.String("GOOGLE_CHAT_WEBHOOK_URL")
+	cfg.Mattermost.Channel = config.String("MATTERMOST_CHANNEL")
+	cfg.Email.To = config.List("EMAIL_TO")
+	cfg.Webhook.URL = config.String("REPORT_WEBHOOK_URL")
+	cfg.Confluence.Space = config.String("CONFLUENCE_SPACE")
+	cfg.Notion.DatabaseID = config.String("NOTION_DATABASE_ID")
+	cfg.Sheets.SpreadsheetID = config.String("GOOGLE_SHEETS_SPREADSHEET_ID")
+	cfg.Archive.Bucket = config.String("ARCHIVE_BUCKET")
+	cfg.Slack.TeamGroup = config.String("TEAM_GROUP")
+	cfg.Slack.EscalateGroup = config.String("ESCALATION_GROUP")
+	cfg.Slack.ReportTitle = "Frontend Report"
+	cfg.Slack.ShowAssignee = true // Show assignee for frontend
+	cfg.Slack.UseCheckmark = true // Use checkmark emoji
+
+	return cfg
+}
+
+// MiddletierConfig builds the Middletier report configuration from environment variables
+func MiddletierConfig() Config {
+	cfg := baseConfig("middletier", "fips-poker-web-mt")
+
+	// Middletier has no label filter by default
+	cfg.GitHub.Labels = config.List("MIDDLETIER_LABELS")
+
+	cfg.Source = sourceFromEnv("MIDDLETIER_SOURCE")
+	setSLA(&cfg, "MIDDLETIER_")
+	setFilters(&cfg, "MIDDLETIER_")
+	setFeatures(&cfg, "MIDDLETIER_")
+	cfg.Slack.MessageFormat = messageFormatFromEnv("MIDDLETIER_")
+	cfg.SortOrder = sortOrderFromEnv("MIDDLETIER_")
+	cfg.GitLab.Project = gitlabProject("MIDDLETIER_GITLAB_PROJECT", cfg.GitHub.Repo)
+	cfg.Bitbucket.Repo = config.Or("MIDDLETIER_BITBUCKET_REPO", cfg.GitHub.Repo)
+	cfg.AzureDevOps.Repo = config.Or("MIDDLETIER_AZURE_DEVOPS_REPO", cfg.GitHub.Repo)
+	cfg.Tracker = trackerFromEnv("MIDDLETIER_TRACKER")
+	cfg.Linear.TeamKeys = config.List("MIDDLETIER_LINEAR_TEAMS")
+	cfg.SprintBoard = config.Int("MIDDLETIER_JIRA_SPRINT_BOARD")
+	cfg.Roster = config.List("MIDDLETIER_REVIEWER_ROSTER")
+	if len(cfg.Roster) == 0 {
+		cfg.Roster = config.List("REVIEWER_ROSTER")
+	}
+	clearJiraLinks(&cfg)
+
+	cfg.Slack.Channel = config.String("MIDDLETIER_SLACK_CHANNEL") // Use separate channel for middletier
+	cfg.Slack.Channels = channelTargets("MIDDLETIER_SLACK_CHANNELS")
+	cfg.Slack.WebhookURL = config.String("MIDDLETIER_SLACK_WEBHOOK_URL")
+	cfg.Leadership = config.Or("MIDDLETIER_LEADERSHIP_SLACK_CHANNEL", config.String("LEADERSHIP_SLACK_CHANNEL"))
+	cfg.Teams.WebhookURL = config.String("MIDDLETIER_TEAMS_WEBHOOK_URL")
+	cfg.Discord.WebhookURL = config.String("MIDDLETIER_DISCORD_WEBHOOK_URL")
+	cfg.Discord.ChannelID = config.String("MIDDLETIER_DISCORD_CHANNEL_ID")
+	cfg.GoogleChat.WebhookURL = config.String("MIDDLETIER_GOOGLE_CHAT_WEBHOOK_URL")
+	cfg.Mattermost.Channel = config.String("MIDDLETIER_MATTERMOST_CHANNEL")
+	cfg.Email.To = config.List("MIDDLETIER_EMAIL_TO")
+	cfg.Webhook.URL = config.String("MIDDLETIER_REPORT_WEBHOOK_URL")
+	cfg.Confluence.Space = config.String("MIDDLETIER_CONFLUENCE_SPACE")
+	if cfg.Confluence.Space == "" {
+		cfg.Confluence.Space = config.String("CONFLUENCE_SPACE")
+	}
+	cfg.Notion.DatabaseID = config.Or("MIDDLETIER_NOTION_DATABASE_ID", config.String("NOTION_DATABASE_ID"))
+	cfg.Sheets.SpreadsheetID = config.Or("MIDDLETIER_GOOGLE_SHEETS_SPREADSHEET_ID", config.String("GOOGLE_SHEETS_SPREADSHEET_ID"))
+	cfg.Archive.Bucket = config.Or("MIDDLETIER_ARCHIVE_BUCKET", config.String("ARCHIVE_BUCKET"))
+	if cfg.Slack.Channel == "" {
+		cfg.Slack.Channel = config.String("SLACK_CHANNEL")
+		if len(cfg.Slack.Channels) == 0 {
+			cfg.Slack.Channels = channelTargets("SLACK_CHANNELS")
+		}
+		if cfg.Slack.WebhookURL == "" {
+			cfg.Slack.WebhookURL = config.String("SLACK_WEBHOOK_URL")
+		}
+	}
+	cfg.Slack.TeamGroup = config.String("MIDDLETIER_TEAM_GROUP") // Use separate team group for middletier
+	cfg.Slack.EscalateGroup = config.Or("MIDDLETIER_ESCALATION_GROUP", config.String("ESCALATION_GROUP"))
+	cfg.Slack.MentionUsers = config.String("MIDDLETIER_MENTION_USERS") // Comma-separated Slack user IDs to mention
+	cfg.Slack.ReportTitle = "Middletier Report"
+	cfg.Slack.ShowAssignee = false // Don't show assignee for middletier
+	cfg.Slack.UseCheckmark = false // Use memo emoji instead of checkmark
+
+	return cfg
+}
+
+// baseConfig builds the settings shared by all reports
+func baseConfig(name, repo string) Config {
+	threadDetail := config.Bool("SLACK_THREAD_DETAILS")
+	turnaround := config.Bool("SLACK_REVIEW_TURNAROUND")
+	reminders := config.Duration("SLACK_REVIEW_REMINDER_AFTER")
+	mentionPolicy := strings.ToLower(config.String("SLACK_MENTION_POLICY"))
+	switch mentionPolicy {
+	case "", slack.MentionPolicyTeam, slack.MentionPolicyTargeted, slack.MentionPolicyNone:
+	default:
+		slog.Warn("Unknown SLACK_MENTION_POLICY, using the default", "policy", mentionPolicy, "default", slack.MentionPolicyTeam)
+		mentionPolicy = ""
+	}
+	gh := config.LoadGitHub()
+	jiraConn := config.LoadJira()
+
+	cfg := Config{
+		Name:        name,
+		UserMapping: config.UserMapping("USER_MAPPING"),
+		Timeout:     config.Duration("REPORT_TIMEOUT"),
+		Digest:      config.Bool("SLACK_DM_DIGEST"),
+		Reminders:   reminders,
+		RemindEvery: config.Duration("SLACK_REVIEW_REMINDER_EVERY"),
+		EmailLookup: config.Bool("SLACK_EMAIL_LOOKUP"),
+		Snapshots:   strings.ToLower(config.String("SNAPSHOTS")) != "false",
+		Audit:       strings.ToLower(config.String("AUDIT_LOG")) != "false",
+		ShowChanges: config.Bool("SLACK_SHOW_CHANGES"),
+		Highlight:   config.Bool("SLACK_HIGHLIGHT_STATUS_CHANGES"),
+		Anomaly:     anomalyThreshold(),
+		TrendChart:  config.Bool("SLACK_TREND_CHART"),
+		AuthorStats: config.Bool("SLACK_AUTHOR_STATS"),
+		Turnaround:  turnaround,
+		MergeRate:   config.Bool("SLACK_MERGE_RATE"),
+		CycleTime:   config.Bool("SLACK_CYCLE_TIME"),
+		MuteBlocked: config.Int("SLACK_BLOCKED_MENTION_LIMIT"),
+		Velocity:    config.Bool("SLACK_SPRINT_SUMMARY"),
+		ReviewLoad:  config.Bool("SLACK_REVIEW_LOAD"),
+		RoundRobin:  config.Bool("SLACK_SUGGEST_REVIEWERS"),
+		Labels:      config.List("SLACK_LABEL_BREAKDOWN"),
+		Leaderboard: leaderboardWindow(),
+		GitHub: github.FetchOptions{
+			Token:        gh.Token,
+			Owner:        gh.Owner,
+			Repo:         repo,
+			BaseURL:      gh.APIURL,
+			FetchDetails: threadDetail || turnaround || reminders > 0 || mentionPolicy == slack.MentionPolicyTargeted, // Reviews tell unreviewed PRs apart
+			ReviewTimes:  turnaround || reminders > 0,                                                                 // Reminders count from when each review was requested
+			SSOEmails:    gh.SSOEmails,
+			Timeout:      gh.Timeout,
+		},
+		GitLab: gitlab.FetchOptions{
+			URL:   config.String("GITLAB_URL"),
+			Token: config.String("GITLAB_TOKEN"),
+		},
+		Bitbucket: bitbucket.FetchOptions{
+			URL:         config.String("BITBUCKET_URL"),
+			Workspace:   config.String("BITBUCKET_WORKSPACE"),
+			Username:    config.String("BITBUCKET_USERNAME"),
+			AppPassword: config.String("BITBUCKET_APP_PASSWORD"),
+			Token:       config.String("BITBUCKET_TOKEN"),
+		},
+		Linear: linear.FetchOptions{
+			APIKey: config.String("LINEAR_API_KEY"),
+		},
+		Asana: asana.FetchOptions{
+			Token: config.String("ASANA_TOKEN"),
+		},
+		AzureDevOps: azuredevops.FetchOptions{
+			URL:          config.String("AZURE_DEVOPS_URL"),
+			Organization: config.String("AZURE_DEVOPS_ORG"),
+			Project:      config.String("AZURE_DEVOPS_PROJECT"),
+			Token:        config.String("AZURE_DEVOPS_TOKEN"),
+		},
+		Teams: teams.Options{},
+		Discord: discord.Options{
+			BotToken: config.String("DISCORD_BOT_TOKEN"),
+		},
+		GoogleChat: googlechat.Options{},
+		Mattermost: mattermost.Options{
+			URL:         config.String("MATTERMOST_URL"),
+			Token:       config.String("MATTERMOST_TOKEN"),
+			UserMapping: config.UserMapping("MATTERMOST_USER_MAPPING"),
+		},
+		Email: email.Options{
+			Host:     config.String("SMTP_HOST"),
+			Port:     config.String("SMTP_PORT"),
+			Username: config.String("SMTP_USERNAME"),
+			Password: config.String("SMTP_PASSWORD"),
+			From:     config.String("EMAIL_FROM"),
+		},
+		Confluence: confluence.Options{
+			URL:      config.String("CONFLUENCE_URL"),
+			Username: config.Or("CONFLUENCE_USERNAME", jiraConn.Username),
+			APIToken: config.Or("CONFLUENCE_API_TOKEN", jiraConn.APIToken),
+			UsePAT:   config.Bool("CONFLUENCE_USE_PAT"),
+			ParentID: config.String("CONFLUENCE_PARENT_ID"),
+			Mode:     confluenceMode(),
+		},
+		Notion: notion.Options{
+			Token: config.String("NOTION_TOKEN"),
+		},
+		Sheets: sheets.Options{
+			CredentialsFile: config.String("GOOGLE_SHEETS_CREDENTIALS"),
+			Sheet:           config.Or("GOOGLE_SHEETS_SHEET", sheets.DefaultSheet),
+		},
+		Archive: archive.Options{
+			Prefix:          config.String("ARCHIVE_PREFIX"),
+			Endpoint:        config.String("ARCHIVE_ENDPOINT"),
+			Region:          config.Or("ARCHIVE_REGION", config.Or("AWS_REGION", archive.DefaultRegion)),
+			AccessKeyID:     config.Or("ARCHIVE_ACCESS_KEY_ID", config.String("AWS_ACCESS_KEY_ID")),
+			SecretAccessKey: config.Or("ARCHIVE_SECRET_ACCESS_KEY", config.String("AWS_SECRET_ACCESS_KEY")),
+			SessionToken:    config.String("AWS_SESSION_TOKEN"),
+		},
+		Datadog: datadog.Options{
+			APIKey: config.Or("DATADOG_API_KEY", config.String("DD_API_KEY")),
+			Site:   config.Or("DATADOG_SITE", config.Or("DD_SITE", datadog.DefaultSite)),
+			Tags:   config.List("DATADOG_TAGS"),
+		},
+		Pushgateway: pushgateway.Options{
+			URL: config.String("PUSHGATEWAY_URL"),
+			Job: config.Or("PUSHGATEWAY_JOB", pushgateway.DefaultJob),
+		},
+		HTML: htmlreport.Options{
+			Dir:     config.String("HTML_REPORT_DIR"),
+			Refresh: config.Duration("HTML_REPORT_REFRESH"),
+			BaseURL: config.String("HTML_REPORT_BASE_URL"),
+		},
+		Webhook: webhook.Options{
+			Headers: config.Map("REPORT_WEBHOOK_HEADERS"),
+			Secret:  config.String("REPORT_WEBHOOK_SECRET"),
+		},
+		Jira: jira.FetchOptions{
+			URL:      jiraConn.URL,
+			Username: jiraConn.Username,
+			APIToken: jiraConn.APIToken,
+			UsePAT:   jiraConn.UsePAT,
+			Timeout:  jiraConn.Timeout,
+		},
+		Slack: slack.MessageOptions{
+			Token:           config.String("SLACK_TOKEN"),
+			APIURL:          config.String("SLACK_API_URL"),
+			Timeout:         config.Duration("SLACK_TIMEOUT"),
+			GithubOwner:     gh.Owner,
+			GithubRepo:      repo,
+			JiraURL:         jiraConn.URL,
+			MentionPolicy:   mentionPolicy,
+			StaleAfter:      config.Duration("SLACK_STALE_AFTER"),
+			EscalateAfter:   time.Duration(config.Int("SLACK_ESCALATE_AFTER_DAYS")) * 24 * time.Hour,
+			MaxLength:       config.Int("SLACK_MAX_LENGTH"),
+			SplitThread:     config.Bool("SLACK_SPLIT_THREAD"),
+			ThreadDetail:    threadDetail,
+			UpdateExisting:  config.Bool("SLACK_UPDATE_EXISTING"),
+			LiveStatus:      config.Bool("SLACK_LIVE_STATUS"),
+			UpdateWindow:    config.Duration("SLACK_UPDATE_WINDOW"),
+			StateFile:       config.String("STATE_FILE"),
+			UserCacheTTL:    config.Duration("SLACK_USER_CACHE_TTL"),
+			Interactive:     config.Bool("SLACK_INTERACTIVE"),
+			Template:        config.String("SLACK_TEMPLATE"),
+			TemplateFile:    config.String("SLACK_TEMPLATE_FILE"),
+			Emoji:           emojiFromEnv(),
+			Locale:          localeFromEnv(),
+			UnfurlLinks:     config.Bool("SLACK_UNFURL_LINKS"),
+			ExportFormat:    strings.ToLower(config.String("SLACK_ATTACH_EXPORT")),
+			PreviewUser:     config.String("SLACK_PREVIEW_USER"),
+			PostAt:          envPostAt("SLACK_POST_AT"),
+			AgeBuckets:      config.Bool("SLACK_AGE_BUCKETS"),
+			GroupByTicket:   config.Bool("SLACK_GROUP_BY_TICKET"),
+			GroupByAssignee: config.Bool("SLACK_GROUP_BY_ASSIGNEE"),
+			LinkPrevious:    config.Bool("SLACK_LINK_PREVIOUS_REPORT"),
+		},
+	}
+
+	setQuietHours(&cfg)
+
+	// Assignee blocks would split the PRs sharing a ticket apart again
+	if cfg.Slack.GroupByTicket && cfg.Slack.GroupByAssignee {
+		cfg.Invalid = errkind.Configf("SLACK_GROUP_BY_TICKET and SLACK_GROUP_BY_ASSIGNEE can't be combined, set only one of them")
+	}
+
+	return cfg
+}
+
+// logger returns the default logger with the report name attached
+func (cfg Config) logger() *slog.Logger {
+	return slog.With("report", cfg.Name)
+}
+
+// anomalyThreshold reads SLACK_ANOMALY_THRESHOLD, the percentage above the
+// trailing average that counts as unusual growth (e.g., "50")
+func anomalyThreshold() float64 {
+	value := strings.TrimSuffix(strings.TrimSpace(config.String("SLACK_ANOMALY_THRESHOLD")), "%")
+	if value == "" {
+		return 0
+	}
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || percent <= 0 {
+		slog.Warn("Invalid SLACK_ANOMALY_THRESHOLD, expected a percentage such as 50", "value", value)
+		return 0
+	}
+	return percent / 100
+}
+
+// emojiFromEnv reads emoji overrides from SLACK_EMOJI (e.g., "date=:calendar:,blocked=:no_entry:")
+// and per-JIRA-status emoji from SLACK_STATUS_EMOJI (e.g., "In Review=:eyes:,Done=:white_check_mark:")
+func emojiFromEnv() slack.Emoji {
+	emoji := slack.Emoji{Status: config.Map("SLACK_STATUS_EMOJI")}
+
+	for key, value := range config.Map("SLACK_EMOJI") {
+		switch strings.ToLower(key) {
+		case "title":
+			emoji.Title = value
+		case "date":
+			emoji.Date = value
+		case "total":
+			emoji.Total = value
+		case "blocked":
+			emoji.Blocked = value
+		case "draft":
+			emoji.Draft = value
+		case "none":
+			emoji.NoBlockedDraft = value
+		case "snoozed":
+			emoji.Snoozed = value
+		case "attention":
+			emoji.Attention = value
+		case "changes":
+			emoji.Changes = value
+		case "stats":
+			emoji.Stats = value
+		case "turnaround":
+			emoji.Turnaround = value
+		case "weekly":
+			emoji.Weekly = value
+		case "monthly":
+			emoji.Monthly = value
+		case "age":
+			emoji.Age = value
+		case "reviewload":
+			emoji.ReviewLoad = value
+		case "labels":
+			emoji.Labels = value
+		case "anomaly":
+			emoji.Anomaly = value
+		case "sprintsummary":
+			emoji.SprintSummary = value
+		case "merged":
+			emoji.Merged = value
+		case "pending":
+			emoji.Pending = value
+		case "leaderboard":
+			emoji.Leaderboard = value
+		case "mergerate":
+			emoji.MergeRate = value
+		case "sprint":
+			emoji.Sprint = value
+		case "cycletime":
+			emoji.CycleTime = value
+		case "statuschange":
+			emoji.StatusChange = value
+		case "sla":
+			emoji.SLA = value
+		case "dataissues":
+			emoji.DataIssues = value
+		case "ticket":
+			emoji.Ticket = value
+		case "escalation":
+			emoji.Escalation = value
+		case "assignee":
+			emoji.Assignee = value
+		default:
+			slog.Warn("Unknown SLACK_EMOJI key", "key", key, "supported", "title, date, total, blocked, draft, none, snoozed, attention, anomaly, changes, stats, turnaround, weekly, monthly, age, merged, pending, leaderboard, reviewload, labels, sla, mergerate, sprint, sprintsummary, cycletime, statuschange, dataissues, ticket, escalation, assignee")
+		}
+	}
+
+	return emoji
+}
+
+// setSLA sets the review SLAs of a report from its prefixed environment
+// variables, falling back to the shared ones. PRs are then fetched with their
+// review times. Review times can only be fetched from GitHub.
+func setSLA(cfg *Config, prefix string) {
+	for _, limit := range []struct {
+		key    string
+		target *time.Duration
+	}{
+		{"SLA_FIRST_REVIEW", &cfg.SLA.FirstReview},
+		{"SLA_APPROVAL", &cfg.SLA.Approval},
+	} {
+		*limit.target = config.Duration(prefix + limit.key)
+		if *limit.target == 0 {
+			*limit.target = config.Duration(limit.key)
+		}
+	}
+
+	if cfg.SLA.Enabled() && cfg.Source != SourceGitHub {
+		cfg.logger().Warn("Review SLAs can only be tracked on GitHub, ignoring them")
+		cfg.SLA = model.SLA{}
+	}
+	if cfg.SLA.Enabled() {
+		cfg.GitHub.FetchDetails = true
+		cfg.GitHub.ReviewTimes = true
+	}
+}
+
+// setFilters sets the base branch, age, title and rule filters of a report
+// from its prefixed environment variables. An invalid title pattern or rule
+// fails the report's runs rather than widening it to every PR.
+func setFilters(cfg *Config, prefix string) {
+	cfg.GitHub.BaseBranches = config.List(prefix + "BASE_BRANCHES")
+	cfg.GitHub.MaxAge = config.Duration(prefix + "MAX_AGE")
+	if cfg.GitHub.MaxAge < 0 {
+		cfg.logger().Warn("Invalid "+prefix+"MAX_AGE, not filtering PRs by age", "value", config.String(prefix+"MAX_AGE"))
+		cfg.GitHub.MaxAge = 0
+	}
+	if pattern := config.String(prefix + "TITLE_PATTERN"); pattern != "" {
+		titlePattern, err := regexp.Compile(pattern)
+		if err != nil {
+			cfg.Invalid = errkind.Configf("invalid %sTITLE_PATTERN: %w", prefix, err)
+		} else {
+			cfg.GitHub.TitlePattern = titlePattern
+		}
+	}
+	if source := config.String(prefix + "RULE"); source != "" {
+		rule, err := rules.Compile(source)
+		if err != nil {
+			cfg.Invalid = errkind.Configf("invalid %sRULE: %w", prefix, err)
+		} else {
+			cfg.GitHub.Rule = rule
+		}
+	}
+}
+
+// setFeatures applies the feature flags of a report (see config.LoadFeatures)
+// over the individual settings of the features they gate. Flags of registered
+// enrichers and sections are checked when the report runs.
+func setFeatures(cfg *Config, prefix string) {
+	cfg.Features = config.LoadFeatures(prefix)
+
+	if enabled, set := cfg.Features.Lookup(config.FeatureDelta); set {
+		cfg.ShowChanges = enabled
+	}
+	if enabled, set := cfg.Features.Lookup(config.FeatureEmails); set {
+		cfg.EmailLookup = enabled
+	}
+	if enabled, set := cfg.Features.Lookup(config.FeatureReviews); set {
+		cfg.GitHub.SkipReviews = !enabled
+		cfg.GitHub.FetchDetails = cfg.GitHub.FetchDetails || enabled
+		if !enabled && (cfg.Turnaround || cfg.SLA.Enabled() || cfg.Slack.MentionPolicy == slack.MentionPolicyTargeted) {
+			cfg.logger().Warn("Reviews are turned off, review turnaround, SLAs and targeted mentions treat every PR as unreviewed")
+		}
+	}
+	if enabled, set := cfg.Features.Lookup(config.FeatureChecks); set {
+		cfg.GitHub.SkipChecks = !enabled
+		cfg.GitHub.FetchDetails = cfg.GitHub.FetchDetails || enabled
+	}
+	if len(cfg.Features) > 0 {
+		cfg.logger().Debug("Applied feature flags", "features", cfg.Features)
+	}
+}
+
+// leaderboardWindow returns the window of the reviewer leaderboard, or 0 when
+// it is turned off
+func leaderboardWindow() time.Duration {
+	if !config.Bool("SLACK_REVIEW_LEADERBOARD") {
+		return 0
+	}
+	if window := config.Duration("SLACK_LEADERBOARD_WINDOW"); window > 0 {
+		return window
+	}
+	return defaultLeaderboardWindow
+}
+
+// setQuietHours sets the quiet hours of a report from QUIET_HOURS (e.g.,
+// "22:00-08:00,Sat-Sun"), QUIET_HOURS_CALENDAR and QUIET_HOURS_MODE. Invalid
+// QUIET_HOURS fail the report's runs rather than pinging everyone at night.
+func setQuietHours(cfg *Config) {
+	windows, err := quiet.ParseWindows(config.String("QUIET_HOURS"))
+	if err != nil {
+		cfg.Invalid = errkind.Configf("invalid QUIET_HOURS: %w", err)
+	}
+
+	mode := strings.ToLower(config.Or("QUIET_HOURS_MODE", quiet.ModeMute))
+	if mode != quiet.ModeMute && mode != quiet.ModeDefer {
+		slog.Warn("Unknown QUIET_HOURS_MODE, using the default", "mode", mode, "default", quiet.ModeMute)
+		mode = quiet.ModeMute
+	}
+
+	cfg.Quiet = quiet.Options{
+		Windows:  windows,
+		Calendar: config.String("QUIET_HOURS_CALENDAR"),
+		Mode:     mode,
+	}
+}
+
+// envPostAt reads a delivery time, either as "HH:MM" today in the local time
+// zone (set TZ to change it) or as an RFC 3339 timestamp. It returns the zero
+// time when unset or invalid.
+func envPostAt(key string) time.Time {
+	value := strings.TrimSpace(config.String(key))
+	if value == "" {
+		return time.Time{}
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+
+	clock, err := time.ParseInLocation("15:04", value, time.Local)
+	if err != nil {
+		slog.Warn("Invalid time, expected HH:MM or RFC 3339, posting immediately", "key", key, "value", value)
+		return time.Time{}
+	}
+
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+}
+
+// trackerFromEnv reads the ticket tracker of a report, defaulting to JIRA
+func trackerFromEnv(key string) string {
+	tracker := strings.ToLower(config.String(key))
+	switch tracker {
+	case "":
+		return TrackerJira
+	case TrackerJira, TrackerLinear, TrackerAsana:
+		return tracker
+	default:
+		slog.Warn("Unknown tracker, using the default", "key", key, "tracker", tracker, "supported", []string{TrackerJira, TrackerLinear, TrackerAsana}, "default", TrackerJira)
+		return TrackerJira
+	}
+}
+
+// clearJiraLinks stops linking tickets to JIRA when a report uses another
+// tracker, whose tickets carry their own links
+func clearJiraLinks(cfg *Config) {
+	if cfg.Source == SourceAzureDevOps || cfg.Tracker != TrackerJira {
+		cfg.Jira.URL, cfg.Slack.JiraURL = "", ""
+	}
+}
+
+// sourceFromEnv reads the PR source of a report, defaulting to GitHub. Sources
+// registered with RegisterSource are accepted too.
+func sourceFromEnv(key string) string {
+	source := strings.ToLower(config.String(key))
+	if source == "" {
+		return SourceGitHub
+	}
+	if _, exists := sources[source]; !exists {
+		supported := make([]string, 0, len(sources))
+		for name := range sources {
+			supported = append(supported, name)
+		}
+		sort.Strings(supported)
+		slog.Warn("Unknown source, using the default", "key", key, "source", source, "supported", supported, "default", SourceGitHub)
+		return SourceGitHub
+	}
+	return source
+}
+
+// gitlabProject reads the GitLab project path of a report, defaulting to the
+// repository name in GITLAB_GROUP
+func gitlabProject(key, repo string) string {
+	if project := config.String(key); project != "" {
+		return project
+	}
+	if group := config.String("GITLAB_GROUP"); group != "" {
+		return group + "/" + repo
+	}
+	return ""
+}
+
+// confluenceMode reads CONFLUENCE_PAGE_MODE, defaulting to a rolling page
+func confluenceMode() string {
+	mode := strings.ToLower(config.String("CONFLUENCE_PAGE_MODE"))
+	switch mode {
+	case "":
+		return confluence.ModeRolling
+	case confluence.ModeRolling, confluence.ModeDaily:
+		return mode
+	default:
+		slog.Warn("Unknown CONFLUENCE_PAGE_MODE, using the default", "mode", mode, "supported", []string{confluence.ModeRolling, confluence.ModeDaily}, "default", confluence.ModeRolling)
+		return confluence.ModeRolling
+	}
+}
+
+// channelTargets reads a comma-separated list of channels, each optionally
+// followed by its verbosity (e.g., "team-channel,leads-channel=summary")
+func channelTargets(key string) []slack.ChannelTarget {
+	var targets []slack.ChannelTarget
+	for _, entry := range config.List(key) {
+		channel, verbosity, _ := strings.Cut(entry, "=")
+		verbosity = strings.ToLower(strings.TrimSpace(verbosity))
+		if verbosity != "" && verbosity != slack.VerbosityFull && verbosity != slack.VerbositySummary {
+			slog.Warn("Unknown channel verbosity, using the default", "key", key, "channel", channel, "verbosity", verbosity, "default", slack.VerbosityFull)
+			verbosity = ""
+		}
+		targets = append(targets, slack.ChannelTarget{Channel: strings.TrimSpace(channel), Verbosity: verbosity})
+	}
+	return targets
+}
+
+// messageFormatFromEnv reads the message format of a report from
+// prefix+"MESSAGE_FORMAT" or MESSAGE_FORMAT, falling back to the plain text
+// layout for unknown formats
+func messageFormatFromEnv(prefix string) string {
+	key := prefix + "MESSAGE_FORMAT"
+	format := strings.ToLower(config.String(key))
+	if format == "" {
+		key = "MESSAGE_FORMAT"
+		format = strings.ToLower(config.String(key))
+	}
+	switch format {
+	case "", slack.MessageFormatV1, slack.MessageFormatV2:
+		return format
+	}
+	slog.Warn("Unknown message format, using the default", "key", key, "format", format, "known", []string{slack.MessageFormatV1, slack.MessageFormatV2}, "default", slack.MessageFormatV1)
+	return ""
+}
+
+// sortOrderFromEnv reads the sort order of a report from prefix+"SORT_BY" or
+// SORT_BY, keeping the order of the source when it's invalid
+func sortOrderFromEnv(prefix string) model.SortOrder {
+	key := prefix + "SORT_BY"
+	value := config.String(key)
+	if value == "" {
+		key = "SORT_BY"
+		value = config.String(key)
+	}
+	order, err := model.ParseSortOrder(value)
+	if err != nil {
+		slog.Warn("Ignoring invalid sort order", "key", key, "value", value, "error", err)
+	}
+	return order
+}
+
+// localeFromEnv reads SLACK_LOCALE, falling back to English for unsupported locales
+func localeFromEnv() string {
+	locale := config.String("SLACK_LOCALE")
+	if locale != "" && !model.IsSupportedLocale(locale) {
+		slog.Warn("Unsupported SLACK_LOCALE, using the default", "locale", locale, "supported", model.Locales(), "default", model.DefaultLocale)
+		return ""
+	}
+	return locale
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/store"
+)
+
+// monthlyCycleTime records the PRs merged since the last run in the state
+// database and, in the first report of a month, returns the cycle time of the
+// previous month compared with the month before. It returns nil otherwise.
+func monthlyCycleTime(cfg Config) *model.CycleTime {
+	path := statePath(cfg)
+	if !store.IsDatabase(path) {
+		cfg.logger().Warn("Cycle times are kept in the state database, the report won't have them", "state", store.Redacted(path))
+		return nil
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		cfg.logger().Warn("Could not load cycle times", "error", err)
+		return nil
+	}
+	defer db.Close()
+
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	lastMonth := month.AddDate(0, -1, 0)
+	recordMerges(cfg, db, lastMonth)
+
+	// Only the first report of the month shows the previous month
+	previous, err := db.LastSnapshot(cfg.Name, now)
+	if err != nil {
+		cfg.logger().Warn("Could not load the last snapshot", "error", err)
+		return nil
+	}
+	if previous != nil && !previous.TakenAt.Before(month) {
+		return nil
+	}
+
+	cycleTime := cycleTimeOf(cfg, db, lastMonth, month)
+	if cycleTime != nil {
+		cycleTime.Previous = cycleTimeOf(cfg, db, lastMonth.AddDate(0, -1, 0), lastMonth)
+	}
+	return cycleTime
+}
+
+// recordMerges saves the PRs merged since the last recorded merge, or since
+// the start of the previous month on the first run. Merged PRs can only be
+// fetched from GitHub.
+func recordMerges(cfg Config, db store.Store, lastMonth time.Time) {
+	if cfg.Source != SourceGitHub {
+		cfg.logger().Warn("Merged PRs can only be fetched from GitHub, no merges are recorded")
+		return
+	}
+
+	since, err := db.LastMerge(cfg.Name)
+	if err != nil {
+		cfg.logger().Warn("Could not load the last merge", "error", err)
+		return
+	}
+	if since.Before(lastMonth) {
+		since = lastMonth
+	}
+
+	githubPRs, err := cfg.gitHubClient().FetchMergedPRs(cfg.GitHub, since)
+	if err != nil {
+		cfg.logger().Warn("Could not fetch merged PRs", "error", err)
+		return
+	}
+
+	merges := make([]store.Merge, len(githubPRs))
+	for i, pr := range githubPRs {
+		merges[i] = store.Merge{Number: pr.Number, OpenedAt: pr.CreatedAt, MergedAt: pr.MergedAt}
+	}
+	if err := db.SaveMerges(cfg.Name, merges); err != nil {
+		cfg.logger().Warn("Could not save merges", "error", err)
+		return
+	}
+
+	cfg.logger().Debug("Recorded merges", "merges", len(merges), "since", since)
+}
+
+// cycleTimeOf computes the cycle time of the PRs merged within [since, until)
+func cycleTimeOf(cfg Config, db store.Store, since, until time.Time) *model.CycleTime {
+	merges, err := db.Merges(cfg.Name, since, until)
+	if err != nil {
+		cfg.logger().Warn("Could not load merges", "error", err)
+		return nil
+	}
+
+	cycleTimes := make([]time.Duration, len(merges))
+	for i, merge := range merges {
+		cycleTimes[i] = merge.MergedAt.Sub(merge.OpenedAt)
+	}
+	return model.NewCycleTime(since, cycleTimes)
+}
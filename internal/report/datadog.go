@@ -0,0 +1,81 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"pr-reporter/internal/datadog"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+)
+
+// sendDatadog sends an event for a report run and, when the PRs were fetched,
+// gauges of its PR counts so review health can be graphed in Datadog
+func sendDatadog(cfg Config, prs []*slack.PRInfo, duration time.Duration, runErr error) {
+	tags := []string{"report:" + cfg.Name, "source:" + sourceName(cfg)}
+
+	event := datadog.Event{
+		AggregationKey: "pr-reporter-" + cfg.Name,
+		SourceTypeName: "pr-reporter",
+		Tags:           tags,
+	}
+	metrics := []datadog.Metric{
+		{Name: "pr_reporter.run.duration", Value: duration.Seconds(), Tags: tags},
+		{Name: "pr_reporter.run.success", Value: boolValue(runErr == nil), Tags: tags},
+	}
+
+	if prs != nil {
+		report := newReport(cfg, prs)
+		blocked := len(report.Blocked())
+		stale := len(stalePRs(cfg, report))
+		metrics = append(metrics,
+			datadog.Metric{Name: "pr_reporter.prs.open", Value: float64(len(report.PRs)), Tags: tags},
+			datadog.Metric{Name: "pr_reporter.prs.blocked", Value: float64(blocked), Tags: tags},
+			datadog.Metric{Name: "pr_reporter.prs.stale", Value: float64(stale), Tags: tags},
+			datadog.Metric{Name: "pr_reporter.prs.draft", Value: float64(len(report.Drafts())), Tags: tags},
+		)
+		if cfg.Turnaround {
+			turnaround := reviewTurnaround(cfg, prs)
+			if turnaround.Reviewed > 0 {
+				metrics = append(metrics, datadog.Metric{Name: "pr_reporter.review.first_review_hours", Value: turnaround.FirstReview.Hours(), Tags: tags})
+			}
+			if turnaround.Approved > 0 {
+				metrics = append(metrics, datadog.Metric{Name: "pr_reporter.review.approval_hours", Value: turnaround.Approval.Hours(), Tags: tags})
+			}
+		}
+		event.Text = fmt.Sprintf("%d open, %d blocked, %d stale, %d draft PR(s)", len(report.PRs), blocked, stale, len(report.Drafts()))
+	}
+
+	if runErr != nil {
+		event.Title = fmt.Sprintf("%s run failed", cfg.Slack.ReportTitle)
+		event.Text = runErr.Error()
+		event.AlertType = datadog.AlertError
+	} else {
+		event.Title = fmt.Sprintf("%s run succeeded", cfg.Slack.ReportTitle)
+		event.AlertType = datadog.AlertSuccess
+	}
+
+	if err := datadog.SendEvent(cfg.Datadog, event); err != nil {
+		cfg.logger().Warn("Could not send Datadog event", "error", err)
+	}
+	if err := datadog.SendMetrics(cfg.Datadog, metrics); err != nil {
+		cfg.logger().Warn("Could not send Datadog metrics", "error", err)
+	}
+}
+
+// stalePRs returns the PRs ready for review that weren't updated within the
+// report's stale threshold
+func stalePRs(cfg Config, report model.Report) []*model.PR {
+	staleAfter := cfg.Slack.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = slack.DefaultStaleAfter
+	}
+
+	var prs []*model.PR
+	for _, pr := range report.PRs {
+		if !pr.IsDraft && !pr.UpdatedAt.IsZero() && report.Date.Sub(pr.UpdatedAt) > staleAfter {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
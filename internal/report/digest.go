@@ -0,0 +1,57 @@
+package report
+
+import (
+	"strings"
+
+	"pr-reporter/internal/slack"
+)
+
+// buildDigests groups PRs by the mapped Slack users involved in them, as
+// author, assignee or requested reviewer
+func buildDigests(cfg Config, prs []*slack.PRInfo) map[string][]slack.DigestPR {
+	digests := make(map[string][]slack.DigestPR)
+
+	for _, pr := range prs {
+		roles := make(map[string][]string) // Slack user ID -> roles
+		var order []string
+
+		addRole := func(githubUser, role string) {
+			if githubUser == "" || strings.HasPrefix(githubUser, "team:") {
+				return
+			}
+			slackID, exists := lookupSlackID(cfg.UserMapping, githubUser)
+			if !exists {
+				return
+			}
+			if _, seen := roles[slackID]; !seen {
+				order = append(order, slackID)
+			}
+			roles[slackID] = append(roles[slackID], role)
+		}
+
+		addRole(pr.Author, slack.RoleAuthor)
+		addRole(pr.GithubAssignee, slack.RoleAssignee)
+		for _, reviewer := range pr.RequestedReviewers {
+			addRole(reviewer, slack.RoleReviewer)
+		}
+
+		for _, slackID := range order {
+			digests[slackID] = append(digests[slackID], slack.DigestPR{PR: pr, Roles: roles[slackID]})
+		}
+	}
+
+	return digests
+}
+
+// lookupSlackID finds the Slack user ID of a GitHub user, ignoring case
+func lookupSlackID(mapping map[string]string, githubUser string) (string, bool) {
+	if slackID, exists := mapping[githubUser]; exists {
+		return slackID, true
+	}
+	for user, slackID := range mapping {
+		if strings.EqualFold(user, githubUser) {
+			return slackID, true
+		}
+	}
+	return "", false
+}
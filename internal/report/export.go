@@ -0,0 +1,43 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"pr-reporter/internal/export"
+)
+
+// Export collects the PRs of a report with their JIRA tickets and writes them
+// to out in the given format without posting anywhere. An out of "-" writes
+// to standard output.
+func Export(cfg Config, format, out string) error {
+	if !export.IsSupported(format) {
+		return fmt.Errorf("unsupported export format %q (supported: %s)", format, strings.Join(export.Formats(), ", "))
+	}
+	if out == "" {
+		return fmt.Errorf("output path is required")
+	}
+
+	prs, err := CollectPRs(cfg)
+	if err != nil {
+		return err
+	}
+
+	data, err := export.Encode(newReport(cfg, prs), format)
+	if err != nil {
+		return err
+	}
+
+	if out == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(out, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", out, err)
+	}
+
+	cfg.logger().Info("Exported PRs", "prs", len(prs), "file", out)
+	return nil
+}
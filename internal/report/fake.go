@@ -0,0 +1,188 @@
+package report
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"pr-reporter/internal/github"
+	"pr-reporter/internal/jira"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+)
+
+// FakeGitHub is a GitHubClient serving fixed data without network access
+type FakeGitHub struct {
+	PRs     []*github.PRResult  // Open PRs returned by FetchPRs, with their details already set
+	Merged  []*github.PRResult  // PRs returned by FetchMergedPRs when merged since the given time
+	Reviews map[string]int      // Reviewer -> PRs reviewed, returned by FetchReviewCounts
+	Emails  map[string][]string // Login -> email addresses, returned by FetchUserEmails
+	Err     error               // Returned by every call when set
+}
+
+// NewFakeGitHub returns a FakeGitHub serving prs as the open PRs
+func NewFakeGitHub(prs ...*github.PRResult) *FakeGitHub {
+	return &FakeGitHub{PRs: prs}
+}
+
+func (f *FakeGitHub) FetchPRs(ctx context.Context, opts github.FetchOptions) ([]*github.PRResult, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.PRs, nil
+}
+
+func (f *FakeGitHub) FetchDetails(ctx context.Context, opts github.FetchOptions, prs []*github.PRResult) error {
+	return f.Err
+}
+
+func (f *FakeGitHub) FetchMergedPRs(opts github.FetchOptions, since time.Time) ([]*github.PRResult, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	var merged []*github.PRResult
+	for _, pr := range f.Merged {
+		if !pr.MergedAt.Before(since) {
+			merged = append(merged, pr)
+		}
+	}
+	return merged, nil
+}
+
+func (f *FakeGitHub) FetchReviewCounts(opts github.FetchOptions, since time.Time) (map[string]int, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Reviews, nil
+}
+
+func (f *FakeGitHub) FetchUserEmails(opts github.FetchOptions, logins []string, prs []*github.PRResult) (map[string][]string, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	emails := make(map[string][]string)
+	for _, login := range logins {
+		if addresses, exists := f.Emails[login]; exists {
+			emails[login] = addresses
+		}
+	}
+	return emails, nil
+}
+
+// FakeJira is a JiraClient serving fixed tickets and sprints without network
+// access
+type FakeJira struct {
+	Tickets      map[string]*jira.TicketInfo // Ticket ID -> ticket; others are "Not Found" like missing JIRA tickets
+	ActiveSprint *jira.Sprint                // Returned by FetchActiveSprint
+	ClosedSprint *jira.Sprint                // Returned by FetchLastClosedSprint
+	Err          error                       // Returned by every call when set
+}
+
+// NewFakeJira returns a FakeJira serving tickets
+func NewFakeJira(tickets ...*jira.TicketInfo) *FakeJira {
+	f := &FakeJira{Tickets: make(map[string]*jira.TicketInfo)}
+	for _, ticket := range tickets {
+		f.Tickets[ticket.TicketID] = ticket
+	}
+	return f
+}
+
+func (f *FakeJira) FetchTicketInfo(ctx context.Context, opts jira.FetchOptions, ticketID string) (*jira.TicketInfo, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if ticket, exists := f.Tickets[ticketID]; exists {
+		return ticket, nil
+	}
+	return &jira.TicketInfo{TicketID: ticketID, Status: "Not Found", Summary: "Ticket not found"}, nil
+}
+
+func (f *FakeJira) FetchTicketsInfo(ctx context.Context, opts jira.FetchOptions, ticketIDs []string) (map[string]*jira.TicketInfo, error) {
+	results := make(map[string]*jira.TicketInfo)
+	for _, ticketID := range ticketIDs {
+		ticket, err := f.FetchTicketInfo(ctx, opts, ticketID)
+		if err != nil {
+			return results, err
+		}
+		results[ticketID] = ticket
+	}
+	return results, nil
+}
+
+func (f *FakeJira) FetchActiveSprint(opts jira.FetchOptions, boardID int) (*jira.Sprint, error) {
+	return f.ActiveSprint, f.Err
+}
+
+func (f *FakeJira) FetchLastClosedSprint(opts jira.FetchOptions, boardID int) (*jira.Sprint, error) {
+	return f.ClosedSprint, f.Err
+}
+
+// FakeSlack is a SlackClient recording what would have been sent. Reports
+// are delivered concurrently, so read its fields only after the run returned.
+type FakeSlack struct {
+	Reports   [][]*slack.PRInfo               // PRs of each report sent
+	Previews  [][]*slack.PRInfo               // PRs of each preview sent
+	Digests   []map[string][]slack.DigestPR   // Each batch of digests sent
+	Reminders []map[string][]slack.ReminderPR // Each batch of review reminders sent
+	Alerts    [][]model.SLABreach             // Breaches of each SLA alert sent
+	Options   []slack.MessageOptions          // Options of each report sent, e.g. to check footer sections
+	Err       error                           // Returned by every call when set
+
+	mu sync.Mutex
+}
+
+// NewFakeSlack returns an empty FakeSlack
+func NewFakeSlack() *FakeSlack {
+	return &FakeSlack{}
+}
+
+func (f *FakeSlack) SendPRReport(ctx context.Context, opts slack.MessageOptions, prs []*slack.PRInfo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Reports = append(f.Reports, prs)
+	f.Options = append(f.Options, opts)
+	return nil
+}
+
+func (f *FakeSlack) SendPreview(ctx context.Context, opts slack.MessageOptions, report string, prs []*slack.PRInfo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Previews = append(f.Previews, prs)
+	return nil
+}
+
+func (f *FakeSlack) SendDigests(ctx context.Context, opts slack.MessageOptions, digests map[string][]slack.DigestPR) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Digests = append(f.Digests, digests)
+	return nil
+}
+
+func (f *FakeSlack) SendReminders(ctx context.Context, opts slack.MessageOptions, reminders map[string][]slack.ReminderPR) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Reminders = append(f.Reminders, reminders)
+	return nil
+}
+
+func (f *FakeSlack) SendSLAAlert(opts slack.MessageOptions, breaches []model.SLABreach, compliance model.SLACompliance) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Alerts = append(f.Alerts, breaches)
+	return nil
+}
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"pr-reporter/internal/state"
+	"pr-reporter/internal/store"
+)
+
+// History writes the deliveries recorded in the audit log since the given
+// time to w, oldest first: when, which report, which output and target, and
+// whether it went out. Only the report of cfg is listed unless allReports is
+// set, and only failed deliveries with failedOnly.
+func History(cfg Config, allReports bool, since time.Time, failedOnly bool, w io.Writer) error {
+	path := statePath(cfg)
+	if !store.IsDatabase(path) {
+		return fmt.Errorf("the audit log is kept in a state database (see STATE_FILE), not %s", store.Redacted(path))
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	deliveries, err := db.Deliveries(since)
+	if err != nil {
+		return err
+	}
+
+	var listed []store.Delivery
+	for _, d := range deliveries {
+		if (allReports || d.Report == cfg.Name) && (!failedOnly || !d.Succeeded()) {
+			listed = append(listed, d)
+		}
+	}
+	if len(listed) == 0 {
+		_, err = fmt.Fprintf(w, "No deliveries since %s\n", since.Format("2006-01-02 15:04"))
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SENT\tREPORT\tOUTPUT\tTARGET\tPAYLOAD\tSTATUS")
+	for _, d := range listed {
+		status := "sent"
+		if !d.Succeeded() {
+			status = "failed: " + d.Error
+		}
+		target := d.Target
+		if target == "" {
+			target = "-"
+		}
+		hash := d.PayloadHash
+		if len(hash) > 12 {
+			hash = hash[:12]
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", d.SentAt.Format("2006-01-02 15:04:05"), d.Report, d.Output, target, hash, status)
+	}
+	return table.Flush()
+}
+
+// PostedReports writes the latest Slack report posted to every channel to w,
+// with its permalink and the permalink of the report posted before it on an
+// earlier day. Only the reports of cfg's repository are listed unless
+// allReports is set.
+func PostedReports(cfg Config, allReports bool, w io.Writer) error {
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		return err
+	}
+
+	repo := "|" + cfg.Slack.GithubOwner + "/" + cfg.Slack.GithubRepo
+	var keys []string
+	for key, msg := range store.Messages {
+		if (allReports || strings.HasSuffix(key, repo)) && len(msg.Parts) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		_, err = fmt.Fprintln(w, "No posted reports")
+		return err
+	}
+	sort.Strings(keys)
+
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "POSTED\tCHANNEL\tREPOSITORY\tPERMALINK\tPREVIOUS")
+	for _, key := range keys {
+		msg := store.Messages[key]
+		channel, repository, _ := strings.Cut(key, "|")
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", msg.PostedAt.Format("2006-01-02 15:04:05"), channel, repository,
+			orDash(msg.Permalink), orDash(msg.PreviousPermalink))
+	}
+	return table.Flush()
+}
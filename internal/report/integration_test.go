@@ -0,0 +1,382 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"pr-reporter/internal/errkind"
+)
+
+// stubAPIs serves the GitHub, JIRA and Slack APIs from the recorded responses
+// in testdata and records what the report posts to Slack
+type stubAPIs struct {
+	GitHub *httptest.Server
+	Jira   *httptest.Server
+	Slack  *httptest.Server
+
+	mu          sync.Mutex
+	posted      []url.Values // chat.postMessage requests, in order
+	githubError int          // Status returned by every GitHub request when set
+	jiraError   int          // Status returned by every JIRA request when set
+}
+
+// newStubAPIs starts the stub servers and points the frontend report at them
+func newStubAPIs(t *testing.T) *stubAPIs {
+	t.Helper()
+	stubs := &stubAPIs{}
+
+	stubs.GitHub = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if stubs.githubError != 0 {
+			http.Error(w, `{"message": "stubbed failure"}`, stubs.githubError)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-github-token" {
+			http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/repos/acme/fips-web-client/pulls":
+			serveFixture(t, w, "github/pulls.json")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(stubs.GitHub.Close)
+
+	stubs.Jira = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if stubs.jiraError != 0 {
+			http.Error(w, `{"errorMessages": ["stubbed failure"]}`, stubs.jiraError)
+			return
+		}
+		ticket, found := strings.CutPrefix(r.URL.Path, "/rest/api/2/issue/")
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		if _, err := os.Stat(filepath.Join("testdata", "jira", ticket+".json")); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorMessages": ["Issue does not exist or you do not have permission to see it."]}`))
+			return
+		}
+		serveFixture(t, w, "jira/"+ticket+".json")
+	}))
+	t.Cleanup(stubs.Jira.Close)
+
+	stubs.Slack = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("invalid Slack request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch strings.TrimPrefix(r.URL.Path, "/") {
+		case "chat.postMessage":
+			stubs.mu.Lock()
+			stubs.posted = append(stubs.posted, r.PostForm)
+			stubs.mu.Unlock()
+			json.NewEncoder(w).Encode(map[string]any{"ok": true, "channel": r.PostForm.Get("channel"), "ts": "1714550400.000100"})
+		case "chat.getPermalink":
+			json.NewEncoder(w).Encode(map[string]any{"ok": true, "permalink": "https://acme.slack.com/archives/C0TEAM/p1714550400000100"})
+		default:
+			json.NewEncoder(w).Encode(map[string]any{"ok": true})
+		}
+	}))
+	t.Cleanup(stubs.Slack.Close)
+
+	for key, value := range map[string]string{
+		"GITHUB_TOKEN":    "test-github-token",
+		"GITHUB_OWNER":    "acme",
+		"GITHUB_API_URL":  stubs.GitHub.URL,
+		"JIRA_URL":        stubs.Jira.URL,
+		"JIRA_USERNAME":   "reporter@acme.test",
+		"JIRA_API_TOKEN":  "test-jira-token",
+		"SLACK_TOKEN":     "xoxb-test",
+		"SLACK_API_URL":   stubs.Slack.URL,
+		"SLACK_CHANNEL":   "C0TEAM",
+		"USER_MAPPING":    "U0ALICE:alice,U0BOB:bob",
+		"STATE_FILE":      filepath.Join(t.TempDir(), "state.json"),
+		"FRONTEND_LABELS": "Poker",
+	} {
+		t.Setenv(key, value)
+	}
+
+	return stubs
+}
+
+// serveFixture writes a recorded JSON response from testdata
+func serveFixture(t *testing.T, w http.ResponseWriter, name string) {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Errorf("missing fixture %s: %v", name, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// messages returns the text of every message posted to Slack
+func (s *stubAPIs) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var texts []string
+	for _, form := range s.posted {
+		texts = append(texts, form.Get("text"))
+	}
+	return texts
+}
+
+// postedReport returns the only report posted to Slack
+func (s *stubAPIs) postedReport(t *testing.T) string {
+	t.Helper()
+	messages := s.messages()
+	if len(messages) != 1 {
+		t.Fatalf("got %d Slack messages, want 1: %q", len(messages), messages)
+	}
+	return messages[0]
+}
+
+// assertPRs checks which of the fixture PRs made it into the report
+func assertPRs(t *testing.T, report string, included, excluded []string) {
+	t.Helper()
+	for _, pr := range included {
+		if !strings.Contains(report, pr) {
+			t.Errorf("report is missing %s:\n%s", pr, report)
+		}
+	}
+	for _, pr := range excluded {
+		if strings.Contains(report, pr) {
+			t.Errorf("report includes %s, which should be filtered out:\n%s", pr, report)
+		}
+	}
+}
+
+func TestFrontendReport(t *testing.T) {
+	stubs := newStubAPIs(t)
+
+	if err := RunReport(FrontendConfig()); err != nil {
+		t.Fatalf("RunReport: %v", err)
+	}
+
+	report := stubs.postedReport(t)
+	if got := stubs.posted[0].Get("channel"); got != "C0TEAM" {
+		t.Errorf("report posted to %q, want C0TEAM", got)
+	}
+
+	// #103 lacks the Poker label and is by an unmapped bot, #104 is by an
+	// unmapped user and #106 has no author
+	assertPRs(t, report, []string{"pull/101", "pull/102", "pull/105"}, []string{"pull/103", "pull/104", "pull/106"})
+
+	if strings.Contains(report, "Data issues") {
+		t.Errorf("report lists data issues although every API answered:\n%s", report)
+	}
+
+	for _, want := range []string{
+		"POKER-101",
+		"Filter lobby tables by stake", // JIRA summary
+		"In Review",                    // JIRA status
+		"Blocked",
+		"<@U0ALICE>", // Assignee mapped to Slack
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestFrontendReportFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		included []string
+		excluded []string
+	}{
+		{
+			name:     "base branch",
+			env:      map[string]string{"FRONTEND_BASE_BRANCHES": "main"},
+			included: []string{"pull/101", "pull/102"},
+			excluded: []string{"pull/105"},
+		},
+		{
+			name:     "title pattern",
+			env:      map[string]string{"FRONTEND_TITLE_PATTERN": "(?i)hotfix"},
+			included: []string{"pull/105"},
+			excluded: []string{"pull/101", "pull/102"},
+		},
+		{
+			name:     "rule",
+			env:      map[string]string{"FRONTEND_RULE": `!draft && has(labels, "Poker")`},
+			included: []string{"pull/101"},
+			excluded: []string{"pull/102", "pull/105"},
+		},
+		{
+			name:     "labels",
+			env:      map[string]string{"FRONTEND_LABELS": "bug"},
+			included: []string{"pull/102"},
+			excluded: []string{"pull/101", "pull/105"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubs := newStubAPIs(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			if err := RunReport(FrontendConfig()); err != nil {
+				t.Fatalf("RunReport: %v", err)
+			}
+			assertPRs(t, stubs.postedReport(t), tt.included, tt.excluded)
+		})
+	}
+}
+
+func TestFrontendReportJiraUnavailable(t *testing.T) {
+	stubs := newStubAPIs(t)
+	stubs.jiraError = http.StatusInternalServerError
+
+	// Tickets that can't be looked up don't stop the report, which says so
+	if err := RunReport(FrontendConfig()); err != nil {
+		t.Fatalf("RunReport: %v", err)
+	}
+
+	report := stubs.postedReport(t)
+	assertPRs(t, report, []string{"pull/101", "pull/102", "pull/105"}, nil)
+	if strings.Contains(report, "Filter lobby tables by stake") {
+		t.Errorf("report shows a JIRA summary although JIRA failed:\n%s", report)
+	}
+	for _, want := range []string{"Data issues", "could not fetch 3 of 3 JIRA tickets", "POKER-101, POKER-102, POKER-105"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestFrontendReportGitHubUnavailable(t *testing.T) {
+	stubs := newStubAPIs(t)
+	stubs.githubError = http.StatusUnauthorized
+
+	err := RunReport(FrontendConfig())
+	if err == nil || !strings.Contains(err.Error(), "error fetching PRs") {
+		t.Fatalf("RunReport error = %v, want a fetch error", err)
+	}
+	var authErr *errkind.AuthError
+	if !errors.As(err, &authErr) || authErr.API != "GitHub" || !errors.Is(err, errkind.ErrFetch) {
+		t.Errorf("RunReport error = %#v, want a GitHub auth error failing the fetch", err)
+	}
+	if code := errkind.ExitCode(err); code != errkind.ExitAuth {
+		t.Errorf("exit code = %d, want %d", code, errkind.ExitAuth)
+	}
+	if messages := stubs.messages(); len(messages) != 0 {
+		t.Errorf("posted %d Slack messages after GitHub failed, want none", len(messages))
+	}
+}
+
+func TestFrontendReportFeatureFlags(t *testing.T) {
+	stubs := newStubAPIs(t)
+	stubs.jiraError = http.StatusInternalServerError
+	t.Setenv("FEATURES", "tickets=true")
+	t.Setenv("FRONTEND_FEATURES", "tickets=false")
+
+	// The report's flag overrides the shared one, so JIRA isn't asked at all
+	if err := RunReport(FrontendConfig()); err != nil {
+		t.Fatalf("RunReport: %v", err)
+	}
+
+	report := stubs.postedReport(t)
+	assertPRs(t, report, []string{"pull/101", "pull/102", "pull/105"}, nil)
+	for _, unwanted := range []string{"Data issues", "Filter lobby tables by stake"} {
+		if strings.Contains(report, unwanted) {
+			t.Errorf("report includes %q with tickets turned off:\n%s", unwanted, report)
+		}
+	}
+}
+
+func TestFrontendReportSortOrder(t *testing.T) {
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{sortBy: "", want: []string{"pull/101", "pull/102", "pull/105"}},
+		{sortBy: "number:desc", want: []string{"pull/105", "pull/102", "pull/101"}},
+		{sortBy: "age", want: []string{"pull/101", "pull/102", "pull/105"}},
+		{sortBy: "age:asc", want: []string{"pull/105", "pull/102", "pull/101"}},
+		// POKER-102 has a priority that isn't ranked, so its PR comes last either way
+		{sortBy: "priority", want: []string{"pull/105", "pull/101", "pull/102"}},
+		{sortBy: "priority:asc", want: []string{"pull/101", "pull/105", "pull/102"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			stubs := newStubAPIs(t)
+			t.Setenv("FRONTEND_SORT_BY", tt.sortBy)
+
+			if err := RunReport(FrontendConfig()); err != nil {
+				t.Fatalf("RunReport: %v", err)
+			}
+
+			report := stubs.postedReport(t)
+			for i := 1; i < len(tt.want); i++ {
+				if strings.Index(report, tt.want[i-1]) > strings.Index(report, tt.want[i]) {
+					t.Errorf("report lists %s after %s, want %v:\n%s", tt.want[i-1], tt.want[i], tt.want, report)
+				}
+			}
+		})
+	}
+}
+
+func TestFrontendReportInvalidRule(t *testing.T) {
+	stubs := newStubAPIs(t)
+	t.Setenv("FRONTEND_RULE", `!draft &&`)
+
+	// A typo in the rule fails the run instead of reporting every PR
+	err := RunReport(FrontendConfig())
+	if code := errkind.ExitCode(err); code != errkind.ExitConfig {
+		t.Errorf("RunReport error = %v (exit code %d), want a config error", err, code)
+	}
+	if messages := stubs.messages(); len(messages) != 0 {
+		t.Errorf("posted %d Slack messages with an invalid rule, want none", len(messages))
+	}
+}
+
+func TestFrontendReportInvalidQuietHours(t *testing.T) {
+	stubs := newStubAPIs(t)
+	t.Setenv("QUIET_HOURS", "22:00-8,Sat-Sun")
+
+	// A typo in the quiet hours fails the run instead of pinging everyone
+	err := RunReport(FrontendConfig())
+	if code := errkind.ExitCode(err); code != errkind.ExitConfig {
+		t.Errorf("RunReport error = %v (exit code %d), want a config error", err, code)
+	}
+	if messages := stubs.messages(); len(messages) != 0 {
+		t.Errorf("posted %d Slack messages with invalid quiet hours, want none", len(messages))
+	}
+}
+
+func TestOnDemandReportDuringQuietHours(t *testing.T) {
+	stubs := newStubAPIs(t)
+	t.Setenv("QUIET_HOURS", "00:00-24:00")
+	t.Setenv("QUIET_HOURS_MODE", "defer")
+
+	// Reports requested from Slack aren't deferred, they're posted without pinging anyone
+	if _, err := RunOnDemand("report=frontend", "C0ASKED"); err != nil {
+		t.Fatalf("RunOnDemand: %v", err)
+	}
+
+	report := stubs.postedReport(t)
+	if got := stubs.posted[0].Get("channel"); got != "C0ASKED" {
+		t.Errorf("report posted to %q, want C0ASKED", got)
+	}
+	assertPRs(t, report, []string{"pull/101", "pull/102", "pull/105"}, nil)
+	if strings.Contains(report, "<@") || strings.Contains(report, "<!subteam") {
+		t.Errorf("report mentions people during quiet hours:\n%s", report)
+	}
+}
@@ -0,0 +1,17 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+)
+
+// labelBreakdown counts the open PRs of the tracked labels and compares them
+// with the last report at least model.LabelBreakdownDays old
+func labelBreakdown(cfg Config, prs []*slack.PRInfo) *model.LabelBreakdown {
+	if previous := lastReportBefore(cfg, time.Now().AddDate(0, 0, -model.LabelBreakdownDays)); previous != nil {
+		return model.NewLabelBreakdown(cfg.Labels, prs, previous.PRs, previous.TakenAt)
+	}
+	return model.NewLabelBreakdown(cfg.Labels, prs, nil, time.Time{})
+}
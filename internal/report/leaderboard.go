@@ -0,0 +1,32 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/model"
+)
+
+// defaultLeaderboardWindow is the window of the reviewer leaderboard when
+// none is configured
+const defaultLeaderboardWindow = 7 * 24 * time.Hour
+
+// reviewLeaderboard ranks the reviewers of the report's PRs by the PRs they
+// reviewed within the leaderboard window. Reviews can only be fetched from
+// GitHub; nil is returned for other sources, and with the error when fetching
+// fails.
+func reviewLeaderboard(cfg Config) (*model.Leaderboard, error) {
+	if cfg.Source != SourceGitHub {
+		cfg.logger().Warn("Reviews can only be counted on GitHub, the report won't have a review leaderboard")
+		return nil, nil
+	}
+
+	since := time.Now().Add(-cfg.Leaderboard)
+
+	counts, err := cfg.gitHubClient().FetchReviewCounts(cfg.GitHub, since)
+	if err != nil {
+		cfg.logger().Warn("Could not count reviews", "error", err)
+		return nil, err
+	}
+
+	return model.NewLeaderboard(counts, since), nil
+}
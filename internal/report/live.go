@@ -0,0 +1,27 @@
+package report
+
+import (
+	"log/slog"
+	"time"
+)
+
+// RefreshLive re-runs every report with a live status message at the given
+// interval, so the pinned messages stay current between scheduled runs. It
+// never returns.
+func RefreshLive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for _, name := range Names {
+			cfg, err := ConfigFor(name)
+			if err != nil || !cfg.Slack.LiveStatus {
+				continue
+			}
+
+			if err := RunReport(slackOnly(cfg)); err != nil {
+				slog.Warn("Could not refresh live status", "report", name, "error", err)
+			}
+		}
+	}
+}
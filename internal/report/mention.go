@@ -0,0 +1,171 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"pr-reporter/internal/slack"
+)
+
+// mentionHelp lists what the bot understands when mentioned
+const mentionHelp = "Here's what I can do:\n" +
+	"• `status` – open, blocked and draft PR counts per report\n" +
+	"• `status POKER-123` – PRs and JIRA status for a ticket\n" +
+	"• `report` – post every report here now\n" +
+	"• `report frontend labels=Poker` – post a single report, optionally with other labels\n" +
+	"• `reminders off` / `reminders on` – stop or resume your review reminder DMs"
+
+// HandleMention answers a message mentioning the bot, such as "status POKER-123"
+// or "report frontend labels=Poker"
+func HandleMention(text, channelID, userID string) (string, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return mentionHelp, nil
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "status":
+		if len(fields) > 1 {
+			return ticketStatus(strings.ToUpper(fields[1]))
+		}
+		return reportsStatus()
+	case "report":
+		// Takes the arguments of the slash command and posts to the channel
+		// the bot was mentioned in
+		return RunOnDemand(strings.Join(fields[1:], " "), channelID)
+	case "reminders":
+		if len(fields) > 1 {
+			return setReminders(userID, strings.ToLower(fields[1]))
+		}
+		return mentionHelp, nil
+	default:
+		return mentionHelp, nil
+	}
+}
+
+// setReminders turns the review reminders of the mentioning user "off" or "on"
+func setReminders(userID, setting string) (string, error) {
+	if setting != "off" && setting != "on" {
+		return mentionHelp, nil
+	}
+
+	// Reports share the state file, which keeps the opt-outs
+	cfg, err := ConfigFor(Names[0])
+	if err != nil {
+		return "", err
+	}
+	if err := SetReminders(cfg, userID, setting == "on"); err != nil {
+		return "", err
+	}
+
+	if setting == "off" {
+		return "🔕 Got it, no more review reminders for you. Mention me with `reminders on` to get them again.", nil
+	}
+	return "🔔 Review reminders are back on.", nil
+}
+
+// reportsStatus summarizes the open PRs of every report
+func reportsStatus() (string, error) {
+	lines := []string{"📊 *Open PRs*"}
+
+	for _, name := range Names {
+		cfg, err := ConfigFor(name)
+		if err != nil {
+			return "", err
+		}
+
+		prs, err := CollectPRs(cfg)
+		if err != nil {
+			return "", fmt.Errorf("%s report failed: %w", cfg.Name, err)
+		}
+
+		blocked, drafts := 0, 0
+		for _, pr := range prs {
+			if pr.IsBlocked {
+				blocked++
+			}
+			if pr.IsDraft {
+				drafts++
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("• *%s* (%s): %d open, %d blocked, %d draft",
+			cfg.Slack.ReportTitle, cfg.GitHub.Repo, len(prs), blocked, drafts))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// ticketStatus looks up the status of a ticket and the open PRs referencing it
+func ticketStatus(ticketID string) (string, error) {
+	ctx := context.Background()
+	var prLines []string
+	var ticketCfg Config
+
+	for _, name := range Names {
+		cfg, err := ConfigFor(name)
+		if err != nil {
+			return "", err
+		}
+		if ticketCfg.Name == "" {
+			ticketCfg = cfg // Tickets without PRs are looked up in the first report's tracker
+		}
+
+		githubPRs, err := fetchPRs(ctx, cfg)
+		if err != nil {
+			return "", fmt.Errorf("error fetching PRs from %s: %w", sourceName(cfg), err)
+		}
+
+		for _, pr := range githubPRs {
+			if pr.JiraTicket != ticketID {
+				continue
+			}
+			if len(prLines) == 0 {
+				ticketCfg = cfg // The ticket lives in the tracker of the report referencing it
+			}
+
+			line := fmt.Sprintf("• <%s|%s#%d> %s", pr.URL, cfg.GitHub.Repo, pr.Number, pr.Title)
+			if pr.Assignee != "" {
+				line += " – assigned to " + slack.MapGitHubUserToMention(cfg.UserMapping, pr.Assignee)
+			}
+			if pr.IsDraft {
+				line += " (draft)"
+			}
+			prLines = append(prLines, line)
+		}
+	}
+
+	ticketText := ticketID
+	lines := []string{}
+	ticket, err := fetchTicket(ctx, ticketCfg, ticketID)
+	if err != nil {
+		slog.Warn("Could not fetch ticket", "ticket", ticketID, "error", err)
+		if ticketCfg.Jira.URL != "" {
+			ticketText = fmt.Sprintf("<%s/browse/%s|%s>", ticketCfg.Jira.URL, ticketID, ticketID)
+		}
+		lines = append(lines, fmt.Sprintf("*%s* – ticket status unavailable", ticketText))
+	} else {
+		ticketURL := ticket.URL
+		if ticketURL == "" && ticketCfg.Jira.URL != "" {
+			ticketURL = fmt.Sprintf("%s/browse/%s", ticketCfg.Jira.URL, ticketID)
+		}
+		if ticketURL != "" {
+			ticketText = fmt.Sprintf("<%s|%s>", ticketURL, ticketID)
+		}
+		header := fmt.Sprintf("*%s* %s – *%s*", ticketText, ticket.Summary, ticket.Status)
+		if ticket.IsBlocked {
+			header += " 🚫"
+		}
+		lines = append(lines, header)
+	}
+
+	if len(prLines) == 0 {
+		lines = append(lines, "No open PRs reference this ticket.")
+	} else {
+		lines = append(lines, prLines...)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
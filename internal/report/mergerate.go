@@ -0,0 +1,29 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+)
+
+// mergeRate computes the rolling merge rate of the report from the open PRs
+// and the PRs merged during the window. Merged PRs can only be fetched from
+// GitHub; nil is returned for other sources, and with the error when fetching
+// fails.
+func mergeRate(cfg Config, prs []*slack.PRInfo) (*model.MergeRate, error) {
+	if cfg.Source != SourceGitHub {
+		cfg.logger().Warn("Merged PRs can only be fetched from GitHub, the report won't have a merge rate")
+		return nil, nil
+	}
+
+	now := time.Now()
+	githubPRs, err := cfg.gitHubClient().FetchMergedPRs(cfg.GitHub, now.Add(-model.MergeRateDays*24*time.Hour))
+	if err != nil {
+		cfg.logger().Warn("Could not fetch merged PRs", "error", err)
+		return nil, err
+	}
+
+	rate := model.NewMergeRate(prs, buildSlackPRs(cfg, githubPRs, nil), now)
+	return &rate, nil
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+)
+
+// MonthlyConfig turns a daily report configuration into its monthly
+// retrospective: PRs opened and merged during the month, average age of the
+// open PRs, top blockers and the longest open PR instead of the PR list. The
+// retrospective is only posted to Slack, to the leadership channel if one is
+// configured.
+func MonthlyConfig(cfg Config) Config {
+	cfg = slackOnly(cfg)
+	cfg.Monthly = true
+	cfg.Weekly = false
+	cfg.Digest = false
+	cfg.ShowChanges = false
+	cfg.TrendChart = false
+	cfg.AuthorStats = false
+
+	cfg.Slack.Verbosity = slack.VerbositySummary
+	if cfg.Leadership != "" {
+		cfg.Slack.Channel = cfg.Leadership
+		cfg.Slack.Channels = nil
+		cfg.Slack.WebhookURL = "" // An incoming webhook only posts to the team's channel
+	}
+	channels := make([]slack.ChannelTarget, len(cfg.Slack.Channels))
+	for i, target := range cfg.Slack.Channels {
+		target.Verbosity = slack.VerbositySummary
+		channels[i] = target
+	}
+	cfg.Slack.Channels = channels
+	cfg.Slack.UpdateExisting = false
+	cfg.Slack.LiveStatus = false
+	cfg.Slack.PreviewUser = ""
+	cfg.Slack.TeamGroup = ""
+	cfg.Slack.MentionUsers = ""
+
+	return cfg
+}
+
+// monthlySummary summarizes the month ending now (or the month that just
+// ended, on the 1st) from the open PRs and the PRs merged during the month.
+// Merged PRs can only be fetched from GitHub.
+func monthlySummary(cfg Config, prs []*slack.PRInfo) *model.MonthlySummary {
+	start, end := model.RetrospectiveMonth(time.Now())
+
+	var merged []*model.PR
+	if cfg.Source == SourceGitHub {
+		githubPRs, err := cfg.gitHubClient().FetchMergedPRs(cfg.GitHub, start)
+		if err != nil {
+			cfg.logger().Warn("Could not fetch merged PRs", "error", err)
+		}
+		merged = buildSlackPRs(cfg, githubPRs, nil)
+	} else {
+		cfg.logger().Warn("Merged PRs can only be fetched from GitHub, the monthly retrospective won't count merges")
+	}
+
+	cfg.logger().Debug("Built monthly retrospective", "start", start, "end", end)
+	return model.NewMonthlySummary(prs, merged, start, end)
+}
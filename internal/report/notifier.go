@@ -0,0 +1,253 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"pr-reporter/internal/archive"
+	"pr-reporter/internal/confluence"
+	"pr-reporter/internal/discord"
+	"pr-reporter/internal/email"
+	"pr-reporter/internal/googlechat"
+	"pr-reporter/internal/htmlreport"
+	"pr-reporter/internal/mattermost"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/notion"
+	"pr-reporter/internal/sheets"
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/teams"
+	"pr-reporter/internal/tracing"
+	"pr-reporter/internal/webhook"
+)
+
+// Notifier delivers a report to one output
+type Notifier interface {
+	Name() string                                          // Output name for logs and traces
+	Notify(ctx context.Context, report model.Report) error // Deliver the report
+}
+
+// NotifierFunc adapts a function to a Notifier
+type NotifierFunc struct {
+	OutputName string
+	Func       func(ctx context.Context, report model.Report) error
+}
+
+// Name returns the output name
+func (n NotifierFunc) Name() string {
+	return n.OutputName
+}
+
+// Notify calls the function
+func (n NotifierFunc) Notify(ctx context.Context, report model.Report) error {
+	return n.Func(ctx, report)
+}
+
+// deliverers are the outputs a report can be delivered to, in delivery
+// order. Slack comes first and is used unless the report only has other
+// outputs configured.
+var deliverers = []Deliverer{
+	{
+		Name:    "slack",
+		Enabled: func(cfg Config) bool { return cfg.Slack.Token != "" || cfg.Slack.WebhookURL != "" },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			return deliverSlack(ctx, cfg, report.PRs)
+		},
+	},
+	{
+		Name:    "teams",
+		Enabled: func(cfg Config) bool { return cfg.Teams.WebhookURL != "" },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Sending report to Microsoft Teams")
+			if err := teams.SendReport(ctx, cfg.Teams, report); err != nil {
+				return fmt.Errorf("error sending report to Teams: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "discord",
+		Enabled: func(cfg Config) bool { return cfg.Discord.Configured() },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Sending report to Discord")
+			if err := discord.SendReport(ctx, cfg.Discord, report); err != nil {
+				return fmt.Errorf("error sending report to Discord: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "googlechat",
+		Enabled: func(cfg Config) bool { return cfg.GoogleChat.WebhookURL != "" },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Sending report to Google Chat")
+			if err := googlechat.SendReport(ctx, cfg.GoogleChat, report); err != nil {
+				return fmt.Errorf("error sending report to Google Chat: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "mattermost",
+		Enabled: func(cfg Config) bool { return cfg.Mattermost.Configured() },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Sending report to Mattermost", "channel", cfg.Mattermost.Channel)
+			if err := mattermost.SendReport(ctx, cfg.Mattermost, report); err != nil {
+				return fmt.Errorf("error sending report to Mattermost: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "email",
+		Enabled: func(cfg Config) bool { return cfg.Email.Configured() },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Emailing report", "to", cfg.Email.To)
+			if err := email.SendReport(ctx, cfg.Email, report); err != nil {
+				return fmt.Errorf("error emailing report: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "webhook",
+		Enabled: func(cfg Config) bool { return cfg.Webhook.URL != "" },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Sending report to JSON webhook")
+			if err := webhook.SendReport(ctx, cfg.Webhook, report); err != nil {
+				return fmt.Errorf("error sending report to webhook: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "confluence",
+		Enabled: func(cfg Config) bool { return cfg.Confluence.Configured() },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Publishing report to Confluence", "space", cfg.Confluence.Space)
+			if err := confluence.PublishReport(ctx, cfg.Confluence, report); err != nil {
+				return fmt.Errorf("error publishing report to Confluence: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "notion",
+		Enabled: func(cfg Config) bool { return cfg.Notion.Configured() },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Syncing PRs to Notion", "database", cfg.Notion.DatabaseID)
+			if err := notion.SyncReport(ctx, cfg.Notion, report); err != nil {
+				return fmt.Errorf("error syncing PRs to Notion: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "sheets",
+		Enabled: func(cfg Config) bool { return cfg.Sheets.Configured() },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Appending PRs to Google Sheet", "spreadsheet", cfg.Sheets.SpreadsheetID)
+			if err := sheets.AppendReport(ctx, cfg.Sheets, report); err != nil {
+				return fmt.Errorf("error appending PRs to Google Sheets: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "archive",
+		Enabled: func(cfg Config) bool { return cfg.Archive.Configured() },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Archiving report", "bucket", cfg.Archive.Bucket)
+			message, err := slack.RenderMessage(cfg.Slack, report.PRs)
+			if err == nil {
+				err = archive.ArchiveReport(ctx, cfg.Archive, report, message)
+			}
+			if err != nil {
+				return fmt.Errorf("error archiving report: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:    "html",
+		Enabled: func(cfg Config) bool { return cfg.HTML.Dir != "" },
+		Deliver: func(ctx context.Context, cfg Config, report model.Report) error {
+			cfg.logger().Info("Writing HTML report", "dir", cfg.HTML.Dir)
+			if err := htmlreport.WriteReport(cfg.HTML, report); err != nil {
+				return fmt.Errorf("error writing HTML report: %w", err)
+			}
+			return nil
+		},
+	},
+}
+
+// notifiers returns a notifier for every output configured for the report:
+// the registered deliverers enabled for it followed by cfg.Notifiers. Without
+// any, the report goes to Slack so the missing configuration is reported.
+func notifiers(cfg Config) []Notifier {
+	var outputs []Notifier
+	for _, deliverer := range deliverers {
+		if deliverer.Enabled(cfg) {
+			outputs = append(outputs, deliverer.notifier(cfg))
+		}
+	}
+	outputs = append(outputs, cfg.Notifiers...)
+
+	if len(outputs) == 0 {
+		outputs = append(outputs, deliverers[0].notifier(cfg))
+	}
+	return outputs
+}
+
+// dispatch delivers the report with every notifier concurrently and returns
+// the error of each notifier, in notifier order. One failing output doesn't
+// stop the others.
+func dispatch(ctx context.Context, notifiers []Notifier, report model.Report) []error {
+	errs := make([]error, len(notifiers))
+
+	var wg sync.WaitGroup
+	for i, notifier := range notifiers {
+		wg.Add(1)
+		go func(i int, notifier Notifier) {
+			defer wg.Done()
+
+			ctx, span := tracing.Start(ctx, "Notify", attribute.String("output", notifier.Name()))
+			errs[i] = notifier.Notify(ctx, report)
+			tracing.End(span, errs[i])
+		}(i, notifier)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// combineErrors combines the errors of the notifiers, in notifier order
+func combineErrors(errs []error) error {
+	var failed combinedError
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		return failed
+	}
+
+	return nil
+}
+
+// combinedError lists several errors in one message, keeping each of them for
+// errors.Is and errors.As
+type combinedError []error
+
+func (e combinedError) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e combinedError) Unwrap() []error { return e }
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseArgs parses command arguments such as "middletier labels=Poker,Hotfix".
+// key=value pairs are returned by key; a bare word is treated as report=word.
+func ParseArgs(text string) (map[string]string, error) {
+	args := make(map[string]string)
+	for _, field := range strings.Fields(text) {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			key, value = "report", field
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		switch key {
+		case "report", "repo", "labels":
+			args[key] = strings.TrimSpace(value)
+		default:
+			return nil, fmt.Errorf("unknown argument %q (supported: report, repo, labels)", key)
+		}
+	}
+	return args, nil
+}
+
+// RunOnDemand runs reports requested from Slack and posts them to channelID.
+// Without a report or repo argument every report is run.
+func RunOnDemand(text, channelID string) (string, error) {
+	args, err := ParseArgs(text)
+	if err != nil {
+		return "", err
+	}
+
+	name := args["report"]
+	if name == "" {
+		name = args["repo"]
+	}
+
+	names := Names
+	if name != "" {
+		names = []string{name}
+	}
+
+	var cfgs []Config
+	for _, n := range names {
+		cfg, err := ConfigFor(n)
+		if err != nil {
+			return "", err
+		}
+
+		// Post to the channel the report was requested in, without
+		// touching the scheduled report posted earlier
+		if channelID != "" {
+			cfg.Slack.Channel = channelID
+			cfg.Slack.Channels = nil
+			cfg.Slack.WebhookURL = ""
+		}
+		cfg = slackOnly(cfg)
+		cfg.Slack.UpdateExisting = false
+		cfg.Slack.LiveStatus = false
+		cfg.Slack.PreviewUser = ""
+		cfg.Slack.PostAt = time.Time{}
+		cfg.OnDemand = true
+
+		if labels, exists := args["labels"]; exists {
+			cfg.GitHub.Labels = nil
+			for _, label := range strings.Split(labels, ",") {
+				if label = strings.TrimSpace(label); label != "" {
+					cfg.GitHub.Labels = append(cfg.GitHub.Labels, label)
+				}
+			}
+		}
+
+		cfg.logger().Info("Running on-demand report", "channel", channelID)
+		cfgs = append(cfgs, cfg)
+	}
+
+	if err := runPool(cfgs, concurrency(), RunReport); err != nil {
+		return "", err
+	}
+
+	var sent []string
+	for _, cfg := range cfgs {
+		sent = append(sent, cfg.Name)
+	}
+
+	return fmt.Sprintf("✅ Sent %s report(s)", strings.Join(sent, ", ")), nil
+}
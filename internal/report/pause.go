@@ -0,0 +1,80 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"pr-reporter/internal/state"
+)
+
+// Pause stops the report from running, scheduled or on demand, for duration
+// (0: until Resume is called). by records who paused it.
+func Pause(cfg Config, duration time.Duration, by string) error {
+	if duration < 0 {
+		return fmt.Errorf("pause duration must not be negative, got %s", duration)
+	}
+
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		return err
+	}
+
+	pause := &state.Pause{At: time.Now(), By: by}
+	if duration > 0 {
+		pause.Until = pause.At.Add(duration)
+	}
+	store.Paused[cfg.Name] = pause
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	cfg.logger().Info("Paused report", "until", pause.Until, "by", by)
+	return nil
+}
+
+// Resume lets a paused report run again
+func Resume(cfg Config) error {
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		return err
+	}
+
+	if _, exists := store.Paused[cfg.Name]; !exists {
+		return fmt.Errorf("%s report isn't paused", cfg.Name)
+	}
+
+	delete(store.Paused, cfg.Name)
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	cfg.logger().Info("Resumed report")
+	return nil
+}
+
+// activePause returns the pause of the report, or nil when it isn't paused
+// or its state can't be read
+func activePause(cfg Config) *state.Pause {
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		cfg.logger().Warn("Could not check whether the report is paused", "error", err)
+		return nil
+	}
+
+	pause, exists := store.Paused[cfg.Name]
+	if !exists || !pause.Active(time.Now()) {
+		return nil
+	}
+	return pause
+}
+
+// skipPaused reports whether the report is paused, logging that its run is
+// skipped
+func skipPaused(cfg Config) bool {
+	pause := activePause(cfg)
+	if pause == nil {
+		return false
+	}
+	cfg.logger().Info("Report is paused, skipping the run", "since", pause.At, "until", pause.Until, "by", pause.By)
+	return true
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+	"pr-reporter/internal/errkind"
+	"pr-reporter/internal/github"
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/jira"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/tracing"
+)
+
+// A report runs as a pipeline of typed stages:
+//
+//	fetch    the open PRs are fetched from the report's source (Fetcher)
+//	enrich   enrichers add data from other APIs to them (Enricher)
+//	format   they become report PRs and the report sections are added (Section)
+//	deliver  the report is sent to every configured output (Deliverer)
+//
+// Stages are registered rather than wired into the run: a new source,
+// enricher, section or output registers itself with RegisterSource,
+// RegisterEnricher, RegisterSection or RegisterDeliverer, from an init
+// function or before any report runs.
+
+// Fetcher fetches the open PRs of a report from its source
+type Fetcher func(ctx context.Context, cfg Config) ([]*github.PRResult, error)
+
+// PRBatch holds the fetched PRs of a run and the data enrichers add to them
+type PRBatch struct {
+	PRs     []*github.PRResult          // Fetched PRs; enrichers may fill in their fields
+	Tickets map[string]*jira.TicketInfo // Tickets referenced by the PRs, by ID
+	Issues  []string                    // What couldn't be fetched, listed in the report (see AddIssue)
+
+	mu sync.Mutex
+}
+
+// AddIssue records data an enricher couldn't fetch, such as tickets its
+// tracker didn't return, so the report says so instead of showing gaps.
+// Enrichers may call it concurrently.
+func (b *PRBatch) AddIssue(issue string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Issues = append(b.Issues, issue)
+}
+
+// Enricher adds data from another API to the fetched PRs of a report.
+// Enrichers start along with the fetch so they can prepare what doesn't
+// depend on the PRs; fetched blocks until the PRs are fetched and returns nil
+// when the fetch failed. Enrichers run concurrently, so each one only sets its
+// own fields. Their errors are logged and listed in the report's data issues
+// without failing the run.
+type Enricher struct {
+	Name    string                                                               // Name for logs and traces
+	Feature string                                                               // Feature flag turning the enricher off when set to false (optional)
+	Enabled func(cfg Config) bool                                                // Whether the enricher runs for the report
+	Enrich  func(ctx context.Context, cfg Config, fetched func() *PRBatch) error // Enrich the fetched PRs
+}
+
+// Section adds a section, such as the merge rate, to the report options of
+// cfg.Slack, which every output renders from
+type Section struct {
+	Name    string                                 // Name for logs
+	Feature string                                 // Feature flag turning the section off when set to false (optional)
+	Enabled func(cfg Config) bool                  // Whether the report has the section
+	Add     func(cfg *Config, prs []*slack.PRInfo) // Compute the section from the PRs of the report
+}
+
+// Deliverer sends the report to an output
+type Deliverer struct {
+	Name    string                                                           // Output name for logs and traces
+	Enabled func(cfg Config) bool                                            // Whether the output is configured for the report
+	Deliver func(ctx context.Context, cfg Config, report model.Report) error // Deliver the report
+}
+
+// notifier returns the Notifier delivering the report of cfg
+func (d Deliverer) notifier(cfg Config) Notifier {
+	return NotifierFunc{OutputName: d.Name, Func: func(ctx context.Context, report model.Report) error {
+		return d.Deliver(ctx, cfg, report)
+	}}
+}
+
+// RegisterSource registers the fetcher of a PR source, selected with
+// <REPORT>_SOURCE=name
+func RegisterSource(name string, fetch Fetcher) {
+	sources[name] = fetch
+}
+
+// RegisterEnricher adds an enricher to every report run
+func RegisterEnricher(enricher Enricher) {
+	enrichers = append(enrichers, enricher)
+}
+
+// RegisterSection adds a report section, after the built-in ones
+func RegisterSection(section Section) {
+	sections = append(sections, section)
+}
+
+// RegisterDeliverer adds an output reports can be delivered to
+func RegisterDeliverer(deliverer Deliverer) {
+	deliverers = append(deliverers, deliverer)
+}
+
+// fetchAndEnrich runs the fetch and enrich stages of a report
+func fetchAndEnrich(ctx context.Context, cfg Config) (*PRBatch, error) {
+	if cfg.Invalid != nil {
+		return nil, cfg.Invalid
+	}
+
+	enrichCtx, cancelEnrich := context.WithCancel(ctx)
+	defer cancelEnrich()
+
+	var batch *PRBatch
+	done := make(chan struct{})
+	fetched := func() *PRBatch {
+		<-done
+		return batch
+	}
+
+	var g errgroup.Group
+	for _, enricher := range enrichers {
+		if !enricher.Enabled(cfg) || !featureEnabled(cfg, enricher.Feature) {
+			continue
+		}
+		enricher := enricher
+		g.Go(func() error {
+			ctx, span := tracing.Start(enrichCtx, "Enrich", attribute.String("enricher", enricher.Name))
+			err := enricher.Enrich(ctx, cfg, fetched)
+			tracing.End(span, err)
+			if err != nil {
+				cfg.logger().Warn("Could not enrich PRs", "enricher", enricher.Name, "error", err)
+				if batch := fetched(); batch != nil {
+					batch.AddIssue(err.Error())
+				}
+			}
+			return nil
+		})
+	}
+
+	repo := sourceName(cfg)
+	_, fetchSpan := tracing.Start(ctx, "FetchPRs", attribute.String("source", cfg.Source), attribute.String("repo", repo))
+	prs, err := fetchPRs(ctx, cfg)
+	fetchSpan.SetAttributes(attribute.Int("prs", len(prs)))
+	tracing.End(fetchSpan, err)
+
+	if err != nil {
+		// Enrichers waiting for the PRs get nil and stop
+		cancelEnrich()
+		close(done)
+		g.Wait()
+		err = fmt.Errorf("error fetching PRs from %s: %w", repo, err)
+		var statusErr *httpx.StatusError
+		if errors.As(err, &statusErr) && (errors.Is(statusErr, httpx.ErrUnauthorized) || errors.Is(statusErr, httpx.ErrForbidden)) {
+			err = &errkind.AuthError{API: statusErr.API, Err: err}
+		}
+		return nil, &errkind.FetchError{Err: err}
+	}
+
+	cfg.logger().Info("Fetched PRs", "repo", repo, "prs", len(prs))
+	batch = &PRBatch{PRs: prs}
+	close(done)
+	g.Wait()
+
+	return batch, nil
+}
+
+// format adds the enabled sections to the report of cfg
+func format(cfg *Config, prs []*slack.PRInfo) {
+	for _, section := range sections {
+		if section.Enabled(*cfg) && featureEnabled(*cfg, section.Feature) {
+			section.Add(cfg, prs)
+		}
+	}
+}
+
+// featureEnabled reports whether a stage gated by feature runs for the
+// report: unless the feature's flag is set to false
+func featureEnabled(cfg Config, feature string) bool {
+	return feature == "" || cfg.Features.Enabled(feature, true)
+}
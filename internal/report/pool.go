@@ -0,0 +1,59 @@
+package report
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+	"pr-reporter/internal/config"
+)
+
+// defaultConcurrency is how many reports run at the same time without
+// REPORT_CONCURRENCY
+const defaultConcurrency = 4
+
+// concurrency reads REPORT_CONCURRENCY, the number of reports run at the same
+// time by RunAll and on-demand runs of every report
+func concurrency() int {
+	if workers := config.Int("REPORT_CONCURRENCY"); workers > 0 {
+		return workers
+	}
+	return defaultConcurrency
+}
+
+// RunAll runs several reports (e.g., one per team or repository) as one-off
+// batch jobs, a few at a time, so many teams neither wait for each other nor
+// hit the APIs all at once. A failing report doesn't stop the others; the
+// returned error lists every report that failed.
+func RunAll(cfgs []Config) error {
+	return runPool(cfgs, concurrency(), RunOnce)
+}
+
+// runPool calls run for each configuration with at most workers calls at a
+// time and combines their errors, each prefixed with the report's name
+func runPool(cfgs []Config, workers int, run func(Config) error) error {
+	errs := make([]error, len(cfgs))
+
+	var g errgroup.Group
+	g.SetLimit(workers)
+	for i, cfg := range cfgs {
+		i, cfg := i, cfg
+		g.Go(func() error {
+			// One report panicking must not take down the others
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("%s report panicked: %v", cfg.Name, r)
+					cfg.logger().Error("Report panicked", "panic", r)
+				}
+			}()
+
+			if err := run(cfg); err != nil {
+				errs[i] = fmt.Errorf("%s report failed: %w", cfg.Name, err)
+				cfg.logger().Error("Report failed", "error", err)
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	return combineErrors(errs)
+}
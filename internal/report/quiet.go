@@ -0,0 +1,74 @@
+package report
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/quiet"
+	"pr-reporter/internal/slack"
+)
+
+// applyQuietHours adjusts a run that falls within the quiet hours of cfg:
+// with quiet.ModeDefer the Slack report is scheduled for the end of the quiet
+// hours, otherwise the report is posted without pinging anyone. Reports that
+// can't be deferred, because they were requested from Slack or have an output
+// other than a Slack bot post, are posted without pinging anyone too.
+func applyQuietHours(ctx context.Context, cfg *Config) {
+	if !cfg.Quiet.Enabled() {
+		return
+	}
+	until, isQuiet := cfg.Quiet.Until(ctx, time.Now())
+	if !isQuiet {
+		return
+	}
+	logger := cfg.logger().With("until", until.Format("2006-01-02 15:04 MST"))
+
+	// DMs and SLA alerts aren't scheduled with the report, they wait for the next run
+	cfg.Digest = false
+	cfg.Reminders = 0
+	cfg.SLA = model.SLA{}
+
+	switch {
+	case cfg.Quiet.Mode != quiet.ModeDefer:
+		logger.Info("Quiet hours, posting the report without mentions")
+	case cfg.OnDemand:
+		logger.Info("Quiet hours, posting the requested report now without mentions")
+	case !canSchedule(*cfg):
+		logger.Info("Quiet hours, but the report has outputs that can't be scheduled, posting it without mentions")
+	default:
+		if cfg.Slack.PostAt.Before(until) {
+			logger.Info("Quiet hours, scheduling the Slack report for their end")
+			cfg.Slack.PostAt = until
+		}
+		return
+	}
+
+	cfg.Muted = true
+	cfg.Slack.MentionPolicy = slack.MentionPolicyNone
+	cfg.Slack.EscalateGroup = ""
+}
+
+// canSchedule reports whether every output of cfg can schedule the report for
+// later: only Slack reports posted with the bot token can
+func canSchedule(cfg Config) bool {
+	for _, notifier := range notifiers(cfg) {
+		if notifier.Name() != "slack" {
+			return false
+		}
+	}
+	return cfg.Slack.WebhookURL == ""
+}
+
+// muteMentions shows the people on the PRs of a report posted during quiet
+// hours by name rather than mentioning them
+func muteMentions(prs []*slack.PRInfo) {
+	for _, pr := range prs {
+		if strings.HasPrefix(pr.Assignee, "<@") {
+			pr.Assignee = pr.GithubAssignee
+		}
+		pr.ReviewerMentions = nil
+		pr.SuggestedReviewer = ""
+	}
+}
@@ -0,0 +1,127 @@
+package report
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/state"
+)
+
+// defaultRemindEvery is how often a reviewer is reminded of the same PR when
+// cfg.RemindEvery is unset
+const defaultRemindEvery = 24 * time.Hour
+
+// sendReminders DMs each mapped requested reviewer the PRs whose review has
+// been pending for longer than cfg.Reminders, counted from when the review
+// was requested, or else from when the PR was opened or marked ready for
+// review. Users who opted out are skipped, and each reviewer is reminded of a
+// PR at most once per cfg.RemindEvery.
+func sendReminders(ctx context.Context, cfg Config, prs []*slack.PRInfo) {
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		cfg.logger().Warn("Could not send review reminders", "error", err)
+		return
+	}
+
+	now := time.Now()
+	every := cfg.RemindEvery
+	if every <= 0 {
+		every = defaultRemindEvery
+	}
+	for key, reminder := range store.Reminded {
+		if now.Sub(reminder.At) >= every {
+			delete(store.Reminded, key)
+		}
+	}
+
+	reminders := buildReminders(cfg, prs, store, now)
+	if len(reminders) > 0 {
+		cfg.logger().Info("Sending review reminder DMs", "users", len(reminders))
+		if err := cfg.slackClient().SendReminders(ctx, cfg.Slack, reminders); err != nil {
+			cfg.logger().Warn("Could not send review reminder DMs", "error", err)
+		}
+	}
+
+	// Record the reminders even when some failed, so nobody is reminded on every run
+	for userID, items := range reminders {
+		for _, item := range items {
+			store.Reminded[state.ReminderKey(reminderPRKey(cfg, item.PR), userID)] = &state.Reminder{At: now}
+		}
+	}
+	if err := store.Save(); err != nil {
+		cfg.logger().Warn("Could not save review reminders", "error", err)
+	}
+}
+
+// buildReminders groups the PRs with reviews pending for longer than
+// cfg.Reminders by the Slack users whose review they wait for, longest
+// waiting first. Users who opted out and PRs a user was reminded of recently,
+// as recorded in store, are left out.
+func buildReminders(cfg Config, prs []*slack.PRInfo, store *state.Store, now time.Time) map[string][]slack.ReminderPR {
+	reminders := make(map[string][]slack.ReminderPR)
+
+	for _, pr := range prs {
+		if pr.IsDraft {
+			continue
+		}
+		ready := pr.ReadyAt
+		if ready.IsZero() {
+			ready = pr.CreatedAt
+		}
+
+		for _, reviewer := range pr.RequestedReviewers {
+			if strings.HasPrefix(reviewer, "team:") {
+				continue
+			}
+			slackID, exists := lookupSlackID(cfg.UserMapping, reviewer)
+			if !exists || store.OptOuts[slackID] != nil || store.Reminded[state.ReminderKey(reminderPRKey(cfg, pr), slackID)] != nil {
+				continue
+			}
+
+			// Reviews requested while the PR was a draft wait from when it became ready
+			since := ready
+			if requested := pr.ReviewRequestedAt[reviewer]; requested.After(since) {
+				since = requested
+			}
+			if since.IsZero() || now.Sub(since) < cfg.Reminders {
+				continue
+			}
+			reminders[slackID] = append(reminders[slackID], slack.ReminderPR{PR: pr, Waiting: now.Sub(since)})
+		}
+	}
+
+	for _, items := range reminders {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Waiting > items[j].Waiting })
+	}
+
+	return reminders
+}
+
+// reminderPRKey identifies a PR of the report of cfg in the reminders store
+func reminderPRKey(cfg Config, pr *slack.PRInfo) string {
+	return state.PRKey(cfg.Slack.GithubOwner, cfg.Slack.GithubRepo, pr.Number)
+}
+
+// SetReminders turns review reminders off or back on for a Slack user, in
+// every report sharing the state file of cfg
+func SetReminders(cfg Config, userID string, enabled bool) error {
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		return err
+	}
+
+	if enabled {
+		delete(store.OptOuts, userID)
+	} else {
+		store.OptOuts[userID] = &state.OptOut{At: time.Now()}
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	cfg.logger().Info("Set review reminders", "user", userID, "enabled", enabled)
+	return nil
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/store"
+)
+
+// Replay re-renders the last report of a day from its snapshot in the state
+// database and writes it to w, or posts it to Slack again as a new message
+// when send is set. Only the PR list is replayed: sections computed from live
+// data at run time (changes, stats, merge rate, ...) are left out.
+func Replay(cfg Config, date time.Time, send bool, w io.Writer) error {
+	path := statePath(cfg)
+	if !store.IsDatabase(path) {
+		return fmt.Errorf("replaying needs the snapshots of a state database (see STATE_FILE), not %s", store.Redacted(path))
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	snapshot, err := db.LastSnapshot(cfg.Name, day.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+	if snapshot == nil || snapshot.TakenAt.Before(day) {
+		return fmt.Errorf("no %s snapshot on %s", cfg.Name, day.Format("2006-01-02"))
+	}
+
+	cfg = slackOnly(cfg)
+	cfg.Slack.Date = snapshot.TakenAt
+	cfg.Slack.UpdateExisting = false
+	cfg.Slack.LiveStatus = false
+	cfg.Slack.PreviewUser = ""
+	cfg.Slack.PostAt = time.Time{}
+	cfg.Slack.LinkPrevious = false
+
+	cfg.logger().Info("Replaying report", "taken", snapshot.TakenAt.Format("2006-01-02 15:04"), "prs", len(snapshot.PRs))
+
+	if send {
+		err := cfg.slackClient().SendPRReport(context.Background(), cfg.Slack, snapshot.PRs)
+		auditMessage(cfg, "slack-replay", snapshot.PRs, err)
+		return err
+	}
+
+	message, err := slack.RenderMessage(cfg.Slack, snapshot.PRs)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, message)
+	return err
+}
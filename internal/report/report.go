@@ -0,0 +1,720 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+	"pr-reporter/internal/asana"
+	"pr-reporter/internal/azuredevops"
+	"pr-reporter/internal/bitbucket"
+	"pr-reporter/internal/config"
+	"pr-reporter/internal/discord"
+	"pr-reporter/internal/errkind"
+	"pr-reporter/internal/github"
+	"pr-reporter/internal/gitlab"
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/jira"
+	"pr-reporter/internal/linear"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/tracing"
+)
+
+// RunReport fetches PRs and their JIRA tickets and sends the report to Slack.
+// When a preview user is configured the report is sent to them for approval
+// instead. Paused reports (see Pause) are skipped.
+func RunReport(cfg Config) error {
+	if skipPaused(cfg) {
+		return nil
+	}
+
+	start := time.Now()
+	prs, err := runReport(cfg)
+
+	if cfg.Datadog.APIKey != "" {
+		sendDatadog(cfg, prs, time.Since(start), err)
+	}
+
+	return err
+}
+
+// runReport is RunReport, also returning the collected PRs
+func runReport(cfg Config) (slackPRs []*slack.PRInfo, err error) {
+	ctx, cancel := runContext(cfg)
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, "RunReport", attribute.String("report", cfg.Name), attribute.String("source", cfg.Source))
+	defer func() { tracing.End(span, err) }()
+
+	applyQuietHours(ctx, &cfg)
+
+	slackPRs, cfg.Slack.DataIssues, err = collectPRs(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Slack.PreviewUser != "" {
+		cfg.logger().Info("Sending report preview for approval", "user", cfg.Slack.PreviewUser)
+		if err := cfg.slackClient().SendPreview(ctx, cfg.Slack, cfg.Name, slackPRs); err != nil {
+			return slackPRs, &errkind.DeliveryError{Err: fmt.Errorf("error sending preview to Slack: %w", err)}
+		}
+		return slackPRs, nil
+	}
+
+	return slackPRs, deliver(ctx, cfg, slackPRs)
+}
+
+// Approve posts a report approved from a preview with the previewed PRs
+func Approve(name string, prs []*slack.PRInfo) error {
+	cfg, err := ConfigFor(name)
+	if err != nil {
+		return err
+	}
+	cfg.Slack.PreviewUser = ""
+
+	ctx, cancel := runContext(cfg)
+	defer cancel()
+	return deliver(ctx, cfg, prs)
+}
+
+// runContext returns the context of a run, canceled after cfg.Timeout when it
+// is set so a hung server can't stall the run forever
+func runContext(cfg Config) (context.Context, context.CancelFunc) {
+	if cfg.Timeout > 0 {
+		return context.WithTimeout(context.Background(), cfg.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// deliver sends the report to every configured output concurrently
+func deliver(ctx context.Context, cfg Config, slackPRs []*slack.PRInfo) (err error) {
+	ctx, span := tracing.Start(ctx, "Deliver", attribute.Int("prs", len(slackPRs)))
+	defer func() { tracing.End(span, err) }()
+
+	if cfg.Slack.GroupByTicket {
+		slackPRs = model.GroupByTicket(slackPRs)
+	}
+	if cfg.Slack.GroupByAssignee {
+		slackPRs = model.GroupByAssignee(slackPRs)
+	}
+	format(&cfg, slackPRs)
+
+	outputs := notifiers(cfg)
+	report := newReport(cfg, slackPRs)
+	errs := dispatch(ctx, outputs, report)
+	recordDeliveries(cfg, report, outputs, errs)
+	if err := combineErrors(errs); err != nil {
+		return &errkind.DeliveryError{Err: err}
+	}
+
+	saveSnapshot(cfg, slackPRs)
+	if cfg.SLA.Enabled() {
+		alertSLABreaches(cfg, slackPRs)
+	}
+	return nil
+}
+
+// slackOnly removes every output except Slack and turns off snapshots, SLA
+// alerts and review reminders, for runs that only concern Slack such as slash
+// commands and live status refreshes
+func slackOnly(cfg Config) Config {
+	cfg.Teams.WebhookURL = ""
+	cfg.Discord = discord.Options{}
+	cfg.GoogleChat.WebhookURL = ""
+	cfg.Mattermost.Channel = ""
+	cfg.Email.To = nil
+	cfg.Webhook.URL = ""
+	cfg.HTML.Dir = ""
+	cfg.Confluence.Space = ""
+	cfg.Notion.DatabaseID = ""
+	cfg.Sheets.SpreadsheetID = ""
+	cfg.Archive.Bucket = ""
+	cfg.Notifiers = nil
+	cfg.Snapshots = false
+	cfg.SLA = model.SLA{}
+	cfg.Datadog.APIKey = ""
+	cfg.Reminders = 0
+	return cfg
+}
+
+// deliverSlack sends the report and personal digests to Slack
+func deliverSlack(ctx context.Context, cfg Config, slackPRs []*slack.PRInfo) (err error) {
+	_, span := tracing.Start(ctx, "SendSlackReport", attribute.String("channel", cfg.Slack.Channel))
+	defer func() { tracing.End(span, err) }()
+
+	if cfg.Slack.WebhookURL != "" {
+		cfg.logger().Info("Sending report to Slack incoming webhook")
+	} else if len(cfg.Slack.Channels) > 0 {
+		var channels []string
+		for _, target := range cfg.Slack.Channels {
+			channels = append(channels, target.Channel)
+		}
+		cfg.logger().Info("Sending report to Slack", "channels", channels)
+	} else {
+		cfg.logger().Info("Sending report to Slack", "channel", cfg.Slack.Channel)
+	}
+
+	// Send to Slack
+	if err := cfg.slackClient().SendPRReport(ctx, cfg.Slack, slackPRs); err != nil {
+		return fmt.Errorf("error sending message to Slack: %w", err)
+	}
+
+	// Send personal digests in addition to the channel report
+	if cfg.Digest {
+		digests := buildDigests(cfg, slackPRs)
+		cfg.logger().Info("Sending digest DMs", "users", len(digests))
+		if err := cfg.slackClient().SendDigests(ctx, cfg.Slack, digests); err != nil {
+			cfg.logger().Warn("Could not send digest DMs", "error", err)
+		}
+	}
+
+	// Remind reviewers of the reviews they have owed for a while
+	if cfg.Reminders > 0 {
+		sendReminders(ctx, cfg, slackPRs)
+	}
+
+	return nil
+}
+
+// newReport builds the output-independent report model
+func newReport(cfg Config, prs []*model.PR) model.Report {
+	return model.Report{
+		Name:        cfg.Name,
+		Title:       cfg.Slack.ReportTitle,
+		GithubOwner: cfg.GitHub.Owner,
+		GithubRepo:  cfg.GitHub.Repo,
+		JiraURL:     cfg.Jira.URL,
+		Locale:      cfg.Slack.Locale,
+		Date:        time.Now(),
+		PRs:         prs,
+	}
+}
+
+// CollectPRs fetches the PRs of a report from GitHub and enriches them with
+// their JIRA ticket information
+func CollectPRs(cfg Config) ([]*slack.PRInfo, error) {
+	ctx, cancel := runContext(cfg)
+	defer cancel()
+	prs, _, err := collectPRs(ctx, cfg)
+	return prs, err
+}
+
+// collectPRs is CollectPRs, traced as a child of the span in ctx. It also
+// returns what couldn't be fetched, for the report's data issues.
+func collectPRs(ctx context.Context, cfg Config) (prs []*slack.PRInfo, issues []string, err error) {
+	ctx, span := tracing.Start(ctx, "CollectPRs", attribute.String("report", cfg.Name))
+	defer func() { tracing.End(span, err) }()
+
+	cfg.logger().Info("Fetching PRs", "repo", sourceName(cfg), "labels", cfg.GitHub.Labels)
+
+	batch, err := fetchAndEnrich(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Say which of the report's APIs were skipped after failing repeatedly
+	issues = batch.Issues
+	for _, circuit := range httpx.OpenCircuits(fetchAPIs(cfg)...) {
+		issues = append(issues, circuit.Error())
+	}
+
+	prs = buildSlackPRs(cfg, batch.PRs, batch.Tickets)
+	model.SortPRs(prs, cfg.SortOrder)
+	return prs, issues, nil
+}
+
+// enrichers add data to the fetched PRs of every report (see Enricher)
+var enrichers = []Enricher{
+	{
+		// Fills gaps in USER_MAPPING by matching email addresses
+		Name:    "users",
+		Feature: config.FeatureEmails,
+		Enabled: func(cfg Config) bool { return cfg.EmailLookup && cfg.Source == SourceGitHub },
+		Enrich:  mapUsersByEmail,
+	},
+	{
+		Name:    "details",
+		Enabled: func(cfg Config) bool { return cfg.Source == SourceGitHub && cfg.GitHub.FetchDetails },
+		Enrich: func(ctx context.Context, cfg Config, fetched func() *PRBatch) error {
+			batch := fetched()
+			if batch == nil || len(batch.PRs) == 0 {
+				return nil
+			}
+			cfg.logger().Info("Fetching reviews and checks", "prs", len(batch.PRs))
+			return cfg.gitHubClient().FetchDetails(ctx, cfg.GitHub, batch.PRs)
+		},
+	},
+	{
+		Name:    "tickets",
+		Feature: config.FeatureTickets,
+		Enabled: func(cfg Config) bool { return true },
+		Enrich:  enrichTickets,
+	},
+}
+
+// enrichTickets looks up the tickets referenced by the fetched PRs in the
+// report's tracker
+func enrichTickets(ctx context.Context, cfg Config, fetched func() *PRBatch) error {
+	batch := fetched()
+	if batch == nil {
+		return nil
+	}
+
+	// PRs referencing the same ticket share a single lookup
+	var ticketIDs []string
+	seen := make(map[string]bool)
+	for _, pr := range batch.PRs {
+		if pr.JiraTicket != "" && !seen[pr.JiraTicket] {
+			seen[pr.JiraTicket] = true
+			ticketIDs = append(ticketIDs, pr.JiraTicket)
+		}
+	}
+	if len(ticketIDs) == 0 {
+		return nil
+	}
+
+	cfg.logger().Info("Fetching ticket info", "tickets", len(ticketIDs), "prs", len(batch.PRs))
+	tickets, failed, err := fetchTickets(ctx, cfg, ticketIDs)
+	if err != nil {
+		return fmt.Errorf("could not fetch %s tickets, their status is unknown: %w", trackerName(cfg), err)
+	}
+	batch.Tickets = tickets
+	if len(failed) > 0 {
+		batch.AddIssue(fmt.Sprintf("could not fetch %d of %d %s tickets, their status is unknown: %s",
+			len(failed), len(ticketIDs), trackerName(cfg), abbreviate(failed, maxIssueItems)))
+	}
+	return nil
+}
+
+// maxIssueItems is how many tickets or PRs a data issue names before
+// abbreviating the rest
+const maxIssueItems = 10
+
+// abbreviate joins the first max items, noting how many more there are
+func abbreviate(items []string, max int) string {
+	if len(items) <= max {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:max], ", "), len(items)-max)
+}
+
+// trackerName names the tracker of a report's tickets, for data issues
+func trackerName(cfg Config) string {
+	switch {
+	case cfg.Source == SourceAzureDevOps:
+		return "Azure DevOps"
+	case cfg.Tracker == TrackerLinear:
+		return "Linear"
+	case cfg.Tracker == TrackerAsana:
+		return "Asana"
+	}
+	return "JIRA"
+}
+
+// sections are the optional sections of every report, in the order they are
+// computed (see Section)
+var sections = []Section{
+	{
+		Name:    "blocked",
+		Enabled: func(cfg Config) bool { return cfg.MuteBlocked > 0 },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			trackBlockedStreaks(*cfg, prs)
+		},
+	},
+	{
+		Name:    "status-changes",
+		Enabled: func(cfg Config) bool { return cfg.Highlight },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			highlightStatusChanges(*cfg, prs)
+		},
+	},
+	{
+		Name:    "changes",
+		Feature: config.FeatureDelta,
+		Enabled: func(cfg Config) bool { return cfg.ShowChanges },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.Changes = changesSinceLastReport(*cfg, prs)
+		},
+	},
+	{
+		Name:    "anomalies",
+		Enabled: func(cfg Config) bool { return cfg.Anomaly > 0 },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.Anomalies = detectAnomalies(*cfg, prs)
+		},
+	},
+	{
+		Name:    "trend",
+		Enabled: func(cfg Config) bool { return cfg.TrendChart },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.Trend = openPRTrend(*cfg, prs)
+		},
+	},
+	{
+		Name:    "author-stats",
+		Enabled: func(cfg Config) bool { return cfg.AuthorStats },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.AuthorStats = authorStats(*cfg, prs)
+		},
+	},
+	{
+		Name:    "turnaround",
+		Enabled: func(cfg Config) bool { return cfg.Turnaround },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			turnaround := reviewTurnaround(*cfg, prs)
+			cfg.Slack.Turnaround = &turnaround
+		},
+	},
+	{
+		Name:    "merge-rate",
+		Enabled: func(cfg Config) bool { return cfg.MergeRate },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			var err error
+			if cfg.Slack.MergeRate, err = mergeRate(*cfg, prs); err != nil {
+				cfg.Slack.DataIssues = append(cfg.Slack.DataIssues, "could not fetch merged PRs from GitHub, the merge rate is left out")
+			}
+		},
+	},
+	{
+		Name:    "cycle-time",
+		Enabled: func(cfg Config) bool { return cfg.CycleTime },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.CycleTime = monthlyCycleTime(*cfg)
+		},
+	},
+	{
+		Name:    "sprint",
+		Enabled: func(cfg Config) bool { return cfg.SprintBoard > 0 },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.Sprint = sprintBurndown(*cfg, prs)
+		},
+	},
+	{
+		Name:    "sprint-summary",
+		Enabled: func(cfg Config) bool { return cfg.SprintBoard > 0 && cfg.Velocity },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.SprintSummary = sprintSummary(*cfg, prs)
+		},
+	},
+	{
+		Name:    "weekly",
+		Enabled: func(cfg Config) bool { return cfg.Weekly },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.Weekly = weeklySummary(*cfg, prs)
+		},
+	},
+	{
+		Name:    "monthly",
+		Enabled: func(cfg Config) bool { return cfg.Monthly },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.Monthly = monthlySummary(*cfg, prs)
+		},
+	},
+	{
+		Name:    "leaderboard",
+		Enabled: func(cfg Config) bool { return cfg.Leaderboard > 0 },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			var err error
+			if cfg.Slack.Leaderboard, err = reviewLeaderboard(*cfg); err != nil {
+				cfg.Slack.DataIssues = append(cfg.Slack.DataIssues, "could not count reviews on GitHub, the review leaderboard is left out")
+			}
+		},
+	},
+	{
+		Name:    "labels",
+		Enabled: func(cfg Config) bool { return len(cfg.Labels) > 0 },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.Labels = labelBreakdown(*cfg, prs)
+		},
+	},
+	{
+		Name:    "review-load",
+		Enabled: func(cfg Config) bool { return cfg.ReviewLoad },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			cfg.Slack.ReviewLoad = model.ComputeReviewLoad(prs)
+		},
+	},
+	{
+		Name:    "suggested-reviewers",
+		Enabled: func(cfg Config) bool { return cfg.RoundRobin },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			suggestReviewers(*cfg, prs)
+		},
+	},
+	{
+		// Last, so no section adds mentions back
+		Name:    "quiet-hours",
+		Enabled: func(cfg Config) bool { return cfg.Muted },
+		Add: func(cfg *Config, prs []*slack.PRInfo) {
+			muteMentions(prs)
+		},
+	},
+}
+
+// fetchPRs fetches the open PRs of a report from its source, with the ticket
+// IDs of its tracker. The label and user filters of cfg.GitHub apply to every
+// source.
+func fetchPRs(ctx context.Context, cfg Config) ([]*github.PRResult, error) {
+	if cfg.Invalid != nil {
+		return nil, cfg.Invalid
+	}
+
+	prs, err := fetchSourcePRs(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Source != SourceAzureDevOps {
+		for _, pr := range prs {
+			switch cfg.Tracker {
+			case TrackerLinear:
+				pr.JiraTicket = linear.IssueIDFromTitle(cfg.Linear.TeamKeys, pr.Title)
+			case TrackerAsana:
+				pr.JiraTicket = asana.TaskIDFromText(pr.Body)
+			}
+		}
+	}
+
+	return prs, nil
+}
+
+// sources fetch the open PRs of reports by source name (see Fetcher). The
+// filters of cfg.GitHub apply to every source.
+var sources = map[string]Fetcher{
+	SourceGitHub: func(ctx context.Context, cfg Config) ([]*github.PRResult, error) {
+		return cfg.gitHubClient().FetchPRs(ctx, cfg.GitHub)
+	},
+	SourceGitLab: func(ctx context.Context, cfg Config) ([]*github.PRResult, error) {
+		opts := cfg.GitLab
+		opts.Options = cfg.GitHub.Options
+		opts.FetchDetails = cfg.GitHub.FetchDetails
+		return gitlab.FetchMRs(ctx, opts)
+	},
+	SourceBitbucket: func(ctx context.Context, cfg Config) ([]*github.PRResult, error) {
+		if len(cfg.GitHub.Labels) > 0 {
+			cfg.logger().Warn("Bitbucket has no PR labels, ignoring the label filter", "labels", cfg.GitHub.Labels)
+		}
+		opts := cfg.Bitbucket
+		opts.Options = cfg.GitHub.Options
+		opts.Labels = nil
+		return bitbucket.FetchPRs(ctx, opts)
+	},
+	SourceAzureDevOps: func(ctx context.Context, cfg Config) ([]*github.PRResult, error) {
+		opts := cfg.AzureDevOps
+		opts.Options = cfg.GitHub.Options
+		return azuredevops.FetchPRs(ctx, opts)
+	},
+}
+
+// fetchSourcePRs fetches the open PRs of a report from its source
+func fetchSourcePRs(ctx context.Context, cfg Config) ([]*github.PRResult, error) {
+	fetch, exists := sources[cfg.Source]
+	if !exists {
+		fetch = sources[SourceGitHub]
+	}
+	return fetch(ctx, cfg)
+}
+
+// ticketConcurrency is how many tickets are looked up at the same time with
+// trackers without batch lookups
+const ticketConcurrency = 8
+
+// fetchTickets fetches the tickets referenced by a report's PRs: work items
+// for Azure DevOps, otherwise issues of the report's tracker. Trackers without
+// batch lookups are called once per ticket, each in its own span, a few at a
+// time. Tickets that can't be fetched are logged, left out and returned as
+// failed, sorted.
+func fetchTickets(ctx context.Context, cfg Config, ticketIDs []string) (tickets map[string]*jira.TicketInfo, failed []string, err error) {
+	ctx, span := tracing.Start(ctx, "FetchTickets", attribute.String("tracker", cfg.Tracker), attribute.Int("tickets", len(ticketIDs)))
+	defer func() { tracing.End(span, err) }()
+
+	if cfg.Source == SourceAzureDevOps {
+		tickets, err = azuredevops.FetchWorkItems(ctx, cfg.AzureDevOps, ticketIDs)
+		return tickets, nil, err
+	}
+
+	var mu sync.Mutex
+	tickets = make(map[string]*jira.TicketInfo)
+
+	var g errgroup.Group
+	g.SetLimit(ticketConcurrency)
+	for _, ticketID := range ticketIDs {
+		ticketID := ticketID
+		g.Go(func() error {
+			_, ticketSpan := tracing.Start(ctx, "FetchTicket", attribute.String("ticket", ticketID))
+			ticket, err := fetchTicket(ctx, cfg, ticketID)
+			if ticket != nil {
+				ticketSpan.SetAttributes(attribute.String("status", ticket.Status))
+			}
+			tracing.End(ticketSpan, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				cfg.logger().Warn("Could not fetch ticket", "ticket", ticketID, "error", err)
+				failed = append(failed, ticketID)
+				return nil
+			}
+			tickets[ticketID] = ticket
+			return nil
+		})
+	}
+	g.Wait()
+
+	sort.Strings(failed)
+	span.SetAttributes(attribute.Int("failed", len(failed)))
+	return tickets, failed, nil
+}
+
+// fetchTicket fetches a single ticket from the tracker of a report
+func fetchTicket(ctx context.Context, cfg Config, ticketID string) (*jira.TicketInfo, error) {
+	if cfg.Source != SourceAzureDevOps {
+		switch cfg.Tracker {
+		case TrackerLinear:
+			return linear.FetchIssueInfo(ctx, cfg.Linear, ticketID)
+		case TrackerAsana:
+			return asana.FetchTaskInfo(ctx, cfg.Asana, ticketID)
+		}
+		return cfg.jiraClient().FetchTicketInfo(ctx, cfg.Jira, ticketID)
+	}
+
+	tickets, err := azuredevops.FetchWorkItems(ctx, cfg.AzureDevOps, []string{ticketID})
+	if err != nil {
+		return nil, err
+	}
+	ticket, exists := tickets[ticketID]
+	if !exists {
+		return nil, fmt.Errorf("work item %s not found", ticketID)
+	}
+	return ticket, nil
+}
+
+// sourceName names the repository a report is fetched from, for logs
+func sourceName(cfg Config) string {
+	switch cfg.Source {
+	case SourceGitLab:
+		return "GitLab project " + cfg.GitLab.Project
+	case SourceBitbucket:
+		return "Bitbucket repository " + cfg.Bitbucket.Workspace + "/" + cfg.Bitbucket.Repo
+	case SourceAzureDevOps:
+		return "Azure DevOps repository " + cfg.AzureDevOps.Project + "/" + cfg.AzureDevOps.Repo
+	}
+	return cfg.GitHub.Owner + "/" + cfg.GitHub.Repo
+}
+
+// fetchAPIs returns the names of the APIs the PRs and tickets of a report are
+// fetched from, as their transports are named
+func fetchAPIs(cfg Config) []string {
+	var apis []string
+	switch cfg.Source {
+	case SourceGitLab:
+		apis = append(apis, "GitLab")
+	case SourceBitbucket:
+		apis = append(apis, "Bitbucket")
+	case SourceAzureDevOps:
+		// Azure DevOps work items are the tickets too
+		return []string{"Azure DevOps"}
+	default:
+		apis = append(apis, "GitHub")
+	}
+
+	switch cfg.Tracker {
+	case TrackerLinear:
+		apis = append(apis, "Linear")
+	case TrackerAsana:
+		apis = append(apis, "Asana")
+	default:
+		apis = append(apis, "JIRA")
+	}
+	return apis
+}
+
+// buildSlackPRs converts GitHub PR results and JIRA info to the Slack PR format
+func buildSlackPRs(cfg Config, githubPRs []*github.PRResult, jiraInfo map[string]*jira.TicketInfo) []*slack.PRInfo {
+	slackPRs := make([]*slack.PRInfo, len(githubPRs))
+	for i, pr := range githubPRs {
+		jiraStatus := ""
+		jiraDescription := pr.Title
+		ticketURL := ""
+		ticketAssignee := ""
+		priority := ""
+		isBlocked := false
+
+		// Get JIRA info if available
+		if pr.JiraTicket != "" && jiraInfo != nil {
+			if ticket, exists := jiraInfo[pr.JiraTicket]; exists {
+				jiraStatus = ticket.Status
+				jiraDescription = ticket.Summary
+				ticketURL = ticket.URL
+				ticketAssignee = ticket.Assignee
+				priority = ticket.Priority
+				isBlocked = ticket.IsBlocked
+			}
+		}
+
+		// Convert assignee to Slack mention format if mapping exists
+		assignee := pr.Assignee
+		if assignee != "" {
+			assignee = slack.MapGitHubUserToMention(cfg.UserMapping, pr.Assignee)
+		}
+
+		// Unassigned PRs show the assignee of their ticket, if the tracker has one
+		githubAssignee := pr.Assignee
+		if githubAssignee == "" && ticketAssignee != "" {
+			assignee, githubAssignee = ticketAssignee, ticketAssignee
+		}
+
+		// Collect reviewers with their latest review state
+		var reviewers []string
+		for _, review := range pr.Reviews {
+			state := strings.ToLower(strings.ReplaceAll(review.State, "_", " "))
+			reviewers = append(reviewers, fmt.Sprintf("%s (%s)", review.User, state))
+		}
+		var reviewerMentions []string
+		for _, reviewer := range pr.Reviewers {
+			reviewers = append(reviewers, fmt.Sprintf("%s (requested)", reviewer))
+			if slackID, exists := lookupSlackID(cfg.UserMapping, reviewer); exists {
+				reviewerMentions = append(reviewerMentions, fmt.Sprintf("<@%s>", slackID))
+			}
+		}
+
+		slackPRs[i] = &slack.PRInfo{
+			Number:      pr.Number,
+			Title:       pr.Title,
+			URL:         pr.URL,
+			Assignee:    assignee,
+			JiraTicket:  pr.JiraTicket,
+			JiraStatus:  jiraStatus,
+			Priority:    priority,
+			Description: jiraDescription,
+			IsDraft:     pr.IsDraft,
+			IsBlocked:   isBlocked,
+			Author:      pr.Author,
+			Labels:      pr.Labels,
+			Reviewers:   reviewers,
+			ChecksState: pr.ChecksState,
+			CreatedAt:   pr.CreatedAt,
+			UpdatedAt:   pr.UpdatedAt,
+			ReviewCount: len(pr.Reviews),
+			TicketURL:   ticketURL,
+
+			ReadyAt:       pr.ReadyAt,
+			FirstReviewAt: pr.FirstReviewAt,
+			ApprovedAt:    pr.ApprovedAt,
+			MergedAt:      pr.MergedAt,
+
+			GithubAssignee:     githubAssignee,
+			RequestedReviewers: pr.Reviewers,
+			ReviewRequestedAt:  pr.RequestedAt,
+			ReviewerMentions:   reviewerMentions,
+		}
+	}
+
+	return slackPRs
+}
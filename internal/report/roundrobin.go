@@ -0,0 +1,106 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/state"
+)
+
+// suggestReviewers suggests a reviewer for every ready PR without an assignee
+// or requested reviewers, taking turns through the report's roster and
+// skipping the PR's author. A PR keeps its suggestion in later reports until
+// someone is assigned or requested; the turn carries over between runs in the
+// state file.
+func suggestReviewers(cfg Config, prs []*slack.PRInfo) {
+	roster := reviewerRoster(cfg)
+	if len(roster) == 0 {
+		cfg.logger().Warn("No reviewers to suggest, the roster is empty")
+		return
+	}
+
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		cfg.logger().Warn("Could not suggest reviewers", "error", err)
+		return
+	}
+
+	rotation, exists := store.Rotation[cfg.Name]
+	if !exists {
+		rotation = &state.Rotation{}
+		store.Rotation[cfg.Name] = rotation
+	}
+	if rotation.Suggested == nil {
+		rotation.Suggested = make(map[string]string)
+	}
+
+	unattended := make(map[string]bool)
+	for _, pr := range prs {
+		if pr.IsDraft || pr.Assignee != "" || len(pr.RequestedReviewers) > 0 {
+			continue
+		}
+
+		key := state.PRKey(cfg.Slack.GithubOwner, cfg.Slack.GithubRepo, pr.Number)
+		unattended[key] = true
+		author, _ := lookupSlackID(cfg.UserMapping, pr.Author)
+
+		reviewer := rotation.Suggested[key]
+		if reviewer == "" || reviewer == author || !slices.Contains(roster, reviewer) {
+			if reviewer = nextReviewer(roster, rotation.Last, author); reviewer == "" {
+				continue
+			}
+			rotation.Last = reviewer
+			rotation.Suggested[key] = reviewer
+		}
+		pr.SuggestedReviewer = fmt.Sprintf("<@%s>", reviewer)
+	}
+
+	for key := range rotation.Suggested {
+		if !unattended[key] {
+			delete(rotation.Suggested, key)
+		}
+	}
+
+	if err := store.Save(); err != nil {
+		cfg.logger().Warn("Could not save suggested reviewers", "error", err)
+	}
+
+	cfg.logger().Debug("Suggested reviewers", "prs", len(rotation.Suggested), "roster", len(roster))
+}
+
+// reviewerRoster returns the Slack user IDs reviewers are suggested from: the
+// configured roster, or the members of the report channel, sorted so the
+// rotation doesn't depend on the order Slack lists them in
+func reviewerRoster(cfg Config) []string {
+	if len(cfg.Roster) > 0 {
+		return cfg.Roster
+	}
+
+	members, err := slack.ReportChannelUsers(context.Background(), cfg.Slack)
+	if err != nil {
+		cfg.logger().Warn("Could not list channel members to suggest reviewers", "error", err)
+		return nil
+	}
+
+	var roster []string
+	for _, member := range members {
+		roster = append(roster, member.ID)
+	}
+	sort.Strings(roster)
+	return roster
+}
+
+// nextReviewer returns the roster member after last, wrapping around and
+// skipping author, or "" when the author is the only member
+func nextReviewer(roster []string, last, author string) string {
+	start := slices.Index(roster, last) + 1
+	for i := range roster {
+		if reviewer := roster[(start+i)%len(roster)]; reviewer != author {
+			return reviewer
+		}
+	}
+	return ""
+}
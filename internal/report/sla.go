@@ -0,0 +1,111 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/state"
+)
+
+// slaRetention is how long SLA records count towards compliance
+const slaRetention = 30 * 24 * time.Hour
+
+// alertSLABreaches records the review SLA status of the open PRs in the state
+// and posts an alert for PRs that breached their SLA since the last run.
+// Each breach is alerted once.
+func alertSLABreaches(cfg Config, prs []*slack.PRInfo) {
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		cfg.logger().Warn("Could not track review SLAs", "error", err)
+		return
+	}
+
+	now := time.Now()
+	breaches := trackSLA(cfg, store, prs, now)
+	compliance := slaCompliance(cfg, store, now)
+
+	if len(breaches) > 0 {
+		cfg.logger().Info("Sending SLA alert", "prs", len(breaches))
+		err := cfg.slackClient().SendSLAAlert(cfg.Slack, breaches, compliance)
+		auditMessage(cfg, "slack-sla", breaches, err)
+		if err != nil {
+			// Keep the breaches unalerted so the next run retries
+			cfg.logger().Warn("Could not send SLA alert", "error", err)
+			return
+		}
+	}
+
+	if err := store.Save(); err != nil {
+		cfg.logger().Warn("Could not save SLA records", "error", err)
+	}
+}
+
+// trackSLA updates the SLA records of the open PRs and returns the breaches
+// that weren't alerted yet, marking them as alerted. Records of closed PRs
+// whose deadline hadn't passed are dropped, older records are pruned.
+func trackSLA(cfg Config, store *state.Store, prs []*slack.PRInfo, now time.Time) []model.SLABreach {
+	open := make(map[string]bool)
+	var breaches []model.SLABreach
+
+	for _, pr := range prs {
+		start := model.SLAStart(pr)
+		if start.IsZero() {
+			continue
+		}
+
+		prKey := state.PRKey(cfg.Slack.GithubOwner, cfg.Slack.GithubRepo, pr.Number)
+		for step, limit := range cfg.SLA.Limits() {
+			key := state.SLAKey(prKey, step)
+			open[key] = true
+
+			record, exists := store.SLA[key]
+			if !exists {
+				record = &state.SLARecord{Report: cfg.Name, Step: step}
+				store.SLA[key] = record
+			}
+			if record.MetAt.IsZero() && record.AlertedAt.IsZero() {
+				record.Deadline = start.Add(limit)
+			}
+			if metAt := model.SLAMetAt(pr, step); !metAt.IsZero() && record.MetAt.IsZero() {
+				record.MetAt = metAt
+			}
+
+			if record.MetAt.IsZero() && record.AlertedAt.IsZero() && record.Breached(now) {
+				record.AlertedAt = now
+				breaches = append(breaches, model.SLABreach{PR: pr, Step: step, Limit: limit, Waiting: now.Sub(start)})
+			}
+		}
+	}
+
+	for key, record := range store.SLA {
+		if record.Report != cfg.Name {
+			continue
+		}
+		if !open[key] && record.MetAt.IsZero() && !record.Breached(now) {
+			delete(store.SLA, key)
+		} else if record.Deadline.Before(now.Add(-slaRetention)) {
+			delete(store.SLA, key)
+		}
+	}
+
+	sort.Slice(breaches, func(i, j int) bool { return breaches[i].Waiting > breaches[j].Waiting })
+	return breaches
+}
+
+// slaCompliance counts the report's review steps completed within their SLA
+// among those completed or breached within the retention period
+func slaCompliance(cfg Config, store *state.Store, now time.Time) model.SLACompliance {
+	var compliance model.SLACompliance
+	for _, record := range store.SLA {
+		if record.Report != cfg.Name || (record.MetAt.IsZero() && !record.Breached(now)) {
+			continue
+		}
+		compliance.Total++
+		if !record.Breached(now) {
+			compliance.Met++
+		}
+	}
+	return compliance
+}
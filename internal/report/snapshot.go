@@ -0,0 +1,212 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"pr-reporter/internal/chart"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/state"
+	"pr-reporter/internal/store"
+)
+
+// snapshotRetention is how long snapshots of report runs are kept
+const snapshotRetention = 365 * 24 * time.Hour
+
+// statePath returns the path of the state shared with the Slack package
+func statePath(cfg Config) string {
+	if cfg.Slack.StateFile != "" {
+		return cfg.Slack.StateFile
+	}
+	return state.DefaultPath
+}
+
+// saveSnapshot records the PRs of a delivered report in the state database,
+// for deltas and history. JSON state files don't keep snapshots.
+func saveSnapshot(cfg Config, prs []*slack.PRInfo) {
+	path := statePath(cfg)
+	if !cfg.Snapshots || !store.IsDatabase(path) {
+		return
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		cfg.logger().Warn("Could not save snapshot", "error", err)
+		return
+	}
+	defer db.Close()
+
+	now := time.Now()
+	if err := db.SaveSnapshot(store.Snapshot{Report: cfg.Name, TakenAt: now, PRs: prs}); err != nil {
+		cfg.logger().Warn("Could not save snapshot", "error", err)
+		return
+	}
+	if err := db.PruneSnapshots(now.Add(-snapshotRetention)); err != nil {
+		cfg.logger().Warn("Could not prune snapshots", "error", err)
+	}
+
+	cfg.logger().Debug("Saved snapshot", "prs", len(prs), "state", store.Redacted(path))
+}
+
+// changesSinceLastReport compares the PRs with the last snapshot of the
+// report. It returns nil when there is no earlier snapshot to compare with.
+func changesSinceLastReport(cfg Config, prs []*slack.PRInfo) *model.Changes {
+	previous := lastReport(cfg)
+	if previous == nil {
+		return nil
+	}
+	return model.CompareReports(previous.PRs, prs, previous.TakenAt, model.LocaleStrings(cfg.Slack.Locale))
+}
+
+// highlightStatusChanges marks the PRs whose JIRA status changed since the
+// last snapshot of the report
+func highlightStatusChanges(cfg Config, prs []*slack.PRInfo) {
+	if previous := lastReport(cfg); previous != nil {
+		model.MarkStatusChanges(previous.PRs, prs)
+	}
+}
+
+// lastReport returns the last snapshot of the report, or nil when there is
+// none or snapshots aren't kept
+func lastReport(cfg Config) *store.Snapshot {
+	return lastReportBefore(cfg, time.Now())
+}
+
+// lastReportBefore returns the last snapshot of the report taken before the
+// given time, or nil when there is none or snapshots aren't kept
+func lastReportBefore(cfg Config, before time.Time) *store.Snapshot {
+	path := statePath(cfg)
+	if !store.IsDatabase(path) {
+		return nil
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		cfg.logger().Warn("Could not load the last snapshot", "error", err)
+		return nil
+	}
+	defer db.Close()
+
+	previous, err := db.LastSnapshot(cfg.Name, before)
+	if err != nil {
+		cfg.logger().Warn("Could not load the last snapshot", "error", err)
+		return nil
+	}
+	if previous == nil {
+		cfg.logger().Debug("No earlier snapshot to compare with")
+		return nil
+	}
+
+	return previous
+}
+
+// trendDays is how far back the open PR trend chart goes
+const trendDays = 30
+
+// openPRTrend returns the open PR count of the report at the last run of
+// each of the past days, ending with the current PRs
+func openPRTrend(cfg Config, prs []*slack.PRInfo) []chart.Point {
+	path := statePath(cfg)
+	if !store.IsDatabase(path) {
+		return nil
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		cfg.logger().Warn("Could not load snapshots", "error", err)
+		return nil
+	}
+	defer db.Close()
+
+	now := time.Now()
+	snapshots, err := db.Snapshots(cfg.Name, now.AddDate(0, 0, -trendDays))
+	if err != nil {
+		cfg.logger().Warn("Could not load snapshots", "error", err)
+		return nil
+	}
+
+	// Snapshots are oldest first, so the last one of a day wins
+	today := now.Format("2006-01-02")
+	var points []chart.Point
+	lastDay := ""
+	for _, snapshot := range snapshots {
+		day := snapshot.TakenAt.Format("2006-01-02")
+		if day == today {
+			break
+		}
+		point := chart.Point{Time: snapshot.TakenAt, Value: len(snapshot.PRs)}
+		if day == lastDay {
+			points[len(points)-1] = point
+		} else {
+			points = append(points, point)
+		}
+		lastDay = day
+	}
+	points = append(points, chart.Point{Time: now, Value: len(prs)})
+
+	cfg.logger().Debug("Built open PR trend", "points", len(points))
+
+	return points
+}
+
+// statsWindow is the period of the closed PR counts in per-author stats
+const statsWindow = 7 * 24 * time.Hour
+
+// authorStats computes per-author stats of the open PRs. PRs that were in a
+// report within the stats window but aren't open anymore count as closed.
+func authorStats(cfg Config, prs []*slack.PRInfo) []model.AuthorStats {
+	now := time.Now()
+	return model.ComputeAuthorStats(prs, closedSince(cfg, prs, now.Add(-statsWindow)), now)
+}
+
+// reviewTurnaround averages the review turnaround of the open PRs and of the
+// PRs closed within the stats window, which are usually the approved ones
+func reviewTurnaround(cfg Config, prs []*slack.PRInfo) model.Turnaround {
+	closed := closedSince(cfg, prs, time.Now().Add(-statsWindow))
+	return model.ComputeTurnaround(append(append([]*model.PR{}, prs...), closed...))
+}
+
+// closedSince returns the PRs of the report's snapshots since the given time
+// that aren't open anymore, as they were last seen
+func closedSince(cfg Config, open []*slack.PRInfo, since time.Time) []*model.PR {
+	path := statePath(cfg)
+	if !store.IsDatabase(path) {
+		return nil
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		cfg.logger().Warn("Could not load snapshots", "error", err)
+		return nil
+	}
+	defer db.Close()
+
+	snapshots, err := db.Snapshots(cfg.Name, since)
+	if err != nil {
+		cfg.logger().Warn("Could not load snapshots", "error", err)
+		return nil
+	}
+
+	isOpen := make(map[int]bool, len(open))
+	for _, pr := range open {
+		isOpen[pr.Number] = true
+	}
+
+	lastSeen := make(map[int]*model.PR)
+	for _, snapshot := range snapshots {
+		for _, pr := range snapshot.PRs {
+			if !isOpen[pr.Number] {
+				lastSeen[pr.Number] = pr
+			}
+		}
+	}
+
+	var closed []*model.PR
+	for _, pr := range lastSeen {
+		closed = append(closed, pr)
+	}
+	sort.Slice(closed, func(i, j int) bool { return closed[i].Number < closed[j].Number })
+
+	return closed
+}
@@ -0,0 +1,53 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"pr-reporter/internal/state"
+)
+
+// Snooze hides a PR of the report from later Slack reports until the snooze
+// expires, like the "Snooze" button. Snoozed PRs are listed on a separate line.
+func Snooze(cfg Config, number int, duration time.Duration) error {
+	if duration <= 0 {
+		return fmt.Errorf("snooze duration must be positive, got %s", duration)
+	}
+
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	key := state.PRKey(cfg.Slack.GithubOwner, cfg.Slack.GithubRepo, number)
+	store.Acks[key] = &state.Ack{Action: state.ActionSnooze, At: now, Until: now.Add(duration)}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	cfg.logger().Info("Snoozed PR", "pr", key, "until", now.Add(duration).Format("2006-01-02 15:04"))
+	return nil
+}
+
+// Unsnooze shows a snoozed PR of the report again in the next Slack report
+func Unsnooze(cfg Config, number int) error {
+	store, err := state.Load(statePath(cfg))
+	if err != nil {
+		return err
+	}
+
+	key := state.PRKey(cfg.Slack.GithubOwner, cfg.Slack.GithubRepo, number)
+	ack, exists := store.Acks[key]
+	if !exists || ack.Action != state.ActionSnooze {
+		return fmt.Errorf("%s isn't snoozed", key)
+	}
+
+	delete(store.Acks, key)
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	cfg.logger().Info("Removed the snooze of PR", "pr", key)
+	return nil
+}
@@ -0,0 +1,89 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+	"pr-reporter/internal/store"
+)
+
+// sprintBurndown correlates the open PRs with the active sprint of the
+// report's JIRA board. It returns nil when the sprint can't be fetched or the
+// board has no active sprint.
+func sprintBurndown(cfg Config, prs []*slack.PRInfo) *model.SprintBurndown {
+	if cfg.Tracker != TrackerJira || cfg.Jira.URL == "" {
+		cfg.logger().Warn("Sprints can only be fetched from JIRA, the report won't have a sprint burn-down")
+		return nil
+	}
+
+	sprint, err := cfg.jiraClient().FetchActiveSprint(cfg.Jira, cfg.SprintBoard)
+	if err != nil {
+		cfg.logger().Warn("Could not fetch the active sprint", "error", err)
+		return nil
+	}
+	if sprint == nil {
+		return nil
+	}
+
+	return model.NewSprintBurndown(sprint.Name, sprint.End, sprint.Tickets, prs)
+}
+
+// sprintSummary sums up the last closed sprint of the report's JIRA board in
+// the first report after it closed, from the snapshots taken while it ran. It
+// returns nil for every other report, or when the sprint or the snapshots
+// can't be read.
+func sprintSummary(cfg Config, prs []*slack.PRInfo) *model.SprintSummary {
+	if cfg.Tracker != TrackerJira || cfg.Jira.URL == "" {
+		cfg.logger().Warn("Sprints can only be fetched from JIRA, the report won't have a sprint summary")
+		return nil
+	}
+	path := statePath(cfg)
+	if !store.IsDatabase(path) {
+		cfg.logger().Warn("The sprint summary needs the snapshots of a state database (see STATE_FILE)")
+		return nil
+	}
+
+	sprint, err := cfg.jiraClient().FetchLastClosedSprint(cfg.Jira, cfg.SprintBoard)
+	if err != nil {
+		cfg.logger().Warn("Could not fetch the last closed sprint", "error", err)
+		return nil
+	}
+	if sprint == nil || sprint.Completed.IsZero() {
+		return nil
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		cfg.logger().Warn("Could not load snapshots", "error", err)
+		return nil
+	}
+	defer db.Close()
+
+	// Only the first report after the sprint closed shows the summary
+	previous, err := db.LastSnapshot(cfg.Name, time.Now())
+	if err != nil {
+		cfg.logger().Warn("Could not load the last snapshot", "error", err)
+		return nil
+	}
+	if previous == nil || !previous.TakenAt.Before(sprint.Completed) {
+		cfg.logger().Debug("Sprint summary was already reported", "sprint", sprint.Name, "completed", sprint.Completed)
+		return nil
+	}
+
+	snapshots, err := db.Snapshots(cfg.Name, sprint.Start)
+	if err != nil {
+		cfg.logger().Warn("Could not load snapshots", "error", err)
+		return nil
+	}
+	var history [][]*model.PR
+	var atClose []*model.PR
+	for _, snapshot := range snapshots {
+		if snapshot.TakenAt.Before(sprint.Completed) {
+			history = append(history, snapshot.PRs)
+			atClose = snapshot.PRs
+		}
+	}
+
+	return model.NewSprintSummary(sprint.Name, sprint.Start, sprint.Completed, sprint.Tickets, history, atClose, prs)
+}
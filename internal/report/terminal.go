@@ -0,0 +1,38 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"pr-reporter/internal/terminal"
+)
+
+// ShowTable collects the PRs of a report and prints them as a color-coded
+// table to standard output without posting anywhere. With a refresh interval,
+// it redraws the table on every interval until interrupted.
+func ShowTable(cfg Config, refresh time.Duration) error {
+	color := terminal.ColorEnabled(os.Stdout)
+
+	for {
+		prs, err := CollectPRs(cfg)
+		switch {
+		case err != nil && refresh <= 0:
+			return err
+		case err != nil:
+			cfg.logger().Warn("Could not refresh PRs", "error", err)
+		default:
+			if refresh > 0 {
+				fmt.Print(terminal.ClearScreen)
+			}
+			terminal.Render(os.Stdout, newReport(cfg, prs), color)
+		}
+
+		if refresh <= 0 {
+			return nil
+		}
+
+		cfg.logger().Info("Refreshing (Ctrl+C to quit)", "in", refresh)
+		time.Sleep(refresh)
+	}
+}
@@ -0,0 +1,110 @@
+package report
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"pr-reporter/internal/github"
+	"pr-reporter/internal/slack"
+)
+
+// mapUsersByEmail is the enricher filling gaps in USER_MAPPING (see
+// autoMapUsers). Channel members don't depend on the PRs, so they are listed
+// while the PRs are fetched; the listing is canceled when every user turns out
+// to be mapped.
+func mapUsersByEmail(ctx context.Context, cfg Config, fetched func() *PRBatch) error {
+	listingCtx, cancelListing := context.WithCancel(ctx)
+	defer cancelListing()
+
+	var members []slack.SlackUser
+	listed := make(chan struct{})
+	go func() {
+		defer close(listed)
+		members = channelMembers(listingCtx, cfg)
+	}()
+
+	var logins []string
+	batch := fetched()
+	if batch != nil {
+		logins = unmappedUsers(cfg, batch.PRs)
+	}
+	if len(logins) == 0 {
+		cancelListing()
+		<-listed
+		return nil
+	}
+
+	<-listed
+	autoMapUsers(cfg, batch.PRs, logins, members)
+	return nil
+}
+
+// unmappedUsers returns the GitHub users that appear on the PRs but are
+// missing from USER_MAPPING, sorted
+func unmappedUsers(cfg Config, githubPRs []*github.PRResult) []string {
+	unmapped := make(map[string]bool)
+	addUser := func(login string) {
+		if login == "" || strings.HasPrefix(login, "team:") {
+			return
+		}
+		if _, exists := lookupSlackID(cfg.UserMapping, login); !exists {
+			unmapped[login] = true
+		}
+	}
+
+	for _, pr := range githubPRs {
+		addUser(pr.Author)
+		addUser(pr.Assignee)
+		for _, reviewer := range pr.Reviewers {
+			addUser(reviewer)
+		}
+		for _, review := range pr.Reviews {
+			addUser(review.User)
+		}
+	}
+
+	logins := make([]string, 0, len(unmapped))
+	for login := range unmapped {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	return logins
+}
+
+// channelMembers lists the members of the report channel, whose emails
+// autoMapUsers matches first. It returns none when listing fails or ctx is
+// canceled because no user turned out to be unmapped.
+func channelMembers(ctx context.Context, cfg Config) []slack.SlackUser {
+	members, err := slack.ReportChannelUsers(ctx, cfg.Slack)
+	if err != nil && ctx.Err() == nil {
+		cfg.logger().Warn("Could not list channel members, looking up emails individually", "error", err)
+	}
+	return members
+}
+
+// autoMapUsers looks up Slack accounts for the unmapped GitHub users logins
+// by matching their email addresses with members of the report channel or
+// the workspace. Found users are added to cfg.UserMapping, so mentions and
+// digests reach them too.
+func autoMapUsers(cfg Config, githubPRs []*github.PRResult, logins []string, members []slack.SlackUser) {
+	cfg.logger().Info("Looking up Slack accounts of unmapped GitHub users by email", "users", len(logins))
+
+	emails, err := cfg.gitHubClient().FetchUserEmails(cfg.GitHub, logins, githubPRs)
+	if err != nil {
+		cfg.logger().Warn("Could not fetch GitHub user emails", "error", err)
+		return
+	}
+
+	mapping, err := slack.MapUsersByEmail(cfg.Slack, emails, members)
+	if err != nil {
+		cfg.logger().Warn("Could not look up Slack users by email", "error", err)
+		return
+	}
+
+	for login, slackID := range mapping {
+		cfg.UserMapping[login] = slackID
+	}
+
+	cfg.logger().Info("Mapped GitHub users to Slack by email", "mapped", len(mapping), "users", len(logins))
+}
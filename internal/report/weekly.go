@@ -0,0 +1,53 @@
+package report
+
+import (
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/slack"
+)
+
+// WeeklyConfig turns a daily report configuration into its weekly summary:
+// merges and average cycle time of the week, PRs still pending after 7 days
+// and blocked tickets, followed by per-author stats instead of the PR list.
+// The summary is only posted to Slack, as a new message.
+func WeeklyConfig(cfg Config) Config {
+	cfg = slackOnly(cfg)
+	cfg.Weekly = true
+	cfg.Digest = false
+	cfg.ShowChanges = false
+	cfg.TrendChart = false
+	cfg.AuthorStats = true
+
+	cfg.Slack.Verbosity = slack.VerbositySummary
+	channels := make([]slack.ChannelTarget, len(cfg.Slack.Channels))
+	for i, target := range cfg.Slack.Channels {
+		target.Verbosity = slack.VerbositySummary
+		channels[i] = target
+	}
+	cfg.Slack.Channels = channels
+	cfg.Slack.UpdateExisting = false
+	cfg.Slack.LiveStatus = false
+	cfg.Slack.PreviewUser = ""
+
+	return cfg
+}
+
+// weeklySummary summarizes the week ending now from the open PRs and the PRs
+// merged during the week. Merged PRs can only be fetched from GitHub.
+func weeklySummary(cfg Config, prs []*slack.PRInfo) *model.WeeklySummary {
+	now := time.Now()
+
+	var merged []*model.PR
+	if cfg.Source == SourceGitHub {
+		githubPRs, err := cfg.gitHubClient().FetchMergedPRs(cfg.GitHub, now.Add(-7*24*time.Hour))
+		if err != nil {
+			cfg.logger().Warn("Could not fetch merged PRs", "error", err)
+		}
+		merged = buildSlackPRs(cfg, githubPRs, nil)
+	} else {
+		cfg.logger().Warn("Merged PRs can only be fetched from GitHub, the weekly summary won't list merges")
+	}
+
+	return model.NewWeeklySummary(prs, merged, now)
+}
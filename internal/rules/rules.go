@@ -0,0 +1,153 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Knetic/govaluate"
+)
+
+// PR holds the fields of a PR an inclusion rule can refer to
+type PR struct {
+	Number    int
+	Title     string
+	Author    string
+	Assignee  string
+	Base      string // Branch the PR targets
+	Head      string // Branch the PR is opened from
+	Labels    []string
+	Reviewers []string // Requested reviewers that haven't reviewed yet
+	Draft     bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// variables returns the values of the rule variables for a PR
+func (pr PR) variables(now time.Time) map[string]interface{} {
+	days := func(t time.Time) float64 {
+		if t.IsZero() {
+			return 0
+		}
+		return now.Sub(t).Hours() / 24
+	}
+
+	// Lists are passed as []string: govaluate would flatten []interface{}
+	// values into the other arguments of a function
+	labels, reviewers := pr.Labels, pr.Reviewers
+	if labels == nil {
+		labels = []string{}
+	}
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+
+	return map[string]interface{}{
+		"number":    float64(pr.Number),
+		"title":     pr.Title,
+		"author":    pr.Author,
+		"assignee":  pr.Assignee,
+		"base":      pr.Base,
+		"head":      pr.Head,
+		"labels":    labels,
+		"reviewers": reviewers,
+		"draft":     pr.Draft,
+		"age_days":  days(pr.CreatedAt),
+		"idle_days": days(pr.UpdatedAt),
+	}
+}
+
+// functions are the functions rules can call
+var functions = map[string]govaluate.ExpressionFunction{
+	// has(list, value) reports whether the list holds the value (case-insensitive)
+	"has": func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("has takes a list and a value, got %d arguments", len(args))
+		}
+		list, isList := args[0].([]string)
+		value, isString := args[1].(string)
+		if !isList || !isString {
+			return nil, fmt.Errorf("has takes a list and a string, e.g. has(labels, \"Poker\")")
+		}
+		for _, item := range list {
+			if strings.EqualFold(item, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	},
+	// matches(text, pattern) reports whether the text matches the regular expression
+	"matches": func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("matches takes a text and a pattern, got %d arguments", len(args))
+		}
+		text, isText := args[0].(string)
+		pattern, isPattern := args[1].(string)
+		if !isText || !isPattern {
+			return nil, fmt.Errorf("matches takes two strings, e.g. matches(title, \"^POKER-\")")
+		}
+		return regexp.MatchString(pattern, text)
+	},
+}
+
+// Rule is a compiled inclusion rule such as
+// `has(labels, "Poker") && !draft && age_days < 30`
+type Rule struct {
+	source string
+	expr   *govaluate.EvaluableExpression
+}
+
+// Compile parses a rule. Rules use the operators of govaluate (&&, ||, !,
+// ==, !=, <, <=, >, >=, =~, +, -, ...), the variables number, title, author,
+// assignee, base, head, labels, reviewers, draft, age_days and idle_days, and
+// the functions has(list, value) and matches(text, pattern).
+func Compile(source string) (*Rule, error) {
+	expr, err := govaluate.NewEvaluableExpressionWithFunctions(source, functions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", source, err)
+	}
+
+	known := PR{}.variables(time.Now())
+	for _, name := range expr.Vars() {
+		if _, exists := known[name]; !exists {
+			names := make([]string, 0, len(known))
+			for name := range known {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("invalid rule %q: unknown variable %q (available: %s)", source, name, strings.Join(names, ", "))
+		}
+	}
+
+	rule := &Rule{source: source, expr: expr}
+	// Catch rules that don't result in true or false before they're used
+	if _, err := rule.Match(PR{}); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Match reports whether a PR satisfies the rule
+func (r *Rule) Match(pr PR) (bool, error) {
+	result, err := r.expr.Evaluate(pr.variables(time.Now()))
+	if err != nil {
+		return false, fmt.Errorf("error evaluating rule %q: %w", r.source, err)
+	}
+	matched, isBool := result.(bool)
+	if !isBool {
+		return false, fmt.Errorf("rule %q must result in true or false, got %v", r.source, result)
+	}
+	return matched, nil
+}
+
+// String returns the source of the rule
+func (r *Rule) String() string {
+	return r.source
+}
+
+// MarshalText encodes the rule as its source
+func (r *Rule) MarshalText() ([]byte, error) {
+	return []byte(r.source), nil
+}
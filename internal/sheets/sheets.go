@@ -0,0 +1,234 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/model"
+)
+
+// DefaultSheet is the sheet (tab) rows are written to when none is configured
+const DefaultSheet = "PRs"
+
+// apiURL is the Google Sheets API base URL
+const apiURL = "https://sheets.googleapis.com/v4/spreadsheets"
+
+// scope grants read and write access to spreadsheets
+const scope = "https://www.googleapis.com/auth/spreadsheets"
+
+// Options contains options for appending the PR list to a Google Sheet
+type Options struct {
+	CredentialsFile string // Service account JSON key file; the sheet must be shared with its email
+	SpreadsheetID   string // ID of the spreadsheet, from its URL
+	Sheet           string // Sheet (tab) name (default: PRs)
+}
+
+// Configured reports whether the options contain a spreadsheet to write to
+func (o Options) Configured() bool {
+	return o.CredentialsFile != "" && o.SpreadsheetID != ""
+}
+
+// header is the first row of the sheet
+var header = []interface{}{"Date", "Report", "PR", "Title", "URL", "Author", "Assignee", "Ticket", "Ticket Status", "Draft", "Blocked", "Age (days)", "Created"}
+
+// serviceAccount is the part of a service account key file used here
+type serviceAccount struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// AppendReport writes one row per PR for the report date. Rows of the same
+// report, day and PR are updated in place, so reruns on the same day don't
+// add duplicates and every day adds its own rows for history.
+func AppendReport(ctx context.Context, opts Options, report model.Report) error {
+	if !opts.Configured() {
+		return fmt.Errorf("Google Sheets credentials file and spreadsheet ID are required")
+	}
+
+	client, err := newClient(opts.CredentialsFile)
+	if err != nil {
+		return err
+	}
+
+	sheet := opts.Sheet
+	if sheet == "" {
+		sheet = DefaultSheet
+	}
+
+	// Find the rows already written for the report today, keyed by PR number
+	var existing struct {
+		Values [][]string `json:"values"`
+	}
+	if err := call(ctx, client, http.MethodGet, valuesURL(opts, sheet, "A:C", ""), nil, &existing); err != nil {
+		return fmt.Errorf("error reading sheet %q: %w", sheet, err)
+	}
+
+	date := report.Date.Format("2006-01-02")
+	rowOf := make(map[string]int)
+	for i, values := range existing.Values {
+		if len(values) >= 3 && values[0] == date && values[1] == report.Name {
+			rowOf[values[2]] = i + 1 // Sheet rows are 1-based
+		}
+	}
+
+	var updates []map[string]interface{}
+	var appends [][]interface{}
+	if len(existing.Values) == 0 {
+		appends = append(appends, header)
+	}
+	for _, pr := range report.PRs {
+		values := prRow(report, pr)
+		if row, exists := rowOf[strconv.Itoa(pr.Number)]; exists {
+			updates = append(updates, map[string]interface{}{
+				"range":  fmt.Sprintf("%s!A%d", quoteSheet(sheet), row),
+				"values": [][]interface{}{values},
+			})
+			continue
+		}
+		appends = append(appends, values)
+	}
+
+	if len(updates) > 0 {
+		body := map[string]interface{}{"valueInputOption": "RAW", "data": updates}
+		if err := call(ctx, client, http.MethodPost, fmt.Sprintf("%s/%s/values:batchUpdate", apiURL, url.PathEscape(opts.SpreadsheetID)), body, nil); err != nil {
+			return fmt.Errorf("error updating rows in sheet %q: %w", sheet, err)
+		}
+	}
+
+	if len(appends) > 0 {
+		body := map[string]interface{}{"values": appends}
+		if err := call(ctx, client, http.MethodPost, valuesURL(opts, sheet, "A1", ":append?valueInputOption=RAW&insertDataOption=INSERT_ROWS"), body, nil); err != nil {
+			return fmt.Errorf("error appending rows to sheet %q: %w", sheet, err)
+		}
+	}
+
+	slog.Debug("Wrote report to sheet", "report", report.Name, "sheet", sheet, "appended", len(appends), "updated", len(updates))
+
+	return nil
+}
+
+// prRow returns the sheet row of a PR, in the column order of header. Values
+// are written as-is, so dates stay text and compare reliably on reruns.
+func prRow(report model.Report, pr *model.PR) []interface{} {
+	var age interface{} = ""
+	created := ""
+	if !pr.CreatedAt.IsZero() {
+		age = int(report.Date.Sub(pr.CreatedAt).Hours() / 24)
+		created = pr.CreatedAt.Format("2006-01-02")
+	}
+
+	return []interface{}{
+		report.Date.Format("2006-01-02"),
+		report.Name,
+		pr.Number,
+		pr.Title,
+		report.PRURL(pr),
+		pr.Author,
+		pr.GithubAssignee,
+		pr.JiraTicket,
+		pr.JiraStatus,
+		pr.IsDraft,
+		pr.IsBlocked,
+		age,
+		created,
+	}
+}
+
+// httpClient is used to call the Google Sheets API
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Google Sheets"}).Client(30 * time.Second)
+
+// newClient returns an HTTP client authenticated as the service account of
+// the key file
+func newClient(credentialsFile string) (*http.Client, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Google credentials file: %w", err)
+	}
+
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("error parsing Google credentials file: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("Google credentials file is not a service account key")
+	}
+
+	cfg := &jwt.Config{
+		Email:        account.ClientEmail,
+		PrivateKey:   []byte(account.PrivateKey),
+		PrivateKeyID: account.PrivateKeyID,
+		TokenURL:     account.TokenURI,
+		Scopes:       []string{scope},
+	}
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = "https://oauth2.googleapis.com/token"
+	}
+
+	// Token and API requests share the rate limited transport
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	client := cfg.Client(ctx)
+	client.Timeout = 30 * time.Second
+	return client, nil
+}
+
+// valuesURL returns the URL of a range of the sheet with an optional suffix
+// such as ":append?..."
+func valuesURL(opts Options, sheet, cells, suffix string) string {
+	return fmt.Sprintf("%s/%s/values/%s%s", apiURL, url.PathEscape(opts.SpreadsheetID), url.PathEscape(quoteSheet(sheet)+"!"+cells), suffix)
+}
+
+// quoteSheet quotes a sheet name for use in A1 notation
+func quoteSheet(sheet string) string {
+	return "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+}
+
+// call sends a request to the Sheets API and decodes the response into
+// result unless it is nil
+func call(ctx context.Context, client *http.Client, method, rawURL string, payload, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return httpx.NewStatusError("Google Sheets", resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
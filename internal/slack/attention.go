@@ -0,0 +1,85 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/model"
+)
+
+// attentionReasons returns why a PR needs attention, or nil if it doesn't.
+// Drafts only count when they are blocked since they aren't ready for review.
+func attentionReasons(opts MessageOptions, text model.Strings, pr *PRInfo, now time.Time) []string {
+	var reasons []string
+	if pr.IsBlocked {
+		reasons = append(reasons, text.ReasonBlocked)
+	}
+	if pr.IsDraft {
+		return reasons
+	}
+
+	staleAfter := opts.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	if !pr.UpdatedAt.IsZero() && now.Sub(pr.UpdatedAt) > staleAfter {
+		reasons = append(reasons, text.ReasonStale)
+	}
+
+	if pr.ReviewCount == 0 {
+		reasons = append(reasons, text.ReasonUnreviewed)
+	}
+
+	return reasons
+}
+
+// prMentions returns the Slack mentions of the assignee and requested
+// reviewers of a PR. Unmapped users can't be pinged and are left out.
+func prMentions(pr *PRInfo) []string {
+	var mentions []string
+	if strings.HasPrefix(pr.Assignee, "<@") {
+		mentions = append(mentions, pr.Assignee)
+	}
+	mentions = append(mentions, pr.ReviewerMentions...)
+	return mentions
+}
+
+// targetedMentions formats one line per PR that needs attention, pinging only
+// the people responsible for it, as of now. It also returns every mentioned
+// user once.
+func targetedMentions(opts MessageOptions, emoji Emoji, text model.Strings, prs []*PRInfo, now time.Time) ([]string, []string) {
+	seen := make(map[string]bool)
+
+	var lines []string
+	var mentions []string
+	for _, pr := range prs {
+		reasons := attentionReasons(opts, text, pr, now)
+		if len(reasons) == 0 {
+			continue
+		}
+
+		prPings := prMentions(pr)
+		for _, mention := range prPings {
+			if !seen[mention] {
+				seen[mention] = true
+				mentions = append(mentions, mention)
+			}
+		}
+
+		line := fmt.Sprintf("• %s _(%s)_", prLink(opts, pr), strings.Join(reasons, ", "))
+		if len(prPings) > 0 {
+			line += " " + strings.Join(prPings, " ")
+		}
+		if note := stillBlocked(text, pr, now); note != "" {
+			line += fmt.Sprintf(" – _%s_", note)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) > 0 {
+		lines = append([]string{fmt.Sprintf("%s *%s:*", emoji.Attention, text.NeedsAttention)}, lines...)
+	}
+
+	return lines, mentions
+}
@@ -0,0 +1,278 @@
+package slack
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/state"
+)
+
+// Action IDs of the buttons attached to each PR in interactive reports
+const (
+	ActionIDReviewing = "pr_reviewing"
+	ActionIDSnooze    = "pr_snooze"
+	ActionIDNotMine   = "pr_not_mine"
+)
+
+// maxBlocksPerMessage is the number of blocks Slack accepts in a single message
+const maxBlocksPerMessage = 50
+
+// messagePart is a single Slack message of a (possibly split) report
+type messagePart struct {
+	text   string        // Message text, or notification fallback when blocks are set
+	blocks []slack.Block // Optional Block Kit layout
+}
+
+// options returns the Slack message options for the part
+func (p messagePart) options() []slack.MsgOption {
+	msgOpts := []slack.MsgOption{slack.MsgOptionText(p.text, false)}
+	if len(p.blocks) > 0 {
+		msgOpts = append(msgOpts, slack.MsgOptionBlocks(p.blocks...))
+	}
+	return msgOpts
+}
+
+// continuationReserve leaves room for the "(continued n/m)" note added to split parts
+const continuationReserve = 32
+
+// buildTextParts splits the report into plain text messages
+func buildTextParts(opts MessageOptions, content reportContent, maxLength int) []messagePart {
+	continued := model.LocaleStrings(opts.Locale).Continued
+
+	if maxLength > 2*continuationReserve {
+		maxLength -= continuationReserve
+	}
+	texts := splitMessage(content.lines(), maxLength)
+
+	parts := make([]messagePart, len(texts))
+	for i, text := range texts {
+		if len(texts) > 1 && i > 0 {
+			text = fmt.Sprintf("_(%s %d/%d)_\n%s", continued, i+1, len(texts), text)
+		}
+		parts[i] = messagePart{text: text}
+	}
+
+	return parts
+}
+
+// renderTextParts formats the report as plain text parts, without button
+// actions, for deliveries that don't go through the regular channel post
+func renderTextParts(opts MessageOptions, prs []*PRInfo) ([]messagePart, error) {
+	tmpl, err := loadTemplates(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	texts, err := formatMessages(opts, tmpl, prs)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]messagePart, len(texts))
+	for i, text := range texts {
+		parts[i] = messagePart{text: text}
+	}
+	return parts, nil
+}
+
+// renderBlockParts formats the report as Block Kit parts, without button
+// actions, for deliveries that don't go through the regular channel post
+func renderBlockParts(opts MessageOptions, prs []*PRInfo) ([]messagePart, error) {
+	tmpl, err := loadTemplates(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := formatReport(opts, tmpl, prs, len(prs), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Interactive = false
+	return buildBlockParts(opts, content, prs), nil
+}
+
+// formatMessages formats the report for prs as the plain text messages posted
+// to Slack, split at opts.MaxLength. It has no side effects and, with opts.Date
+// set, its output only depends on its arguments; tmpl holds the parsed custom
+// templates (nil: built-in formatting).
+func formatMessages(opts MessageOptions, tmpl *template.Template, prs []*PRInfo) ([]string, error) {
+	content, err := formatReport(opts, tmpl, prs, len(prs), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLength := opts.MaxLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxLength
+	}
+
+	parts := buildTextParts(opts, content, maxLength)
+	texts := make([]string, len(parts))
+	for i, part := range parts {
+		texts[i] = part.text
+	}
+	return texts, nil
+}
+
+// RenderMessage formats the report as the plain text posted to Slack, in one
+// piece, for archiving
+func RenderMessage(opts MessageOptions, prs []*PRInfo) (string, error) {
+	tmpl, err := loadTemplates(opts)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := formatReport(opts, tmpl, prs, len(prs), nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(content.lines(), "\n"), nil
+}
+
+// buildBlockParts lays the report out as Block Kit sections, with action
+// buttons under each PR in interactive reports and the PRs grouped by ticket
+// status (or by assignee with GroupByAssignee) in MessageFormatV2, split into
+// messages that respect Slack's block limit
+func buildBlockParts(opts MessageOptions, content reportContent, prs []*PRInfo) []messagePart {
+	// Group blocks into units that must stay in the same message
+	var units [][]slack.Block
+
+	if header := strings.TrimSpace(strings.Join(content.header, "\n")); header != "" {
+		units = append(units, []slack.Block{textSection(header)})
+	}
+
+	groups := [][]int{make([]int, len(prs))}
+	for i := range prs {
+		groups[0][i] = i
+	}
+	switch {
+	case len(content.headings) > 0:
+		groups = groupByHeading(content.headings)
+	case opts.MessageFormat == MessageFormatV2 && len(prs) > 0:
+		groups = groupByStatus(opts, prs)
+	}
+
+	for _, group := range groups {
+		var heading []slack.Block
+		switch {
+		case len(content.headings) > 0:
+			heading = []slack.Block{textSection(content.headings[group[0]])}
+			if opts.MessageFormat == MessageFormatV2 {
+				heading = append([]slack.Block{slack.NewDividerBlock()}, heading...)
+			}
+		case opts.MessageFormat == MessageFormatV2:
+			status := statusName(opts, prs[group[0]])
+			heading = []slack.Block{slack.NewDividerBlock(), textSection(fmt.Sprintf("*%s* (%d)", status, len(group)))}
+		}
+		for n, i := range group {
+			unit := []slack.Block{textSection(content.prLines[i])}
+			if opts.Interactive {
+				pr := prs[i]
+				value := state.PRKey(opts.GithubOwner, opts.GithubRepo, pr.Number)
+				unit = append(unit, slack.NewActionBlock(
+					"pr_actions_"+strconv.Itoa(pr.Number),
+					newButton(ActionIDReviewing, value, "👀 Reviewing"),
+					newButton(ActionIDSnooze, value, "💤 Snooze 1 day"),
+					newButton(ActionIDNotMine, value, "🙅 Not mine"),
+				))
+			}
+			// Keep each heading with the first PR under it
+			if n == 0 {
+				unit = append(heading, unit...)
+			}
+			units = append(units, unit)
+		}
+	}
+
+	if footer := strings.TrimSpace(strings.Join(content.footer, "\n")); footer != "" {
+		units = append(units, []slack.Block{textSection(footer)})
+	}
+
+	// Pack units into messages, keeping one block free for the continuation note
+	var chunks [][]slack.Block
+	var current []slack.Block
+	for _, unit := range units {
+		if len(current)+len(unit) > maxBlocksPerMessage-1 {
+			chunks = append(chunks, current)
+			current = nil
+		}
+		current = append(current, unit...)
+	}
+	if len(current) > 0 || len(chunks) == 0 {
+		chunks = append(chunks, current)
+	}
+
+	fallback := opts.ReportTitle
+	if fallback == "" {
+		fallback = "Open PR report"
+	}
+
+	continued := model.LocaleStrings(opts.Locale).Continued
+	parts := make([]messagePart, len(chunks))
+	for i, blocks := range chunks {
+		text := fallback
+		if len(chunks) > 1 && i > 0 {
+			note := fmt.Sprintf("_(%s %d/%d)_", continued, i+1, len(chunks))
+			blocks = append([]slack.Block{slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, note, false, false))}, blocks...)
+			text = fmt.Sprintf("%s (%s %d/%d)", fallback, continued, i+1, len(chunks))
+		}
+		parts[i] = messagePart{text: text, blocks: blocks}
+	}
+
+	return parts
+}
+
+// groupByStatus returns the indexes of prs grouped by ticket status, groups
+// in the order their first PR is listed
+func groupByStatus(opts MessageOptions, prs []*PRInfo) [][]int {
+	var groups [][]int
+	index := make(map[string]int)
+	for i, pr := range prs {
+		status := statusName(opts, pr)
+		g, exists := index[status]
+		if !exists {
+			g = len(groups)
+			index[status] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// groupByHeading returns the indexes of the PR lines in blocks that each
+// start at a heading, such as the PRs of one assignee
+func groupByHeading(headings []string) [][]int {
+	var groups [][]int
+	for i, heading := range headings {
+		if heading != "" || len(groups) == 0 {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], i)
+	}
+	return groups
+}
+
+// statusName returns the ticket status a PR is grouped under
+func statusName(opts MessageOptions, pr *PRInfo) string {
+	if pr.JiraStatus == "" {
+		return model.LocaleStrings(opts.Locale).UnknownStatus
+	}
+	return pr.JiraStatus
+}
+
+// textSection creates a section block with markdown text
+func textSection(text string) slack.Block {
+	return slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil)
+}
+
+// newButton creates a button carrying the PR key as its value
+func newButton(actionID, value, label string) *slack.ButtonBlockElement {
+	return slack.NewButtonBlockElement(actionID, value, slack.NewTextBlockObject(slack.PlainTextType, label, true, false))
+}
@@ -0,0 +1,149 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/logging"
+	"pr-reporter/internal/state"
+)
+
+// channelIDRegex matches Slack conversation IDs (public, private and DM channels)
+var channelIDRegex = regexp.MustCompile(`^[CGD][A-Z0-9]{8,}$`)
+
+// IsChannelID reports whether channel looks like a Slack channel ID rather than a name
+func IsChannelID(channel string) bool {
+	return channelIDRegex.MatchString(channel)
+}
+
+// GetChannelUsers fetches the list of users from a specified Slack channel.
+// channel can be a name (with or without "#") or a channel ID. Name lookups
+// are cached in cacheFile when it is set, so later runs skip listing every
+// conversation in the workspace. apiURL replaces the Slack Web API URL when
+// set.
+func GetChannelUsers(ctx context.Context, apiURL, token, channel, cacheFile string) ([]string, error) {
+	api := newClient(apiURL, token)
+
+	// Test authentication first
+	if logging.Debug() {
+		authTest, err := api.AuthTest()
+		if err != nil {
+			return nil, fmt.Errorf("Slack authentication failed: %w", err)
+		}
+		slog.Debug("Authenticated to Slack", "user", authTest.User, "team", authTest.Team)
+	}
+
+	channelID, err := ResolveChannelID(api, channel, cacheFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get channel members
+
+	var members []string
+	cursor := ""
+	for {
+		page, nextCursor, err := api.GetUsersInConversationContext(ctx, &slack.GetUsersInConversationParameters{
+			ChannelID: channelID,
+			Cursor:    cursor,
+			Limit:     1000,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching channel members: %w", err)
+		}
+		members = append(members, page...)
+
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+
+	slog.Debug("Fetched channel members", "channel", channel, "id", channelID, "members", len(members))
+
+	return members, nil
+}
+
+// ResolveChannelID returns the ID of a channel given by name or ID. IDs are
+// used as-is; names are looked up in the cache first (verified with
+// conversations.info) and otherwise by listing conversations.
+func ResolveChannelID(api *slack.Client, channel, cacheFile string) (string, error) {
+	channel = strings.TrimSpace(channel)
+	if IsChannelID(channel) {
+		return channel, nil
+	}
+
+	channelName := strings.TrimPrefix(channel, "#")
+
+	// Try the on-disk cache, making sure the channel wasn't renamed since
+	var store *state.Store
+	if cacheFile != "" {
+		var err error
+		store, err = state.Load(cacheFile)
+		if err != nil {
+			slog.Warn("Could not load channel cache", "error", err)
+		} else if cachedID, exists := store.Channels[channelName]; exists {
+			info, err := api.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: cachedID})
+			if err == nil && info.Name == channelName && !info.IsArchived {
+				slog.Debug("Found channel in cache", "channel", channelName, "id", cachedID)
+				return cachedID, nil
+			}
+			slog.Debug("Cached channel ID is stale, looking it up again", "channel", channelName, "id", cachedID)
+			delete(store.Channels, channelName)
+		}
+	}
+
+	channelID, err := findChannelByName(api, channelName)
+	if err != nil {
+		return "", err
+	}
+
+	if store != nil {
+		store.Channels[channelName] = channelID
+		if err := store.Save(); err != nil {
+			slog.Warn("Could not save channel cache", "error", err)
+		}
+	}
+
+	return channelID, nil
+}
+
+// findChannelByName lists public and private conversations page by page until
+// the channel is found
+func findChannelByName(api *slack.Client, channelName string) (string, error) {
+	conversationTypes := []string{"public_channel", "private_channel"}
+
+	for _, convType := range conversationTypes {
+		cursor := ""
+		for {
+			conversations, nextCursor, err := api.GetConversations(&slack.GetConversationsParameters{
+				Types:           []string{convType},
+				Cursor:          cursor,
+				ExcludeArchived: true,
+				Limit:           1000,
+			})
+			if err != nil {
+				slog.Warn("Could not list channels", "type", convType, "error", err)
+				break
+			}
+
+			for _, conv := range conversations {
+				if conv.Name == channelName {
+					slog.Debug("Found channel", "channel", channelName, "id", conv.ID, "type", convType)
+					return conv.ID, nil
+				}
+			}
+
+			if nextCursor == "" {
+				break
+			}
+			cursor = nextCursor
+		}
+	}
+
+	return "", fmt.Errorf("channel #%s not found", channelName)
+}
@@ -0,0 +1,93 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/slack-go/slack"
+)
+
+// Command is a slash command invocation received from Slack
+type Command struct {
+	Name        string // Command name (e.g., "/pr-report")
+	Text        string // Arguments typed after the command
+	ChannelID   string // Channel the command was used in
+	UserID      string // User who used the command
+	ResponseURL string // URL for delayed responses
+}
+
+// CommandFunc runs a slash command and returns the reply shown to the user
+type CommandFunc func(cmd Command) (string, error)
+
+// CommandOptions contains options for handling Slack slash commands
+type CommandOptions struct {
+	SigningSecret string // Slack app signing secret used to verify requests
+}
+
+// NewCommandHandler returns an HTTP handler for a slash command request URL.
+// Slack expects an answer within 3 seconds, so the command is acknowledged
+// right away and run in the background; its reply is sent to the response URL.
+func NewCommandHandler(opts CommandOptions, run CommandFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := verifyRequest(r, opts.SigningSecret)
+		if err != nil {
+			slog.Warn("Rejected slash command request", "error", err)
+			http.Error(w, "invalid request", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		s, err := slack.SlashCommandParse(r)
+		if err != nil {
+			http.Error(w, "invalid command", http.StatusBadRequest)
+			return
+		}
+
+		cmd := Command{
+			Name:        s.Command,
+			Text:        s.Text,
+			ChannelID:   s.ChannelID,
+			UserID:      s.UserID,
+			ResponseURL: s.ResponseURL,
+		}
+
+		go runCommand(cmd, run)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&slack.Msg{
+			ResponseType: slack.ResponseTypeEphemeral,
+			Text:         "⏳ Generating report...",
+		})
+	})
+}
+
+// runCommand runs a slash command and sends its reply to the response URL
+func runCommand(cmd Command, run CommandFunc) {
+	logger := slog.With("command", cmd.Name, "text", cmd.Text)
+	logger.Debug("Received slash command", "user", cmd.UserID, "channel", cmd.ChannelID)
+
+	reply, err := run(cmd)
+	if err != nil {
+		logger.Warn("Slash command failed", "error", err)
+		reply = "❌ " + err.Error()
+	}
+	if reply == "" || cmd.ResponseURL == "" {
+		return
+	}
+
+	err = postWebhookMessage(cmd.ResponseURL, &slack.WebhookMessage{
+		Text:         reply,
+		ResponseType: slack.ResponseTypeEphemeral,
+	})
+	if err != nil {
+		logger.Warn("Could not send slash command reply", "error", err)
+	}
+}
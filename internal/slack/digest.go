@@ -0,0 +1,115 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// Roles a user can have on a PR in their personal digest
+const (
+	RoleAuthor   = "author"
+	RoleAssignee = "assignee"
+	RoleReviewer = "reviewer"
+)
+
+// DigestPR is a PR listed in a user's personal digest
+type DigestPR struct {
+	PR    *PRInfo
+	Roles []string // Why the PR is listed (RoleAuthor, RoleAssignee, RoleReviewer)
+}
+
+// SendDigests sends each Slack user a direct message listing the PRs they
+// authored, are assigned to or were asked to review.
+// digests maps Slack user IDs to their PRs.
+func SendDigests(ctx context.Context, opts MessageOptions, digests map[string][]DigestPR) error {
+	messages := make(map[string]string, len(digests))
+	for userID, items := range digests {
+		messages[userID] = formatDigest(opts, items)
+	}
+	return sendDirectMessages(ctx, opts, "digest", messages)
+}
+
+// sendDirectMessages sends each Slack user their message. kind names the
+// messages in logs and errors (e.g., "digest"). messages maps Slack user IDs
+// to their text.
+func sendDirectMessages(ctx context.Context, opts MessageOptions, kind string, messages map[string]string) error {
+	if opts.Token == "" {
+		return fmt.Errorf("Slack token is required")
+	}
+
+	api := newClient(opts.APIURL, opts.Token)
+
+	// Send in a stable order so logs are easy to follow
+	userIDs := make([]string, 0, len(messages))
+	for userID := range messages {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	var failed []string
+	for _, userID := range userIDs {
+		channel, _, _, err := api.OpenConversationContext(ctx, &slack.OpenConversationParameters{
+			Users: []string{userID},
+		})
+		if err != nil {
+			slog.Warn("Could not open DM", "user", userID, "error", err)
+			failed = append(failed, userID)
+			continue
+		}
+
+		_, _, err = api.PostMessageContext(
+			ctx,
+			channel.ID,
+			append(postOptions(opts), slack.MsgOptionText(messages[userID], false))...,
+		)
+		if err != nil {
+			slog.Warn("Could not send direct message", "kind", kind, "user", userID, "error", err)
+			failed = append(failed, userID)
+			continue
+		}
+
+		slog.Debug("Sent direct message", "kind", kind, "user", userID)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not send %s to %d of %d user(s): %s", kind, len(failed), len(userIDs), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// formatDigest formats a user's personal PR digest
+func formatDigest(opts MessageOptions, items []DigestPR) string {
+	title := "Your open PRs"
+	if opts.ReportTitle != "" {
+		title = fmt.Sprintf("Your open PRs (%s)", opts.ReportTitle)
+	}
+
+	lines := []string{fmt.Sprintf("👋 *%s*", title), ""}
+	emoji := resolveEmoji(opts)
+
+	for i, item := range items {
+		pr := item.PR
+
+		status := pr.JiraStatus
+		if status == "" {
+			status = "Unknown"
+		}
+
+		line := fmt.Sprintf("%d. *%s* %s | %s _(%s)_", i+1, prLink(opts, pr), pr.Title, emoji.formatStatusChange(pr, status), strings.Join(item.Roles, ", "))
+		if pr.IsBlocked {
+			line += " " + emoji.Blocked
+		}
+		if pr.IsDraft {
+			line += " " + emoji.Draft
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
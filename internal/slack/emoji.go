@@ -0,0 +1,121 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Emoji holds the emoji used in reports. Empty fields keep the defaults, so
+// workspaces only need to configure what they want to change.
+type Emoji struct {
+	Title          string            // Before the report title (default: 📋)
+	Date           string            // Before the date (default: :date:)
+	Total          string            // Before the open PR total (default: :bar_chart:)
+	Blocked        string            // Before the blocked PR summary (default: 🚫)
+	Draft          string            // Before the draft PR summary (default: 📝)
+	NoBlockedDraft string            // When nothing is blocked or draft (default: ✅, or 📝 without UseCheckmark)
+	Snoozed        string            // Before the snoozed PR summary (default: 💤)
+	Attention      string            // Before PRs that need attention with the targeted mention policy (default: 🔔)
+	Changes        string            // Before the changes since the previous report (default: 🔄)
+	Stats          string            // Before the per-author stats table (default: 👤)
+	Turnaround     string            // Before the review turnaround line (default: ⏱️)
+	Weekly         string            // Before the weekly summary title (default: 🗓️)
+	Monthly        string            // Before the monthly retrospective title (default: 📆)
+	Anomaly        string            // Before the unusual backlog growth warning (default: ⚠️)
+	Age            string            // Before the open PRs by age range (default: 🕰️)
+	Labels         string            // Before the open PRs by label (default: 🏷️)
+	ReviewLoad     string            // Before the outstanding review requests per reviewer (default: 👀)
+	Merged         string            // Before the PRs merged during the week (default: ✅)
+	Pending        string            // Before the PRs pending for more than a week (default: ⏳)
+	Leaderboard    string            // Before the reviewer leaderboard (default: 🏆)
+	SLA            string            // Before review SLA breach alerts (default: ⏰)
+	MergeRate      string            // Before the merge rate line (default: 📈)
+	Sprint         string            // Before the sprint burn-down (default: 🏃)
+	SprintSummary  string            // Before the summary of the sprint that just closed (default: 🏁)
+	CycleTime      string            // Before the monthly cycle time (default: ⌛)
+	StatusChange   string            // After JIRA statuses that changed since the previous report (default: ⬆️)
+	DataIssues     string            // Before the data that couldn't be fetched for the report (default: ⚠️)
+	Ticket         string            // Before the heading of PRs sharing a ticket (default: 🎫)
+	Escalation     string            // Before the PRs open long enough to be escalated (default: 🚨)
+	Assignee       string            // Before the heading of each assignee's PRs (default: 👤)
+	Status         map[string]string // JIRA status name (case-insensitive) -> emoji shown before the status
+}
+
+// resolveEmoji fills in the default for every emoji that isn't configured
+func resolveEmoji(opts MessageOptions) Emoji {
+	e := opts.Emoji
+
+	setDefault := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+
+	noBlockedDraft := "✅"
+	if !opts.UseCheckmark {
+		noBlockedDraft = "📝"
+	}
+
+	setDefault(&e.Title, "📋")
+	setDefault(&e.Date, ":date:")
+	setDefault(&e.Total, ":bar_chart:")
+	setDefault(&e.Blocked, "🚫")
+	setDefault(&e.Draft, "📝")
+	setDefault(&e.NoBlockedDraft, noBlockedDraft)
+	setDefault(&e.Snoozed, "💤")
+	setDefault(&e.Attention, "🔔")
+	setDefault(&e.Changes, "🔄")
+	setDefault(&e.Stats, "👤")
+	setDefault(&e.Turnaround, "⏱️")
+	setDefault(&e.Weekly, "🗓️")
+	setDefault(&e.Monthly, "📆")
+	setDefault(&e.Anomaly, "⚠️")
+	setDefault(&e.Age, "🕰️")
+	setDefault(&e.Labels, "🏷️")
+	setDefault(&e.ReviewLoad, "👀")
+	setDefault(&e.Merged, "✅")
+	setDefault(&e.Pending, "⏳")
+	setDefault(&e.Leaderboard, "🏆")
+	setDefault(&e.SLA, "⏰")
+	setDefault(&e.MergeRate, "📈")
+	setDefault(&e.Sprint, "🏃")
+	setDefault(&e.SprintSummary, "🏁")
+	setDefault(&e.CycleTime, "⌛")
+	setDefault(&e.StatusChange, "⬆️")
+	setDefault(&e.DataIssues, "⚠️")
+	setDefault(&e.Ticket, "🎫")
+	setDefault(&e.Escalation, "🚨")
+	setDefault(&e.Assignee, "👤")
+
+	return e
+}
+
+// statusEmoji returns the emoji configured for a JIRA status, or ""
+func (e Emoji) statusEmoji(status string) string {
+	if emoji, exists := e.Status[status]; exists {
+		return emoji
+	}
+	for name, emoji := range e.Status {
+		if strings.EqualFold(name, status) {
+			return emoji
+		}
+	}
+	return ""
+}
+
+// formatStatus formats a JIRA status in bold, prefixed with its emoji if one is configured
+func (e Emoji) formatStatus(status string) string {
+	if emoji := e.statusEmoji(status); emoji != "" {
+		return emoji + " *" + status + "*"
+	}
+	return "*" + status + "*"
+}
+
+// formatStatusChange formats the JIRA status of a PR, preceded by its status
+// in the previous report when it changed since (e.g., "In Progress → *Blocked* ⬆️")
+func (e Emoji) formatStatusChange(pr *PRInfo, status string) string {
+	if pr.PreviousStatus == "" {
+		return e.formatStatus(status)
+	}
+	return fmt.Sprintf("%s → %s %s", pr.PreviousStatus, e.formatStatus(status), e.StatusChange)
+}
@@ -0,0 +1,72 @@
+package slack
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+// EventOptions contains options for handling Slack Events API requests
+type EventOptions struct {
+	SigningSecret string // Slack app signing secret used to verify requests
+	BotToken      string // Slack bot token used to reply
+	APIURL        string // Slack Web API URL, e.g. an egress proxy (default: https://slack.com/api/)
+}
+
+// NewEventHandler returns an HTTP handler for the Events API request URL. It
+// answers the URL verification challenge and replies to app mentions in a
+// thread under the mention, as in Socket Mode. Slack expects an answer within
+// 3 seconds, so mentions are acknowledged right away and answered in the
+// background.
+func NewEventHandler(opts EventOptions, onMention MentionFunc) http.Handler {
+	api := newClient(opts.APIURL, opts.BotToken)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := verifyRequest(r, opts.SigningSecret)
+		if err != nil {
+			slog.Warn("Rejected event request", "error", err)
+			http.Error(w, "invalid request", http.StatusUnauthorized)
+			return
+		}
+
+		// Requests are verified by their signature instead of the deprecated
+		// verification token
+		event, err := slackevents.ParseEvent(json.RawMessage(body), slackevents.OptionNoVerifyToken())
+		if err != nil {
+			slog.Warn("Could not parse event", "error", err)
+			http.Error(w, "invalid event", http.StatusBadRequest)
+			return
+		}
+
+		switch event.Type {
+		case slackevents.URLVerification:
+			var challenge slackevents.ChallengeResponse
+			if err := json.Unmarshal(body, &challenge); err != nil {
+				http.Error(w, "invalid challenge", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(challenge.Challenge))
+			return
+
+		case slackevents.CallbackEvent:
+			// Slack retries events it thinks went unanswered, but the first
+			// delivery is already being answered
+			if r.Header.Get("X-Slack-Retry-Num") != "" {
+				break
+			}
+			if mention, ok := event.InnerEvent.Data.(*slackevents.AppMentionEvent); ok {
+				go handleMention(api, mention, onMention)
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,52 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/export"
+	"pr-reporter/internal/model"
+)
+
+// uploadExport uploads the PR dataset as a file in the thread of the posted report
+func uploadExport(ctx context.Context, api *slack.Client, opts MessageOptions, channelID, threadTS string, prs []*PRInfo) error {
+	report := model.Report{
+		Title:       opts.ReportTitle,
+		GithubOwner: opts.GithubOwner,
+		GithubRepo:  opts.GithubRepo,
+		JiraURL:     opts.JiraURL,
+		Date:        time.Now(),
+		PRs:         prs,
+	}
+
+	data, err := export.Encode(report, opts.ExportFormat)
+	if err != nil {
+		return err
+	}
+	filename := export.Filename(report, opts.ExportFormat)
+
+	title := "Open PRs"
+	if opts.ReportTitle != "" {
+		title = opts.ReportTitle + " PRs"
+	}
+
+	_, err = api.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+		Reader:          bytes.NewReader(data),
+		FileSize:        len(data),
+		Filename:        filename,
+		Title:           title,
+		Channel:         channelID,
+		ThreadTimestamp: threadTS,
+	})
+	if err != nil {
+		return fmt.Errorf("error uploading %s: %w", filename, err)
+	}
+
+	slog.Debug("Uploaded PR export", "file", filename, "bytes", len(data), "thread", threadTS)
+
+	return nil
+}
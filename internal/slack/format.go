@@ -0,0 +1,942 @@
+package slack
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/state"
+)
+
+// reportContent holds the formatted lines of a report, split into sections so
+// they can be rendered either as plain text or as Block Kit blocks
+type reportContent struct {
+	header   []string // Title, date and totals
+	prLines  []string // One line per listed PR, in the same order as the PRs
+	headings []string // Heading of the assignee block starting at each PR line (empty: none)
+	footer   []string // Blocked/draft summary and mentions
+}
+
+// lines returns all report lines in order, each assignee heading set apart
+// from the previous block and kept with the first PR line under it
+func (c reportContent) lines() []string {
+	var lines []string
+	lines = append(lines, c.header...)
+	for i, line := range c.prLines {
+		if i < len(c.headings) && c.headings[i] != "" {
+			line = c.headings[i] + "\n" + line
+			if i > 0 {
+				line = "\n" + line
+			}
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, c.footer...)
+	return lines
+}
+
+// formatReport formats the report for the listed PRs. total is the number of
+// open PRs including snoozed ones, acks holds button actions keyed by PR number
+// and snoozed lists PRs that are hidden from the list until their snooze expires.
+// Sections defined in tmpl replace the built-in formatting. Ages and dates are
+// relative to opts.Date, which is the only input read from the clock when unset.
+func formatReport(opts MessageOptions, tmpl *template.Template, prs []*PRInfo, total int, acks map[int]*state.Ack, snoozed []*PRInfo) (reportContent, error) {
+	var content reportContent
+	emoji := resolveEmoji(opts)
+	text := model.LocaleStrings(opts.Locale)
+
+	// Format message with date and total on separate lines with emojis
+	reportDate := opts.Date
+	if reportDate.IsZero() {
+		reportDate = time.Now()
+	}
+	currentDate := reportDate.Format("2006-01-02")
+	dateText := fmt.Sprintf("%s *%s*", emoji.Date, currentDate)
+	if opts.LiveStatus {
+		// A live status message is edited all day, show when it was last refreshed
+		dateText = fmt.Sprintf("%s *%s %s*", emoji.Date, text.Updated, reportDate.Format("2006-01-02 15:04"))
+	}
+	if opts.Weekly != nil {
+		dateText = fmt.Sprintf("%s *%s: %s – %s*", emoji.Weekly, text.WeeklySummary,
+			opts.Weekly.Start.Format("2006-01-02"), opts.Weekly.End.Format("2006-01-02"))
+	}
+	if opts.Monthly != nil {
+		dateText = fmt.Sprintf("%s *%s: %s*", emoji.Monthly, text.MonthlyRetro, opts.Monthly.Start.Format("2006-01"))
+	}
+	if opts.PreviousReport != "" {
+		dateText += fmt.Sprintf(" · <%s|%s>", opts.PreviousReport, text.PreviousReport)
+	}
+	totalText := fmt.Sprintf("%s *%s: %d*", emoji.Total, text.TotalOpenPRs, total)
+
+	// Warn about unusual growth before anything else
+	if len(opts.Anomalies) > 0 {
+		content.header = append(content.header, anomalyLine(opts.Anomalies, emoji, text))
+		content.header = append(content.header, "") // Empty line for spacing
+	}
+
+	// Add report title if provided
+	if opts.ReportTitle != "" {
+		content.header = append(content.header, fmt.Sprintf("%s *%s*", emoji.Title, opts.ReportTitle))
+		content.header = append(content.header, "") // Empty line for spacing
+	}
+
+	content.header = append(content.header, dateText)
+	content.header = append(content.header, "") // Empty line for spacing
+	content.header = append(content.header, totalText)
+	var ageBuckets []model.AgeBucket
+	if opts.AgeBuckets {
+		ageBuckets = model.ComputeAgeBuckets(append(append([]*PRInfo{}, prs...), snoozed...), reportDate)
+		if line := ageBucketsLine(ageBuckets, emoji, text); line != "" {
+			content.header = append(content.header, line)
+		}
+	}
+	content.header = append(content.header, "") // Empty line for spacing
+
+	// Summarize the week after the totals
+	if opts.Weekly != nil {
+		content.header = append(content.header, weeklyLines(opts, emoji, text)...)
+		content.header = append(content.header, "") // Empty line for spacing
+	}
+	if opts.Monthly != nil {
+		content.header = append(content.header, monthlyLines(opts, emoji, text)...)
+		content.header = append(content.header, "") // Empty line for spacing
+	}
+
+	// Show movement since the previous report before the list itself
+	if !opts.Changes.Empty() {
+		content.header = append(content.header, changesLines(opts, emoji, text)...)
+		content.header = append(content.header, "") // Empty line for spacing
+	}
+
+	// Track blocked/draft PRs for summary at the end
+	var blockedPRs []string
+	var draftPRs []string
+
+	for i, pr := range prs {
+		statusPart := pr.JiraStatus
+		if statusPart == "" {
+			statusPart = text.UnknownStatus
+		}
+
+		// Track blocked and draft PRs for end summary with links
+		if pr.IsBlocked && pr.IsDraft {
+			blockedPRs = append(blockedPRs, fmt.Sprintf("%s (%s)", prLink(opts, pr), text.BlockedAndDraft))
+		} else if pr.IsBlocked {
+			blockedPRs = append(blockedPRs, prLink(opts, pr))
+		} else if pr.IsDraft {
+			draftPRs = append(draftPRs, prLink(opts, pr))
+		}
+
+		// Format assignee
+		assigneeText := pr.Assignee
+		if assigneeText == "" {
+			assigneeText = text.Unassigned
+		}
+
+		// Format JIRA ticket link
+		jiraLink := pr.JiraTicket
+		if url := ticketURL(opts, pr); url != "" {
+			jiraLink = fmt.Sprintf("<%s|%s>", url, pr.JiraTicket)
+		} else if pr.JiraTicket == "" {
+			jiraLink = "N/A"
+		}
+
+		// Format description
+		description := pr.Description
+		if description == "" {
+			description = text.NoDescription
+		}
+
+		// Format the PR line
+		var prLine string
+		if opts.ThreadDetail {
+			// Compact line, full details are posted in the thread
+			prLine = fmt.Sprintf("%d. *%s* | Jira: %s | %s",
+				i+1,
+				prLink(opts, pr),
+				jiraLink,
+				emoji.formatStatusChange(pr, statusPart))
+		} else if opts.ShowAssignee {
+			prLine = fmt.Sprintf("%d. *%s* %s %s | Jira: %s | %s | %s",
+				i+1,
+				prLink(opts, pr),
+				text.AssignedTo,
+				assigneeText,
+				jiraLink,
+				description,
+				emoji.formatStatusChange(pr, statusPart))
+		} else {
+			prLine = fmt.Sprintf("%d. *%s* | Jira: %s | %s | %s",
+				i+1,
+				prLink(opts, pr),
+				jiraLink,
+				description,
+				emoji.formatStatusChange(pr, statusPart))
+		}
+
+		// Reflect button actions taken on the previous report
+		if ack, exists := acks[pr.Number]; exists {
+			prLine += ackNote(ack)
+		}
+		if note := stillBlocked(text, pr, reportDate); note != "" {
+			prLine += fmt.Sprintf(" – _%s_", note)
+		}
+		if pr.SuggestedReviewer != "" {
+			prLine += fmt.Sprintf(" – %s: %s", text.Suggested, pr.SuggestedReviewer)
+		}
+
+		// Head the PRs sharing a ticket with the ticket, once
+		if opts.GroupByTicket && startsTicketGroup(prs, i) {
+			prLine = fmt.Sprintf("%s *%s*: %s | %s\n%s", emoji.Ticket, jiraLink, description, emoji.formatStatus(statusPart), prLine)
+		}
+
+		content.prLines = append(content.prLines, prLine)
+
+		// Head each assignee's PRs with the assignee's name, without mentioning them again
+		if opts.GroupByAssignee {
+			heading := ""
+			if i == 0 || prs[i-1].GithubAssignee != pr.GithubAssignee {
+				name := pr.GithubAssignee
+				if name == "" {
+					name = text.Unassigned
+				}
+				heading = fmt.Sprintf("%s *%s* (%d)", emoji.Assignee, name, assigneeBlockSize(prs, i))
+			}
+			content.headings = append(content.headings, heading)
+		}
+	}
+
+	// Summaries only keep the totals and the blocked/draft summary
+	if opts.Verbosity == VerbositySummary {
+		content.prLines = nil
+		content.headings = nil
+	}
+
+	// Add blocked/draft summary at the end
+	content.footer = append(content.footer, "")
+
+	if len(blockedPRs) > 0 || len(draftPRs) > 0 {
+		if len(blockedPRs) > 0 {
+			content.footer = append(content.footer, fmt.Sprintf("%s *%s:* %s", emoji.Blocked, text.Blocked, strings.Join(blockedPRs, ", ")))
+		}
+		if len(draftPRs) > 0 {
+			content.footer = append(content.footer, fmt.Sprintf("%s *%s:* %s", emoji.Draft, text.Draft, strings.Join(draftPRs, ", ")))
+		}
+	} else {
+		content.footer = append(content.footer, fmt.Sprintf("%s *%s:* %s", emoji.NoBlockedDraft, text.BlockedOrDraft, text.None))
+	}
+
+	// List snoozed PRs so they aren't forgotten entirely
+	if len(snoozed) > 0 {
+		var snoozedLinks []string
+		for _, pr := range snoozed {
+			snoozedLinks = append(snoozedLinks, prLink(opts, pr))
+		}
+		content.footer = append(content.footer, fmt.Sprintf("%s *%s:* %s", emoji.Snoozed, text.Snoozed, strings.Join(snoozedLinks, ", ")))
+	}
+
+	// Average review turnaround
+	if opts.Turnaround != nil && opts.Turnaround.Reviewed > 0 {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, turnaroundLine(*opts.Turnaround, emoji, text))
+	}
+
+	// Merged vs. opened PRs per day
+	if opts.MergeRate != nil {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, mergeRateLines(*opts.MergeRate, emoji, text)...)
+	}
+
+	// Previous month's cycle time
+	if opts.CycleTime != nil {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, cycleTimeLine(*opts.CycleTime, emoji, text))
+	}
+
+	// Sprint tickets by PR progress
+	if opts.Sprint != nil && opts.Sprint.Total > 0 {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, sprintLines(*opts.Sprint, emoji, text)...)
+	}
+
+	// Open PRs of tracked labels, such as the hotfix load
+	if opts.Labels != nil && len(opts.Labels.Labels) > 0 {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, labelsLine(*opts.Labels, emoji, text))
+	}
+
+	// Stories and PRs of the sprint that just closed
+	if opts.SprintSummary != nil {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, sprintSummaryLines(opts, emoji, text)...)
+	}
+
+	// Per-author stats as a fixed-width table
+	if len(opts.AuthorStats) > 0 {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, fmt.Sprintf("%s *%s*", emoji.Stats, text.AuthorStats))
+		content.footer = append(content.footer, authorStatsTable(opts.AuthorStats, text))
+	}
+
+	// Reviewers ranked by PRs reviewed
+	if opts.Leaderboard != nil && len(opts.Leaderboard.Reviewers) > 0 {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, leaderboardLines(*opts.Leaderboard, emoji, text, reportDate)...)
+	}
+
+	// Reviewers with the most outstanding review requests
+	if len(opts.ReviewLoad) > 0 {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, reviewLoadLine(opts.ReviewLoad, emoji, text))
+	}
+
+	// Say what couldn't be fetched rather than leaving readers to guess why
+	// statuses are unknown
+	if len(opts.DataIssues) > 0 {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, fmt.Sprintf("%s *%s:*", emoji.DataIssues, text.DataIssues))
+		for _, issue := range opts.DataIssues {
+			content.footer = append(content.footer, "• "+issue)
+		}
+	}
+
+	// Ping the team, or only the people responsible for PRs that need attention
+	var mention string
+	switch opts.MentionPolicy {
+	case MentionPolicyNone:
+	case MentionPolicyTargeted:
+		lines, mentions := targetedMentions(opts, emoji, text, prs, reportDate)
+		if len(lines) > 0 {
+			content.footer = append(content.footer, "")
+			content.footer = append(content.footer, lines...)
+		}
+		mention = strings.Join(mentions, " ")
+	default:
+		// Add team mention or individual user mentions if provided
+		mention = mentionText(opts)
+		if mention != "" {
+			content.footer = append(content.footer, "")
+			content.footer = append(content.footer, fmt.Sprintf("%s %s", mention, text.CallToAction))
+		}
+	}
+
+	// Escalate PRs open for too long to the leads, whatever the mention policy
+	escalation := escalationLine(opts, emoji, text, prs, reportDate)
+	if escalation != "" {
+		content.footer = append(content.footer, "")
+		content.footer = append(content.footer, escalation)
+	}
+
+	// Replace sections defined by custom templates
+	if tmpl != nil {
+		data := TemplateData{
+			Title:       opts.ReportTitle,
+			Date:        currentDate,
+			Previous:    opts.PreviousReport,
+			Total:       total,
+			AgeBuckets:  ageBuckets,
+			Anomalies:   opts.Anomalies,
+			Changes:     opts.Changes,
+			AuthorStats: opts.AuthorStats,
+			Turnaround:  opts.Turnaround,
+			MergeRate:   opts.MergeRate,
+			CycleTime:   opts.CycleTime,
+			Sprint:      opts.Sprint,
+			LastSprint:  opts.SprintSummary,
+			Weekly:      opts.Weekly,
+			Monthly:     opts.Monthly,
+			Leaderboard: opts.Leaderboard,
+			Labels:      opts.Labels,
+			ReviewLoad:  opts.ReviewLoad,
+			DataIssues:  opts.DataIssues,
+			Mention:     mention,
+			Escalation:  escalation,
+			Text:        text,
+		}
+		for i, pr := range prs {
+			prData := newTemplatePR(opts, emoji, text, i+1, pr, acks[pr.Number], reportDate)
+			data.PRs = append(data.PRs, prData)
+			if pr.IsBlocked {
+				data.Blocked = append(data.Blocked, prData)
+			} else if pr.IsDraft {
+				data.Drafts = append(data.Drafts, prData)
+			}
+		}
+		for _, pr := range snoozed {
+			data.Snoozed = append(data.Snoozed, newTemplatePR(opts, emoji, text, 0, pr, nil, reportDate))
+		}
+
+		if lines, defined, err := executeTemplate(tmpl, "header", data); err != nil {
+			return content, err
+		} else if defined {
+			content.header = lines
+		}
+
+		for i, prData := range data.PRs {
+			if i >= len(content.prLines) {
+				break
+			}
+			if lines, defined, err := executeTemplate(tmpl, "pr", prData); err != nil {
+				return content, err
+			} else if defined {
+				content.prLines[i] = strings.Join(lines, "\n")
+			}
+		}
+
+		if lines, defined, err := executeTemplate(tmpl, "footer", data); err != nil {
+			return content, err
+		} else if defined {
+			content.footer = lines
+		}
+	}
+
+	return content, nil
+}
+
+// changesLines formats the PRs opened, closed and transitioned since the
+// previous report
+func changesLines(opts MessageOptions, emoji Emoji, text model.Strings) []string {
+	changes := opts.Changes
+	lines := []string{fmt.Sprintf("%s *%s* (%s)", emoji.Changes, text.Changes, changes.Since.Format("2006-01-02 15:04"))}
+
+	if len(changes.Opened) > 0 {
+		lines = append(lines, fmt.Sprintf("• *%s:* %s", text.Opened, prLinks(opts, changes.Opened)))
+	}
+	if len(changes.Closed) > 0 {
+		lines = append(lines, fmt.Sprintf("• *%s:* %s", text.Closed, prLinks(opts, changes.Closed)))
+	}
+	if len(changes.Transitions) > 0 {
+		// One entry per PR, with all of its transitions
+		var transitions []string
+		for i, transition := range changes.Transitions {
+			change := fmt.Sprintf("%s → %s", transition.From, transition.To)
+			if i > 0 && changes.Transitions[i-1].PR.Number == transition.PR.Number {
+				transitions[len(transitions)-1] += ", " + change
+				continue
+			}
+			transitions = append(transitions, prLink(opts, transition.PR)+" "+change)
+		}
+		lines = append(lines, fmt.Sprintf("• *%s:* %s", text.Transitions, strings.Join(transitions, "; ")))
+	}
+
+	return lines
+}
+
+// weeklyLines formats the merges, pending PRs and blocked tickets of the week
+func weeklyLines(opts MessageOptions, emoji Emoji, text model.Strings) []string {
+	weekly := opts.Weekly
+
+	merged := fmt.Sprintf("%s *%s: %d*", emoji.Merged, text.Merged, len(weekly.Merged))
+	if weekly.CycleTime > 0 {
+		merged += fmt.Sprintf(" · %s %s", text.CycleTime, formatWait(weekly.CycleTime))
+	}
+	if len(weekly.Merged) > 0 {
+		merged += " – " + prLinks(opts, weekly.Merged)
+	}
+
+	pending := fmt.Sprintf("%s *%s: %d*", emoji.Pending, text.Pending, len(weekly.Pending))
+	if len(weekly.Pending) > 0 {
+		pending += " – " + prLinks(opts, weekly.Pending)
+	}
+
+	return []string{
+		merged,
+		pending,
+		fmt.Sprintf("%s *%s: %d*", emoji.Blocked, text.BlockedTickets, weekly.BlockedTickets),
+	}
+}
+
+// anomalyLine formats the counts that grew unusually with their trailing
+// averages on one line
+func anomalyLine(anomalies []model.Anomaly, emoji Emoji, text model.Strings) string {
+	var counts []string
+	for _, anomaly := range anomalies {
+		metric := text.OpenCount
+		if anomaly.Metric == model.MetricBlocked {
+			metric = text.BlockedCount
+		}
+		counts = append(counts, fmt.Sprintf("%d %s (%dd %s %.1f, %+.0f%%)",
+			anomaly.Current, metric, model.AnomalyDays, text.Avg, anomaly.Average, anomaly.Increase()*100))
+	}
+	return fmt.Sprintf("%s *%s:* %s", emoji.Anomaly, text.UnusualGrowth, strings.Join(counts, " · "))
+}
+
+// ageBucketsLine formats the non-empty age ranges of the open PRs on one
+// line, or returns "" when no PR has a creation time
+func ageBucketsLine(buckets []model.AgeBucket, emoji Emoji, text model.Strings) string {
+	var counts []string
+	for _, bucket := range buckets {
+		if bucket.Count > 0 {
+			counts = append(counts, fmt.Sprintf("%s: %d", bucket.Label, bucket.Count))
+		}
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s *%s:* %s", emoji.Age, text.Age, strings.Join(counts, ", "))
+}
+
+// monthlyLines formats the totals, average age, top blockers and longest
+// open PR of the month
+func monthlyLines(opts MessageOptions, emoji Emoji, text model.Strings) []string {
+	monthly := opts.Monthly
+
+	lines := []string{
+		fmt.Sprintf("%s *%s: %d* · *%s: %d*", emoji.Merged, text.OpenedPRs, monthly.Opened, text.Merged, monthly.Merged),
+		fmt.Sprintf("%s *%s: %s*", emoji.Pending, text.OpenAge, formatDays(monthly.AverageAge)),
+	}
+
+	if len(monthly.Blockers) > 0 {
+		var blockers []string
+		for _, pr := range monthly.Blockers {
+			blockers = append(blockers, fmt.Sprintf("%s (%s)", prLink(opts, pr), formatDays(monthly.End.Sub(pr.CreatedAt))))
+		}
+		lines = append(lines, fmt.Sprintf("%s *%s:* %s", emoji.Blocked, text.TopBlockers, strings.Join(blockers, ", ")))
+	}
+	if pr := monthly.LongestOpen; pr != nil {
+		lines = append(lines, fmt.Sprintf("%s *%s:* %s %s (%s)", emoji.Total, text.LongestOpen, prLink(opts, pr), pr.Title,
+			formatDays(monthly.End.Sub(pr.CreatedAt))))
+	}
+
+	return lines
+}
+
+// leaderboardSize is how many reviewers the leaderboard shows
+const leaderboardSize = 10
+
+// leaderboardMedals are shown before the first three reviewers
+var leaderboardMedals = []string{"🥇", "🥈", "🥉"}
+
+// leaderboardLines formats the top reviewers with their review counts on one
+// line, up to now. Reviewers are shown by GitHub username so nobody gets pinged.
+func leaderboardLines(leaderboard model.Leaderboard, emoji Emoji, text model.Strings, now time.Time) []string {
+	var ranking []string
+	for i, reviewer := range leaderboard.Reviewers {
+		if i == leaderboardSize {
+			break
+		}
+		rank := fmt.Sprintf("%d.", i+1)
+		if i < len(leaderboardMedals) {
+			rank = leaderboardMedals[i]
+		}
+		ranking = append(ranking, fmt.Sprintf("%s %s (%d)", rank, reviewer.Reviewer, reviewer.Reviews))
+	}
+
+	return []string{
+		fmt.Sprintf("%s *%s* (%s – %s)", emoji.Leaderboard, text.Leaderboard, leaderboard.Since.Format("2006-01-02"), now.Format("2006-01-02")),
+		strings.Join(ranking, " · "),
+	}
+}
+
+// labelsLine formats the open PRs of each tracked label on one line, with the
+// change since the compared report when there is one
+func labelsLine(breakdown model.LabelBreakdown, emoji Emoji, text model.Strings) string {
+	var counts []string
+	for _, label := range breakdown.Labels {
+		count := fmt.Sprintf("%s %d", label.Label, label.Open)
+		if !breakdown.Compared.IsZero() {
+			count += fmt.Sprintf(" (%+d)", label.Change())
+		}
+		counts = append(counts, count)
+	}
+
+	title := fmt.Sprintf("%s *%s*", emoji.Labels, text.ByLabel)
+	if !breakdown.Compared.IsZero() {
+		title += fmt.Sprintf(" (%s %s)", text.ComparedWith, breakdown.Compared.Format("2006-01-02"))
+	}
+	return title + ": " + strings.Join(counts, " · ")
+}
+
+// sprintSummaryLines formats the stories done, PRs per story and carryover
+// PRs of the sprint that just closed
+func sprintSummaryLines(opts MessageOptions, emoji Emoji, text model.Strings) []string {
+	summary := opts.SprintSummary
+
+	title := fmt.Sprintf("%s *%s %s*", emoji.SprintSummary, summary.Name, text.SprintClosed)
+	if !summary.Start.IsZero() {
+		title += fmt.Sprintf(" (%s – %s)", summary.Start.Format("2006-01-02"), summary.Completed.Format("2006-01-02"))
+	}
+
+	stats := fmt.Sprintf("%d/%d %s · %s %s · %d %s", summary.Done, summary.Stories, text.StoriesDone,
+		strconv.FormatFloat(summary.PRsPerStory(), 'f', 1, 64), text.PRsPerStory, len(summary.Carryover), text.CarryoverPRs)
+	if len(summary.Carryover) > 0 {
+		stats += " – " + prLinks(opts, summary.Carryover)
+	}
+
+	return []string{title, stats}
+}
+
+// reviewLoadLine formats the reviewers with the most outstanding review
+// requests on one line. Reviewers are shown by GitHub username so nobody gets
+// pinged.
+func reviewLoadLine(load []model.ReviewLoad, emoji Emoji, text model.Strings) string {
+	var reviewers []string
+	for i, reviewer := range load {
+		if i == leaderboardSize {
+			break
+		}
+		reviewers = append(reviewers, fmt.Sprintf("%s (%d)", reviewer.Reviewer, reviewer.Pending))
+	}
+	return fmt.Sprintf("%s *%s:* %s", emoji.ReviewLoad, text.ReviewLoad, strings.Join(reviewers, " · "))
+}
+
+// authorStatsTable formats per-author stats as a code block with aligned
+// columns
+func authorStatsTable(stats []model.AuthorStats, text model.Strings) string {
+	rows := [][]string{{text.Author, text.Open, text.AverageAge, text.Oldest, text.ClosedRecently}}
+	for _, s := range stats {
+		averageAge, oldest := "-", "-"
+		if s.Open > 0 && s.AverageAge > 0 {
+			averageAge = formatDays(s.AverageAge)
+		}
+		if s.Oldest != nil {
+			oldest = fmt.Sprintf("#%d", s.Oldest.Number)
+			if !s.Oldest.CreatedAt.IsZero() {
+				oldest += fmt.Sprintf(" (%s)", formatDays(time.Since(s.Oldest.CreatedAt)))
+			}
+		}
+		rows = append(rows, []string{s.Author, strconv.Itoa(s.Open), averageAge, oldest, strconv.Itoa(s.Closed)})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	lines := []string{"```"}
+	for _, row := range rows {
+		var cells []string
+		for i, cell := range row {
+			cells = append(cells, cell+strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+	lines = append(lines, "```")
+
+	return strings.Join(lines, "\n")
+}
+
+// turnaroundLine formats the average review turnaround with the number of
+// PRs behind each average
+func turnaroundLine(t model.Turnaround, emoji Emoji, text model.Strings) string {
+	line := fmt.Sprintf("%s *%s:* %s %s (%d)", emoji.Turnaround, text.ReviewTurnaround, text.FirstReview, formatWait(t.FirstReview), t.Reviewed)
+	if t.Approved > 0 {
+		line += fmt.Sprintf(" · %s %s (%d)", text.Approval, formatWait(t.Approval), t.Approved)
+	}
+	return line
+}
+
+// mergeRateLines formats the merge rate against the open rate, whether the
+// queue is draining or growing and the PRs merged each day
+func mergeRateLines(rate model.MergeRate, emoji Emoji, text model.Strings) []string {
+	trend := text.QueueSteady
+	switch merged, opened := rate.Merged(), rate.Opened; {
+	case merged > opened:
+		trend = text.QueueDraining
+	case merged < opened:
+		trend = text.QueueGrowing
+	}
+
+	var days []string
+	for _, count := range rate.MergedPerDay {
+		days = append(days, strconv.Itoa(count))
+	}
+
+	return []string{
+		fmt.Sprintf("%s *%s:* %.1f %s · %.1f %s · %s", emoji.MergeRate, text.MergeRate, rate.MergedRate(), text.MergedPerDay, rate.OpenedRate(), text.OpenedPerDay, trend),
+		fmt.Sprintf("%s: %s", text.MergedByDay, strings.Join(days, " ")),
+	}
+}
+
+// cycleTimeLine formats the cycle time percentiles of a month with the
+// median of the month before
+func cycleTimeLine(c model.CycleTime, emoji Emoji, text model.Strings) string {
+	line := fmt.Sprintf("%s *%s %s:* %s %s · p75 %s · p90 %s (%d %s)", emoji.CycleTime, text.MonthlyCycleTime, c.Month.Format("2006-01"),
+		text.Median, formatWait(c.Median), formatWait(c.P75), formatWait(c.P90), c.Merged, text.MergedPRs)
+	if c.Previous != nil {
+		line += fmt.Sprintf(" · %s %s: %s", c.Previous.Month.Format("2006-01"), text.Median, formatWait(c.Previous.Median))
+	}
+	return line
+}
+
+// sprintLines formats the sprint burn-down: its tickets done, in review and
+// without PRs, and the open PRs of tickets outside the sprint
+func sprintLines(sprint model.SprintBurndown, emoji Emoji, text model.Strings) []string {
+	title := fmt.Sprintf("%s *%s: %s*", emoji.Sprint, text.SprintBurndown, sprint.Name)
+	if !sprint.End.IsZero() {
+		title += fmt.Sprintf(" (%s %s)", text.SprintEnds, sprint.End.Format("2006-01-02"))
+	}
+
+	counts := fmt.Sprintf("%d %s: %d %s · %d %s · %d %s", sprint.Total, text.SprintTickets,
+		sprint.Done, text.SprintDone, sprint.InReview, text.SprintInReview, sprint.NoPR, text.SprintNoPR)
+	if sprint.OutsideSprint > 0 {
+		counts += fmt.Sprintf(" · %d %s", sprint.OutsideSprint, text.OutsideSprint)
+	}
+
+	return []string{title, counts}
+}
+
+// formatWait formats a waiting time in minutes, hours or days depending on
+// its length (e.g., "45m", "5.2h", "3.1d")
+func formatWait(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return strconv.FormatFloat(d.Hours(), 'f', 1, 64) + "h"
+	default:
+		return formatDays(d)
+	}
+}
+
+// formatDays formats a duration in days with one decimal (e.g., "4.5d")
+func formatDays(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours()/24, 'f', 1, 64) + "d"
+}
+
+// mentionText returns the configured user or team group mentions, or "" if none
+func mentionText(opts MessageOptions) string {
+	if opts.MentionUsers != "" {
+		// Mention specific users (comma-separated user IDs)
+		var mentions []string
+		for _, userID := range strings.Split(opts.MentionUsers, ",") {
+			userID = strings.TrimSpace(userID)
+			if userID != "" {
+				mentions = append(mentions, fmt.Sprintf("<@%s>", userID))
+			}
+		}
+		return strings.Join(mentions, " ")
+	} else if opts.TeamGroup != "" {
+		// Mention team group
+		return fmt.Sprintf("<!subteam^%s>", opts.TeamGroup)
+	}
+	return ""
+}
+
+// escalationLine lists the PRs open for longer than opts.EscalateAfter and
+// mentions opts.EscalateGroup, or returns "" when none is. Drafts aren't
+// escalated.
+func escalationLine(opts MessageOptions, emoji Emoji, text model.Strings, prs []*PRInfo, now time.Time) string {
+	if opts.EscalateAfter <= 0 || opts.EscalateGroup == "" {
+		return ""
+	}
+
+	var links []string
+	for _, pr := range prs {
+		if !pr.IsDraft && !pr.CreatedAt.IsZero() && now.Sub(pr.CreatedAt) > opts.EscalateAfter {
+			links = append(links, prLink(opts, pr))
+		}
+	}
+	if len(links) == 0 {
+		return ""
+	}
+
+	days := int(opts.EscalateAfter.Hours() / 24)
+	return fmt.Sprintf("%s *%s %dd:* %s <!subteam^%s>", emoji.Escalation, text.OpenLongerThan, days, strings.Join(links, ", "), opts.EscalateGroup)
+}
+
+// ackNote formats a button action for display at the end of a PR line
+func ackNote(ack *state.Ack) string {
+	switch ack.Action {
+	case state.ActionReviewing:
+		return fmt.Sprintf(" 👀 _<@%s> is reviewing_", ack.UserID)
+	case state.ActionNotMine:
+		return fmt.Sprintf(" 🙅 _not mine (<@%s>)_", ack.UserID)
+	default:
+		return ""
+	}
+}
+
+// stillBlocked notes how long a PR has been blocked once its assignee isn't
+// mentioned anymore (e.g., "still blocked (6d)"), or returns ""
+func stillBlocked(text model.Strings, pr *PRInfo, now time.Time) string {
+	if !pr.MentionsMuted {
+		return ""
+	}
+	return fmt.Sprintf("%s (%dd)", text.StillBlocked, int(now.Sub(pr.BlockedSince).Hours()/24))
+}
+
+// startsTicketGroup reports whether prs[i] is the first of several PRs in a
+// row referencing the same ticket
+func startsTicketGroup(prs []*PRInfo, i int) bool {
+	ticket := prs[i].JiraTicket
+	if ticket == "" || i > 0 && prs[i-1].JiraTicket == ticket {
+		return false
+	}
+	return i+1 < len(prs) && prs[i+1].JiraTicket == ticket
+}
+
+// assigneeBlockSize returns the number of PRs in a row from prs[i] with its
+// assignee
+func assigneeBlockSize(prs []*PRInfo, i int) int {
+	size := 1
+	for i+size < len(prs) && prs[i+size].GithubAssignee == prs[i].GithubAssignee {
+		size++
+	}
+	return size
+}
+
+// prURL returns the web URL of a PR, defaulting to its GitHub URL in the
+// configured repository
+func prURL(opts MessageOptions, pr *PRInfo) string {
+	if pr.URL != "" {
+		return pr.URL
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/pull/%d", opts.GithubOwner, opts.GithubRepo, pr.Number)
+}
+
+// ticketURL returns the web URL of a PR's ticket, or "" when it can't be linked
+func ticketURL(opts MessageOptions, pr *PRInfo) string {
+	if pr.TicketURL != "" {
+		return pr.TicketURL
+	}
+	if pr.JiraTicket == "" || opts.JiraURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/browse/%s", opts.JiraURL, pr.JiraTicket)
+}
+
+// prLink formats a Slack link to a PR
+func prLink(opts MessageOptions, pr *PRInfo) string {
+	return fmt.Sprintf("<%s|PR-%d>", prURL(opts, pr), pr.Number)
+}
+
+// prLinks formats comma-separated Slack links to PRs
+func prLinks(opts MessageOptions, prs []*PRInfo) string {
+	var links []string
+	for _, pr := range prs {
+		links = append(links, prLink(opts, pr))
+	}
+	return strings.Join(links, ", ")
+}
+
+// formatPRDetails formats the full details of a single PR for a thread reply
+func formatPRDetails(opts MessageOptions, pr *PRInfo) string {
+	var lines []string
+
+	lines = append(lines, fmt.Sprintf("*%s* %s", prLink(opts, pr), pr.Title))
+
+	if opts.ShowAssignee {
+		assigneeText := pr.Assignee
+		if assigneeText == "" {
+			assigneeText = "unassigned"
+		}
+		lines = append(lines, fmt.Sprintf("• *Assignee:* %s", assigneeText))
+	}
+
+	if pr.SuggestedReviewer != "" {
+		lines = append(lines, fmt.Sprintf("• *Suggested reviewer:* %s", pr.SuggestedReviewer))
+	}
+
+	if pr.Author != "" {
+		lines = append(lines, fmt.Sprintf("• *Author:* %s", pr.Author))
+	}
+
+	if pr.JiraTicket != "" {
+		jiraLink := pr.JiraTicket
+		if url := ticketURL(opts, pr); url != "" {
+			jiraLink = fmt.Sprintf("<%s|%s>", url, pr.JiraTicket)
+		}
+		status := pr.JiraStatus
+		if status == "" {
+			status = "Unknown"
+		}
+		if statusEmoji := resolveEmoji(opts).statusEmoji(status); statusEmoji != "" {
+			status = statusEmoji + " " + status
+		}
+		lines = append(lines, fmt.Sprintf("• *Jira:* %s (%s)", jiraLink, status))
+	} else {
+		lines = append(lines, "• *Jira:* N/A")
+	}
+
+	description := pr.Description
+	if description == "" {
+		description = "No description"
+	}
+	lines = append(lines, fmt.Sprintf("• *Description:* %s", description))
+
+	if len(pr.Labels) > 0 {
+		lines = append(lines, fmt.Sprintf("• *Labels:* %s", strings.Join(pr.Labels, ", ")))
+	}
+
+	if len(pr.Reviewers) > 0 {
+		lines = append(lines, fmt.Sprintf("• *Reviewers:* %s", strings.Join(pr.Reviewers, ", ")))
+	} else {
+		lines = append(lines, "• *Reviewers:* none")
+	}
+
+	switch pr.ChecksState {
+	case "success":
+		lines = append(lines, "• *Checks:* ✅ passing")
+	case "failure":
+		lines = append(lines, "• *Checks:* ❌ failing")
+	case "pending":
+		lines = append(lines, "• *Checks:* ⏳ pending")
+	}
+
+	if pr.IsBlocked {
+		lines = append(lines, resolveEmoji(opts).Blocked+" Blocked")
+	}
+	if pr.IsDraft {
+		lines = append(lines, resolveEmoji(opts).Draft+" Draft")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// splitMessage joins lines into messages of at most maxLength characters,
+// breaking only between lines. A single line longer than maxLength is cut
+// into several pieces so that no part exceeds the limit.
+func splitMessage(lines []string, maxLength int) []string {
+	var parts []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, line := range lines {
+		// Hard-split lines that cannot fit in a message on their own
+		for len(line) > maxLength {
+			flush()
+			cut := maxLength
+			// Avoid cutting in the middle of a multi-byte character
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			parts = append(parts, line[:cut])
+			line = line[cut:]
+		}
+
+		needed := len(line)
+		if current.Len() > 0 {
+			needed++ // newline separator
+		}
+		if current.Len()+needed > maxLength {
+			flush()
+		}
+
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(line)
+	}
+	flush()
+
+	if len(parts) == 0 {
+		parts = append(parts, "")
+	}
+
+	return parts
+}
@@ -0,0 +1,245 @@
+package slack
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden with the current output")
+
+// reportDate is the date every golden report is rendered for
+var reportDate = time.Date(2024, time.May, 6, 9, 0, 0, 0, time.UTC)
+
+// goldenOptions returns the options of a typical team report
+func goldenOptions() MessageOptions {
+	return MessageOptions{
+		GithubOwner:  "acme",
+		GithubRepo:   "fips-web-client",
+		JiraURL:      "https://acme.atlassian.net",
+		TeamGroup:    "S0WEBTEAM",
+		ReportTitle:  "Frontend Report",
+		ShowAssignee: true,
+		UseCheckmark: true,
+		Date:         reportDate,
+	}
+}
+
+// manyPRs returns n open PRs cycling through the JIRA statuses
+func manyPRs(n int) []*PRInfo {
+	statuses := []string{"In Progress", "In Review", "Ready for QA", "Blocked"}
+	prs := make([]*PRInfo, n)
+	for i := range prs {
+		status := statuses[i%len(statuses)]
+		prs[i] = &PRInfo{
+			Number:      1000 + i,
+			Title:       fmt.Sprintf("POKER-%d: Lobby change %d", 2000+i, i),
+			Assignee:    fmt.Sprintf("<@U0DEV%02d>", i%7),
+			JiraTicket:  fmt.Sprintf("POKER-%d", 2000+i),
+			JiraStatus:  status,
+			Description: fmt.Sprintf("Lobby change %d", i),
+			IsDraft:     i%9 == 0,
+			IsBlocked:   status == "Blocked",
+			UpdatedAt:   reportDate.Add(-time.Duration(i) * time.Hour),
+		}
+	}
+	return prs
+}
+
+func TestFormatMessagesGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		opts func(opts *MessageOptions)
+		prs  []*PRInfo
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "blocked_only",
+			opts: func(opts *MessageOptions) { opts.MentionPolicy = MentionPolicyTargeted },
+			prs: []*PRInfo{
+				{
+					Number:           412,
+					Assignee:         "<@U0ALICE>",
+					JiraTicket:       "POKER-412",
+					JiraStatus:       "Blocked",
+					Description:      "Filter lobby tables by stake",
+					IsBlocked:        true,
+					ReviewCount:      1,
+					UpdatedAt:        reportDate.Add(-2 * time.Hour),
+					ReviewerMentions: []string{"<@U0BOB>"},
+				},
+				{
+					Number:        415,
+					Assignee:      "<@U0CAROL>",
+					JiraTicket:    "POKER-415",
+					JiraStatus:    "Blocked",
+					Description:   "Table chat moderation",
+					IsBlocked:     true,
+					IsDraft:       true,
+					UpdatedAt:     reportDate.Add(-5 * 24 * time.Hour),
+					BlockedSince:  reportDate.Add(-8 * 24 * time.Hour),
+					MentionsMuted: true,
+				},
+			},
+		},
+		{
+			name: "sixty_prs",
+			prs:  manyPRs(60),
+		},
+		{
+			name: "missing_jira",
+			opts: func(opts *MessageOptions) {
+				opts.DataIssues = []string{"could not fetch 1 of 2 JIRA tickets, their status is unknown: POKER-502"}
+			},
+			prs: []*PRInfo{
+				{Number: 501, Assignee: "dave", Title: "Bump webpack", Description: ""},
+				{Number: 502, Assignee: "", JiraTicket: "POKER-502", Description: "Fix seat picker"},
+				{Number: 503, Assignee: "<@U0ALICE>", JiraTicket: "POKER-503", JiraStatus: "In Review", Description: "Cashier redesign", PreviousStatus: "In Progress"},
+			},
+		},
+		{
+			name: "escalation",
+			opts: func(opts *MessageOptions) {
+				opts.EscalateAfter = 7 * 24 * time.Hour
+				opts.EscalateGroup = "S0LEADS"
+			},
+			prs: []*PRInfo{
+				{Number: 701, Assignee: "<@U0ALICE>", JiraTicket: "POKER-701", JiraStatus: "In Review", Description: "Rake calculation", CreatedAt: reportDate.Add(-10 * 24 * time.Hour)},
+				{Number: 702, Assignee: "<@U0BOB>", JiraTicket: "POKER-702", JiraStatus: "In Progress", Description: "Avatar upload", CreatedAt: reportDate.Add(-2 * 24 * time.Hour)},
+				{Number: 703, Assignee: "<@U0CAROL>", JiraTicket: "POKER-703", JiraStatus: "In Progress", Description: "Lobby filters", IsDraft: true, CreatedAt: reportDate.Add(-30 * 24 * time.Hour)},
+			},
+		},
+		{
+			name: "grouped_assignees",
+			opts: func(opts *MessageOptions) { opts.GroupByAssignee = true },
+			prs: []*PRInfo{
+				{Number: 801, Assignee: "<@U0ALICE>", GithubAssignee: "alice", JiraTicket: "POKER-801", JiraStatus: "In Review", Description: "Seat reservations"},
+				{Number: 802, Assignee: "<@U0ALICE>", GithubAssignee: "alice", JiraTicket: "POKER-802", JiraStatus: "In Progress", Description: "Rebuy limits"},
+				{Number: 803, Assignee: "<@U0BOB>", GithubAssignee: "bob", JiraTicket: "POKER-803", JiraStatus: "Blocked", Description: "Ledger export", IsBlocked: true},
+				{Number: 804, Title: "Bump webpack"},
+			},
+		},
+		{
+			name: "grouped_assignees_template",
+			opts: func(opts *MessageOptions) {
+				opts.GroupByAssignee = true
+				opts.Template = `{{define "pr"}}{{.Index}}. {{.Link}} {{.JiraStatus}}{{end}}`
+			},
+			prs: []*PRInfo{
+				{Number: 801, Assignee: "<@U0ALICE>", GithubAssignee: "alice", JiraStatus: "In Review"},
+				{Number: 802, Assignee: "<@U0BOB>", GithubAssignee: "bob", JiraStatus: "In Progress"},
+			},
+		},
+		{
+			name: "grouped_tickets",
+			opts: func(opts *MessageOptions) { opts.GroupByTicket = true },
+			prs: []*PRInfo{
+				{Number: 601, Assignee: "<@U0ALICE>", JiraTicket: "POKER-600", JiraStatus: "In Review", Description: "Tournament lobby"},
+				{Number: 602, Assignee: "<@U0BOB>", JiraTicket: "POKER-600", JiraStatus: "In Review", Description: "Tournament lobby"},
+				{Number: 603, Assignee: "<@U0CAROL>", JiraTicket: "POKER-603", JiraStatus: "In Progress", Description: "Hand history export"},
+				{Number: 604, Assignee: "dave", Title: "Bump webpack"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := goldenOptions()
+			if tt.opts != nil {
+				tt.opts(&opts)
+			}
+
+			tmpl, err := loadTemplates(opts)
+			if err != nil {
+				t.Fatalf("loadTemplates: %v", err)
+			}
+			messages, err := formatMessages(opts, tmpl, tt.prs)
+			if err != nil {
+				t.Fatalf("formatMessages: %v", err)
+			}
+			var got strings.Builder
+			for i, message := range messages {
+				fmt.Fprintf(&got, "=== message %d/%d ===\n%s\n", i+1, len(messages), message)
+			}
+			assertGolden(t, tt.name, got.String())
+		})
+	}
+}
+
+func TestBuildBlockPartsV2Golden(t *testing.T) {
+	opts := goldenOptions()
+	opts.MessageFormat = MessageFormatV2
+	prs := manyPRs(6)
+	prs[5].JiraStatus = ""
+
+	content, err := formatReport(opts, nil, prs, len(prs), nil, nil)
+	if err != nil {
+		t.Fatalf("formatReport: %v", err)
+	}
+
+	var got strings.Builder
+	parts := buildBlockParts(opts, content, prs)
+	for i, part := range parts {
+		blocks, err := json.MarshalIndent(part.blocks, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&got, "=== message %d/%d: %s ===\n%s\n", i+1, len(parts), part.text, blocks)
+	}
+	assertGolden(t, "v2_grouped", got.String())
+}
+
+func TestBuildBlockPartsV2AssigneesGolden(t *testing.T) {
+	opts := goldenOptions()
+	opts.MessageFormat = MessageFormatV2
+	opts.GroupByAssignee = true
+	prs := manyPRs(4)
+	prs[0].GithubAssignee, prs[1].GithubAssignee = "alice", "alice"
+	prs[2].GithubAssignee = "bob"
+
+	content, err := formatReport(opts, nil, prs, len(prs), nil, nil)
+	if err != nil {
+		t.Fatalf("formatReport: %v", err)
+	}
+
+	// Assignee blocks replace the status sections, each heading above its own PRs
+	var got strings.Builder
+	for i, part := range buildBlockParts(opts, content, prs) {
+		blocks, err := json.MarshalIndent(part.blocks, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&got, "=== message %d: %s ===\n%s\n", i+1, part.text, blocks)
+	}
+	assertGolden(t, "v2_assignees", got.String())
+}
+
+// assertGolden compares got with testdata/golden/<name>.golden, rewriting the
+// file first with -update
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file, run go test ./internal/slack -update: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("report doesn't match %s (run go test ./internal/slack -update if the change is intended)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,198 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/state"
+)
+
+// InteractionOptions contains options for handling Slack interactivity requests
+type InteractionOptions struct {
+	SigningSecret  string        // Slack app signing secret used to verify requests
+	StateFile      string        // Path of the state file shared with the reporters
+	SnoozeDuration time.Duration // How long "Snooze" hides a PR (default: 24h)
+	OnApprove      ApproveFunc   // Posts reports approved from a preview (optional)
+}
+
+// interactionHandler records button clicks from interactive reports
+type interactionHandler struct {
+	opts InteractionOptions
+	mu   sync.Mutex // Serializes state file updates
+}
+
+// NewInteractionHandler returns an HTTP handler for the Slack interactivity
+// request URL. Button clicks on interactive reports are verified, recorded in
+// the state file and reflected in the next report.
+func NewInteractionHandler(opts InteractionOptions) http.Handler {
+	return newInteractionHandler(opts)
+}
+
+// newInteractionHandler applies option defaults and creates the handler
+func newInteractionHandler(opts InteractionOptions) *interactionHandler {
+	if opts.StateFile == "" {
+		opts.StateFile = state.DefaultPath
+	}
+	if opts.SnoozeDuration <= 0 {
+		opts.SnoozeDuration = 24 * time.Hour
+	}
+	return &interactionHandler{opts: opts}
+}
+
+func (h *interactionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := verifyRequest(r, h.opts.SigningSecret)
+	if err != nil {
+		slog.Warn("Rejected interactivity request", "error", err)
+		http.Error(w, "invalid request", http.StatusUnauthorized)
+		return
+	}
+
+	// Interactivity payloads are sent as a form-encoded "payload" field
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(r.PostForm.Get("payload")), &callback); err != nil {
+		slog.Warn("Could not parse interactivity payload", "error", err)
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if callback.Type != slack.InteractionTypeBlockActions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	h.handleCallback(callback)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleCallback records the button clicks of a block actions callback and
+// confirms them privately to the user who clicked
+func (h *interactionHandler) handleCallback(callback slack.InteractionCallback) {
+	for _, action := range callback.ActionCallback.BlockActions {
+		// Posting an approved report can take longer than Slack waits for a response
+		if action.ActionID == ActionIDApprove || action.ActionID == ActionIDDiscard {
+			go h.handlePreviewCallback(action.ActionID, action.Value, callback)
+			continue
+		}
+
+		reply, err := h.recordAction(action.ActionID, action.Value, callback.User.ID)
+		if err != nil {
+			slog.Warn("Could not record action", "action", action.ActionID, "pr", action.Value, "error", err)
+			reply = "Sorry, your response could not be recorded."
+		}
+		if reply == "" || callback.ResponseURL == "" {
+			continue
+		}
+
+		err = postWebhookMessage(callback.ResponseURL, &slack.WebhookMessage{
+			Text:            reply,
+			ResponseType:    slack.ResponseTypeEphemeral,
+			ReplaceOriginal: false,
+		})
+		if err != nil {
+			slog.Warn("Could not send confirmation", "user", callback.User.ID, "error", err)
+		}
+	}
+}
+
+// handlePreviewCallback approves or discards a preview and replaces the
+// approval buttons with the outcome
+func (h *interactionHandler) handlePreviewCallback(actionID, previewID string, callback slack.InteractionCallback) {
+	reply, err := h.handlePreviewAction(actionID, previewID, callback.User.ID)
+	if err != nil {
+		slog.Warn("Could not handle preview", "preview", previewID, "error", err)
+		reply = fmt.Sprintf("❌ Could not post the report: %v", err)
+	}
+	if callback.ResponseURL == "" {
+		return
+	}
+
+	err = postWebhookMessage(callback.ResponseURL, &slack.WebhookMessage{
+		Text:            reply,
+		ReplaceOriginal: true,
+	})
+	if err != nil {
+		slog.Warn("Could not update preview message", "user", callback.User.ID, "error", err)
+	}
+}
+
+// recordAction stores a button click in the state file and returns the
+// confirmation shown to the user
+func (h *interactionHandler) recordAction(actionID, prKey, userID string) (string, error) {
+	now := time.Now()
+	ack := &state.Ack{UserID: userID, At: now}
+
+	var reply string
+	switch actionID {
+	case ActionIDReviewing:
+		ack.Action = state.ActionReviewing
+		reply = fmt.Sprintf("👀 Marked %s as being reviewed by you.", prKey)
+	case ActionIDSnooze:
+		ack.Action = state.ActionSnooze
+		ack.Until = now.Add(h.opts.SnoozeDuration)
+		reply = fmt.Sprintf("💤 Snoozed %s until %s.", prKey, ack.Until.Format("2006-01-02 15:04"))
+	case ActionIDNotMine:
+		ack.Action = state.ActionNotMine
+		reply = fmt.Sprintf("🙅 Noted that %s is not yours.", prKey)
+	default:
+		return "", nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	store, err := state.Load(h.opts.StateFile)
+	if err != nil {
+		return "", err
+	}
+
+	store.Acks[prKey] = ack
+	if err := store.Save(); err != nil {
+		return "", err
+	}
+
+	slog.Debug("Recorded action", "action", ack.Action, "pr", prKey, "user", userID)
+
+	return reply, nil
+}
+
+// verifyRequest checks the Slack request signature and returns the request body
+func verifyRequest(r *http.Request, signingSecret string) ([]byte, error) {
+	if signingSecret == "" {
+		return nil, fmt.Errorf("signing secret is not configured")
+	}
+
+	verifier, err := slack.NewSecretsVerifier(r.Header, signingSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(io.TeeReader(r.Body, &verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifier.Ensure(); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
@@ -0,0 +1,177 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/state"
+)
+
+// Action IDs of the buttons attached to report previews
+const (
+	ActionIDApprove = "preview_approve"
+	ActionIDDiscard = "preview_discard"
+)
+
+// previewExpiry is how long an unanswered preview can still be approved
+const previewExpiry = 24 * time.Hour
+
+// ApproveFunc posts an approved report. report is the report name the
+// preview was sent for and prs are the PRs shown in the preview.
+type ApproveFunc func(report string, prs []*PRInfo) error
+
+// SendPreview sends the rendered report as a direct message to
+// opts.PreviewUser with "Approve & post" and "Discard" buttons instead of
+// posting it. The PRs are kept in the state file so the approved report
+// matches the preview exactly.
+func SendPreview(ctx context.Context, opts MessageOptions, report string, prs []*PRInfo) error {
+	if opts.Token == "" {
+		return fmt.Errorf("Slack token is required")
+	}
+	if opts.PreviewUser == "" {
+		return fmt.Errorf("preview user is required")
+	}
+
+	parts, err := renderTextParts(opts, prs)
+	if err != nil {
+		return err
+	}
+
+	stateFile := opts.StateFile
+	if stateFile == "" {
+		stateFile = state.DefaultPath
+	}
+	store, err := state.Load(stateFile)
+	if err != nil {
+		return fmt.Errorf("error loading state for preview: %w", err)
+	}
+
+	prsJSON, err := json.Marshal(prs)
+	if err != nil {
+		return fmt.Errorf("error encoding previewed PRs: %w", err)
+	}
+
+	api := newClient(opts.APIURL, opts.Token)
+
+	channel, _, _, err := api.OpenConversationContext(ctx, &slack.OpenConversationParameters{
+		Users: []string{opts.PreviewUser},
+	})
+	if err != nil {
+		return fmt.Errorf("error opening DM with %s: %w", opts.PreviewUser, err)
+	}
+
+	for i, part := range parts {
+		if _, _, err := api.PostMessageContext(ctx, channel.ID, append(part.options(), postOptions(opts)...)...); err != nil {
+			return fmt.Errorf("error sending preview part %d/%d: %w", i+1, len(parts), err)
+		}
+	}
+
+	now := time.Now()
+	previewID := fmt.Sprintf("%s-%d", report, now.UnixNano())
+
+	destination := opts.Channel
+	if len(opts.Channels) > 0 {
+		destination = fmt.Sprintf("%d channels", len(opts.Channels))
+	}
+	prompt := fmt.Sprintf("👆 Preview of the %s report. Post it to %s?", report, destination)
+
+	_, _, err = api.PostMessageContext(
+		ctx,
+		channel.ID,
+		slack.MsgOptionText(prompt, false),
+		slack.MsgOptionBlocks(
+			textSection(prompt),
+			slack.NewActionBlock(
+				"preview_actions",
+				newButton(ActionIDApprove, previewID, "✅ Approve & post"),
+				newButton(ActionIDDiscard, previewID, "🗑️ Discard"),
+			),
+		),
+		slack.MsgOptionAsUser(true),
+	)
+	if err != nil {
+		return fmt.Errorf("error sending preview approval buttons: %w", err)
+	}
+
+	// Forget previews nobody answered
+	for id, preview := range store.Previews {
+		if now.Sub(preview.CreatedAt) > previewExpiry {
+			delete(store.Previews, id)
+		}
+	}
+
+	store.Previews[previewID] = &state.Preview{
+		Report:    report,
+		UserID:    opts.PreviewUser,
+		PRs:       prsJSON,
+		CreatedAt: now,
+	}
+	if err := store.Save(); err != nil {
+		return fmt.Errorf("error saving preview: %w", err)
+	}
+
+	slog.Debug("Sent preview", "preview", previewID, "parts", len(parts), "user", opts.PreviewUser)
+
+	return nil
+}
+
+// takePreview removes a pending preview from the state file and returns it
+func (h *interactionHandler) takePreview(previewID string) (*state.Preview, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	store, err := state.Load(h.opts.StateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, exists := store.Previews[previewID]
+	if !exists || time.Since(preview.CreatedAt) > previewExpiry {
+		return nil, nil
+	}
+
+	delete(store.Previews, previewID)
+	if err := store.Save(); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// handlePreviewAction posts or discards a previewed report and returns the
+// text that replaces the approval buttons
+func (h *interactionHandler) handlePreviewAction(actionID, previewID, userID string) (string, error) {
+	preview, err := h.takePreview(previewID)
+	if err != nil {
+		return "", err
+	}
+	if preview == nil {
+		return "⌛ This preview has expired or was already handled.", nil
+	}
+
+	if actionID == ActionIDDiscard {
+		slog.Info("Preview discarded", "report", preview.Report, "user", userID)
+		return fmt.Sprintf("🗑️ Discarded the %s report.", preview.Report), nil
+	}
+
+	if h.opts.OnApprove == nil {
+		return "", fmt.Errorf("approving previews is not supported by this server")
+	}
+
+	var prs []*PRInfo
+	if err := json.Unmarshal(preview.PRs, &prs); err != nil {
+		return "", fmt.Errorf("error decoding previewed PRs: %w", err)
+	}
+
+	slog.Info("Preview approved, posting it", "report", preview.Report, "user", userID)
+
+	if err := h.opts.OnApprove(preview.Report, prs); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("✅ Posted the %s report.", preview.Report), nil
+}
@@ -0,0 +1,45 @@
+package slack
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/httpx"
+)
+
+// transport retries rate-limited and failed Slack requests and spaces them
+// to stay below Slack's per-method rate limits
+var transport = httpx.NewTransport(http.DefaultTransport, httpx.Options{Name: "Slack", Rate: 4})
+
+// httpClient is shared by all Slack API and webhook requests
+var httpClient = &http.Client{
+	Timeout:   5 * time.Minute, // Covers retries after rate limiting
+	Transport: transport,
+}
+
+// timeoutClient returns httpClient, or a client sharing its transport whose
+// requests time out after timeout when it is set
+func timeoutClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		return httpClient
+	}
+	return &http.Client{Timeout: timeout, Transport: httpClient.Transport}
+}
+
+// newClient creates a Slack API client that retries rate-limited and failed
+// requests. apiURL replaces https://slack.com/api/ when set.
+func newClient(apiURL, token string, options ...slack.Option) *slack.Client {
+	defaults := []slack.Option{slack.OptionHTTPClient(httpClient)}
+	if apiURL != "" {
+		defaults = append(defaults, slack.OptionAPIURL(strings.TrimRight(apiURL, "/")+"/"))
+	}
+	return slack.New(token, append(defaults, options...)...)
+}
+
+// postWebhookMessage sends a message to a response or webhook URL, retrying
+// when rate limited
+func postWebhookMessage(url string, msg *slack.WebhookMessage) error {
+	return slack.PostWebhookCustomHTTP(url, httpClient, msg)
+}
@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReminderPR is a PR listed in a reviewer's reminder
+type ReminderPR struct {
+	PR      *PRInfo
+	Waiting time.Duration // How long the review has been pending
+}
+
+// SendReminders sends each Slack user a direct message listing the PRs whose
+// review they have owed for a while. reminders maps Slack user IDs to their
+// PRs, longest waiting first.
+func SendReminders(ctx context.Context, opts MessageOptions, reminders map[string][]ReminderPR) error {
+	messages := make(map[string]string, len(reminders))
+	for userID, items := range reminders {
+		messages[userID] = formatReminder(opts, items)
+	}
+	return sendDirectMessages(ctx, opts, "review reminder", messages)
+}
+
+// formatReminder formats a reviewer's reminder, with how to opt out
+func formatReminder(opts MessageOptions, items []ReminderPR) string {
+	title := "Reviews waiting on you"
+	if opts.ReportTitle != "" {
+		title = fmt.Sprintf("Reviews waiting on you (%s)", opts.ReportTitle)
+	}
+
+	lines := []string{fmt.Sprintf("⏰ *%s*", title), ""}
+	for i, item := range items {
+		lines = append(lines, fmt.Sprintf("%d. *%s* %s – waiting %s", i+1, prLink(opts, item.PR), item.PR.Title, formatWait(item.Waiting)))
+	}
+	lines = append(lines, "", "_Mention me with `reminders off` to stop these reminders._")
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,55 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// maxScheduleAhead is how far in the future Slack accepts scheduled messages
+const maxScheduleAhead = 120 * 24 * time.Hour
+
+// shouldSchedule reports whether the report should be scheduled with
+// chat.scheduleMessage rather than posted right away. Post times that have
+// already passed are posted immediately.
+func shouldSchedule(opts MessageOptions) bool {
+	return !opts.PostAt.IsZero() && time.Until(opts.PostAt) > 0
+}
+
+// scheduleParts schedules the report parts for delivery at opts.PostAt.
+// Scheduled messages have no timestamp until Slack posts them, so they can't
+// be threaded, updated later or remembered in the state store.
+func scheduleParts(ctx context.Context, api *slack.Client, opts MessageOptions, parts []messagePart) error {
+	if time.Until(opts.PostAt) > maxScheduleAhead {
+		return fmt.Errorf("post time %s is more than 120 days ahead", opts.PostAt.Format(time.RFC3339))
+	}
+
+	if opts.SplitThread || opts.ThreadDetail {
+		slog.Warn("Scheduled reports can't be threaded, posting all parts to the channel", "parts", len(parts))
+	}
+	if opts.UpdateExisting || opts.LiveStatus {
+		slog.Warn("Scheduled reports can't update an earlier report, a new one will be posted")
+	}
+	if opts.ExportFormat != "" || len(opts.Trend) > 1 {
+		slog.Warn("Files can't be attached to scheduled reports, skipping the PR export and trend chart")
+	}
+
+	for i, part := range parts {
+		// Space parts one second apart so Slack delivers them in order
+		postAt := opts.PostAt.Add(time.Duration(i) * time.Second)
+
+		_, scheduledID, err := api.ScheduleMessageContext(ctx, opts.Channel, strconv.FormatInt(postAt.Unix(), 10), append(part.options(), postOptions(opts)...)...)
+		if err != nil {
+			return fmt.Errorf("error scheduling message part %d/%d: %w", i+1, len(parts), err)
+		}
+
+		slog.Debug("Scheduled report part", "channel", opts.Channel, "part", i+1, "parts", len(parts), "at", postAt, "id", scheduledID)
+	}
+
+	slog.Info("Report scheduled", "channel", opts.Channel, "at", opts.PostAt.Format("2006-01-02 15:04 MST"))
+	return nil
+}
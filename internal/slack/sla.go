@@ -0,0 +1,82 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/model"
+)
+
+// SendSLAAlert posts a dedicated alert listing PRs that breached their review
+// SLA, mentioning the people who can unblock them, to the report's channels
+func SendSLAAlert(opts MessageOptions, breaches []model.SLABreach, compliance model.SLACompliance) error {
+	text := formatSLAAlert(opts, breaches, compliance)
+
+	if opts.WebhookURL != "" {
+		return postWebhook(context.Background(), timeoutClient(opts.Timeout), opts.WebhookURL, webhookPayload{Text: text, UnfurlLinks: opts.UnfurlLinks, UnfurlMedia: opts.UnfurlLinks})
+	}
+	if opts.Token == "" {
+		return fmt.Errorf("Slack token is required")
+	}
+
+	channels := []string{opts.Channel}
+	if len(opts.Channels) > 0 {
+		channels = nil
+		for _, target := range opts.Channels {
+			channels = append(channels, target.Channel)
+		}
+	}
+
+	api := newClient(opts.APIURL, opts.Token, slack.OptionHTTPClient(timeoutClient(opts.Timeout)))
+	for _, channel := range channels {
+		if _, _, err := api.PostMessage(channel, append(postOptions(opts), slack.MsgOptionText(text, false))...); err != nil {
+			return fmt.Errorf("error posting SLA alert to %s: %w", channel, err)
+		}
+		slog.Debug("Posted SLA alert", "channel", channel, "prs", len(breaches))
+	}
+
+	return nil
+}
+
+// formatSLAAlert formats one line per breach, followed by the compliance
+func formatSLAAlert(opts MessageOptions, breaches []model.SLABreach, compliance model.SLACompliance) string {
+	emoji := resolveEmoji(opts)
+	text := model.LocaleStrings(opts.Locale)
+
+	title := fmt.Sprintf("%s *%s*", emoji.SLA, text.SLABreached)
+	if opts.ReportTitle != "" {
+		title += fmt.Sprintf(" (%s)", opts.ReportTitle)
+	}
+	lines := []string{title}
+
+	for _, breach := range breaches {
+		step := text.FirstReview
+		if breach.Step == model.SLAApproval {
+			step = text.Approval
+		}
+		line := fmt.Sprintf("• %s %s: %s (SLA %s)", prLink(opts, breach.PR), step, formatWait(breach.Waiting), formatWait(breach.Limit))
+		if mentions := slaMentions(opts, breach.PR); mentions != "" {
+			line += " " + mentions
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", fmt.Sprintf("%s: %d%% (%d/%d)", text.SLACompliance, compliance.Percent(), compliance.Met, compliance.Total))
+
+	return strings.Join(lines, "\n")
+}
+
+// slaMentions returns who to ping about a breach: the requested reviewers,
+// else the assignee, else the team
+func slaMentions(opts MessageOptions, pr *PRInfo) string {
+	if len(pr.ReviewerMentions) > 0 {
+		return strings.Join(pr.ReviewerMentions, " ")
+	}
+	if strings.HasPrefix(pr.Assignee, "<@") {
+		return pr.Assignee
+	}
+	return mentionText(opts)
+}
@@ -1,321 +1,473 @@
 package slack
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/slack-go/slack"
+	"pr-reporter/internal/chart"
+	"pr-reporter/internal/errkind"
+	"pr-reporter/internal/logging"
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/state"
 )
 
 // MessageOptions contains options for sending a PR report to Slack
 type MessageOptions struct {
-	Token        string // Slack bot token
-	Channel      string // Slack channel to post to (e.g., "#channel-name" or "C1234567890")
-	GithubOwner  string // GitHub repository owner (for PR links)
-	GithubRepo   string // GitHub repository name (for PR links)
-	JiraURL      string // JIRA base URL (for ticket links)
-	TeamGroup    string // Slack team group ID to mention (optional)
-	MentionUsers string // Comma-separated Slack user IDs to mention (alternative to TeamGroup)
-	ReportTitle  string // Optional title for the report (e.g., "Frontend Report")
-	ShowAssignee bool   // Whether to show assignee in PR line (default: true)
-	UseCheckmark bool   // Whether to use checkmark emoji for no blocked/draft (default: true, false = memo emoji)
-	DebugMode    bool   // Enable debug logging
+	Token           string                // Slack bot token
+	APIURL          string                // Slack Web API URL, e.g. an egress proxy (default: https://slack.com/api/)
+	Channel         string                // Slack channel to post to (e.g., "#channel-name" or "C1234567890")
+	WebhookURL      string                // Incoming webhook URL to post the report through instead of the bot token (optional)
+	Timeout         time.Duration         // Timeout of each Slack API call, including rate limit retries (default: 5m)
+	Channels        []ChannelTarget       // Post to several channels instead of Channel, each with its own verbosity
+	Verbosity       string                // VerbosityFull (default) or VerbositySummary
+	GithubOwner     string                // GitHub repository owner (for PR links)
+	GithubRepo      string                // GitHub repository name (for PR links)
+	JiraURL         string                // JIRA base URL (for ticket links)
+	TeamGroup       string                // Slack team group ID to mention (optional)
+	MentionUsers    string                // Comma-separated Slack user IDs to mention (alternative to TeamGroup)
+	MentionPolicy   string                // MentionPolicyTeam (default), MentionPolicyTargeted or MentionPolicyNone
+	StaleAfter      time.Duration         // PRs not updated for this long count as stale (default: 72h)
+	EscalateAfter   time.Duration         // PRs open for longer than this also ping EscalateGroup, separately from the team (0: off)
+	EscalateGroup   string                // Slack user group ID of the leads or managers PRs are escalated to
+	ReportTitle     string                // Optional title for the report (e.g., "Frontend Report")
+	ShowAssignee    bool                  // Whether to show assignee in PR line (default: true)
+	UseCheckmark    bool                  // Whether to use checkmark emoji for no blocked/draft (default: true, false = memo emoji)
+	MaxLength       int                   // Maximum characters per Slack message before splitting (default: 3500)
+	SplitThread     bool                  // Post overflow parts as thread replies instead of chained channel messages
+	ThreadDetail    bool                  // Post a compact summary and one threaded reply per PR with full details
+	UpdateExisting  bool                  // Update the report posted earlier instead of posting a new one
+	LiveStatus      bool                  // Keep a single pinned report updated in place on every run (no update window)
+	UpdateWindow    time.Duration         // How long a posted report is updated (default: until the end of the day)
+	StateFile       string                // Path of the state file used to remember posted reports and button actions
+	UserCacheTTL    time.Duration         // How long Slack user profiles are cached in StateFile (default: 24h, negative: not cached)
+	Interactive     bool                  // Add "Reviewing", "Snooze" and "Not mine" buttons to each PR (Block Kit)
+	MessageFormat   string                // MessageFormatV1 (default) or MessageFormatV2
+	GroupByTicket   bool                  // List PRs sharing a ticket together under a ticket heading (PRs must come grouped, see model.GroupByTicket)
+	GroupByAssignee bool                  // List PRs in a block per assignee under a heading with the assignee (PRs must come grouped, see model.GroupByAssignee)
+	Template        string                // Inline text/template overriding the "header", "pr" and/or "footer" sections
+	TemplateFile    string                // File with text/template definitions, overridden by Template
+	Emoji           Emoji                 // Emoji overrides (empty fields use the defaults)
+	Locale          string                // Report language (e.g., "de"); see Locales (default: English)
+	UnfurlLinks     bool                  // Show link previews (GitHub/JIRA cards) under report messages
+	ExportFormat    string                // Attach the full PR dataset as a file in the report thread: an export format or "" (off)
+	PreviewUser     string                // Slack user ID who must approve a DM preview before the report is posted
+	PostAt          time.Time             // Schedule delivery for this time with chat.scheduleMessage (zero or past = post now)
+	Date            time.Time             // Date shown on the report, for replays of past reports (zero: today)
+	LinkPrevious    bool                  // Link the report posted to the channel on an earlier day next to the date
+	PreviousReport  string                // Permalink of the previous report, set while sending when LinkPrevious is on
+	AgeBuckets      bool                  // Show how many open PRs fall into each age range below the total
+	Anomalies       []model.Anomaly       // Counts that grew unusually, warned about above the report (nil: none)
+	Changes         *model.Changes        // What changed since the previous report, listed above the PRs (nil: not shown)
+	Trend           []chart.Point         // Open PR counts over time, attached as a chart in the report thread (fewer than 2: none)
+	AuthorStats     []model.AuthorStats   // Per-author stats, appended as a table (nil: not shown)
+	Turnaround      *model.Turnaround     // Average review turnaround, appended below the PRs (nil: not shown)
+	MergeRate       *model.MergeRate      // Merged vs. opened PRs per day, appended below the PRs (nil: not shown)
+	CycleTime       *model.CycleTime      // Previous month's cycle time, appended below the PRs (nil: not shown)
+	Sprint          *model.SprintBurndown // Tickets of the active sprint by PR progress, appended below the PRs (nil: not shown)
+	SprintSummary   *model.SprintSummary  // Summary of the sprint that just closed, appended below the PRs (nil: not shown)
+	Weekly          *model.WeeklySummary  // Weekly summary shown in place of the date (nil: daily report)
+	Monthly         *model.MonthlySummary // Monthly retrospective shown in place of the date (nil: daily report)
+	Leaderboard     *model.Leaderboard    // Reviewers ranked by PRs reviewed, appended below the PRs (nil: not shown)
+	Labels          *model.LabelBreakdown // Open PRs of tracked labels, appended below the PRs (nil: not shown)
+	ReviewLoad      []model.ReviewLoad    // Outstanding review requests per reviewer, appended below the PRs (nil: not shown)
+	DataIssues      []string              // What couldn't be fetched for the report, listed below the PRs (nil: not shown)
 }
 
-// PRInfo represents PR information to be sent to Slack
-type PRInfo struct {
-	Number      int
-	Title       string
-	Assignee    string // Slack mention format (e.g., "<@U123456>") or GitHub username
-	JiraTicket  string
-	JiraStatus  string
-	Description string
-	IsDraft     bool
-	IsBlocked   bool
+// Report verbosity levels
+const (
+	VerbosityFull    = "full"    // The report as configured
+	VerbositySummary = "summary" // Totals and the blocked/draft summary, without the PR list
+)
+
+// Message formats, so new layouts can ship while teams pin the one they're used to
+const (
+	MessageFormatV1 = "v1" // Plain text, one line per PR
+	MessageFormatV2 = "v2" // Block Kit, with the PRs grouped by ticket status
+)
+
+// Mention policies deciding who is pinged at the end of a report
+const (
+	MentionPolicyTeam     = "team"     // Ping TeamGroup or MentionUsers
+	MentionPolicyTargeted = "targeted" // Ping only the people on stale, blocked or unreviewed PRs
+	MentionPolicyNone     = "none"     // Don't ping anyone
+)
+
+// DefaultStaleAfter is how long a PR can go without updates before it is stale
+const DefaultStaleAfter = 72 * time.Hour
+
+// ChannelTarget is a destination channel of a report posted to several channels
+type ChannelTarget struct {
+	Channel   string // Channel name or ID
+	Verbosity string // VerbosityFull (default) or VerbositySummary
 }
 
-// SendPRReport formats and sends a PR report message to Slack
-func SendPRReport(opts MessageOptions, prs []*PRInfo) error {
+// DefaultMaxLength is the default per-message character budget. Slack truncates
+// text messages at around 4,000 characters, so keep some headroom.
+const DefaultMaxLength = 3500
+
+// PRInfo represents PR information to be sent to Slack
+type PRInfo = model.PR
+
+// SendPRReport formats and sends a PR report message to Slack. Slack calls
+// are canceled when ctx is done.
+func SendPRReport(ctx context.Context, opts MessageOptions, prs []*PRInfo) error {
+	// Incoming webhooks need neither a token nor a channel
+	if opts.WebhookURL != "" {
+		return sendWebhook(ctx, opts, prs)
+	}
+
 	if opts.Token == "" {
-		return fmt.Errorf("Slack token is required")
+		return errkind.Configf("Slack token is required")
+	}
+	if len(opts.Channels) > 0 {
+		return sendToChannels(ctx, opts, prs)
 	}
 	if opts.Channel == "" {
-		return fmt.Errorf("Slack channel is required")
+		return errkind.Configf("Slack channel is required")
 	}
 	if opts.GithubOwner == "" || opts.GithubRepo == "" {
-		return fmt.Errorf("GitHub owner and repo are required")
+		return errkind.Configf("GitHub owner and repo are required")
+	}
+
+	// Summaries leave out everything that is per PR
+	if opts.Verbosity == VerbositySummary {
+		opts.ThreadDetail = false
+		opts.Interactive = false
 	}
 
-	api := slack.New(opts.Token)
+	// Parse custom templates up front so mistakes are reported before anything is posted
+	tmpl, err := loadTemplates(opts)
+	if err != nil {
+		return err
+	}
+
+	api := newClient(opts.APIURL, opts.Token, slack.OptionHTTPClient(timeoutClient(opts.Timeout)))
+	logger := slog.With("channel", opts.Channel)
 
 	// Test authentication in debug mode
-	if opts.DebugMode {
-		log.Println("Debug: Testing Slack authentication...")
-		authTest, err := api.AuthTest()
+	if logging.Debug() {
+		authTest, err := api.AuthTestContext(ctx)
 		if err != nil {
-			return fmt.Errorf("Slack authentication failed: %v", err)
+			return classify(fmt.Errorf("Slack authentication failed: %w", err))
 		}
-		log.Printf("Debug: Authenticated as: %s (Team: %s)", authTest.User, authTest.Team)
+		logger.Debug("Authenticated to Slack", "user", authTest.User, "team", authTest.Team)
+	}
+
+	// Load state to update posted reports and apply snoozes and button actions
+	stateFile := opts.StateFile
+	if stateFile == "" {
+		stateFile = state.DefaultPath
+	}
+	store, err := state.Load(stateFile)
+	if err != nil {
+		logger.Warn("Could not load state, continuing without it", "error", err)
 	}
 
-	// Format message with date and total on separate lines with emojis
-	currentDate := time.Now().Format("2006-01-02")
-	dateText := fmt.Sprintf(":date: *%s*", currentDate)
-	totalText := fmt.Sprintf(":bar_chart: *Total Open PRs: %d*", len(prs))
+	// Apply snoozes and button actions recorded by the interactivity endpoint
+	// or the command line
+	listed := prs
+	var snoozed []*PRInfo
+	acks := make(map[int]*state.Ack)
+	if store != nil {
+		listed, snoozed, acks = applyAcks(opts, store, prs)
+		logger.Debug("Applied acknowledgements", "acknowledged", len(acks), "snoozed", len(snoozed))
+	}
+
+	// Link the report posted on an earlier day, before this one replaces it
+	key := messageKey(opts)
+	if opts.LinkPrevious && store != nil {
+		if msg, exists := store.Messages[key]; exists {
+			opts.PreviousReport = msg.LastPermalink(time.Now())
+		}
+	}
 
-	var lines []string
+	content, err := formatReport(opts, tmpl, listed, len(prs), acks, snoozed)
+	if err != nil {
+		return err
+	}
 
-	// Add report title if provided
-	if opts.ReportTitle != "" {
-		lines = append(lines, fmt.Sprintf("📋 *%s*", opts.ReportTitle))
-		lines = append(lines, "") // Empty line for spacing
+	var parts []messagePart
+	if opts.Interactive || opts.MessageFormat == MessageFormatV2 {
+		parts = buildBlockParts(opts, content, listed)
+	} else {
+		maxLength := opts.MaxLength
+		if maxLength <= 0 {
+			maxLength = DefaultMaxLength
+		}
+		parts = buildTextParts(opts, content, maxLength)
 	}
 
-	lines = append(lines, dateText)
-	lines = append(lines, "") // Empty line for spacing
-	lines = append(lines, totalText)
-	lines = append(lines, "") // Empty line for spacing
+	logger.Debug("Sending report", "parts", len(parts))
 
-	// Track blocked/draft PRs for summary at the end
-	var blockedPRs []string
-	var draftPRs []string
+	// Hand the report over to Slack for delivery at the configured time
+	if shouldSchedule(opts) {
+		return scheduleParts(ctx, api, opts, parts)
+	}
 
-	for i, pr := range prs {
-		statusPart := pr.JiraStatus
-		if statusPart == "" {
-			statusPart = "Unknown"
+	// Look up a previously posted report that can be updated in place
+	var previous *state.Message
+	if (opts.UpdateExisting || opts.LiveStatus) && store != nil {
+		if msg, exists := store.Messages[key]; exists && len(msg.Parts) > 0 && withinUpdateWindow(opts, msg.PostedAt) {
+			previous = msg
+			logger.Debug("Updating earlier report", "posted", msg.PostedAt, "ts", msg.Parts[0])
 		}
+	}
 
-		// Track blocked and draft PRs for end summary with links
-		if pr.IsBlocked && pr.IsDraft {
-			blockedPRs = append(blockedPRs, fmt.Sprintf("<https://github.com/%s/%s/pull/%d|PR-%d> (Blocked & Draft)",
-				opts.GithubOwner, opts.GithubRepo, pr.Number, pr.Number))
-		} else if pr.IsBlocked {
-			blockedPRs = append(blockedPRs, fmt.Sprintf("<https://github.com/%s/%s/pull/%d|PR-%d>",
-				opts.GithubOwner, opts.GithubRepo, pr.Number, pr.Number))
-		} else if pr.IsDraft {
-			draftPRs = append(draftPRs, fmt.Sprintf("<https://github.com/%s/%s/pull/%d|PR-%d>",
-				opts.GithubOwner, opts.GithubRepo, pr.Number, pr.Number))
+	record := &state.Message{PostedAt: time.Now()}
+	if previous != nil {
+		record.PostedAt = previous.PostedAt
+		record.ChannelID = previous.ChannelID
+		record.PreviousPermalink = previous.PreviousPermalink
+	} else if store != nil {
+		if replaced, exists := store.Messages[key]; exists {
+			record.PreviousPermalink = replaced.LastPermalink(record.PostedAt)
 		}
+	}
 
-		// Format assignee
-		assigneeText := pr.Assignee
-		if assigneeText == "" {
-			assigneeText = "unassigned"
+	// Send message parts to Slack, chaining or threading any overflow
+	var parentTS string
+	for i, part := range parts {
+		threadTS := ""
+		if opts.SplitThread {
+			threadTS = parentTS
 		}
 
-		// Format JIRA ticket link
-		jiraLink := pr.JiraTicket
-		if pr.JiraTicket != "" && opts.JiraURL != "" {
-			jiraLink = fmt.Sprintf("<%s/browse/%s|%s>", opts.JiraURL, pr.JiraTicket, pr.JiraTicket)
-		} else if pr.JiraTicket == "" {
-			jiraLink = "N/A"
+		previousTS := ""
+		if previous != nil && i < len(previous.Parts) {
+			previousTS = previous.Parts[i]
 		}
 
-		// Format description
-		description := pr.Description
-		if description == "" {
-			description = "No description"
+		channelID, ts, err := postOrUpdate(ctx, api, opts, record.ChannelID, previousTS, threadTS, part)
+		if err != nil {
+			return classify(fmt.Errorf("error posting message part %d/%d to Slack: %w", i+1, len(parts), err))
 		}
 
-		// Format the PR line
-		var prLine string
-		if opts.ShowAssignee {
-			prLine = fmt.Sprintf("%d. *<https://github.com/%s/%s/pull/%d|PR-%d>* assigned to %s | Jira: %s | %s | *%s*",
-				i+1,
-				opts.GithubOwner,
-				opts.GithubRepo,
-				pr.Number,
-				pr.Number,
-				assigneeText,
-				jiraLink,
-				description,
-				statusPart)
-		} else {
-			prLine = fmt.Sprintf("%d. *<https://github.com/%s/%s/pull/%d|PR-%d>* | Jira: %s | %s | *%s*",
-				i+1,
-				opts.GithubOwner,
-				opts.GithubRepo,
-				pr.Number,
-				pr.Number,
-				jiraLink,
-				description,
-				statusPart)
+		record.ChannelID = channelID
+		record.Parts = append(record.Parts, ts)
+		if parentTS == "" {
+			parentTS = ts
 		}
 
-		lines = append(lines, prLine)
+		logger.Debug("Sent report part", "part", i+1, "parts", len(parts), "characters", len(part.text), "blocks", len(part.blocks), "ts", ts)
 	}
 
-	// Add blocked/draft summary at the end
-	lines = append(lines, "")
+	// Keep the permalink for links from later reports and the history command
+	if previous != nil && previous.Parts[0] == record.Parts[0] && previous.Permalink != "" {
+		record.Permalink = previous.Permalink
+	} else if permalink, err := api.GetPermalinkContext(ctx, &slack.PermalinkParameters{Channel: record.ChannelID, Ts: record.Parts[0]}); err != nil {
+		logger.Warn("Could not get the permalink of the report", "error", err)
+	} else {
+		record.Permalink = permalink
+	}
 
-	if len(blockedPRs) > 0 || len(draftPRs) > 0 {
-		if len(blockedPRs) > 0 {
-			lines = append(lines, fmt.Sprintf("🚫 *Blocked:* %s", strings.Join(blockedPRs, ", ")))
-		}
-		if len(draftPRs) > 0 {
-			lines = append(lines, fmt.Sprintf("📝 *Draft:* %s", strings.Join(draftPRs, ", ")))
+	// Pin a newly posted live status message so it doesn't get buried
+	if opts.LiveStatus && (previous == nil || previous.Parts[0] != record.Parts[0]) {
+		if err := api.AddPinContext(ctx, record.ChannelID, slack.NewRefToMessage(record.ChannelID, record.Parts[0])); err != nil {
+			logger.Warn("Could not pin live status message", "error", err)
+		} else {
+			logger.Debug("Pinned live status message", "ts", record.Parts[0])
 		}
-	} else {
-		// Use checkmark or memo emoji based on opts.UseCheckmark
-		emoji := "✅"
-		if !opts.UseCheckmark {
-			emoji = "📝"
+	}
+
+	// Remove parts and replies from the previous report that are no longer needed
+	if previous != nil {
+		var stale []string
+		if len(previous.Parts) > len(parts) {
+			stale = append(stale, previous.Parts[len(parts):]...)
 		}
-		lines = append(lines, fmt.Sprintf("%s *Blocked/Draft:* N/A", emoji))
-	}
-
-	// Add team mention or individual user mentions if provided
-	if opts.MentionUsers != "" {
-		// Mention specific users (comma-separated user IDs)
-		lines = append(lines, "")
-		userIDs := strings.Split(opts.MentionUsers, ",")
-		var mentions []string
-		for _, userID := range userIDs {
-			userID = strings.TrimSpace(userID)
-			if userID != "" {
-				mentions = append(mentions, fmt.Sprintf("<@%s>", userID))
+		stale = append(stale, previous.Replies...)
+		for _, ts := range stale {
+			if _, _, err := api.DeleteMessageContext(ctx, record.ChannelID, ts); err != nil {
+				logger.Warn("Could not delete outdated message", "ts", ts, "error", err)
 			}
 		}
-		if len(mentions) > 0 {
-			lines = append(lines, fmt.Sprintf("%s Please make sure to review these pull requests!", strings.Join(mentions, " ")))
-		}
-	} else if opts.TeamGroup != "" {
-		// Mention team group
-		lines = append(lines, "")
-		lines = append(lines, fmt.Sprintf("<!subteam^%s> Please make sure to review these pull requests!", opts.TeamGroup))
 	}
 
-	message := strings.Join(lines, "\n")
+	// Post one threaded reply per PR with full details
+	if opts.ThreadDetail && parentTS != "" {
+		for _, pr := range listed {
+			_, ts, err := api.PostMessageContext(
+				ctx,
+				opts.Channel,
+				append(postOptions(opts), slack.MsgOptionText(formatPRDetails(opts, pr), false), slack.MsgOptionTS(parentTS))...,
+			)
+			if err != nil {
+				return fmt.Errorf("error posting details for PR #%d to Slack thread: %w", pr.Number, err)
+			}
+			record.Replies = append(record.Replies, ts)
+		}
 
-	if opts.DebugMode {
-		log.Printf("Debug: Sending message to channel %s", opts.Channel)
-		log.Printf("Debug: Message length: %d characters", len(message))
+		logger.Debug("Posted PR detail replies", "replies", len(listed), "thread", parentTS)
 	}
 
-	// Send message to Slack
-	_, _, err := api.PostMessage(
-		opts.Channel,
-		slack.MsgOptionText(message, false),
-		slack.MsgOptionAsUser(true),
-	)
+	// Attach the full dataset, including snoozed PRs, for spreadsheet imports.
+	// Updates keep the file of the original post to avoid filling the thread.
+	if opts.ExportFormat != "" && parentTS != "" && previous == nil {
+		if err := uploadExport(ctx, api, opts, record.ChannelID, parentTS, prs); err != nil {
+			logger.Warn("Could not attach PR export", "error", err)
+		}
+	}
 
-	if err != nil {
-		return fmt.Errorf("error posting message to Slack: %v", err)
+	// Attach the open PR trend, also only to new posts
+	if len(opts.Trend) > 1 && parentTS != "" && previous == nil {
+		if err := uploadTrend(ctx, api, opts, record.ChannelID, parentTS); err != nil {
+			logger.Warn("Could not attach trend chart", "error", err)
+		}
 	}
 
-	if opts.DebugMode {
-		log.Println("Debug: Message sent successfully")
+	// Remember the posted report for later updates
+	if store != nil {
+		store.Messages[key] = record
+		if err := store.Save(); err != nil {
+			logger.Warn("Could not save state", "error", err)
+		}
 	}
 
+	logger.Debug("Report sent")
+
 	return nil
 }
 
-// GetChannelUsers fetches the list of users from a specified Slack channel
-func GetChannelUsers(token, channelName string, debugMode bool) ([]string, error) {
-	api := slack.New(token)
+// sendToChannels posts the report to every channel in opts.Channels. A failure
+// in one channel doesn't stop delivery to the others.
+func sendToChannels(ctx context.Context, opts MessageOptions, prs []*PRInfo) error {
+	var failed []string
+	for _, target := range opts.Channels {
+		channelOpts := opts
+		channelOpts.Channels = nil
+		channelOpts.Channel = target.Channel
+		if target.Verbosity != "" {
+			channelOpts.Verbosity = target.Verbosity
+		}
 
-	// Test authentication first
-	if debugMode {
-		log.Println("Debug: Testing Slack authentication...")
-		authTest, err := api.AuthTest()
-		if err != nil {
-			return nil, fmt.Errorf("Slack authentication failed: %v", err)
+		slog.Debug("Sending report to channel", "channel", target.Channel, "verbosity", channelOpts.Verbosity)
+
+		if err := SendPRReport(ctx, channelOpts, prs); err != nil {
+			slog.Warn("Could not send report to channel", "channel", target.Channel, "error", err)
+			failed = append(failed, target.Channel)
 		}
-		log.Printf("Debug: Authenticated as: %s (Team: %s)", authTest.User, authTest.Team)
 	}
 
-	var channelID string
-	channelName = strings.TrimPrefix(channelName, "#")
-
-	if debugMode {
-		log.Printf("Debug: Looking for channel: %s", channelName)
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to send report to %d of %d channels: %s", len(failed), len(opts.Channels), strings.Join(failed, ", "))
 	}
 
-	// Use the conversations API to find the channel
-	conversationTypes := []string{"public_channel", "private_channel"}
+	return nil
+}
 
-	for _, convType := range conversationTypes {
-		if debugMode {
-			log.Printf("Debug: Searching for %s channels...", convType)
+// applyAcks splits PRs into listed and snoozed ones and collects active
+// acknowledgments by PR number. Acknowledgments for PRs of this repository
+// that are no longer open, and expired snoozes, are removed from the store.
+func applyAcks(opts MessageOptions, store *state.Store, prs []*PRInfo) ([]*PRInfo, []*PRInfo, map[int]*state.Ack) {
+	now := time.Now()
+	acks := make(map[int]*state.Ack)
+	open := make(map[string]bool)
+
+	var listed []*PRInfo
+	var snoozed []*PRInfo
+	for _, pr := range prs {
+		key := state.PRKey(opts.GithubOwner, opts.GithubRepo, pr.Number)
+		open[key] = true
+
+		ack, exists := store.Acks[key]
+		if !exists || !ack.Active(now) {
+			listed = append(listed, pr)
+			continue
 		}
 
-		conversations, _, err := api.GetConversations(&slack.GetConversationsParameters{
-			Types: []string{convType},
-			Limit: 1000,
-		})
-
-		if err != nil {
-			if debugMode {
-				log.Printf("Debug: Error fetching %s channels: %v", convType, err)
-			}
+		if ack.Action == state.ActionSnooze {
+			snoozed = append(snoozed, pr)
 			continue
 		}
 
-		for _, conv := range conversations {
-			if conv.Name == channelName {
-				channelID = conv.ID
-				if debugMode {
-					log.Printf("Debug: Found channel #%s with ID: %s (type: %s)", channelName, channelID, convType)
-				}
-				break
-			}
-		}
+		acks[pr.Number] = ack
+		listed = append(listed, pr)
+	}
 
-		if channelID != "" {
-			break
+	repoPrefix := fmt.Sprintf("%s/%s#", opts.GithubOwner, opts.GithubRepo)
+	for key, ack := range store.Acks {
+		if strings.HasPrefix(key, repoPrefix) && (!open[key] || !ack.Active(now)) {
+			delete(store.Acks, key)
 		}
 	}
 
-	// If still not found, try without specifying types
-	if channelID == "" {
-		if debugMode {
-			log.Println("Debug: Channel not found in typed search, trying all accessible channels...")
-		}
+	return listed, snoozed, acks
+}
 
-		conversations, _, err := api.GetConversations(&slack.GetConversationsParameters{
-			Limit: 1000,
-		})
+// authErrors are the Slack API errors of a token that was rejected or lacks
+// a scope
+var authErrors = map[string]bool{
+	"not_authed":       true,
+	"invalid_auth":     true,
+	"account_inactive": true,
+	"token_revoked":    true,
+	"token_expired":    true,
+	"missing_scope":    true,
+}
 
-		if err != nil {
-			return nil, fmt.Errorf("error fetching conversations: %v", err)
-		}
+// classify marks errors of Slack rejecting the token as errkind.AuthError
+func classify(err error) error {
+	var slackErr slack.SlackErrorResponse
+	if errors.As(err, &slackErr) && authErrors[slackErr.Err] {
+		return &errkind.AuthError{API: "Slack", Err: err}
+	}
+	return err
+}
 
-		for _, conv := range conversations {
-			if conv.Name == channelName {
-				channelID = conv.ID
-				if debugMode {
-					log.Printf("Debug: Found channel #%s with ID: %s", channelName, channelID)
-				}
-				break
-			}
+// postOrUpdate updates the message at previousTS when set, otherwise posts a
+// new message (as a thread reply when threadTS is set). It returns the channel
+// ID and timestamp of the resulting message.
+func postOrUpdate(ctx context.Context, api *slack.Client, opts MessageOptions, channelID, previousTS, threadTS string, part messagePart) (string, string, error) {
+	if previousTS != "" && channelID != "" {
+		respChannel, ts, _, err := api.UpdateMessageContext(ctx, channelID, previousTS, part.options()...)
+		if err == nil {
+			return respChannel, ts, nil
 		}
+		// The old message may have been deleted, fall back to posting a new one
+		slog.Warn("Could not update message, posting a new one", "channel", channelID, "ts", previousTS, "error", err)
 	}
 
-	if channelID == "" {
-		return nil, fmt.Errorf("channel #%s not found", channelName)
+	msgOpts := append(part.options(), postOptions(opts)...)
+	if threadTS != "" {
+		msgOpts = append(msgOpts, slack.MsgOptionTS(threadTS))
 	}
 
-	// Get channel members
-	if debugMode {
-		log.Printf("Debug: Getting members for channel ID: %s", channelID)
-	}
+	return api.PostMessageContext(ctx, opts.Channel, msgOpts...)
+}
 
-	members, _, err := api.GetUsersInConversation(&slack.GetUsersInConversationParameters{
-		ChannelID: channelID,
-		Limit:     1000,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error fetching channel members: %v", err)
+// postOptions returns the message options shared by everything the reporter
+// posts. Link and media previews are disabled unless opts.UnfurlLinks is set,
+// so a long report doesn't turn into a wall of GitHub and JIRA cards.
+func postOptions(opts MessageOptions) []slack.MsgOption {
+	msgOpts := []slack.MsgOption{slack.MsgOptionAsUser(true)}
+	if !opts.UnfurlLinks {
+		msgOpts = append(msgOpts, slack.MsgOptionDisableLinkUnfurl(), slack.MsgOptionDisableMediaUnfurl())
 	}
+	return msgOpts
+}
 
-	if debugMode {
-		log.Printf("Debug: Found %d members in channel #%s", len(members), channelName)
-	}
+// messageKey identifies a report in the state store
+func messageKey(opts MessageOptions) string {
+	return fmt.Sprintf("%s|%s/%s", opts.Channel, opts.GithubOwner, opts.GithubRepo)
+}
 
-	return members, nil
+// withinUpdateWindow reports whether a report posted at postedAt should be
+// updated rather than reposted. Without a window, reports are updated for the
+// rest of the calendar day they were posted on. Live status messages are
+// always updated.
+func withinUpdateWindow(opts MessageOptions, postedAt time.Time) bool {
+	if opts.LiveStatus {
+		return true
+	}
+	if opts.UpdateWindow > 0 {
+		return time.Since(postedAt) < opts.UpdateWindow
+	}
+	return postedAt.Format("2006-01-02") == time.Now().Format("2006-01-02")
 }
 
 // MapGitHubUserToMention converts GitHub username to Slack mention format
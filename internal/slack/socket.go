@@ -0,0 +1,131 @@
+package slack
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// SocketOptions contains options for running the bot in Socket Mode
+type SocketOptions struct {
+	AppToken    string             // App-level token (xapp-...) with the connections:write scope
+	BotToken    string             // Slack bot token used to reply
+	APIURL      string             // Slack Web API URL, e.g. an egress proxy (default: https://slack.com/api/)
+	Interaction InteractionOptions // Options for recording button clicks (signing secret is not needed)
+}
+
+// MentionFunc answers an app mention and returns the reply text. text has the
+// bot mention removed.
+type MentionFunc func(text, channelID, userID string) (string, error)
+
+// mentionRegex matches user mentions such as "<@U123456>" in message text
+var mentionRegex = regexp.MustCompile(`<@[A-Z0-9]+>`)
+
+// RunSocketMode connects to Slack over Socket Mode and serves app mentions,
+// slash commands and report button clicks until the connection fails. No
+// public HTTP endpoint is needed.
+func RunSocketMode(opts SocketOptions, onMention MentionFunc, onCommand CommandFunc) error {
+	if opts.AppToken == "" {
+		return fmt.Errorf("Slack app token is required for Socket Mode")
+	}
+	if opts.BotToken == "" {
+		return fmt.Errorf("Slack token is required")
+	}
+
+	api := newClient(opts.APIURL, opts.BotToken, slack.OptionAppLevelToken(opts.AppToken))
+	client := socketmode.New(api)
+	interactions := newInteractionHandler(opts.Interaction)
+
+	go func() {
+		for event := range client.Events {
+			switch event.Type {
+			case socketmode.EventTypeConnecting:
+				slog.Info("Connecting to Slack with Socket Mode")
+			case socketmode.EventTypeConnectionError:
+				slog.Warn("Socket Mode connection failed, retrying")
+			case socketmode.EventTypeConnected:
+				slog.Info("Connected to Slack with Socket Mode")
+
+			case socketmode.EventTypeEventsAPI:
+				client.Ack(*event.Request)
+
+				eventsAPIEvent, ok := event.Data.(slackevents.EventsAPIEvent)
+				if !ok || eventsAPIEvent.Type != slackevents.CallbackEvent {
+					continue
+				}
+				if mention, ok := eventsAPIEvent.InnerEvent.Data.(*slackevents.AppMentionEvent); ok {
+					go handleMention(api, mention, onMention)
+				}
+
+			case socketmode.EventTypeSlashCommand:
+				s, ok := event.Data.(slack.SlashCommand)
+				if !ok {
+					continue
+				}
+				client.Ack(*event.Request, map[string]interface{}{
+					"response_type": slack.ResponseTypeEphemeral,
+					"text":          "⏳ Generating report...",
+				})
+
+				cmd := Command{
+					Name:        s.Command,
+					Text:        s.Text,
+					ChannelID:   s.ChannelID,
+					UserID:      s.UserID,
+					ResponseURL: s.ResponseURL,
+				}
+				go runCommand(cmd, onCommand)
+
+			case socketmode.EventTypeInteractive:
+				client.Ack(*event.Request)
+
+				callback, ok := event.Data.(slack.InteractionCallback)
+				if ok && callback.Type == slack.InteractionTypeBlockActions {
+					go interactions.handleCallback(callback)
+				}
+			}
+		}
+	}()
+
+	return client.Run()
+}
+
+// handleMention answers an app mention in a thread under the mention
+func handleMention(api *slack.Client, mention *slackevents.AppMentionEvent, onMention MentionFunc) {
+	// Ignore messages from bots, including our own replies
+	if mention.BotID != "" {
+		return
+	}
+
+	text := strings.TrimSpace(mentionRegex.ReplaceAllString(mention.Text, ""))
+
+	slog.Debug("Mentioned", "user", mention.User, "channel", mention.Channel, "text", text)
+
+	reply, err := onMention(text, mention.Channel, mention.User)
+	if err != nil {
+		slog.Warn("Could not answer mention", "text", text, "error", err)
+		reply = "❌ " + err.Error()
+	}
+	if reply == "" {
+		return
+	}
+
+	threadTS := mention.ThreadTimeStamp
+	if threadTS == "" {
+		threadTS = mention.TimeStamp
+	}
+
+	_, _, err = api.PostMessage(
+		mention.Channel,
+		slack.MsgOptionText(reply, false),
+		slack.MsgOptionTS(threadTS),
+	)
+	if err != nil {
+		slog.Warn("Could not reply to mention", "channel", mention.Channel, "error", err)
+	}
+}
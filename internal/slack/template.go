@@ -0,0 +1,165 @@
+package slack
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"pr-reporter/internal/model"
+	"pr-reporter/internal/state"
+)
+
+// TemplateData is the data available to the "header" and "footer" report
+// templates
+type TemplateData struct {
+	Title       string                // Report title (may be empty)
+	Date        string                // Report date (YYYY-MM-DD)
+	Previous    string                // Permalink of the report posted on an earlier day, empty when not linked
+	Total       int                   // Number of open PRs, including snoozed ones
+	AgeBuckets  []model.AgeBucket     // Open PRs by age range, nil when not shown
+	PRs         []TemplatePR          // Listed PRs, in report order
+	Blocked     []TemplatePR          // Blocked PRs (including blocked drafts)
+	Drafts      []TemplatePR          // Draft PRs that aren't blocked
+	Snoozed     []TemplatePR          // PRs hidden by the "Snooze" button
+	Text        model.Strings         // Translated report text for the configured locale
+	Anomalies   []model.Anomaly       // Counts that grew unusually, empty when none
+	Changes     *model.Changes        // What changed since the previous report, nil when not shown
+	AuthorStats []model.AuthorStats   // Per-author stats, nil when not shown
+	Turnaround  *model.Turnaround     // Average review turnaround, nil when not shown
+	MergeRate   *model.MergeRate      // Merged vs. opened PRs per day, nil when not shown
+	CycleTime   *model.CycleTime      // Previous month's cycle time, nil when not shown
+	Sprint      *model.SprintBurndown // Sprint burn-down, nil when not shown
+	LastSprint  *model.SprintSummary  // Summary of the sprint that just closed, nil when not shown
+	Weekly      *model.WeeklySummary  // Weekly summary, nil for daily reports
+	Monthly     *model.MonthlySummary // Monthly retrospective, nil for daily reports
+	Leaderboard *model.Leaderboard    // Reviewer leaderboard, nil when not shown
+	Labels      *model.LabelBreakdown // Open PRs of tracked labels, nil when not shown
+	ReviewLoad  []model.ReviewLoad    // Outstanding review requests per reviewer, nil when not shown
+	DataIssues  []string              // What couldn't be fetched for the report, nil when everything was
+	Mention     string                // Team or user mentions (e.g., "<!subteam^S123>"), or the people pinged by the targeted mention policy
+	Escalation  string                // PRs open long enough to be escalated with the lead mention, empty if none
+}
+
+// TemplatePR is the data available to the "pr" report template, which is
+// executed once per listed PR
+type TemplatePR struct {
+	Index        int      // 1-based position in the report
+	Number       int      // PR number
+	URL          string   // PR URL on GitHub
+	Link         string   // Slack link to the PR (e.g., "<https://...|PR-123>")
+	Title        string   // PR title
+	Assignee     string   // Slack mention or GitHub username, empty if unassigned
+	Author       string   // GitHub username of the author
+	JiraTicket   string   // JIRA ticket key, empty if none
+	JiraLink     string   // Slack link to the JIRA ticket, or "N/A"
+	JiraStatus   string   // JIRA status, "Unknown" if not available
+	StatusEmoji  string   // Emoji configured for the JIRA status, empty if none
+	PrevStatus   string   // JIRA status in the previous report when it changed since, empty otherwise
+	Description  string   // JIRA summary or PR title
+	IsDraft      bool     // PR is a draft
+	IsBlocked    bool     // JIRA ticket is blocked
+	Labels       []string // GitHub labels
+	Reviewers    []string // Reviewers with their review state
+	ChecksState  string   // "success", "failure", "pending" or ""
+	Ack          string   // Button action note (e.g., "👀 <@U123> is reviewing"), empty if none
+	StillBlocked string   // "still blocked (6d)" once the assignee isn't mentioned anymore, empty otherwise
+	Suggested    string   // Slack mention of the reviewer suggested round-robin, empty if none
+}
+
+// templateFuncs are the helper functions available in report templates
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// loadTemplates parses the report templates from opts.Template and
+// opts.TemplateFile. It returns nil when no template is configured.
+func loadTemplates(opts MessageOptions) (*template.Template, error) {
+	if opts.Template == "" && opts.TemplateFile == "" {
+		return nil, nil
+	}
+
+	tmpl := template.New("report").Funcs(templateFuncs)
+
+	if opts.TemplateFile != "" {
+		data, err := os.ReadFile(opts.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading template file %s: %w", opts.TemplateFile, err)
+		}
+		if _, err := tmpl.Parse(string(data)); err != nil {
+			return nil, fmt.Errorf("error parsing template file %s: %w", opts.TemplateFile, err)
+		}
+	}
+
+	// Inline templates are parsed last so they override definitions from the file
+	if opts.Template != "" {
+		if _, err := tmpl.Parse(opts.Template); err != nil {
+			return nil, fmt.Errorf("error parsing template: %w", err)
+		}
+	}
+
+	return tmpl, nil
+}
+
+// executeTemplate runs the named template and returns its output as lines.
+// The boolean result is false when the template doesn't define name.
+func executeTemplate(tmpl *template.Template, name string, data interface{}) ([]string, bool, error) {
+	if tmpl == nil || tmpl.Lookup(name) == nil {
+		return nil, false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, true, fmt.Errorf("error executing %q template: %w", name, err)
+	}
+
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n"), true, nil
+}
+
+// newTemplatePR builds the template data of a single PR as of now
+func newTemplatePR(opts MessageOptions, emoji Emoji, text model.Strings, index int, pr *PRInfo, ack *state.Ack, now time.Time) TemplatePR {
+	jiraLink := pr.JiraTicket
+	if url := ticketURL(opts, pr); url != "" {
+		jiraLink = fmt.Sprintf("<%s|%s>", url, pr.JiraTicket)
+	} else if pr.JiraTicket == "" {
+		jiraLink = "N/A"
+	}
+
+	jiraStatus := pr.JiraStatus
+	if jiraStatus == "" {
+		jiraStatus = text.UnknownStatus
+	}
+
+	ackText := ""
+	if ack != nil {
+		ackText = strings.TrimSpace(ackNote(ack))
+	}
+
+	return TemplatePR{
+		Index:        index,
+		Number:       pr.Number,
+		URL:          prURL(opts, pr),
+		Link:         prLink(opts, pr),
+		Title:        pr.Title,
+		Assignee:     pr.Assignee,
+		Author:       pr.Author,
+		JiraTicket:   pr.JiraTicket,
+		JiraLink:     jiraLink,
+		JiraStatus:   jiraStatus,
+		StatusEmoji:  emoji.statusEmoji(jiraStatus),
+		PrevStatus:   pr.PreviousStatus,
+		Description:  pr.Description,
+		IsDraft:      pr.IsDraft,
+		IsBlocked:    pr.IsBlocked,
+		Labels:       pr.Labels,
+		Reviewers:    pr.Reviewers,
+		ChecksState:  pr.ChecksState,
+		Ack:          ackText,
+		StillBlocked: stillBlocked(text, pr, now),
+		Suggested:    pr.SuggestedReviewer,
+	}
+}
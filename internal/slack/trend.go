@@ -0,0 +1,49 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/chart"
+)
+
+// uploadTrend uploads a chart of the open PR count over time in the thread of
+// the posted report
+func uploadTrend(ctx context.Context, api *slack.Client, opts MessageOptions, channelID, threadTS string) error {
+	data, err := chart.TrendPNG(opts.Trend, chart.DefaultWidth, chart.DefaultHeight)
+	if err != nil {
+		return err
+	}
+
+	first, last := opts.Trend[0], opts.Trend[len(opts.Trend)-1]
+	lowest, highest := first.Value, first.Value
+	for _, point := range opts.Trend {
+		if point.Value < lowest {
+			lowest = point.Value
+		}
+		if point.Value > highest {
+			highest = point.Value
+		}
+	}
+	days := int(last.Time.Sub(first.Time).Hours()/24 + 0.5)
+	title := fmt.Sprintf("Open PRs over the last %d days: %d → %d (min %d, max %d)", days, first.Value, last.Value, lowest, highest)
+
+	_, err = api.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+		Reader:          bytes.NewReader(data),
+		FileSize:        len(data),
+		Filename:        "open-prs.png",
+		Title:           title,
+		Channel:         channelID,
+		ThreadTimestamp: threadTS,
+	})
+	if err != nil {
+		return fmt.Errorf("error uploading trend chart: %w", err)
+	}
+
+	slog.Debug("Uploaded trend chart", "points", len(opts.Trend), "thread", threadTS)
+
+	return nil
+}
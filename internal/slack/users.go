@@ -0,0 +1,243 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/state"
+)
+
+// SlackUser is a member of the Slack workspace
+type SlackUser struct {
+	ID    string
+	Name  string // Display name, or real name when no display name is set
+	Email string // Empty without the users:read.email scope
+}
+
+// DefaultUserCacheTTL is how long Slack user profiles are cached by default
+const DefaultUserCacheTTL = 24 * time.Hour
+
+// GetSlackChannelUsers fetches the members of a channel with their names and
+// emails. Bots and deactivated accounts are left out. Profiles are cached in
+// cacheFile for cacheTTL (0: DefaultUserCacheTTL), so later runs only fetch
+// new members; without cacheFile or with a negative cacheTTL every profile is
+// fetched. apiURL replaces the Slack Web API URL when set. It stops with the
+// context's error once ctx is done.
+func GetSlackChannelUsers(ctx context.Context, apiURL, token, channel, cacheFile string, cacheTTL time.Duration) ([]SlackUser, error) {
+	memberIDs, err := GetChannelUsers(ctx, apiURL, token, channel, cacheFile)
+	if err != nil {
+		return nil, err
+	}
+
+	api := newClient(apiURL, token)
+	cache := loadUserCache(cacheFile, cacheTTL)
+	defer cache.save()
+
+	var users []SlackUser
+	fetched := 0
+	for _, userID := range memberIDs {
+		user, cached := cache.get(userID)
+		if !cached {
+			info, err := api.GetUserInfoContext(ctx, userID)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if err != nil {
+				slog.Warn("Could not fetch Slack user", "user", userID, "error", err)
+				continue
+			}
+			user = cache.put(info)
+			fetched++
+		}
+		if user.Inactive {
+			continue
+		}
+
+		users = append(users, SlackUser{
+			ID:    userID,
+			Name:  user.Name,
+			Email: user.Email,
+		})
+	}
+
+	slog.Debug("Fetched member profiles", "channel", channel, "members", len(users), "fetched", fetched, "cached", len(memberIDs)-fetched)
+
+	return users, nil
+}
+
+// userCache holds the Slack user profiles cached in the state file
+type userCache struct {
+	store   *state.Store // nil when caching is off
+	ttl     time.Duration
+	now     time.Time
+	changed bool
+}
+
+// loadUserCache loads the user profiles cached in cacheFile. The cache is
+// off, fetching every profile, without cacheFile, with a negative ttl or when
+// the state can't be loaded.
+func loadUserCache(cacheFile string, ttl time.Duration) *userCache {
+	if ttl == 0 {
+		ttl = DefaultUserCacheTTL
+	}
+	cache := &userCache{ttl: ttl, now: time.Now()}
+	if cacheFile == "" || ttl < 0 {
+		return cache
+	}
+
+	store, err := state.Load(cacheFile)
+	if err != nil {
+		slog.Warn("Could not load user cache", "error", err)
+		return cache
+	}
+	cache.store = store
+	return cache
+}
+
+// get returns the cached profile of a user, unless it expired
+func (c *userCache) get(userID string) (*state.User, bool) {
+	if c.store == nil {
+		return nil, false
+	}
+	user, exists := c.store.Users[userID]
+	if !exists || !user.Fresh(c.now, c.ttl) {
+		return nil, false
+	}
+	return user, true
+}
+
+// byEmail returns the ID of the active user with an email address, if cached
+func (c *userCache) byEmail(email string) (string, bool) {
+	if c.store == nil {
+		return "", false
+	}
+	for userID, user := range c.store.Users {
+		if user.Email == email && !user.Inactive && user.Fresh(c.now, c.ttl) {
+			return userID, true
+		}
+	}
+	return "", false
+}
+
+// put caches the profile of a user fetched from Slack and returns it
+func (c *userCache) put(info *slack.User) *state.User {
+	name := info.Profile.DisplayName
+	if name == "" {
+		name = info.RealName
+	}
+	user := &state.User{
+		Name:      name,
+		Email:     strings.ToLower(info.Profile.Email),
+		Inactive:  info.IsBot || info.Deleted,
+		FetchedAt: c.now,
+	}
+
+	if c.store != nil {
+		c.store.Users[info.ID] = user
+		c.changed = true
+	}
+	return user
+}
+
+// save drops expired profiles and saves the cache when it changed
+func (c *userCache) save() {
+	if c.store == nil || !c.changed {
+		return
+	}
+	for userID, user := range c.store.Users {
+		if !user.Fresh(c.now, c.ttl) {
+			delete(c.store.Users, userID)
+		}
+	}
+	if err := c.store.Save(); err != nil {
+		slog.Warn("Could not save user cache", "error", err)
+	}
+}
+
+// ReportChannelUsers fetches the members of the report channel (the first one
+// when posting to several), or none when the report has no channel. Lookups
+// are cached in the report's state file.
+func ReportChannelUsers(ctx context.Context, opts MessageOptions) ([]SlackUser, error) {
+	channel := opts.Channel
+	if channel == "" && len(opts.Channels) > 0 {
+		channel = opts.Channels[0].Channel
+	}
+	if channel == "" || opts.Token == "" {
+		return nil, nil
+	}
+	return GetSlackChannelUsers(ctx, opts.APIURL, opts.Token, channel, stateFile(opts), opts.UserCacheTTL)
+}
+
+// stateFile returns the state file of a report
+func stateFile(opts MessageOptions) string {
+	if opts.StateFile == "" {
+		return state.DefaultPath
+	}
+	return opts.StateFile
+}
+
+// MapUsersByEmail matches GitHub users to Slack users by email address.
+// emails maps GitHub usernames to their candidate addresses. members (see
+// ReportChannelUsers) are matched first, then users cached in the state file;
+// remaining addresses are looked up with users.lookupByEmail and cached. It
+// returns a GitHub username -> Slack user ID map.
+func MapUsersByEmail(opts MessageOptions, emails map[string][]string, members []SlackUser) (map[string]string, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("Slack token is required")
+	}
+
+	mapping := make(map[string]string)
+	if len(emails) == 0 {
+		return mapping, nil
+	}
+
+	// Index channel members by email so most users need no extra API call
+	byEmail := make(map[string]string)
+	for _, member := range members {
+		if member.Email != "" {
+			byEmail[member.Email] = member.ID
+		}
+	}
+
+	api := newClient(opts.APIURL, opts.Token)
+	cache := loadUserCache(stateFile(opts), opts.UserCacheTTL)
+	defer cache.save()
+
+	// Process users in a stable order so logs are easy to follow
+	logins := make([]string, 0, len(emails))
+	for login := range emails {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+
+	for _, login := range logins {
+		for _, email := range emails[login] {
+			email = strings.ToLower(email)
+
+			userID, exists := byEmail[email]
+			if !exists {
+				userID, exists = cache.byEmail(email)
+			}
+			if !exists {
+				user, err := api.GetUserByEmail(email)
+				if err != nil {
+					slog.Debug("No Slack user with email", "email", email, "login", login, "error", err)
+					continue
+				}
+				cache.put(user)
+				userID = user.ID
+			}
+
+			mapping[login] = userID
+			slog.Debug("Mapped GitHub user to Slack user by email", "login", login, "user", userID, "email", email)
+			break
+		}
+	}
+
+	return mapping, nil
+}
@@ -0,0 +1,86 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/slack-go/slack"
+	"pr-reporter/internal/errkind"
+	"pr-reporter/internal/httpx"
+)
+
+// webhookPayload is a message sent to an incoming webhook. It is built here
+// because slack.WebhookMessage has no unfurl settings.
+type webhookPayload struct {
+	Text        string        `json:"text"`
+	Blocks      []slack.Block `json:"blocks,omitempty"`
+	UnfurlLinks bool          `json:"unfurl_links"`
+	UnfurlMedia bool          `json:"unfurl_media"`
+}
+
+// sendWebhook posts the report through opts.WebhookURL. Incoming webhooks
+// always post to the channel chosen when the webhook was created and return no
+// message timestamp, so reports can't be threaded, updated or interactive.
+func sendWebhook(ctx context.Context, opts MessageOptions, prs []*PRInfo) error {
+	if opts.GithubOwner == "" || opts.GithubRepo == "" {
+		return errkind.Configf("GitHub owner and repo are required")
+	}
+	if opts.SplitThread || opts.ThreadDetail || opts.UpdateExisting || opts.LiveStatus || opts.Interactive || opts.ExportFormat != "" || len(opts.Trend) > 1 {
+		slog.Warn("Threads, updates, buttons, exports and charts aren't supported with an incoming webhook, posting a plain report")
+	}
+
+	render := renderTextParts
+	if opts.MessageFormat == MessageFormatV2 {
+		render = renderBlockParts
+	}
+	parts, err := render(opts, prs)
+	if err != nil {
+		return err
+	}
+
+	for i, part := range parts {
+		payload := webhookPayload{
+			Text:        part.text,
+			Blocks:      part.blocks,
+			UnfurlLinks: opts.UnfurlLinks,
+			UnfurlMedia: opts.UnfurlLinks,
+		}
+		if err := postWebhook(ctx, timeoutClient(opts.Timeout), opts.WebhookURL, payload); err != nil {
+			return fmt.Errorf("error posting message part %d/%d to webhook: %w", i+1, len(parts), err)
+		}
+
+		slog.Debug("Sent report part to webhook", "part", i+1, "parts", len(parts), "characters", len(part.text))
+	}
+
+	return nil
+}
+
+// postWebhook sends a single message to an incoming webhook
+func postWebhook(ctx context.Context, client *http.Client, url string, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return httpx.NewStatusError("Slack webhook", resp)
+	}
+
+	return nil
+}
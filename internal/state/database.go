@@ -0,0 +1,220 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"pr-reporter/internal/store"
+)
+
+// LegacyPath is the default location of the JSON state file of older
+// versions, imported into the default database on first use
+const LegacyPath = ".pr-reporter-state.json"
+
+// loadDatabase reads the state from the database at path (see store.Open)
+func loadDatabase(path string) (*Store, error) {
+	var legacy *Store
+	if path == DefaultPath {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if _, err := os.Stat(LegacyPath); err == nil {
+				if legacy, err = loadFile(LegacyPath); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	// Saves only write changes, so the legacy state is imported right away
+	if legacy != nil {
+		kinds, err := legacy.encode()
+		if err != nil {
+			return nil, err
+		}
+		if err := db.UpdateValues(kinds, nil); err != nil {
+			return nil, fmt.Errorf("error importing %s: %w", LegacyPath, err)
+		}
+		legacy.path = path
+		return legacy, nil
+	}
+
+	s := newStore(path)
+	for kind, target := range s.kinds() {
+		values, err := db.Values(kind)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			if err := target.set(key, value); err != nil {
+				return nil, fmt.Errorf("error parsing %s %q in %s: %w", kind, key, store.Redacted(path), err)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// saveDatabase writes changed values to the database at the store's path and
+// removes the removed ones, leaving the keys other runs saved in the meantime
+func (s *Store) saveDatabase(set map[string]map[string][]byte, remove map[string][]string) error {
+	if len(set) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	db, err := store.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.UpdateValues(set, remove)
+}
+
+// encode returns the encoded values of the store by kind
+func (s *Store) encode() (map[string]map[string][]byte, error) {
+	kinds := make(map[string]map[string][]byte)
+	for kind, source := range s.kinds() {
+		values, err := source.values()
+		if err != nil {
+			return nil, fmt.Errorf("error encoding %s: %w", kind, err)
+		}
+		kinds[kind] = values
+	}
+	return kinds, nil
+}
+
+// stateMap gives uniform access to one of the maps of a Store
+type stateMap struct {
+	set    func(key string, value []byte) error
+	remove func(key string)
+	values func() (map[string][]byte, error)
+}
+
+// kinds returns the maps of the store keyed by their kind in the database
+func (s *Store) kinds() map[string]stateMap {
+	return map[string]stateMap{
+		"messages": {
+			set: func(key string, value []byte) error {
+				var message Message
+				s.Messages[key] = &message
+				return json.Unmarshal(value, &message)
+			},
+			remove: func(key string) { delete(s.Messages, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.Messages) },
+		},
+		"acks": {
+			set: func(key string, value []byte) error {
+				var ack Ack
+				s.Acks[key] = &ack
+				return json.Unmarshal(value, &ack)
+			},
+			remove: func(key string) { delete(s.Acks, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.Acks) },
+		},
+		"channels": {
+			set: func(key string, value []byte) error {
+				s.Channels[key] = string(value)
+				return nil
+			},
+			remove: func(key string) { delete(s.Channels, key) },
+			values: func() (map[string][]byte, error) {
+				values := make(map[string][]byte)
+				for key, id := range s.Channels {
+					values[key] = []byte(id)
+				}
+				return values, nil
+			},
+		},
+		"previews": {
+			set: func(key string, value []byte) error {
+				var preview Preview
+				s.Previews[key] = &preview
+				return json.Unmarshal(value, &preview)
+			},
+			remove: func(key string) { delete(s.Previews, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.Previews) },
+		},
+		"sla": {
+			set: func(key string, value []byte) error {
+				var record SLARecord
+				s.SLA[key] = &record
+				return json.Unmarshal(value, &record)
+			},
+			remove: func(key string) { delete(s.SLA, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.SLA) },
+		},
+		"blocked": {
+			set: func(key string, value []byte) error {
+				var streak BlockedStreak
+				s.Blocked[key] = &streak
+				return json.Unmarshal(value, &streak)
+			},
+			remove: func(key string) { delete(s.Blocked, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.Blocked) },
+		},
+		"paused": {
+			set: func(key string, value []byte) error {
+				var pause Pause
+				s.Paused[key] = &pause
+				return json.Unmarshal(value, &pause)
+			},
+			remove: func(key string) { delete(s.Paused, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.Paused) },
+		},
+		"users": {
+			set: func(key string, value []byte) error {
+				var user User
+				s.Users[key] = &user
+				return json.Unmarshal(value, &user)
+			},
+			remove: func(key string) { delete(s.Users, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.Users) },
+		},
+		"rotation": {
+			set: func(key string, value []byte) error {
+				var rotation Rotation
+				s.Rotation[key] = &rotation
+				return json.Unmarshal(value, &rotation)
+			},
+			remove: func(key string) { delete(s.Rotation, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.Rotation) },
+		},
+		"opt_outs": {
+			set: func(key string, value []byte) error {
+				var optOut OptOut
+				s.OptOuts[key] = &optOut
+				return json.Unmarshal(value, &optOut)
+			},
+			remove: func(key string) { delete(s.OptOuts, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.OptOuts) },
+		},
+		"reminded": {
+			set: func(key string, value []byte) error {
+				var reminder Reminder
+				s.Reminded[key] = &reminder
+				return json.Unmarshal(value, &reminder)
+			},
+			remove: func(key string) { delete(s.Reminded, key) },
+			values: func() (map[string][]byte, error) { return encodeValues(s.Reminded) },
+		},
+	}
+}
+
+// encodeValues encodes each value of a map as JSON
+func encodeValues[T any](m map[string]*T) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	for key, value := range m {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = data
+	}
+	return values, nil
+}
@@ -0,0 +1,361 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"pr-reporter/internal/store"
+)
+
+// DefaultPath is the default location of the state. Paths ending in .db,
+// .sqlite or .sqlite3 are SQLite databases, postgres:// and redis:// URLs
+// Postgres and Redis databases, others JSON files.
+const DefaultPath = ".pr-reporter.db"
+
+// Message records a report that was posted to Slack so later runs can update it
+type Message struct {
+	ChannelID string    `json:"channel_id"`        // Slack channel ID returned when posting
+	Parts     []string  `json:"parts"`             // Timestamps of the message parts, in order
+	Replies   []string  `json:"replies,omitempty"` // Timestamps of threaded detail replies
+	PostedAt  time.Time `json:"posted_at"`         // When the report was first posted
+
+	Permalink         string `json:"permalink,omitempty"`          // Slack permalink of the first part
+	PreviousPermalink string `json:"previous_permalink,omitempty"` // Permalink of the report posted on an earlier day
+}
+
+// LastPermalink returns the permalink of the latest report posted before the
+// day of now: the message itself when it was posted on an earlier day,
+// otherwise the report it replaced
+func (m *Message) LastPermalink(now time.Time) string {
+	if m.PostedAt.Format("2006-01-02") != now.Format("2006-01-02") && m.Permalink != "" {
+		return m.Permalink
+	}
+	return m.PreviousPermalink
+}
+
+// Actions users can take on a PR from the report buttons
+const (
+	ActionReviewing = "reviewing"
+	ActionSnooze    = "snooze"
+	ActionNotMine   = "not_mine"
+)
+
+// Ack records a user's response to a PR in the report
+type Ack struct {
+	Action string    `json:"action"`          // One of the Action* constants
+	UserID string    `json:"user_id"`         // Slack user ID of the user who clicked (empty for command line snoozes)
+	At     time.Time `json:"at"`              // When the action was taken
+	Until  time.Time `json:"until,omitempty"` // When a snooze expires
+}
+
+// Active reports whether the acknowledgment still applies at the given time
+func (a *Ack) Active(now time.Time) bool {
+	if a.Action == ActionSnooze {
+		return now.Before(a.Until)
+	}
+	return true
+}
+
+// Preview records a report waiting for approval before it is posted
+type Preview struct {
+	Report    string          `json:"report"`     // Report name (e.g., "frontend")
+	UserID    string          `json:"user_id"`    // Slack user ID of the approver
+	PRs       json.RawMessage `json:"prs"`        // PRs of the previewed report, posted as-is when approved
+	CreatedAt time.Time       `json:"created_at"` // When the preview was sent
+}
+
+// SLARecord tracks a review step of a PR against its SLA
+type SLARecord struct {
+	Report    string    `json:"report"`               // Report name (e.g., "frontend")
+	Step      string    `json:"step"`                 // Review step (e.g., "first_review")
+	Deadline  time.Time `json:"deadline"`             // When the SLA is breached
+	MetAt     time.Time `json:"met_at,omitempty"`     // When the step was completed
+	AlertedAt time.Time `json:"alerted_at,omitempty"` // When a breach alert was posted
+}
+
+// Breached reports whether the step missed its deadline by the given time
+func (r *SLARecord) Breached(now time.Time) bool {
+	if r.MetAt.IsZero() {
+		return now.After(r.Deadline)
+	}
+	return r.MetAt.After(r.Deadline)
+}
+
+// SLAKey identifies a review step of a PR
+func SLAKey(prKey, step string) string {
+	return prKey + "|" + step
+}
+
+// BlockedStreak counts the consecutive days a PR was listed as blocked
+type BlockedStreak struct {
+	Report     string    `json:"report"`      // Report name (e.g., "frontend")
+	Since      time.Time `json:"since"`       // First report of the streak
+	LastReport time.Time `json:"last_report"` // Latest report of the streak
+	Reports    int       `json:"reports"`     // Report days in the streak; reruns on the same day count once
+}
+
+// Rotation tracks the round-robin reviewer suggestions of a report
+type Rotation struct {
+	Last      string            `json:"last,omitempty"`      // Slack user ID of the latest suggested reviewer
+	Suggested map[string]string `json:"suggested,omitempty"` // Suggested reviewer Slack user IDs keyed by PRKey
+}
+
+// OptOut records a user turning off review reminders
+type OptOut struct {
+	At time.Time `json:"at"` // When the user opted out
+}
+
+// Reminder records a reviewer being reminded of a PR
+type Reminder struct {
+	At time.Time `json:"at"` // When the reviewer was last reminded
+}
+
+// ReminderKey identifies a reviewer of a PR
+func ReminderKey(prKey, userID string) string {
+	return prKey + "|" + userID
+}
+
+// Pause stops a report from running until it is resumed or expires
+type Pause struct {
+	At    time.Time `json:"at"`              // When the report was paused
+	Until time.Time `json:"until,omitempty"` // When the pause expires (zero: when resumed)
+	By    string    `json:"by,omitempty"`    // Who paused the report
+}
+
+// Active reports whether the pause still applies at the given time
+func (p *Pause) Active(now time.Time) bool {
+	return p.Until.IsZero() || now.Before(p.Until)
+}
+
+// User is a cached Slack user profile
+type User struct {
+	Name      string    `json:"name,omitempty"`     // Display name, or real name when no display name is set
+	Email     string    `json:"email,omitempty"`    // Lowercase email, empty without the users:read.email scope
+	Inactive  bool      `json:"inactive,omitempty"` // Bot or deactivated account
+	FetchedAt time.Time `json:"fetched_at"`         // When the profile was fetched from Slack
+}
+
+// Fresh reports whether the profile was fetched less than ttl before now
+func (u *User) Fresh(now time.Time, ttl time.Duration) bool {
+	return now.Sub(u.FetchedAt) < ttl
+}
+
+// Store holds all state persisted between report runs
+type Store struct {
+	Messages map[string]*Message       `json:"messages"`           // Posted reports keyed by report (channel + repo)
+	Acks     map[string]*Ack           `json:"acks,omitempty"`     // Acknowledgments keyed by PRKey
+	Channels map[string]string         `json:"channels,omitempty"` // Cached Slack channel name -> ID lookups
+	Previews map[string]*Preview       `json:"previews,omitempty"` // Reports waiting for approval keyed by preview ID
+	SLA      map[string]*SLARecord     `json:"sla,omitempty"`      // Review SLA tracking keyed by SLAKey
+	Blocked  map[string]*BlockedStreak `json:"blocked,omitempty"`  // Blocked streaks keyed by PRKey
+	Paused   map[string]*Pause         `json:"paused,omitempty"`   // Paused reports keyed by report name
+	Users    map[string]*User          `json:"users,omitempty"`    // Cached Slack user profiles keyed by user ID
+	Rotation map[string]*Rotation      `json:"rotation,omitempty"` // Reviewer suggestions keyed by report name
+	OptOuts  map[string]*OptOut        `json:"opt_outs,omitempty"` // Users who turned off review reminders keyed by Slack user ID
+	Reminded map[string]*Reminder      `json:"reminded,omitempty"` // Review reminders sent keyed by ReminderKey
+
+	path   string
+	loaded map[string]map[string][]byte // Encoded values by kind as last loaded or saved, to tell what changed
+}
+
+// saveMu serializes saves to state files within the process
+var saveMu sync.Mutex
+
+// Load reads the state at path. A missing file results in an empty store.
+func Load(path string) (*Store, error) {
+	var s *Store
+	var err error
+	if store.IsDatabase(path) {
+		s, err = loadDatabase(path)
+	} else {
+		s, err = loadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.loaded, err = s.encode(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// newStore returns an empty store saved to path
+func newStore(path string) *Store {
+	return &Store{
+		Messages: make(map[string]*Message),
+		Acks:     make(map[string]*Ack),
+		Channels: make(map[string]string),
+		Previews: make(map[string]*Preview),
+		SLA:      make(map[string]*SLARecord),
+		Blocked:  make(map[string]*BlockedStreak),
+		Paused:   make(map[string]*Pause),
+		Users:    make(map[string]*User),
+		Rotation: make(map[string]*Rotation),
+		OptOuts:  make(map[string]*OptOut),
+		Reminded: make(map[string]*Reminder),
+		path:     path,
+	}
+}
+
+// loadFile reads the JSON state file at path
+func loadFile(path string) (*Store, error) {
+	store := newStore(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("error reading state file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("error parsing state file %s: %w", path, err)
+	}
+
+	if store.Messages == nil {
+		store.Messages = make(map[string]*Message)
+	}
+	if store.Acks == nil {
+		store.Acks = make(map[string]*Ack)
+	}
+	if store.Channels == nil {
+		store.Channels = make(map[string]string)
+	}
+	if store.Previews == nil {
+		store.Previews = make(map[string]*Preview)
+	}
+	if store.SLA == nil {
+		store.SLA = make(map[string]*SLARecord)
+	}
+	if store.Blocked == nil {
+		store.Blocked = make(map[string]*BlockedStreak)
+	}
+	if store.Paused == nil {
+		store.Paused = make(map[string]*Pause)
+	}
+	if store.Users == nil {
+		store.Users = make(map[string]*User)
+	}
+	if store.Rotation == nil {
+		store.Rotation = make(map[string]*Rotation)
+	}
+	if store.OptOuts == nil {
+		store.OptOuts = make(map[string]*OptOut)
+	}
+	if store.Reminded == nil {
+		store.Reminded = make(map[string]*Reminder)
+	}
+
+	return store, nil
+}
+
+// Save writes the changes made to the store since it was loaded back to its
+// database or file. Only the keys that changed are written, so reports and
+// replicas running at the same time don't overwrite each other's changes.
+func (s *Store) Save() error {
+	current, err := s.encode()
+	if err != nil {
+		return err
+	}
+	set, remove := changes(s.loaded, current)
+
+	if store.IsDatabase(s.path) {
+		err = s.saveDatabase(set, remove)
+	} else {
+		err = s.saveFile(set, remove)
+	}
+	if err != nil {
+		return err
+	}
+	s.loaded = current
+	return nil
+}
+
+// changes returns the encoded values of each kind that were added or changed
+// between loaded and current, and the keys that were removed
+func changes(loaded, current map[string]map[string][]byte) (map[string]map[string][]byte, map[string][]string) {
+	set := make(map[string]map[string][]byte)
+	remove := make(map[string][]string)
+	for kind, values := range current {
+		previous := loaded[kind]
+		for key, value := range values {
+			if old, exists := previous[key]; !exists || !bytes.Equal(old, value) {
+				if set[kind] == nil {
+					set[kind] = make(map[string][]byte)
+				}
+				set[kind][key] = value
+			}
+		}
+		for key := range previous {
+			if _, exists := values[key]; !exists {
+				remove[kind] = append(remove[kind], key)
+			}
+		}
+	}
+	return set, remove
+}
+
+// saveFile applies changes to the latest state in the file at the store's
+// path. saveMu keeps saves within the process from overwriting each other's
+// changes; the file is meant for a single process.
+func (s *Store) saveFile(set map[string]map[string][]byte, remove map[string][]string) error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+
+	latest, err := loadFile(s.path)
+	if err != nil {
+		return err
+	}
+	targets := latest.kinds()
+	for kind, values := range set {
+		for key, value := range values {
+			if err := targets[kind].set(key, value); err != nil {
+				return fmt.Errorf("error merging %s %q: %w", kind, key, err)
+			}
+		}
+	}
+	for kind, keys := range remove {
+		for _, key := range keys {
+			targets[kind].remove(key)
+		}
+	}
+
+	return latest.writeFile()
+}
+
+// writeFile replaces the file at the store's path with the store atomically
+func (s *Store) writeFile() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding state: %w", err)
+	}
+
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("error creating state directory %s: %w", dir, err)
+		}
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("error writing state file %s: %w", tmp, err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("error replacing state file %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+// PRKey identifies a PR across repositories
+func PRKey(owner, repo string, number int) string {
+	return fmt.Sprintf("%s/%s#%d", owner, repo, number)
+}
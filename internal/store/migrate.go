@@ -0,0 +1,157 @@
+package store
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the versioned schema migrations of each SQL dialect,
+// named "<version>_<name>.sql" (e.g., "0002_merges.sql"). Migrations are
+// never edited once released; schema changes add a new file for every
+// dialect.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationsTable records the applied migrations
+const migrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    INTEGER PRIMARY KEY,
+	name       TEXT NOT NULL,
+	applied_at BIGINT NOT NULL
+);
+`
+
+// migration is one versioned schema change
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// migrations returns the migrations of a dialect ("sqlite" or "postgres"),
+// in version order
+func migrations(dialect string) ([]migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := migrationFiles.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s migrations: %w", dialect, err)
+	}
+
+	var result []migration
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		content, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", entry.Name(), err)
+		}
+		result = append(result, migration{version: version, name: name, sql: string(content)})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].version < result[j].version })
+	for i := 1; i < len(result); i++ {
+		if result[i].version == result[i-1].version {
+			return nil, fmt.Errorf("duplicate migration version %d", result[i].version)
+		}
+	}
+	return result, nil
+}
+
+// migrate applies the migrations the database hasn't applied yet, each in its
+// own transaction. A migration is claimed by recording its version first, so
+// reporters and servers starting at the same time apply it only once.
+func (s *sqlStore) migrate() error {
+	dialect := "sqlite"
+	if s.postgres {
+		dialect = "postgres"
+	}
+	all, err := migrations(dialect)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec(migrationsTable); err != nil {
+		return fmt.Errorf("error creating the migrations table: %w", err)
+	}
+
+	applied, err := s.schemaVersions()
+	if err != nil {
+		return err
+	}
+	if len(all) > 0 {
+		latest := all[len(all)-1].version
+		for version := range applied {
+			if version > latest {
+				return fmt.Errorf("database schema version %d is newer than this build supports (%d), upgrade pr-reporter", version, latest)
+			}
+		}
+	}
+
+	for _, m := range all {
+		if applied[m.version] {
+			continue
+		}
+		if err := s.apply(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaVersions returns the versions of the applied migrations
+func (s *sqlStore) schemaVersions() (map[int]bool, error) {
+	rows, err := s.db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("error reading applied migrations: %w", err)
+		}
+		versions[version] = true
+	}
+	return versions, rows.Err()
+}
+
+// apply runs a migration and records it in one transaction, unless another
+// process recorded it in the meantime
+func (s *sqlStore) apply(m migration) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting migration %s: %w", m.name, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(s.rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?) ON CONFLICT (version) DO NOTHING"),
+		m.version, m.name, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("error recording migration %s: %w", m.name, err)
+	}
+	if claimed, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("error recording migration %s: %w", m.name, err)
+	} else if claimed == 0 {
+		return nil // Applied by another process
+	}
+
+	if _, err := tx.Exec(m.sql); err != nil {
+		return fmt.Errorf("error applying migration %s: %w", m.name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error applying migration %s: %w", m.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+-- Merged PRs for monthly cycle times.
+CREATE TABLE IF NOT EXISTS merges (
+	report    TEXT NOT NULL,
+	number    INTEGER NOT NULL,
+	opened_at BIGINT NOT NULL,
+	merged_at BIGINT NOT NULL,
+	PRIMARY KEY (report, number)
+);
+CREATE INDEX IF NOT EXISTS merges_report_merged_at ON merges (report, merged_at);
@@ -0,0 +1,20 @@
+-- Keyed state values and snapshots of the PRs of every report run.
+-- IF NOT EXISTS keeps databases created before migrations were versioned working.
+CREATE TABLE IF NOT EXISTS state (
+	kind  TEXT NOT NULL,
+	key   TEXT NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY (kind, key)
+);
+CREATE TABLE IF NOT EXISTS snapshots (
+	id       INTEGER PRIMARY KEY AUTOINCREMENT,
+	report   TEXT NOT NULL,
+	taken_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS snapshots_report_taken_at ON snapshots (report, taken_at);
+CREATE TABLE IF NOT EXISTS snapshot_prs (
+	snapshot_id INTEGER NOT NULL REFERENCES snapshots (id) ON DELETE CASCADE,
+	number      INTEGER NOT NULL,
+	pr          TEXT NOT NULL,
+	PRIMARY KEY (snapshot_id, number)
+);
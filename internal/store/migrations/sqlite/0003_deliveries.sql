@@ -0,0 +1,11 @@
+-- Audit log of every report delivery to an output.
+CREATE TABLE deliveries (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	report       TEXT NOT NULL,
+	output       TEXT NOT NULL,
+	target       TEXT NOT NULL,
+	sent_at      INTEGER NOT NULL,
+	payload_hash TEXT NOT NULL,
+	error        TEXT NOT NULL
+);
+CREATE INDEX deliveries_sent_at ON deliveries (sent_at);
@@ -0,0 +1,3 @@
+-- Snapshot times in Unix milliseconds instead of seconds, so runs within the
+-- same second stay in order and before each other.
+UPDATE snapshots SET taken_at = taken_at * 1000;
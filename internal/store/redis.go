@@ -0,0 +1,427 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"pr-reporter/internal/model"
+)
+
+// redisPrefix namespaces the keys of the state in Redis
+const redisPrefix = "pr-reporter:"
+
+// redisStore keeps the state in Redis:
+//   - state values in a hash per kind
+//   - snapshots as JSON in a sorted set per report, scored by time taken in
+//     Unix milliseconds
+//   - merges as JSON in a hash per report, indexed by a sorted set scored by
+//     merge time
+//   - deliveries as JSON in one sorted set, scored by time sent
+type redisStore struct {
+	client *redis.Client
+}
+
+// redisSnapshot is a snapshot as stored in Redis; its score is when it was
+// taken, and the ID keeps snapshots taken at the same millisecond apart and in
+// order
+type redisSnapshot struct {
+	ID  int64       `json:"id"`
+	PRs []*model.PR `json:"prs"`
+}
+
+// snapshotMillisKey marks that the snapshot scores are in milliseconds; older
+// versions scored snapshots in seconds
+const snapshotMillisKey = redisPrefix + "snapshot-millis"
+
+// maxSecondsScore is the highest snapshot score that is in seconds rather than
+// milliseconds (the year 5138 in seconds, 1973 in milliseconds)
+const maxSecondsScore = 1e11
+
+// redisMerge is a merge as stored in Redis
+type redisMerge struct {
+	OpenedAt int64 `json:"opened_at"`
+	MergedAt int64 `json:"merged_at"`
+}
+
+// redisDelivery is a delivery as stored in Redis; the ID keeps identical
+// deliveries at the same second apart
+type redisDelivery struct {
+	ID          int64  `json:"id"`
+	Report      string `json:"report"`
+	Output      string `json:"output"`
+	Target      string `json:"target"`
+	SentAt      int64  `json:"sent_at"`
+	PayloadHash string `json:"payload_hash"`
+	Error       string `json:"error"`
+}
+
+// openRedis connects to the Redis server at a redis:// URL
+func openRedis(rawURL string) (*redisStore, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Redis URL %s: %w", Redacted(rawURL), err)
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error connecting to Redis %s: %w", Redacted(rawURL), err)
+	}
+
+	s := &redisStore{client: client}
+	if err := s.upgradeSnapshotScores(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// upgradeSnapshotScores rescores the snapshots saved by older versions in
+// seconds in milliseconds, once per database. Replicas doing it at the same
+// time don't conflict, since scores in milliseconds are left alone.
+func (s *redisStore) upgradeSnapshotScores() error {
+	ctx := context.Background()
+	if upgraded, err := s.client.Exists(ctx, snapshotMillisKey).Result(); err != nil {
+		return fmt.Errorf("error checking snapshot scores: %w", err)
+	} else if upgraded > 0 {
+		return nil
+	}
+
+	reports, err := s.client.SMembers(ctx, redisPrefix+"reports").Result()
+	if err != nil {
+		return fmt.Errorf("error upgrading snapshot scores: %w", err)
+	}
+	for _, report := range reports {
+		key := redisPrefix + "snapshots:" + report
+		members, err := s.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: "-inf", Max: "(" + strconv.FormatInt(maxSecondsScore, 10)}).Result()
+		if err != nil {
+			return fmt.Errorf("error upgrading %s snapshot scores: %w", report, err)
+		}
+		for i := range members {
+			members[i].Score *= 1000
+		}
+		if len(members) > 0 {
+			if err := s.client.ZAdd(ctx, key, members...).Err(); err != nil {
+				return fmt.Errorf("error upgrading %s snapshot scores: %w", report, err)
+			}
+		}
+	}
+
+	if err := s.client.Set(ctx, snapshotMillisKey, "1", 0).Err(); err != nil {
+		return fmt.Errorf("error upgrading snapshot scores: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection
+func (s *redisStore) Close() error {
+	return s.client.Close()
+}
+
+// Values returns the stored values of a kind keyed by key
+func (s *redisStore) Values(kind string) (map[string][]byte, error) {
+	fields, err := s.client.HGetAll(context.Background(), redisPrefix+"state:"+kind).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", kind, err)
+	}
+
+	values := make(map[string][]byte, len(fields))
+	for key, value := range fields {
+		values[key] = []byte(value)
+	}
+	return values, nil
+}
+
+// UpdateValues sets the fields in set and deletes the fields in remove of the
+// hash of each kind in one transaction. Other fields are left alone.
+func (s *redisStore) UpdateValues(set map[string]map[string][]byte, remove map[string][]string) error {
+	ctx := context.Background()
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for kind, values := range set {
+			if len(values) == 0 {
+				continue
+			}
+			fields := make(map[string]interface{}, len(values))
+			for field, value := range values {
+				fields[field] = string(value)
+			}
+			pipe.HSet(ctx, redisPrefix+"state:"+kind, fields)
+		}
+		for kind, fields := range remove {
+			if len(fields) > 0 {
+				pipe.HDel(ctx, redisPrefix+"state:"+kind, fields...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error saving state: %w", err)
+	}
+	return nil
+}
+
+// SaveSnapshot stores the PRs of a report run
+func (s *redisStore) SaveSnapshot(snapshot Snapshot) error {
+	ctx := context.Background()
+	id, err := s.client.Incr(ctx, redisPrefix+"snapshot-id").Result()
+	if err != nil {
+		return fmt.Errorf("error saving snapshot: %w", err)
+	}
+
+	data, err := json.Marshal(redisSnapshot{ID: id, PRs: snapshot.PRs})
+	if err != nil {
+		return fmt.Errorf("error encoding snapshot: %w", err)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, redisPrefix+"reports", snapshot.Report)
+		pipe.ZAdd(ctx, redisPrefix+"snapshots:"+snapshot.Report, redis.Z{Score: float64(snapshot.TakenAt.UnixMilli()), Member: string(data)})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error saving snapshot: %w", err)
+	}
+	return nil
+}
+
+// LastSnapshot returns the latest snapshot of a report taken before the given
+// time, or nil when there is none
+func (s *redisStore) LastSnapshot(report string, before time.Time) (*Snapshot, error) {
+	ctx := context.Background()
+	key := redisPrefix + "snapshots:" + report
+	latest, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Max:   "(" + strconv.FormatInt(before.UnixMilli(), 10),
+		Min:   "-inf",
+		Count: 1,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading last %s snapshot: %w", report, err)
+	}
+	if len(latest) == 0 {
+		return nil, nil
+	}
+
+	// Snapshots taken at the same millisecond are ordered by ID, not by the
+	// member order of the sorted set
+	score := strconv.FormatFloat(latest[0].Score, 'f', -1, 64)
+	members, err := s.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: score, Max: score}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading last %s snapshot: %w", report, err)
+	}
+
+	snapshots, err := decodeSnapshots(report, members)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshots[len(snapshots)-1], nil
+}
+
+// Snapshots returns the snapshots of a report taken since the given time,
+// oldest first
+func (s *redisStore) Snapshots(report string, since time.Time) ([]Snapshot, error) {
+	members, err := s.client.ZRangeByScoreWithScores(context.Background(), redisPrefix+"snapshots:"+report, &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s snapshots: %w", report, err)
+	}
+	return decodeSnapshots(report, members)
+}
+
+// PruneSnapshots removes snapshots taken before the given time
+func (s *redisStore) PruneSnapshots(before time.Time) error {
+	ctx := context.Background()
+	reports, err := s.client.SMembers(ctx, redisPrefix+"reports").Result()
+	if err != nil {
+		return fmt.Errorf("error pruning snapshots: %w", err)
+	}
+
+	for _, report := range reports {
+		if err := s.client.ZRemRangeByScore(ctx, redisPrefix+"snapshots:"+report, "-inf", "("+strconv.FormatInt(before.UnixMilli(), 10)).Err(); err != nil {
+			return fmt.Errorf("error pruning snapshots: %w", err)
+		}
+	}
+	return nil
+}
+
+// decodeSnapshots decodes snapshots stored in Redis, oldest first and in ID
+// order when taken at the same millisecond
+func decodeSnapshots(report string, members []redis.Z) ([]Snapshot, error) {
+	type scoredSnapshot struct {
+		redisSnapshot
+		takenAt int64 // Score, in Unix milliseconds
+	}
+	stored := make([]scoredSnapshot, len(members))
+	for i, member := range members {
+		data, _ := member.Member.(string)
+		if err := json.Unmarshal([]byte(data), &stored[i].redisSnapshot); err != nil {
+			return nil, fmt.Errorf("error decoding %s snapshot: %w", report, err)
+		}
+		stored[i].takenAt = int64(member.Score)
+	}
+	sort.Slice(stored, func(i, j int) bool {
+		if stored[i].takenAt != stored[j].takenAt {
+			return stored[i].takenAt < stored[j].takenAt
+		}
+		return stored[i].ID < stored[j].ID
+	})
+
+	snapshots := make([]Snapshot, len(stored))
+	for i, snapshot := range stored {
+		snapshots[i] = Snapshot{Report: report, TakenAt: time.UnixMilli(snapshot.takenAt), PRs: snapshot.PRs}
+	}
+	return snapshots, nil
+}
+
+// SaveMerges records merged PRs of a report, replacing earlier records of the
+// same PRs
+func (s *redisStore) SaveMerges(report string, merges []Merge) error {
+	if len(merges) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, merge := range merges {
+			data, err := json.Marshal(redisMerge{OpenedAt: merge.OpenedAt.Unix(), MergedAt: merge.MergedAt.Unix()})
+			if err != nil {
+				return fmt.Errorf("error encoding merge of PR #%d: %w", merge.Number, err)
+			}
+			number := strconv.Itoa(merge.Number)
+			pipe.HSet(ctx, redisPrefix+"merges:"+report, number, string(data))
+			pipe.ZAdd(ctx, redisPrefix+"merged:"+report, redis.Z{Score: float64(merge.MergedAt.Unix()), Member: number})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error saving merges: %w", err)
+	}
+	return nil
+}
+
+// LastMerge returns when the latest recorded PR of a report was merged, or
+// zero when none was recorded
+func (s *redisStore) LastMerge(report string) (time.Time, error) {
+	latest, err := s.client.ZRevRangeWithScores(context.Background(), redisPrefix+"merged:"+report, 0, 0).Result()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error reading last %s merge: %w", report, err)
+	}
+	if len(latest) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(int64(latest[0].Score), 0), nil
+}
+
+// Merges returns the PRs of a report merged within [since, until), oldest
+// merge first
+func (s *redisStore) Merges(report string, since, until time.Time) ([]Merge, error) {
+	ctx := context.Background()
+	numbers, err := s.client.ZRangeByScore(ctx, redisPrefix+"merged:"+report, &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.Unix(), 10),
+		Max: "(" + strconv.FormatInt(until.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s merges: %w", report, err)
+	}
+	if len(numbers) == 0 {
+		return nil, nil
+	}
+
+	values, err := s.client.HMGet(ctx, redisPrefix+"merges:"+report, numbers...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s merges: %w", report, err)
+	}
+
+	var merges []Merge
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var stored redisMerge
+		if err := json.Unmarshal([]byte(data), &stored); err != nil {
+			return nil, fmt.Errorf("error decoding %s merge: %w", report, err)
+		}
+		number, _ := strconv.Atoi(numbers[i])
+		merges = append(merges, Merge{Number: number, OpenedAt: time.Unix(stored.OpenedAt, 0), MergedAt: time.Unix(stored.MergedAt, 0)})
+	}
+	return merges, nil
+}
+
+// SaveDeliveries records deliveries of reports to outputs in the audit log
+func (s *redisStore) SaveDeliveries(deliveries []Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	last, err := s.client.IncrBy(ctx, redisPrefix+"delivery-id", int64(len(deliveries))).Result()
+	if err != nil {
+		return fmt.Errorf("error saving deliveries: %w", err)
+	}
+
+	members := make([]redis.Z, len(deliveries))
+	for i, d := range deliveries {
+		data, err := json.Marshal(redisDelivery{
+			ID:          last - int64(len(deliveries)-1-i),
+			Report:      d.Report,
+			Output:      d.Output,
+			Target:      d.Target,
+			SentAt:      d.SentAt.Unix(),
+			PayloadHash: d.PayloadHash,
+			Error:       d.Error,
+		})
+		if err != nil {
+			return fmt.Errorf("error encoding %s delivery: %w", d.Output, err)
+		}
+		members[i] = redis.Z{Score: float64(d.SentAt.Unix()), Member: string(data)}
+	}
+
+	if err := s.client.ZAdd(ctx, redisPrefix+"deliveries", members...).Err(); err != nil {
+		return fmt.Errorf("error saving deliveries: %w", err)
+	}
+	return nil
+}
+
+// Deliveries returns the deliveries of all reports sent since the given time,
+// oldest first
+func (s *redisStore) Deliveries(since time.Time) ([]Delivery, error) {
+	members, err := s.client.ZRangeByScore(context.Background(), redisPrefix+"deliveries", &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading deliveries: %w", err)
+	}
+
+	deliveries := make([]Delivery, len(members))
+	for i, member := range members {
+		var stored redisDelivery
+		if err := json.Unmarshal([]byte(member), &stored); err != nil {
+			return nil, fmt.Errorf("error decoding delivery: %w", err)
+		}
+		deliveries[i] = Delivery{
+			Report:      stored.Report,
+			Output:      stored.Output,
+			Target:      stored.Target,
+			SentAt:      time.Unix(stored.SentAt, 0),
+			PayloadHash: stored.PayloadHash,
+			Error:       stored.Error,
+		}
+	}
+	return deliveries, nil
+}
+
+// PruneDeliveries removes deliveries sent before the given time
+func (s *redisStore) PruneDeliveries(before time.Time) error {
+	if err := s.client.ZRemRangeByScore(context.Background(), redisPrefix+"deliveries", "-inf", "("+strconv.FormatInt(before.Unix(), 10)).Err(); err != nil {
+		return fmt.Errorf("error pruning deliveries: %w", err)
+	}
+	return nil
+}
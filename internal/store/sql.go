@@ -0,0 +1,372 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"  // Postgres driver
+	_ "modernc.org/sqlite" // SQLite driver without cgo
+	"pr-reporter/internal/model"
+)
+
+// sqlStore keeps the state in a SQLite or Postgres database
+type sqlStore struct {
+	db       *sql.DB
+	postgres bool // Use Postgres placeholders ($1) instead of SQLite ones (?)
+}
+
+// openSQLite opens the SQLite database at path, creating it when needed and
+// migrating its schema
+func openSQLite(path string) (*sqlStore, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("error creating database directory %s: %w", dir, err)
+		}
+	}
+
+	// Reporters and the server share the database, so wait for locks instead
+	// of failing right away
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("error opening database %s: %w", path, err)
+	}
+
+	s := &sqlStore{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error migrating %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// openPostgres connects to the Postgres database at a postgres:// URL,
+// migrating its schema when needed
+func openPostgres(url string) (*sqlStore, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database %s: %w", Redacted(url), err)
+	}
+
+	s := &sqlStore{db: db, postgres: true}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error migrating %s: %w", Redacted(url), err)
+	}
+
+	return s, nil
+}
+
+// rebind rewrites the ? placeholders of a query for the database
+func (s *sqlStore) rebind(query string) string {
+	if !s.postgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Close closes the database
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// Values returns the stored values of a kind (e.g., "messages") keyed by key
+func (s *sqlStore) Values(kind string) (map[string][]byte, error) {
+	rows, err := s.db.Query(s.rebind(`SELECT key, value FROM state WHERE kind = ?`), kind)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", kind, err)
+	}
+	defer rows.Close()
+
+	values := make(map[string][]byte)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", kind, err)
+		}
+		values[key] = []byte(value)
+	}
+
+	return values, rows.Err()
+}
+
+// UpdateValues upserts the values in set and deletes the keys in remove in
+// one transaction. Other keys are left alone.
+func (s *sqlStore) UpdateValues(set map[string]map[string][]byte, remove map[string][]string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for kind, values := range set {
+		for key, value := range values {
+			if _, err := tx.Exec(s.rebind(`INSERT INTO state (kind, key, value) VALUES (?, ?, ?) ON CONFLICT (kind, key) DO UPDATE SET value = excluded.value`), kind, key, string(value)); err != nil {
+				return fmt.Errorf("error saving %s %q: %w", kind, key, err)
+			}
+		}
+	}
+	for kind, keys := range remove {
+		for _, key := range keys {
+			if _, err := tx.Exec(s.rebind(`DELETE FROM state WHERE kind = ? AND key = ?`), kind, key); err != nil {
+				return fmt.Errorf("error removing %s %q: %w", kind, key, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing state: %w", err)
+	}
+
+	return nil
+}
+
+// SaveSnapshot stores the PRs of a report run
+func (s *sqlStore) SaveSnapshot(snapshot Snapshot) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Postgres doesn't report inserted IDs through LastInsertId
+	var id int64
+	err = tx.QueryRow(s.rebind(`INSERT INTO snapshots (report, taken_at) VALUES (?, ?) RETURNING id`), snapshot.Report, snapshot.TakenAt.UnixMilli()).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("error saving snapshot: %w", err)
+	}
+
+	for _, pr := range snapshot.PRs {
+		data, err := json.Marshal(pr)
+		if err != nil {
+			return fmt.Errorf("error encoding PR #%d: %w", pr.Number, err)
+		}
+		if _, err := tx.Exec(s.rebind(`INSERT INTO snapshot_prs (snapshot_id, number, pr) VALUES (?, ?, ?) ON CONFLICT (snapshot_id, number) DO UPDATE SET pr = excluded.pr`), id, pr.Number, string(data)); err != nil {
+			return fmt.Errorf("error saving PR #%d: %w", pr.Number, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// LastSnapshot returns the latest snapshot of a report taken before the given
+// time, or nil when there is none
+func (s *sqlStore) LastSnapshot(report string, before time.Time) (*Snapshot, error) {
+	var id, takenAt int64
+	err := s.db.QueryRow(s.rebind(`SELECT id, taken_at FROM snapshots WHERE report = ? AND taken_at < ? ORDER BY taken_at DESC, id DESC LIMIT 1`),
+		report, before.UnixMilli()).Scan(&id, &takenAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading last %s snapshot: %w", report, err)
+	}
+
+	prs, err := s.snapshotPRs(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{Report: report, TakenAt: time.UnixMilli(takenAt), PRs: prs}, nil
+}
+
+// Snapshots returns the snapshots of a report taken since the given time,
+// oldest first
+func (s *sqlStore) Snapshots(report string, since time.Time) ([]Snapshot, error) {
+	rows, err := s.db.Query(s.rebind(`SELECT id, taken_at FROM snapshots WHERE report = ? AND taken_at >= ? ORDER BY taken_at, id`), report, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s snapshots: %w", report, err)
+	}
+
+	type snapshotRow struct{ id, takenAt int64 }
+	var found []snapshotRow
+	for rows.Next() {
+		var row snapshotRow
+		if err := rows.Scan(&row.id, &row.takenAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error reading %s snapshots: %w", report, err)
+		}
+		found = append(found, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s snapshots: %w", report, err)
+	}
+
+	var snapshots []Snapshot
+	for _, row := range found {
+		prs, err := s.snapshotPRs(row.id)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, Snapshot{Report: report, TakenAt: time.UnixMilli(row.takenAt), PRs: prs})
+	}
+
+	return snapshots, nil
+}
+
+// PruneSnapshots removes snapshots taken before the given time
+func (s *sqlStore) PruneSnapshots(before time.Time) error {
+	if _, err := s.db.Exec(s.rebind(`DELETE FROM snapshots WHERE taken_at < ?`), before.UnixMilli()); err != nil {
+		return fmt.Errorf("error pruning snapshots: %w", err)
+	}
+	return nil
+}
+
+// snapshotPRs returns the PRs of a snapshot in PR number order
+func (s *sqlStore) snapshotPRs(id int64) ([]*model.PR, error) {
+	rows, err := s.db.Query(s.rebind(`SELECT pr FROM snapshot_prs WHERE snapshot_id = ? ORDER BY number`), id)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot PRs: %w", err)
+	}
+	defer rows.Close()
+
+	var prs []*model.PR
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("error reading snapshot PRs: %w", err)
+		}
+		var pr model.PR
+		if err := json.Unmarshal([]byte(data), &pr); err != nil {
+			return nil, fmt.Errorf("error decoding snapshot PR: %w", err)
+		}
+		prs = append(prs, &pr)
+	}
+
+	return prs, rows.Err()
+}
+
+// SaveMerges records merged PRs of a report, replacing earlier records of the
+// same PRs
+func (s *sqlStore) SaveMerges(report string, merges []Merge) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, merge := range merges {
+		if _, err := tx.Exec(s.rebind(`INSERT INTO merges (report, number, opened_at, merged_at) VALUES (?, ?, ?, ?)
+			ON CONFLICT (report, number) DO UPDATE SET opened_at = excluded.opened_at, merged_at = excluded.merged_at`),
+			report, merge.Number, merge.OpenedAt.Unix(), merge.MergedAt.Unix()); err != nil {
+			return fmt.Errorf("error saving merge of PR #%d: %w", merge.Number, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing merges: %w", err)
+	}
+
+	return nil
+}
+
+// LastMerge returns when the latest recorded PR of a report was merged, or
+// zero when none was recorded
+func (s *sqlStore) LastMerge(report string) (time.Time, error) {
+	var mergedAt sql.NullInt64
+	if err := s.db.QueryRow(s.rebind(`SELECT MAX(merged_at) FROM merges WHERE report = ?`), report).Scan(&mergedAt); err != nil {
+		return time.Time{}, fmt.Errorf("error reading last %s merge: %w", report, err)
+	}
+	if !mergedAt.Valid {
+		return time.Time{}, nil
+	}
+	return time.Unix(mergedAt.Int64, 0), nil
+}
+
+// Merges returns the PRs of a report merged within [since, until), oldest
+// merge first
+func (s *sqlStore) Merges(report string, since, until time.Time) ([]Merge, error) {
+	rows, err := s.db.Query(s.rebind(`SELECT number, opened_at, merged_at FROM merges WHERE report = ? AND merged_at >= ? AND merged_at < ? ORDER BY merged_at`),
+		report, since.Unix(), until.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s merges: %w", report, err)
+	}
+	defer rows.Close()
+
+	var merges []Merge
+	for rows.Next() {
+		var number int
+		var openedAt, mergedAt int64
+		if err := rows.Scan(&number, &openedAt, &mergedAt); err != nil {
+			return nil, fmt.Errorf("error reading %s merges: %w", report, err)
+		}
+		merges = append(merges, Merge{Number: number, OpenedAt: time.Unix(openedAt, 0), MergedAt: time.Unix(mergedAt, 0)})
+	}
+
+	return merges, rows.Err()
+}
+
+// SaveDeliveries records deliveries of reports to outputs in the audit log
+func (s *sqlStore) SaveDeliveries(deliveries []Delivery) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, d := range deliveries {
+		if _, err := tx.Exec(s.rebind(`INSERT INTO deliveries (report, output, target, sent_at, payload_hash, error) VALUES (?, ?, ?, ?, ?, ?)`),
+			d.Report, d.Output, d.Target, d.SentAt.Unix(), d.PayloadHash, d.Error); err != nil {
+			return fmt.Errorf("error saving %s delivery: %w", d.Output, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// Deliveries returns the deliveries of all reports sent since the given time,
+// oldest first
+func (s *sqlStore) Deliveries(since time.Time) ([]Delivery, error) {
+	rows, err := s.db.Query(s.rebind(`SELECT report, output, target, sent_at, payload_hash, error FROM deliveries WHERE sent_at >= ? ORDER BY sent_at, id`), since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("error reading deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var d Delivery
+		var sentAt int64
+		if err := rows.Scan(&d.Report, &d.Output, &d.Target, &sentAt, &d.PayloadHash, &d.Error); err != nil {
+			return nil, fmt.Errorf("error reading deliveries: %w", err)
+		}
+		d.SentAt = time.Unix(sentAt, 0)
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// PruneDeliveries removes deliveries sent before the given time
+func (s *sqlStore) PruneDeliveries(before time.Time) error {
+	if _, err := s.db.Exec(s.rebind(`DELETE FROM deliveries WHERE sent_at < ?`), before.Unix()); err != nil {
+		return fmt.Errorf("error pruning deliveries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/model"
+)
+
+// Store persists state between runs: posted messages, acknowledgments and
+// other keyed values, snapshots of the PRs of every report run for deltas and
+// history, merged PRs for cycle times and an audit log of deliveries. It is backed by SQLite, Postgres
+// or Redis; the latter two let several replicas share the state.
+type Store interface {
+	// Values returns the stored values of a kind (e.g., "messages") keyed by key
+	Values(kind string) (map[string][]byte, error)
+	// UpdateValues stores the values of each kind in set, replacing stored
+	// values of the same keys, and removes the keys of each kind in remove,
+	// all at once. Other keys are left alone.
+	UpdateValues(set map[string]map[string][]byte, remove map[string][]string) error
+
+	// SaveSnapshot stores the PRs of a report run
+	SaveSnapshot(snapshot Snapshot) error
+	// LastSnapshot returns the latest snapshot of a report taken before the
+	// given time, or nil when there is none
+	LastSnapshot(report string, before time.Time) (*Snapshot, error)
+	// Snapshots returns the snapshots of a report taken since the given time,
+	// oldest first
+	Snapshots(report string, since time.Time) ([]Snapshot, error)
+	// PruneSnapshots removes snapshots taken before the given time
+	PruneSnapshots(before time.Time) error
+
+	// SaveMerges records merged PRs of a report, replacing earlier records
+	// of the same PRs
+	SaveMerges(report string, merges []Merge) error
+	// LastMerge returns when the latest recorded PR of a report was merged,
+	// or zero when none was recorded
+	LastMerge(report string) (time.Time, error)
+	// Merges returns the PRs of a report merged within [since, until),
+	// oldest merge first
+	Merges(report string, since, until time.Time) ([]Merge, error)
+
+	// SaveDeliveries records deliveries of reports to outputs in the audit log
+	SaveDeliveries(deliveries []Delivery) error
+	// Deliveries returns the deliveries of all reports sent since the given
+	// time, oldest first
+	Deliveries(since time.Time) ([]Delivery, error)
+	// PruneDeliveries removes deliveries sent before the given time
+	PruneDeliveries(before time.Time) error
+
+	// Close releases the connection
+	Close() error
+}
+
+// Snapshot is the list of open PRs of a report at one run
+type Snapshot struct {
+	Report  string      // Report name (e.g., "frontend")
+	TakenAt time.Time   // When the report ran
+	PRs     []*model.PR // Open PRs at that time
+}
+
+// Merge records when a PR of a report was opened and merged
+type Merge struct {
+	Number   int
+	OpenedAt time.Time
+	MergedAt time.Time
+}
+
+// Delivery records one delivery of a report to an output
+type Delivery struct {
+	Report      string    // Report name (e.g., "frontend")
+	Output      string    // Output name (e.g., "slack", "teams", "email")
+	Target      string    // Where the output delivered to: channels, recipients, space, ... ("" when unknown)
+	SentAt      time.Time // When the delivery finished
+	PayloadHash string    // SHA-256 of the delivered report, identical for every output of a run
+	Error       string    // Why the delivery failed, "" when it succeeded
+}
+
+// Succeeded reports whether the delivery went out
+func (d Delivery) Succeeded() bool {
+	return d.Error == ""
+}
+
+// IsDatabase reports whether a state path refers to a database rather than a
+// JSON file: a SQLite file by its extension, or a Postgres or Redis URL
+func IsDatabase(path string) bool {
+	if isPostgres(path) || isRedis(path) {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return true
+	}
+	return false
+}
+
+// Open opens the database at path, creating its tables when needed: a
+// postgres:// or redis:// URL, or else a SQLite file
+func Open(path string) (Store, error) {
+	switch {
+	case isPostgres(path):
+		return openPostgres(path)
+	case isRedis(path):
+		return openRedis(path)
+	default:
+		return openSQLite(path)
+	}
+}
+
+// Redacted returns a state path safe for logs, without the password of
+// database URLs
+func Redacted(path string) string {
+	if !isPostgres(path) && !isRedis(path) {
+		return path
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return "<invalid database URL>"
+	}
+	return u.Redacted()
+}
+
+// isPostgres reports whether a state path is a Postgres URL
+func isPostgres(path string) bool {
+	return strings.HasPrefix(path, "postgres://") || strings.HasPrefix(path, "postgresql://")
+}
+
+// isRedis reports whether a state path is a Redis URL
+func isRedis(path string) bool {
+	return strings.HasPrefix(path, "redis://") || strings.HasPrefix(path, "rediss://")
+}
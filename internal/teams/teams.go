@@ -0,0 +1,220 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/model"
+)
+
+// Options contains options for sending a PR report to Microsoft Teams
+type Options struct {
+	WebhookURL string // Teams incoming webhook (or Workflows "post to a channel") URL
+}
+
+// maxPRsPerCard keeps each Adaptive Card well below the Teams message size limit
+const maxPRsPerCard = 20
+
+// element is an Adaptive Card element
+type element map[string]interface{}
+
+// httpClient is used to post to Teams webhooks
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Teams"}).Client(30 * time.Second)
+
+// SendReport posts the report to a Teams channel as Adaptive Cards. Long
+// reports are split into several cards.
+func SendReport(ctx context.Context, opts Options, report model.Report) error {
+	if opts.WebhookURL == "" {
+		return fmt.Errorf("Teams webhook URL is required")
+	}
+
+	cards := buildCards(report)
+
+	for i, card := range cards {
+		if err := postCard(ctx, opts.WebhookURL, card); err != nil {
+			return fmt.Errorf("error posting card %d/%d to Teams: %w", i+1, len(cards), err)
+		}
+
+		slog.Debug("Sent Teams card", "part", i+1, "parts", len(cards))
+	}
+
+	return nil
+}
+
+// buildCards lays the report out as Adaptive Cards: the header and summary go
+// on the first and last card, with the PRs split between them
+func buildCards(report model.Report) []element {
+	text := report.Text()
+
+	var header []element
+	if report.Title != "" {
+		header = append(header, element{"type": "TextBlock", "text": "📋 " + report.Title, "size": "Large", "weight": "Bolder", "wrap": true})
+	}
+	header = append(header,
+		element{"type": "TextBlock", "text": "📅 " + report.Date.Format("2006-01-02"), "isSubtle": true, "spacing": "None"},
+		element{"type": "TextBlock", "text": fmt.Sprintf("📊 **%s: %d**", text.TotalOpenPRs, len(report.PRs)), "wrap": true},
+	)
+
+	var prElements []element
+	for i, pr := range report.PRs {
+		prElements = append(prElements, prContainer(report, text, i+1, pr))
+	}
+
+	footer := []element{summaryBlock(report, text)}
+
+	// Split PRs into chunks, always producing at least one card
+	var chunks [][]element
+	for start := 0; start < len(prElements); start += maxPRsPerCard {
+		end := start + maxPRsPerCard
+		if end > len(prElements) {
+			end = len(prElements)
+		}
+		chunks = append(chunks, prElements[start:end])
+	}
+	if len(chunks) == 0 {
+		chunks = append(chunks, nil)
+	}
+
+	cards := make([]element, len(chunks))
+	for i, chunk := range chunks {
+		var body []element
+		if i == 0 {
+			body = append(body, header...)
+		} else {
+			body = append(body, element{"type": "TextBlock", "text": fmt.Sprintf("_(%s %d/%d)_", text.Continued, i+1, len(chunks)), "isSubtle": true})
+		}
+		body = append(body, chunk...)
+		if i == len(chunks)-1 {
+			body = append(body, footer...)
+		}
+
+		cards[i] = element{
+			"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+			"type":    "AdaptiveCard",
+			"version": "1.4",
+			"msteams": element{"width": "Full"},
+			"body":    body,
+		}
+	}
+
+	return cards
+}
+
+// prContainer formats a single PR as a container with its JIRA facts
+func prContainer(report model.Report, text model.Strings, index int, pr *model.PR) element {
+	jira := text.None
+	if pr.JiraTicket != "" {
+		jira = pr.JiraTicket
+		if url := report.TicketURL(pr); url != "" {
+			jira = fmt.Sprintf("[%s](%s)", pr.JiraTicket, url)
+		}
+	}
+
+	status := pr.JiraStatus
+	if status == "" {
+		status = text.UnknownStatus
+	}
+
+	assignee := pr.GithubAssignee
+	if assignee == "" {
+		assignee = text.Unassigned
+	}
+
+	description := pr.Description
+	if description == "" {
+		description = text.NoDescription
+	}
+
+	var flags []string
+	if pr.IsBlocked {
+		flags = append(flags, "🚫 "+text.ReasonBlocked)
+	}
+	if pr.IsDraft {
+		flags = append(flags, "📝 "+text.Draft)
+	}
+
+	title := fmt.Sprintf("%d. **[PR-%d](%s)** %s", index, pr.Number, report.PRURL(pr), description)
+	if len(flags) > 0 {
+		title += " · " + strings.Join(flags, " · ")
+	}
+
+	return element{
+		"type":      "Container",
+		"separator": true,
+		"items": []element{
+			{"type": "TextBlock", "text": title, "wrap": true},
+			{"type": "FactSet", "facts": []element{
+				{"title": "Jira", "value": jira},
+				{"title": "Status", "value": status},
+				{"title": "Assignee", "value": assignee},
+			}},
+		},
+	}
+}
+
+// summaryBlock lists blocked and draft PRs at the end of the report
+func summaryBlock(report model.Report, text model.Strings) element {
+	links := func(prs []*model.PR) string {
+		var result []string
+		for _, pr := range prs {
+			result = append(result, fmt.Sprintf("[PR-%d](%s)", pr.Number, report.PRURL(pr)))
+		}
+		return strings.Join(result, ", ")
+	}
+
+	var lines []string
+	blocked, drafts := report.Blocked(), report.Drafts()
+	if len(blocked) > 0 {
+		lines = append(lines, fmt.Sprintf("🚫 **%s:** %s", text.Blocked, links(blocked)))
+	}
+	if len(drafts) > 0 {
+		lines = append(lines, fmt.Sprintf("📝 **%s:** %s", text.Draft, links(drafts)))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, fmt.Sprintf("✅ **%s:** %s", text.BlockedOrDraft, text.None))
+	}
+
+	return element{"type": "TextBlock", "text": strings.Join(lines, "\n\n"), "wrap": true, "separator": true}
+}
+
+// postCard sends a single Adaptive Card to a Teams webhook
+func postCard(ctx context.Context, url string, card element) error {
+	payload := element{
+		"type": "message",
+		"attachments": []element{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Incoming webhooks answer 200, Workflows answer 202
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return httpx.NewStatusError("Teams", resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pr-reporter/internal/model"
+)
+
+// ANSI escape codes used for the color-coded statuses
+const (
+	reset  = "\033[0m"
+	bold   = "\033[1m"
+	dim    = "\033[2m"
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+	cyan   = "\033[36m"
+)
+
+// ClearScreen moves the cursor home and clears the terminal, for redraws
+const ClearScreen = "\033[H\033[2J"
+
+// maxDescriptionLength keeps rows on one line in most terminals
+const maxDescriptionLength = 60
+
+// ColorEnabled reports whether colors should be written to f: only to
+// terminals, and never when NO_COLOR is set (https://no-color.org)
+func ColorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// cell is a table cell with the color it is printed in
+type cell struct {
+	text  string
+	color string
+}
+
+// Render writes the report as a table with color-coded statuses
+func Render(w io.Writer, report model.Report, color bool) {
+	text := report.Text()
+
+	paint := func(s, code string) string {
+		if !color || code == "" {
+			return s
+		}
+		return code + s + reset
+	}
+
+	title := report.Title
+	if title == "" {
+		title = report.Name + " PR Report"
+	}
+	fmt.Fprintf(w, "%s  %s\n", paint(title, bold), paint(report.Date.Format("2006-01-02 15:04"), dim))
+	fmt.Fprintf(w, "%s: %d\n\n", text.TotalOpenPRs, len(report.PRs))
+
+	if len(report.PRs) == 0 {
+		return
+	}
+
+	rows := [][]cell{{{"#", bold}, {"PR", bold}, {"STATUS", bold}, {"TICKET", bold}, {"TICKET STATUS", bold}, {"ASSIGNEE", bold}, {"CHECKS", bold}, {"AGE", bold}, {"DESCRIPTION", bold}}}
+	for i, pr := range report.PRs {
+		status, statusColor := "open", green
+		if pr.IsDraft {
+			status, statusColor = "draft", dim
+		}
+		if pr.IsBlocked {
+			status, statusColor = "blocked", red
+		}
+
+		ticket := pr.JiraTicket
+		if ticket == "" {
+			ticket = "-"
+		}
+		ticketStatus := pr.JiraStatus
+		if ticketStatus == "" {
+			ticketStatus = "-"
+		}
+
+		assignee := pr.GithubAssignee
+		if assignee == "" {
+			assignee = "-"
+		}
+
+		checks, checksColor := pr.ChecksState, ""
+		switch pr.ChecksState {
+		case "success":
+			checksColor = green
+		case "failure":
+			checksColor = red
+		case "pending":
+			checksColor = yellow
+		case "":
+			checks = "-"
+		}
+
+		description := pr.Description
+		if description == "" {
+			description = text.NoDescription
+		}
+
+		rows = append(rows, []cell{
+			{fmt.Sprintf("%d", i+1), dim},
+			{fmt.Sprintf("#%d", pr.Number), cyan},
+			{status, statusColor},
+			{ticket, ""},
+			{ticketStatus, ""},
+			{assignee, ""},
+			{checks, checksColor},
+			{age(report.Date, pr.CreatedAt), ""},
+			{truncate(description, maxDescriptionLength), ""},
+		})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, c := range row {
+			if n := utf8.RuneCountInString(c.text); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	for _, row := range rows {
+		var line []string
+		for i, c := range row {
+			padded := c.text
+			if i < len(row)-1 {
+				padded += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c.text))
+			}
+			line = append(line, paint(padded, c.color))
+		}
+		fmt.Fprintln(w, strings.Join(line, "  "))
+	}
+
+	blocked, drafts := len(report.Blocked()), len(report.Drafts())
+	fmt.Fprintf(w, "\n%s  %s\n", paint(fmt.Sprintf("%s: %d", text.Blocked, blocked), red), paint(fmt.Sprintf("%s: %d", text.Draft, drafts), dim))
+}
+
+// age formats how long ago a PR was opened, e.g. "3d" or "5h"
+func age(now, created time.Time) string {
+	if created.IsZero() {
+		return "-"
+	}
+	d := now.Sub(created)
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// truncate shortens s to at most max characters
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
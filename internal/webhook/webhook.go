@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"pr-reporter/internal/httpx"
+	"pr-reporter/internal/model"
+)
+
+// Options contains options for posting the report to a generic webhook
+type Options struct {
+	URL     string            // Endpoint the JSON report is POSTed to
+	Headers map[string]string // Extra request headers (e.g., Authorization)
+	Secret  string            // Signs the body with HMAC-SHA256 when set
+}
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, prefixed
+// with "sha256=", when a secret is configured
+const SignatureHeader = "X-PR-Reporter-Signature"
+
+// Payload is the JSON document sent to the webhook
+type Payload struct {
+	Report      string    `json:"report"`
+	Title       string    `json:"title"`
+	Repository  string    `json:"repository"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Summary     Summary   `json:"summary"`
+	PRs         []PR      `json:"prs"`
+}
+
+// Summary holds the report totals
+type Summary struct {
+	Total        int            `json:"total"`
+	Blocked      []int          `json:"blocked"`       // Numbers of blocked PRs
+	Drafts       []int          `json:"drafts"`        // Numbers of draft PRs that aren't blocked
+	JiraStatuses map[string]int `json:"jira_statuses"` // JIRA status -> number of PRs
+}
+
+// PR is a single PR of the payload
+type PR struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author"`
+	Assignee    string    `json:"assignee"`
+	JiraTicket  string    `json:"jira_ticket"`
+	JiraURL     string    `json:"jira_url"`
+	JiraStatus  string    `json:"jira_status"`
+	Description string    `json:"description"`
+	IsDraft     bool      `json:"is_draft"`
+	IsBlocked   bool      `json:"is_blocked"`
+	Labels      []string  `json:"labels"`
+	Reviewers   []string  `json:"reviewers"`
+	ChecksState string    `json:"checks_state"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// httpClient is used to post to webhooks
+var httpClient = httpx.NewTransport(nil, httpx.Options{Name: "Webhook"}).Client(30 * time.Second)
+
+// SendReport POSTs the structured report as JSON to the configured URL
+func SendReport(ctx context.Context, opts Options, report model.Report) error {
+	if opts.URL == "" {
+		return fmt.Errorf("webhook URL is required")
+	}
+
+	body, err := json.Marshal(NewPayload(report))
+	if err != nil {
+		return fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pr-reporter")
+	for name, value := range opts.Headers {
+		req.Header.Set(name, value)
+	}
+	if opts.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+sign(opts.Secret, body))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return httpx.NewStatusError("Webhook", resp)
+	}
+
+	slog.Debug("Posted report payload to webhook", "bytes", len(body), "status", resp.Status)
+
+	return nil
+}
+
+// NewPayload builds the webhook payload of a report
+func NewPayload(report model.Report) Payload {
+	payload := Payload{
+		Report:      report.Name,
+		Title:       report.Title,
+		Repository:  report.GithubOwner + "/" + report.GithubRepo,
+		GeneratedAt: report.Date,
+		Summary: Summary{
+			Total:        len(report.PRs),
+			Blocked:      []int{},
+			Drafts:       []int{},
+			JiraStatuses: make(map[string]int),
+		},
+		PRs: []PR{},
+	}
+
+	for _, pr := range report.PRs {
+		payload.PRs = append(payload.PRs, PR{
+			Number:      pr.Number,
+			Title:       pr.Title,
+			URL:         report.PRURL(pr),
+			Author:      pr.Author,
+			Assignee:    pr.GithubAssignee,
+			JiraTicket:  pr.JiraTicket,
+			JiraURL:     report.TicketURL(pr),
+			JiraStatus:  pr.JiraStatus,
+			Description: pr.Description,
+			IsDraft:     pr.IsDraft,
+			IsBlocked:   pr.IsBlocked,
+			Labels:      pr.Labels,
+			Reviewers:   pr.Reviewers,
+			ChecksState: pr.ChecksState,
+			CreatedAt:   pr.CreatedAt,
+			UpdatedAt:   pr.UpdatedAt,
+		})
+		if pr.JiraStatus != "" {
+			payload.Summary.JiraStatuses[pr.JiraStatus]++
+		}
+	}
+	for _, pr := range report.Blocked() {
+		payload.Summary.Blocked = append(payload.Summary.Blocked, pr.Number)
+	}
+	for _, pr := range report.Drafts() {
+		payload.Summary.Drafts = append(payload.Summary.Drafts, pr.Number)
+	}
+
+	return payload
+}
+
+// sign returns the hex HMAC-SHA256 of body
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}